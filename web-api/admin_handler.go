@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrAdminSelf = errors.New("admins cannot disable or delete their own account")
+
+type setRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// AdminHandler berisi HTTP handler untuk /admin. Pengecekan role dilakukan
+// oleh requireRole di main.go, jadi handler di sini tidak memeriksanya lagi.
+type AdminHandler struct {
+	Users     UserService
+	TwoFactor TwoFactorService
+	Avatars   FileStore
+}
+
+// ListUsers menampilkan semua user dengan pagination ?limit= dan ?offset=.
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "users only support limit/offset pagination")
+		return
+	}
+
+	users, total, err := h.Users.ListUsers(c.Request.Context(), page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"meta":  page.Meta(total, nil),
+	})
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// SetUserRole mengganti role user, misalnya menjadikan member sebagai admin.
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	var req setRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	role, err := ParseRole(req.Role)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.Users.SetRole(c.Request.Context(), id, role); err != nil {
+		userError(c, id, err)
+		return
+	}
+	h.GetUser(c)
+}
+
+// DisableUser memblokir login dan semua request user, termasuk lewat API key,
+// lalu mencabut token dan session-nya. EnableUser membatalkannya.
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *AdminHandler) setDisabled(c *gin.Context, disabled bool) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	if disabled && id == currentUser(c).ID {
+		problem(c, http.StatusConflict, ErrAdminSelf.Error())
+		return
+	}
+
+	if err := h.Users.SetDisabled(c.Request.Context(), id, disabled); err != nil {
+		userError(c, id, err)
+		return
+	}
+	h.GetUser(c)
+}
+
+// DeleteUser menghapus user beserta semua datanya seperti DELETE /me.
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+	if user.ID == currentUser(c).ID {
+		problem(c, http.StatusConflict, ErrAdminSelf.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.Users.DeleteUser(ctx, user.ID); err != nil {
+		userError(c, user.ID, err)
+		return
+	}
+	deleteAvatarFile(ctx, h.Avatars, user.AvatarURL)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetTwoFactor mematikan 2FA user yang kehilangan authenticator app dan
+// backup code-nya, supaya user bisa login dengan password saja lalu enroll ulang.
+func (h *AdminHandler) ResetTwoFactor(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.TwoFactor.Disable(c.Request.Context(), user.ID); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) loadUser(c *gin.Context) (*User, bool) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return nil, false
+	}
+	user, err := h.Users.GetUser(c.Request.Context(), id)
+	if err != nil {
+		userError(c, id, err)
+		return nil, false
+	}
+	return user, true
+}
+
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid user id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func userError(c *gin.Context, id uint, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrLastAdmin):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
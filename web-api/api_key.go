@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix memudahkan mengenali API key, misalnya oleh secret scanner.
+const apiKeyPrefix = "tdl_"
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid or expired api key")
+)
+
+// APIKey adalah kredensial jangka panjang untuk script dan integrasi. Key
+// hanya ditampilkan sekali saat dibuat; yang disimpan hanya hash-nya, dan
+// Prefix (beberapa karakter awal) dipakai untuk membedakan key di daftar.
+// Scopes default-nya admin supaya key yang dibuat sebelum ada scope tetap
+// punya akses penuh.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"not null;index"`
+	User       *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name       string     `json:"name" gorm:"type:varchar(128);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash    string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	Scopes     ScopeSet   `json:"scopes" gorm:"type:varchar(255);not null;default:'admin'"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Interface untuk layanan API key
+type APIKeyService interface {
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	CreateAPIKey(ctx context.Context, key *APIKey) (string, error)
+	DeleteAPIKey(ctx context.Context, id uint) error
+	Authenticate(ctx context.Context, key string) (*APIKey, error)
+}
+
+// Struct implementasi APIKeyService dengan GORM
+type APIKeyServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *APIKeyServiceImpl) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CreateAPIKey membuat key baru untuk user yang login dan mengembalikan nilai
+// key dalam bentuk asli.
+func (s *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, key *APIKey) (string, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	value := apiKeyPrefix + secret
+	key.UserID = ownerID(ctx)
+	key.Prefix = value[:len(apiKeyPrefix)+6]
+	key.KeyHash = hashToken(value)
+	if err := s.DB.WithContext(ctx).Create(key).Error; err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (s *APIKeyServiceImpl) DeleteAPIKey(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&APIKey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate mencari key beserta pemiliknya dan mencatat waktu pemakaian terakhir.
+// last_used_at paling sering diperbarui sekali per menit supaya setiap request
+// tidak menulis ke database.
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, value string) (*APIKey, error) {
+	db := s.DB.WithContext(ctx)
+	var key APIKey
+	err := db.Preload("User").Where("key_hash = ?", hashToken(value)).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+	touched := db.Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", key.ID, now.Add(-time.Minute)).
+		Update("last_used_at", now)
+	if touched.Error != nil {
+		return nil, touched.Error
+	}
+	// Pemakaian dicatat ke audit log dengan jeda yang sama seperti last_used_at.
+	if touched.RowsAffected > 0 {
+		if err := recordAuthEvent(db, key.UserID, AuthEventAPIKeyUsed, &key.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &key, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyHandler berisi HTTP handler untuk /api-keys.
+type APIKeyHandler struct {
+	Service APIKeyService
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.Service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// CreateAPIKey membuat API key baru. Nilai key hanya ada di respons ini.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 128 {
+		problem(c, http.StatusBadRequest, "name must be between 1 and 128 characters")
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		problem(c, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	scopes, err := parseScopes(req.Scopes)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(scopes) == 0 {
+		scopes = defaultAPIKeyScopes
+	}
+
+	key := APIKey{Name: req.Name, Scopes: scopes, ExpiresAt: req.ExpiresAt}
+	value, err := h.Service.CreateAPIKey(c.Request.Context(), &key)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"api_key": key, "key": value})
+}
+
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid api key id")
+		return
+	}
+
+	err = h.Service.DeleteAPIKey(c.Request.Context(), uint(id))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
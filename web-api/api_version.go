@@ -0,0 +1,18 @@
+package main
+
+import "github.com/gin-gonic/gin"
+
+// apiV1Prefix adalah prefix route API versi 1. Versi berikutnya didaftarkan
+// dengan prefix sendiri (/api/v2) di samping v1, jadi client v1 tidak
+// terpengaruh perubahan yang tidak kompatibel.
+const apiV1Prefix = "/api/v1"
+
+// deprecatedAlias menandai route lama tanpa prefix versi dengan header
+// Deprecation dan Link ke path penggantinya supaya client tahu harus pindah.
+func deprecatedAlias(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+prefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
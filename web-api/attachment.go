@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxAttachmentBytes = 10 << 20
+	// maxAttachmentsPerTask membatasi jumlah file per task supaya satu task
+	// tidak menghabiskan penyimpanan.
+	maxAttachmentsPerTask   = 50
+	maxAttachmentNameLength = 255
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentType     = errors.New("attachment type is not allowed")
+	ErrTooManyAttachments = fmt.Errorf("a task can have at most %d attachments", maxAttachmentsPerTask)
+)
+
+// attachmentContentTypes adalah tipe file yang boleh di-upload, dideteksi dari
+// isi file (bukan dari header Content-Type kiriman client). Dokumen Office
+// modern terdeteksi sebagai application/zip.
+var attachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+// Attachment adalah file yang dilampirkan ke task. Isi file disimpan di
+// FileStore dengan nama StorageKey; baris ini hanya menyimpan metadata.
+// UploaderID menjadi NULL jika akun pengunggahnya sudah dihapus.
+type Attachment struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TaskID      uint      `json:"task_id" gorm:"not null;index"`
+	Task        *Task     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UploaderID  *uint     `json:"uploader_id"`
+	Uploader    *User     `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	FileName    string    `json:"file_name" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	StorageKey  string    `json:"-" gorm:"not null;uniqueIndex"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Interface untuk layanan lampiran task
+type AttachmentService interface {
+	ListAttachments(ctx context.Context, taskID uint) ([]Attachment, error)
+	GetAttachment(ctx context.Context, taskID, id uint) (*Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *Attachment) error
+	DeleteAttachment(ctx context.Context, taskID, id uint) (*Attachment, error)
+}
+
+// Struct implementasi AttachmentService dengan GORM
+type AttachmentServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *AttachmentServiceImpl) ListAttachments(ctx context.Context, taskID uint) ([]Attachment, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return nil, err
+	}
+	var attachments []Attachment
+	err := db.Where("task_id = ?", taskID).Order("created_at, id").Find(&attachments).Error
+	return attachments, err
+}
+
+func (s *AttachmentServiceImpl) GetAttachment(ctx context.Context, taskID, id uint) (*Attachment, error) {
+	var attachment Attachment
+	if err := findAttachment(s.DB.WithContext(ctx), taskID, id, &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// CreateAttachment menyimpan metadata file yang sudah disimpan ke FileStore
+// atas nama user di context.
+func (s *AttachmentServiceImpl) CreateAttachment(ctx context.Context, attachment *Attachment) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, attachment.TaskID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&Attachment{}).Where("task_id = ?", attachment.TaskID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxAttachmentsPerTask {
+			return ErrTooManyAttachments
+		}
+		uploader := ownerID(ctx)
+		attachment.UploaderID = &uploader
+		return tx.Create(attachment).Error
+	})
+}
+
+// DeleteAttachment menghapus metadata lampiran dan mengembalikannya supaya
+// pemanggil bisa menghapus file-nya dari FileStore.
+func (s *AttachmentServiceImpl) DeleteAttachment(ctx context.Context, taskID, id uint) (*Attachment, error) {
+	var attachment Attachment
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findAttachment(tx, taskID, id, &attachment); err != nil {
+			return err
+		}
+		return tx.Delete(&attachment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// findAttachment memuat lampiran di task yang bisa diakses user di context tx.
+func findAttachment(tx *gorm.DB, taskID, id uint, attachment *Attachment) error {
+	if err := ensureTaskExists(tx, taskID); err != nil {
+		return err
+	}
+	err := tx.Where("task_id = ?", taskID).First(attachment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAttachmentNotFound
+	}
+	return err
+}
+
+// attachmentContentType mengembalikan tipe MIME hasil deteksi tanpa parameter
+// seperti charset, atau ErrAttachmentType jika tipenya tidak diizinkan.
+func attachmentContentType(detected string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil || !attachmentContentTypes[mediaType] {
+		return "", ErrAttachmentType
+	}
+	return mediaType, nil
+}
+
+// cleanAttachmentName mengambil nama file tanpa direktori dan membuang
+// karakter kontrol supaya aman dipakai di header Content-Disposition.
+func cleanAttachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	if len(name) > maxAttachmentNameLength {
+		name = strings.ToValidUTF8(name[len(name)-maxAttachmentNameLength:], "")
+	}
+	return name
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler berisi HTTP handler untuk /tasks/:id/attachments. Isi file
+// disimpan di Files, metadata-nya lewat Service.
+type AttachmentHandler struct {
+	Service  AttachmentService
+	Files    FileStore
+	MaxBytes int64
+}
+
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.Service.ListAttachments(c.Request.Context(), taskID)
+	if err != nil {
+		attachmentError(c, err, taskID, 0)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// UploadAttachment menerima file multipart "file". Tipe file dideteksi dari
+// isinya dan harus termasuk attachmentContentTypes.
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	// Ditambah 64 KiB untuk header multipart di luar isi file.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxBytes+64<<10)
+	file, header, err := c.Request.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || (err == nil && header.Size > h.MaxBytes) {
+		if file != nil {
+			file.Close()
+		}
+		problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("attachment must be at most %d bytes", h.MaxBytes))
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, "multipart field \"file\" is required")
+		return
+	}
+	defer file.Close()
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		internalError(c, err)
+		return
+	}
+	contentType, err := attachmentContentType(http.DetectContentType(sniff[:n]))
+	if err != nil {
+		problem(c, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	suffix, err := randomToken(18)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	attachment := Attachment{
+		TaskID:      taskID,
+		FileName:    cleanAttachmentName(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		StorageKey:  fmt.Sprintf("%d/%s", taskID, suffix),
+	}
+	if err := h.Files.Save(ctx, attachment.StorageKey, file); err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := h.Service.CreateAttachment(ctx, &attachment); err != nil {
+		h.Files.Delete(ctx, attachment.StorageKey)
+		attachmentError(c, err, taskID, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// DownloadAttachment mengirim isi file sebagai unduhan, bukan untuk dibuka
+// langsung di browser, supaya file HTML atau SVG tidak bisa menjalankan script.
+// Jika Files bisa membuat URL presigned (misalnya S3), client diarahkan ke
+// sana dengan 302 sehingga isi file tidak lewat server ini.
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	attachment, err := h.Service.GetAttachment(ctx, taskID, id)
+	if err != nil {
+		attachmentError(c, err, taskID, id)
+		return
+	}
+	if signer, ok := h.Files.(FileURLSigner); ok {
+		url, err := signer.PresignedURL(ctx, attachment.StorageKey, FileDownload{
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+		})
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, url)
+		return
+	}
+
+	file, err := h.Files.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	attachment, err := h.Service.DeleteAttachment(ctx, taskID, id)
+	if err != nil {
+		attachmentError(c, err, taskID, id)
+		return
+	}
+	// Metadata sudah terhapus; file yang gagal dihapus hanya menjadi sampah di storage.
+	h.Files.Delete(ctx, attachment.StorageKey)
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseAttachmentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("attachmentID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid attachment id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func attachmentError(c *gin.Context, err error, taskID, id uint) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		taskNotFound(c, taskID)
+	case errors.Is(err, ErrAttachmentNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrTooManyAttachments):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
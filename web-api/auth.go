@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultAccessTokenTTL = 15 * time.Minute
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenService menerbitkan dan memvalidasi access token JWT (HS256).
+type TokenService struct {
+	Secret    []byte
+	AccessTTL time.Duration
+}
+
+// NewTokenService membuat TokenService dari secret. Secret kosong diganti
+// secret acak, sehingga token lama tidak berlaku lagi setelah server restart.
+func NewTokenService(secret string, accessTTL time.Duration) (*TokenService, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &TokenService{Secret: key, AccessTTL: accessTTL}, nil
+}
+
+// accessClaims menambahkan TokenVersion user ke claim standar. Token yang
+// versinya tidak sama dengan user.TokenVersion ditolak.
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Version int `json:"ver,omitempty"`
+}
+
+// IssueAccessToken membuat token untuk user yang berlaku selama AccessTTL.
+func (s *TokenService) IssueAccessToken(user *User, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.AccessTTL)
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Version: user.TokenVersion,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.Secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// ParseAccessToken memvalidasi tanda tangan dan masa berlaku token lalu
+// mengembalikan id user dan versi token.
+func (s *TokenService) ParseAccessToken(value string) (uint, int, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(value, &claims, func(*jwt.Token) (any, error) {
+		return s.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, 0, ErrInvalidToken
+	}
+
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, 0, ErrInvalidToken
+	}
+	return uint(id), claims.Version, nil
+}
+
+type userContextKey struct{}
+
+// withUser menyimpan user ke context request supaya bisa dibaca service.
+func withUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// userFromContext mengembalikan user yang sedang login, atau nil.
+func userFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userContextKey{}).(*User)
+	return user
+}
+
+type apiKeyContextKey struct{}
+
+// apiKeyFromContext mengembalikan API key yang dipakai untuk login, atau nil
+// jika request memakai access token atau session.
+func apiKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
+
+type sessionTokenContextKey struct{}
+
+// sessionTokenFromContext mengembalikan token cookie session yang dipakai untuk
+// login, atau string kosong jika request memakai access token atau API key.
+func sessionTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(sessionTokenContextKey{}).(string)
+	return token
+}
+
+// currentUser mengembalikan user yang sudah divalidasi requireAuth.
+func currentUser(c *gin.Context) *User {
+	return userFromContext(c.Request.Context())
+}
+
+// requireAuth memvalidasi header Authorization: Bearer <token>, X-API-Key, atau
+// cookie session, memuat user, lalu menyimpannya ke context request untuk
+// handler berikutnya.
+func requireAuth(tokens *TokenService, users UserService, keys APIKeyService, sessions SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var user *User
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			apiKey, err := keys.Authenticate(ctx, key)
+			if errors.Is(err, ErrInvalidAPIKey) {
+				unauthorized(c, err.Error())
+				return
+			}
+			if err != nil {
+				internalError(c, err)
+				c.Abort()
+				return
+			}
+			user = apiKey.User
+			ctx = withScopes(ctx, apiKey.Scopes)
+			ctx = context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+		} else if cookie, err := c.Cookie(sessionCookie); err == nil && cookie != "" && c.GetHeader("Authorization") == "" {
+			session, err := sessions.Authenticate(ctx, cookie)
+			if errors.Is(err, ErrSessionInvalid) {
+				unauthorized(c, err.Error())
+				return
+			}
+			if err != nil {
+				internalError(c, err)
+				c.Abort()
+				return
+			}
+			if !checkCSRF(c, cookie) {
+				return
+			}
+			user = session.User
+			ctx = context.WithValue(ctx, sessionTokenContextKey{}, cookie)
+		} else {
+			scheme, value, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") || value == "" {
+				unauthorized(c, "missing bearer token")
+				return
+			}
+
+			id, version, err := tokens.ParseAccessToken(strings.TrimSpace(value))
+			if err != nil {
+				unauthorized(c, err.Error())
+				return
+			}
+			user, err = users.GetUser(ctx, id)
+			if errors.Is(err, ErrUserNotFound) {
+				unauthorized(c, ErrInvalidToken.Error())
+				return
+			}
+			if err != nil {
+				internalError(c, err)
+				c.Abort()
+				return
+			}
+			// Versi berbeda berarti token dicabut lewat logout-all atau reset password.
+			if version != user.TokenVersion {
+				unauthorized(c, ErrInvalidToken.Error())
+				return
+			}
+		}
+
+		// Token dan session sudah dicabut saat akun dinonaktifkan, tetapi API key tidak.
+		if user.Disabled() {
+			problem(c, http.StatusForbidden, ErrUserDisabled.Error())
+			return
+		}
+
+		c.Request = c.Request.WithContext(withUser(ctx, user))
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	problem(c, http.StatusUnauthorized, message)
+}
+
+// parseCountEnv membaca bilangan bulat nol atau lebih dari environment variable.
+func parseCountEnv(key string, fallback int) (int, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+// parseDurationEnv membaca durasi seperti "15m" dari environment variable.
+func parseDurationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type loginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	// Code adalah kode TOTP atau backup code, wajib jika 2FA aktif.
+	Code string `json:"code"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type registerRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AuthHandler berisi HTTP handler untuk /auth.
+type AuthHandler struct {
+	Users   UserService
+	Tokens  *TokenService
+	Refresh RefreshTokenService
+	Resets  PasswordResetService
+	Mailer  Mailer
+	// ResetURL adalah halaman frontend untuk reset password; token ditambahkan
+	// sebagai query ?token=. Jika kosong, email hanya berisi token.
+	ResetURL      string
+	Verifications EmailVerificationService
+	Verification  VerificationMode
+	// BaseURL adalah alamat publik API ini, dipakai untuk link GET /auth/verify.
+	BaseURL   string
+	TwoFactor TwoFactorService
+	// Issuer adalah nama aplikasi yang tampil di authenticator app.
+	Issuer    string
+	OAuth     OAuthService
+	Providers map[string]*OAuthProvider
+	Mode      AuthMode
+	Sessions  SessionService
+	Avatars   FileStore
+	Events    AuthEventService
+	Passwords PasswordPolicy
+	Logins    *LoginGuard
+}
+
+// Register membuat akun baru dengan password yang di-hash memakai bcrypt.
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req registerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if err := h.Passwords.Validate(req.Password, req.Email); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	user := User{Name: strings.TrimSpace(req.Name), Email: req.Email, PasswordHash: hash}
+	err = h.Users.CreateUser(c.Request.Context(), &user)
+	if errors.Is(err, ErrEmailTaken) {
+		problem(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := h.startVerification(c.Request.Context(), &user); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// VerifyEmail menandai email user terverifikasi memakai token dari email pendaftaran.
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		problem(c, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	user, err := h.Verifications.VerifyEmail(c.Request.Context(), token)
+	if errors.Is(err, ErrVerificationTokenInvalid) {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ResendVerification mengirim ulang email verifikasi. Seperti ForgotPassword,
+// responsnya selalu 202 apa pun status email tersebut.
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req resendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	user, err := h.Users.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		internalError(c, err)
+		return
+	}
+	if user != nil && !user.EmailVerified() {
+		if err := h.startVerification(c.Request.Context(), user); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered and not yet verified, a verification link has been sent"})
+}
+
+// Login menukar email dan password dengan access token. Email yang tidak
+// terdaftar dan password yang salah menghasilkan error yang sama.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if wait := h.Logins.RetryAfter(req.Email, c.ClientIP(), time.Now()); wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		problem(c, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	}
+
+	user, err := h.Users.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		internalError(c, err)
+		return
+	}
+	if !checkPassword(user, req.Password) {
+		h.Logins.Fail(req.Email, c.ClientIP(), time.Now())
+		if user != nil && !h.recordEvent(c, user.ID, AuthEventLoginFailed) {
+			return
+		}
+		problem(c, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+	if !h.allowLogin(c, user) {
+		return
+	}
+	if !h.checkSecondFactor(c, user, req.Code) {
+		return
+	}
+
+	h.Logins.Succeed(req.Email)
+	h.completeLogin(c, user)
+}
+
+// completeLogin memberikan kredensial sesuai AuthMode: cookie session, atau
+// access token dan refresh token.
+func (h *AuthHandler) completeLogin(c *gin.Context, user *User) {
+	if !h.recordEvent(c, user.ID, AuthEventLogin) {
+		return
+	}
+	if h.Mode == AuthSession {
+		h.startSession(c, user)
+		return
+	}
+
+	refresh, stored, err := h.Refresh.IssueRefreshToken(c.Request.Context(), user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	h.respondWithTokens(c, user, refresh, stored)
+}
+
+// startSession membuat session di server dan mengirim id-nya sebagai cookie
+// HttpOnly sehingga tidak bisa dibaca JavaScript.
+func (h *AuthHandler) startSession(c *gin.Context, user *User) {
+	token, session, err := h.Sessions.CreateSession(c.Request.Context(), user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, token, int(time.Until(session.ExpiresAt).Seconds()), "/", "", h.secureCookies(), true)
+	c.JSON(http.StatusOK, gin.H{
+		"expires_at": session.ExpiresAt,
+		"csrf_token": csrfToken(token),
+		"user":       user,
+	})
+}
+
+// CSRFToken mengembalikan CSRF token session yang sedang dipakai, misalnya
+// setelah web UI dimuat ulang dan token dari respons login sudah hilang.
+func (h *AuthHandler) CSRFToken(c *gin.Context) {
+	session := sessionTokenFromContext(c.Request.Context())
+	if session == "" {
+		problem(c, http.StatusBadRequest, "csrf token is only used with session cookies")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"csrf_token": csrfToken(session)})
+}
+
+func (h *AuthHandler) clearSessionCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, "", -1, "/", "", h.secureCookies(), true)
+}
+
+// secureCookies bernilai true jika API diakses lewat HTTPS, sehingga cookie hanya dikirim lewat HTTPS.
+func (h *AuthHandler) secureCookies() bool {
+	return strings.HasPrefix(h.BaseURL, "https://")
+}
+
+// RefreshTokens menukar refresh token dengan access token baru. Refresh token
+// lama langsung tidak berlaku dan diganti token baru (rotasi).
+func (h *AuthHandler) RefreshTokens(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	refresh, stored, err := h.Refresh.RotateRefreshToken(c.Request.Context(), req.RefreshToken)
+	if errors.Is(err, ErrRefreshTokenInvalid) || errors.Is(err, ErrRefreshTokenReused) {
+		problem(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	user, err := h.Users.GetUser(c.Request.Context(), stored.UserID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !h.allowLogin(c, user) {
+		return
+	}
+	h.respondWithTokens(c, user, refresh, stored)
+}
+
+// Logout mencabut refresh token dari body dan/atau session dari cookie.
+// Access token yang sudah diterbitkan tetap berlaku sampai kedaluwarsa (paling
+// lama JWT_ACCESS_TTL); gunakan LogoutAll untuk mencabutnya juga.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	cookie, err := c.Cookie(sessionCookie)
+	if err == nil && cookie != "" && !checkCSRF(c, cookie) {
+		return
+	}
+	var req logoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err := h.Refresh.RevokeRefreshToken(c.Request.Context(), req.RefreshToken); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+	if cookie != "" {
+		if err := h.Sessions.DeleteSession(c.Request.Context(), cookie); err != nil {
+			internalError(c, err)
+			return
+		}
+		h.clearSessionCookie(c)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll mencabut semua access token, refresh token, dan session user di
+// semua perangkat, misalnya jika token dicuri.
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.Users.RevokeCredentials(c.Request.Context(), currentUser(c).ID); err != nil {
+		internalError(c, err)
+		return
+	}
+	h.clearSessionCookie(c)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword mengirim token reset password ke email user. Responsnya selalu
+// 202 supaya endpoint ini tidak bisa dipakai untuk mengecek email yang terdaftar.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	user, err := h.Users.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		internalError(c, err)
+		return
+	}
+	if user != nil {
+		token, reset, err := h.Resets.CreateResetToken(c.Request.Context(), user.ID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		h.sendResetEmail(user, token, reset.ExpiresAt)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered, a reset link has been sent"})
+}
+
+// ResetPassword mengganti password memakai token dari ForgotPassword.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	user, err := h.Resets.GetResetTokenUser(c.Request.Context(), req.Token)
+	if errors.Is(err, ErrResetTokenInvalid) {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := h.Passwords.Validate(req.Password, user.Email); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	err = h.Resets.ResetPassword(c.Request.Context(), req.Token, hash)
+	if errors.Is(err, ErrResetTokenInvalid) {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) sendResetEmail(user *User, token string, expiresAt time.Time) {
+	link := token
+	if h.ResetURL != "" {
+		link = h.ResetURL + "?token=" + url.QueryEscape(token)
+	}
+	h.sendEmail(Email{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use the link below to reset your password. It expires at %s and can only be used once.\n\n%s\n\nIf you did not request this, you can ignore this email.",
+			expiresAt.UTC().Format(time.RFC1123), link),
+	})
+}
+
+// startVerification membuat token verifikasi baru dan mengirimkannya ke email user.
+func (h *AuthHandler) startVerification(ctx context.Context, user *User) error {
+	token, verification, err := h.Verifications.CreateVerificationToken(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimSuffix(h.BaseURL, "/") + apiV1Prefix + "/auth/verify?token=" + url.QueryEscape(token)
+	h.sendEmail(Email{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Open the link below to verify your email address. It expires at %s.\n\n%s",
+			verification.ExpiresAt.UTC().Format(time.RFC1123), link),
+	})
+	return nil
+}
+
+// sendEmail mengirim email di background supaya lama respons tidak bergantung
+// pada server SMTP (dan tidak membocorkan apakah email terdaftar).
+func (h *AuthHandler) sendEmail(email Email) {
+	sendInBackground(h.Mailer, email)
+}
+
+// recordEvent mencatat kejadian ke audit log; false berarti respons error
+// sudah dikirim.
+func (h *AuthHandler) recordEvent(c *gin.Context, userID uint, eventType AuthEventType) bool {
+	if err := h.Events.RecordAuthEvent(c.Request.Context(), userID, eventType); err != nil {
+		internalError(c, err)
+		return false
+	}
+	return true
+}
+
+// allowLogin menolak login dan refresh untuk akun yang dinonaktifkan, dan
+// untuk email yang belum terverifikasi saat mode VerifyLogin.
+func (h *AuthHandler) allowLogin(c *gin.Context, user *User) bool {
+	if user.Disabled() {
+		problem(c, http.StatusForbidden, ErrUserDisabled.Error())
+		return false
+	}
+	if h.Verification == VerifyLogin && !user.EmailVerified() {
+		problem(c, http.StatusForbidden, ErrEmailNotVerified.Error())
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) respondWithTokens(c *gin.Context, user *User, refresh string, stored *RefreshToken) {
+	token, expiresAt, err := h.Tokens.IssueAccessToken(user, time.Now())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":       token,
+		"token_type":         "Bearer",
+		"expires_at":         expiresAt,
+		"refresh_token":      refresh,
+		"refresh_expires_at": stored.ExpiresAt,
+		"user":               user,
+	})
+}
+
+// dummyPasswordHash dipakai saat user tidak ditemukan supaya waktu respons
+// login tidak membocorkan email mana yang terdaftar.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// checkPassword bernilai true jika user ada dan password cocok dengan hash-nya.
+func checkPassword(user *User, password string) bool {
+	if user == nil {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
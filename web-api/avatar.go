@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	maxAvatarBytes = 2 << 20
+	// maxAvatarPixels membatasi ukuran gambar sebelum di-decode supaya file
+	// kecil dengan dimensi sangat besar tidak menghabiskan memori.
+	maxAvatarPixels = 4096 * 4096
+	avatarSize      = 256
+)
+
+var ErrInvalidAvatar = errors.New("avatar must be a JPEG, PNG, GIF or WebP image")
+
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// processAvatar memvalidasi gambar, memotongnya menjadi persegi di tengah,
+// lalu mengecilkannya menjadi avatarSize x avatarSize dalam format PNG.
+func processAvatar(data []byte) ([]byte, error) {
+	if !avatarContentTypes[http.DetectContentType(data)] {
+		return nil, ErrInvalidAvatar
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidAvatar
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxAvatarPixels {
+		return nil, fmt.Errorf("avatar must be at most %d pixels", maxAvatarPixels)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidAvatar
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, avatarSize, avatarSize))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, xdraw.Src, nil)
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, dst); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCalDAVObjectNotFound = errors.New("calendar object not found")
+	ErrCalDAVNoTodo         = errors.New("calendar object must contain exactly one VTODO")
+)
+
+// CalDAVResource menyimpan nama resource dan UID yang dipilih client CalDAV
+// untuk task yang dibuat lewat PUT. Task lain memakai nama task-<id>.ics dan
+// UID bawaan icsTaskUID.
+type CalDAVResource struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_caldav_resources_user_name,priority:1"`
+	User   *User  `gorm:"constraint:OnDelete:CASCADE"`
+	TaskID uint   `gorm:"not null;uniqueIndex"`
+	Task   *Task  `gorm:"constraint:OnDelete:CASCADE"`
+	Name   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_caldav_resources_user_name,priority:2"`
+	UID    string `gorm:"type:varchar(255);not null"`
+}
+
+// CalDAVObject adalah satu task sebagai resource .ics di koleksi CalDAV.
+type CalDAVObject struct {
+	Name string
+	UID  string
+	Task Task
+}
+
+// ETag berubah setiap kali task disimpan lewat UpdateTask, yaitu setiap kali
+// field yang ditulis ke VTODO berubah.
+func (o *CalDAVObject) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, o.Task.ID, o.Task.Version)
+}
+
+// Interface untuk layanan CalDAV
+type CalDAVService interface {
+	ListObjects(ctx context.Context) ([]CalDAVObject, error)
+	GetObject(ctx context.Context, name string) (*CalDAVObject, error)
+	CreateObject(ctx context.Context, name string, todo *ICSTodo) (*CalDAVObject, error)
+	UpdateObject(ctx context.Context, object *CalDAVObject, todo *ICSTodo) error
+	DeleteObject(ctx context.Context, object *CalDAVObject) error
+}
+
+// Struct implementasi CalDAVService dengan GORM. Koleksi CalDAV berisi task
+// pribadi user yang tidak diarsipkan; perubahan lewat Tasks supaya riwayat,
+// undo, dan webhook tetap tercatat. Host dipakai untuk UID bawaan.
+type CalDAVServiceImpl struct {
+	DB    *gorm.DB
+	Tasks TaskService
+	Host  string
+}
+
+func (s *CalDAVServiceImpl) ListObjects(ctx context.Context) ([]CalDAVObject, error) {
+	db := s.DB.WithContext(ctx)
+	var tasks []Task
+	err := TaskFilter{}.apply(db.Scopes(ownedByWorkspace)).Order("id").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	var resources []CalDAVResource
+	if err := db.Scopes(ownedByUser).Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	byTask := make(map[uint]CalDAVResource, len(resources))
+	for _, resource := range resources {
+		byTask[resource.TaskID] = resource
+	}
+
+	objects := make([]CalDAVObject, len(tasks))
+	for i, task := range tasks {
+		objects[i] = s.object(task, byTask[task.ID])
+	}
+	return objects, nil
+}
+
+// GetObject mencari task dari nama resource: nama yang disimpan saat client
+// membuatnya, atau task-<id>.ics untuk task lain.
+func (s *CalDAVServiceImpl) GetObject(ctx context.Context, name string) (*CalDAVObject, error) {
+	db := s.DB.WithContext(ctx)
+	var resource CalDAVResource
+	err := db.Scopes(ownedByUser).Where("name = ?", name).First(&resource).Error
+	taskID := resource.TaskID
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		id, ok := strings.CutPrefix(strings.TrimSuffix(name, ".ics"), "task-")
+		parsed, parseErr := strconv.ParseUint(id, 10, 64)
+		if !ok || !strings.HasSuffix(name, ".ics") || parseErr != nil {
+			return nil, ErrCalDAVObjectNotFound
+		}
+		// Task yang dibuat client hanya bisa dibuka lewat nama pilihannya.
+		var count int64
+		if err := db.Model(&CalDAVResource{}).Where("task_id = ?", parsed).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrCalDAVObjectNotFound
+		}
+		taskID = uint(parsed)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	err = TaskFilter{}.apply(db.Scopes(ownedByWorkspace)).First(&task, taskID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCalDAVObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	object := s.object(task, resource)
+	return &object, nil
+}
+
+// CreateObject membuat task pribadi dari VTODO dan menyimpan nama serta UID
+// yang dipilih client.
+func (s *CalDAVServiceImpl) CreateObject(ctx context.Context, name string, todo *ICSTodo) (*CalDAVObject, error) {
+	task := Task{Status: StatusTodo, Version: 1}
+	if err := todo.apply(&task); err != nil {
+		return nil, err
+	}
+	if err := s.Tasks.CreateTask(ctx, &task); err != nil {
+		return nil, err
+	}
+	resource := CalDAVResource{UserID: ownerID(ctx), TaskID: task.ID, Name: name, UID: todo.UID}
+	if err := s.DB.WithContext(ctx).Create(&resource).Error; err != nil {
+		// Tanpa resource, task tidak bisa dibuka client dengan nama yang ia kirim.
+		if deleteErr := s.Tasks.DeleteTask(ctx, task.ID); deleteErr != nil {
+			return nil, errors.Join(err, deleteErr)
+		}
+		return nil, err
+	}
+	object := s.object(task, resource)
+	return &object, nil
+}
+
+// UpdateObject menyimpan VTODO ke task lewat UpdateTask. Jika task sudah
+// diubah request lain sejak dimuat, ErrTaskVersionConflict dikembalikan.
+func (s *CalDAVServiceImpl) UpdateObject(ctx context.Context, object *CalDAVObject, todo *ICSTodo) error {
+	if err := todo.apply(&object.Task); err != nil {
+		return err
+	}
+	return s.Tasks.UpdateTask(ctx, &object.Task)
+}
+
+// DeleteObject memindahkan task ke trash, sama seperti DELETE /tasks/:id.
+func (s *CalDAVServiceImpl) DeleteObject(ctx context.Context, object *CalDAVObject) error {
+	err := s.Tasks.DeleteTask(ctx, object.Task.ID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return ErrCalDAVObjectNotFound
+	}
+	return err
+}
+
+func (s *CalDAVServiceImpl) object(task Task, resource CalDAVResource) CalDAVObject {
+	if resource.ID == 0 {
+		return CalDAVObject{Name: fmt.Sprintf("task-%d.ics", task.ID), UID: icsTaskUID(task.ID, s.Host), Task: task}
+	}
+	return CalDAVObject{Name: resource.Name, UID: resource.UID, Task: task}
+}
+
+// caldavCTag berubah setiap kali isi koleksi berubah: task baru, diubah,
+// dihapus, atau diarsipkan. Client memakainya untuk tahu apakah perlu
+// mengambil ulang daftar resource.
+func caldavCTag(objects []CalDAVObject) string {
+	hash := sha256.New()
+	for _, object := range objects {
+		fmt.Fprintf(hash, "%s %s\n", object.Name, object.ETag())
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// renderCalDAVObject menulis satu task sebagai dokumen iCalendar dengan satu VTODO.
+func renderCalDAVObject(object *CalDAVObject, now time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//todo-list//tasks//EN")
+	writeICSTodo(&b, &object.Task, object.UID, now)
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// ICSTodo adalah field VTODO yang disimpan ke task. Nilai lain di VTODO
+// (alarm, kategori, RRULE, dan seterusnya) diabaikan.
+type ICSTodo struct {
+	UID         string
+	Summary     string
+	Description string
+	Due         *time.Time
+	Status      TaskStatus
+	Priority    Priority
+	Completed   *time.Time
+}
+
+// parseICSTodo membaca dokumen iCalendar berisi satu VTODO. DUE tanpa zona
+// (floating) dibaca di location, dan DUE berupa tanggal saja menjadi pukul
+// 23:59 di location seperti import.
+func parseICSTodo(data []byte, location *time.Location) (*ICSTodo, error) {
+	var (
+		todo   *ICSTodo
+		count  int
+		inTodo bool
+		depth  int
+	)
+	for _, line := range unfoldICS(data) {
+		name, params, value := parseICSLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO") && !inTodo:
+			inTodo, depth = true, 0
+			todo = &ICSTodo{Status: StatusTodo, Priority: PriorityMedium}
+			count++
+			continue
+		case !inTodo:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			inTodo = false
+			continue
+		case depth > 0:
+			// Properti komponen di dalam VTODO, misalnya VALARM.
+			continue
+		}
+
+		var err error
+		switch name {
+		case "UID":
+			todo.UID = icsUnescape(value)
+		case "SUMMARY":
+			todo.Summary = strings.TrimSpace(icsUnescape(value))
+		case "DESCRIPTION":
+			todo.Description = icsUnescape(value)
+		case "DUE":
+			todo.Due, err = parseICSTime(value, params, location)
+		case "COMPLETED":
+			todo.Completed, err = parseICSTime(value, params, location)
+		case "STATUS":
+			todo.Status = parseICSTodoStatus(value)
+		case "PRIORITY":
+			todo.Priority = parseICSPriority(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+	}
+	if count != 1 || inTodo {
+		return nil, ErrCalDAVNoTodo
+	}
+	if todo.Summary == "" {
+		return nil, errors.New("VTODO must have a SUMMARY")
+	}
+	if todo.UID == "" {
+		return nil, errors.New("VTODO must have a UID")
+	}
+	// Beberapa client hanya menulis COMPLETED tanpa STATUS.
+	if todo.Completed != nil && todo.Status == StatusTodo {
+		todo.Status = StatusDone
+	}
+	return todo, nil
+}
+
+// apply menyalin VTODO ke task. Status yang tidak bisa dicapai langsung (misalnya
+// cancelled ke done) dilewatkan todo dulu. VTODO tanpa DUE tidak menghapus
+// due_at task berulang karena task berulang membutuhkannya.
+func (t *ICSTodo) apply(task *Task) error {
+	task.Title = t.Summary
+	task.Description = t.Description
+	task.Priority = t.Priority
+	if t.Due != nil || task.Recurrence == "" {
+		task.DueAt = t.Due
+	}
+	if !task.Status.CanTransitionTo(t.Status) {
+		if err := task.SetStatus(StatusTodo); err != nil {
+			return err
+		}
+	}
+	if err := task.SetStatus(t.Status); err != nil {
+		return err
+	}
+	if task.Status == StatusDone && t.Completed != nil {
+		task.CompletedAt = t.Completed
+	}
+	return task.Validate()
+}
+
+// unfoldICS memecah dokumen menjadi content line dan menyambung baris lanjutan
+// (diawali spasi atau tab) seperti RFC 5545 bagian 3.1.
+func unfoldICS(data []byte) []string {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseICSLine memecah "NAME;PARAM=VALUE:value". Tanda titik dua di dalam
+// parameter yang dikutip tidak dianggap pemisah.
+func parseICSLine(line string) (string, map[string]string, string) {
+	quoted := false
+	split := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			split = i
+			break
+		}
+	}
+	if split < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	parts := strings.Split(line[:split], ";")
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[split+1:]
+}
+
+func icsUnescape(text string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(text)
+}
+
+// parseICSTime membaca DATE-TIME UTC, DATE-TIME dengan TZID, DATE-TIME
+// floating, atau DATE.
+func parseICSTime(value string, params map[string]string, location *time.Location) (*time.Time, error) {
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	var (
+		parsed time.Time
+		err    error
+	)
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		parsed, err = time.Parse(icsTime, value)
+	case len(value) == len("20060102"):
+		parsed, err = time.ParseInLocation("20060102", value, location)
+		parsed = parsed.Add(23*time.Hour + 59*time.Minute)
+	default:
+		parsed, err = time.ParseInLocation("20060102T150405", value, location)
+	}
+	if err != nil {
+		return nil, errors.New("must be an iCalendar DATE or DATE-TIME")
+	}
+	return &parsed, nil
+}
+
+// parseICSTodoStatus kebalikan icsTodoStatus; nilai yang tidak dikenal menjadi todo.
+func parseICSTodoStatus(value string) TaskStatus {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "IN-PROCESS":
+		return StatusInProgress
+	case "COMPLETED":
+		return StatusDone
+	case "CANCELLED":
+		return StatusCancelled
+	}
+	return StatusTodo
+}
+
+// parseICSPriority kebalikan icsPriority: 1-2 urgent, 3-4 high, 6-9 low, dan
+// 0 (tidak ditentukan) atau 5 medium.
+func parseICSPriority(value string) Priority {
+	n, _ := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case n >= 1 && n <= 2:
+		return PriorityUrgent
+	case n >= 3 && n <= 4:
+		return PriorityHigh
+	case n >= 6 && n <= 9:
+		return PriorityLow
+	}
+	return PriorityMedium
+}
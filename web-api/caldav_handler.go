@@ -0,0 +1,506 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// caldavPath adalah awal semua URL CalDAV. Di bawahnya ada principal
+// /principals/me/, calendar home /calendars/, dan satu koleksi VTODO
+// /calendars/tasks/ berisi task pribadi user.
+const (
+	caldavPath           = "/caldav"
+	caldavPrincipalPath  = caldavPath + "/principals/me/"
+	caldavHomePath       = caldavPath + "/calendars/"
+	caldavCollectionPath = caldavHomePath + "tasks/"
+	maxCalDAVBodyBytes   = 1 << 20
+)
+
+// caldavMethods adalah method yang dilayani CalDAVHandler.Serve.
+var caldavMethods = []string{"OPTIONS", "PROPFIND", "PROPPATCH", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+// CalDAVHandler melayani CalDAV (RFC 4791) untuk client seperti Apple
+// Reminders dan Thunderbird. Hanya bagian protokol yang dipakai client
+// tersebut yang didukung: PROPFIND untuk discovery, REPORT calendar-query dan
+// calendar-multiget, serta GET/PUT/DELETE resource .ics.
+type CalDAVHandler struct {
+	Service CalDAVService
+}
+
+// requireCalDAVAuth memakai HTTP Basic karena client CalDAV tidak bisa
+// mengirim bearer token. Username adalah email akun dan password adalah API
+// key; password akun tidak diterima supaya 2FA tidak bisa dilewati. Scope API
+// key dicek per method di Serve.
+func requireCalDAVAuth(keys APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, value, ok := c.Request.BasicAuth()
+		if !ok || value == "" {
+			caldavUnauthorized(c, "missing basic credentials")
+			return
+		}
+		ctx := c.Request.Context()
+		apiKey, err := keys.Authenticate(ctx, value)
+		if errors.Is(err, ErrInvalidAPIKey) || (err == nil && apiKey.User.Email != normalizeEmail(email)) {
+			caldavUnauthorized(c, ErrInvalidAPIKey.Error())
+			return
+		}
+		if err != nil {
+			internalError(c, err)
+			c.Abort()
+			return
+		}
+		if apiKey.User.Disabled() {
+			problem(c, http.StatusForbidden, ErrUserDisabled.Error())
+			return
+		}
+
+		ctx = withScopes(ctx, apiKey.Scopes)
+		ctx = context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+		c.Request = c.Request.WithContext(withUser(ctx, apiKey.User))
+		c.Next()
+	}
+}
+
+func caldavUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Basic realm="caldav", charset="UTF-8"`)
+	problem(c, http.StatusUnauthorized, message)
+}
+
+// WellKnown mengarahkan /.well-known/caldav (RFC 6764) ke root CalDAV.
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, caldavPath+"/")
+}
+
+// Serve membagi request CalDAV berdasarkan method. PUT dan DELETE
+// membutuhkan scope write:tasks, method lain read:tasks.
+func (h *CalDAVHandler) Serve(c *gin.Context) {
+	scope := ScopeReadTasks
+	if c.Request.Method == http.MethodPut || c.Request.Method == http.MethodDelete {
+		scope = ScopeWriteTasks
+	}
+	if !scopeAllowed(c.Request.Context(), scope) {
+		insufficientScope(c, scope)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCalDAVBodyBytes)
+	c.Header("DAV", "1, 3, calendar-access")
+
+	switch c.Request.Method {
+	case http.MethodOptions:
+		c.Header("Allow", strings.Join(caldavMethods, ", "))
+		c.Status(http.StatusOK)
+	case "PROPFIND":
+		h.propfind(c)
+	case "REPORT":
+		h.report(c)
+	case http.MethodGet, http.MethodHead:
+		h.get(c)
+	case http.MethodPut:
+		h.put(c)
+	case http.MethodDelete:
+		h.delete(c)
+	default:
+		// PROPPATCH (misalnya mengganti warna atau nama koleksi) tidak didukung.
+		problem(c, http.StatusForbidden, "collection properties cannot be changed")
+	}
+}
+
+// caldavTarget adalah resource yang ditunjuk URL request.
+type caldavTarget int
+
+const (
+	caldavNone caldavTarget = iota
+	caldavRoot
+	caldavPrincipal
+	caldavHome
+	caldavCollection
+	caldavObject
+)
+
+// target membaca URL request. Untuk resource .ics, nama resource-nya juga
+// dikembalikan.
+func (h *CalDAVHandler) target(c *gin.Context) (caldavTarget, string) {
+	path := strings.TrimSuffix(c.Request.URL.Path, "/") + "/"
+	switch path {
+	case caldavPath + "/":
+		return caldavRoot, ""
+	case caldavPrincipalPath:
+		return caldavPrincipal, ""
+	case caldavHomePath:
+		return caldavHome, ""
+	case caldavCollectionPath:
+		return caldavCollection, ""
+	}
+	name, ok := strings.CutPrefix(strings.TrimSuffix(path, "/"), caldavCollectionPath)
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return caldavNone, ""
+	}
+	return caldavObject, name
+}
+
+func (h *CalDAVHandler) propfind(c *gin.Context) {
+	target, name := h.target(c)
+	depth := c.GetHeader("Depth")
+	children := depth != "0"
+	ms := newMultistatus()
+
+	switch target {
+	case caldavRoot, caldavPrincipal:
+		ms.response(caldavPath+"/", h.principalProps(c, target == caldavPrincipal)...)
+		if target == caldavRoot && children {
+			ms.response(caldavPrincipalPath, h.principalProps(c, true)...)
+		}
+	case caldavHome:
+		ms.response(caldavHomePath, `<D:resourcetype><D:collection/></D:resourcetype>`, caldavHref("D:current-user-principal", caldavPrincipalPath))
+		if children {
+			objects, ok := h.listObjects(c)
+			if !ok {
+				return
+			}
+			ms.response(caldavCollectionPath, h.collectionProps(c, objects)...)
+		}
+	case caldavCollection:
+		objects, ok := h.listObjects(c)
+		if !ok {
+			return
+		}
+		ms.response(caldavCollectionPath, h.collectionProps(c, objects)...)
+		if children {
+			for i := range objects {
+				ms.response(caldavObjectHref(&objects[i]), caldavObjectProps(&objects[i], false)...)
+			}
+		}
+	case caldavObject:
+		object, ok := h.getObject(c, name)
+		if !ok {
+			return
+		}
+		ms.response(caldavObjectHref(object), caldavObjectProps(object, false)...)
+	default:
+		problem(c, http.StatusNotFound, "not found")
+		return
+	}
+	ms.write(c)
+}
+
+// caldavReport adalah bagian body REPORT yang dibaca. Filter calendar-query
+// hanya dicek nama komponennya; filter waktu dan properti diabaikan dan semua
+// task dikembalikan, yang masih benar karena client menyaring ulang hasilnya.
+type caldavReport struct {
+	XMLName xml.Name
+	Prop    struct {
+		CalendarData *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+	} `xml:"DAV: prop"`
+	Hrefs  []string `xml:"DAV: href"`
+	Filter struct {
+		Calendar struct {
+			Components []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+		} `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+func (h *CalDAVHandler) report(c *gin.Context) {
+	if target, _ := h.target(c); target != caldavCollection {
+		problem(c, http.StatusForbidden, "reports are only supported on "+caldavCollectionPath)
+		return
+	}
+	var report caldavReport
+	if err := xml.NewDecoder(c.Request.Body).Decode(&report); err != nil {
+		problem(c, http.StatusBadRequest, "invalid REPORT body")
+		return
+	}
+	withData := report.Prop.CalendarData != nil
+	ms := newMultistatus()
+
+	switch report.XMLName {
+	case xml.Name{Space: "urn:ietf:params:xml:ns:caldav", Local: "calendar-multiget"}:
+		for _, href := range report.Hrefs {
+			name := caldavObjectName(href)
+			object, err := h.Service.GetObject(c.Request.Context(), name)
+			if errors.Is(err, ErrCalDAVObjectNotFound) {
+				ms.missing(href)
+				continue
+			}
+			if err != nil {
+				internalError(c, err)
+				return
+			}
+			ms.response(caldavObjectHref(object), caldavObjectProps(object, withData)...)
+		}
+	case xml.Name{Space: "urn:ietf:params:xml:ns:caldav", Local: "calendar-query"}:
+		for _, component := range report.Filter.Calendar.Components {
+			if !strings.EqualFold(component.Name, "VTODO") {
+				ms.write(c)
+				return
+			}
+		}
+		objects, ok := h.listObjects(c)
+		if !ok {
+			return
+		}
+		for i := range objects {
+			ms.response(caldavObjectHref(&objects[i]), caldavObjectProps(&objects[i], withData)...)
+		}
+	default:
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", []byte(xml.Header+`<D:error xmlns:D="DAV:"><D:supported-report/></D:error>`))
+		return
+	}
+	ms.write(c)
+}
+
+func (h *CalDAVHandler) get(c *gin.Context) {
+	target, name := h.target(c)
+	if target != caldavObject {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		problem(c, http.StatusMethodNotAllowed, "use PROPFIND or REPORT on collections")
+		return
+	}
+	object, ok := h.getObject(c, name)
+	if !ok {
+		return
+	}
+
+	// ETag resource berasal dari versi task, bukan dari isi body seperti etagMiddleware.
+	streamResponse(c)
+	c.Header("ETag", object.ETag())
+	c.Header("Last-Modified", object.Task.UpdatedAt.UTC().Format(http.TimeFormat))
+	if etagMatches(c.GetHeader("If-None-Match"), object.ETag()) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderCalDAVObject(object, time.Now())))
+}
+
+// put membuat task baru jika resource belum ada, atau mengganti isi task.
+// If-Match dan If-None-Match: * dipakai client untuk mencegah menimpa
+// perubahan yang belum ia lihat.
+func (h *CalDAVHandler) put(c *gin.Context) {
+	target, name := h.target(c)
+	if target != caldavObject || !strings.HasSuffix(name, ".ics") || len(name) > 255 {
+		problem(c, http.StatusForbidden, "calendar objects must be created as "+caldavCollectionPath+"<name>.ics")
+		return
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problem(c, http.StatusRequestEntityTooLarge, "calendar object is too large")
+		return
+	}
+	location, err := parseLocation(c)
+	if err != nil {
+		location = time.UTC
+	}
+	todo, err := parseICSTodo(data, location)
+	if errors.Is(err, ErrCalDAVNoTodo) {
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", []byte(xml.Header+`<D:error xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><C:supported-calendar-component/></D:error>`))
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	object, err := h.Service.GetObject(ctx, name)
+	if err != nil && !errors.Is(err, ErrCalDAVObjectNotFound) {
+		internalError(c, err)
+		return
+	}
+	exists := err == nil
+	if ifMatch := c.GetHeader("If-Match"); (ifMatch != "" && (!exists || !etagMatches(ifMatch, object.ETag()))) ||
+		(exists && c.GetHeader("If-None-Match") == "*") {
+		problem(c, http.StatusPreconditionFailed, "calendar object has changed")
+		return
+	}
+
+	status := http.StatusNoContent
+	if exists {
+		err = h.Service.UpdateObject(ctx, object, todo)
+	} else {
+		object, err = h.Service.CreateObject(ctx, name, todo)
+		status = http.StatusCreated
+	}
+	if errors.Is(err, ErrTaskVersionConflict) {
+		problem(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Header("ETag", object.ETag())
+	c.Status(status)
+}
+
+func (h *CalDAVHandler) delete(c *gin.Context) {
+	target, name := h.target(c)
+	if target != caldavObject {
+		problem(c, http.StatusForbidden, "collections cannot be deleted")
+		return
+	}
+	object, ok := h.getObject(c, name)
+	if !ok {
+		return
+	}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && !etagMatches(ifMatch, object.ETag()) {
+		problem(c, http.StatusPreconditionFailed, "calendar object has changed")
+		return
+	}
+	if err := h.Service.DeleteObject(c.Request.Context(), object); err != nil {
+		caldavError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) listObjects(c *gin.Context) ([]CalDAVObject, bool) {
+	objects, err := h.Service.ListObjects(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return objects, true
+}
+
+func (h *CalDAVHandler) getObject(c *gin.Context, name string) (*CalDAVObject, bool) {
+	object, err := h.Service.GetObject(c.Request.Context(), name)
+	if err != nil {
+		caldavError(c, err)
+		return nil, false
+	}
+	return object, true
+}
+
+func (h *CalDAVHandler) principalProps(c *gin.Context, principal bool) []string {
+	user := currentUser(c)
+	name := user.Name
+	if name == "" {
+		name = user.Email
+	}
+	resourceType := `<D:resourcetype><D:collection/></D:resourcetype>`
+	if principal {
+		resourceType = `<D:resourcetype><D:collection/><D:principal/></D:resourcetype>`
+	}
+	return []string{
+		resourceType,
+		caldavProp("D:displayname", name),
+		caldavHref("D:current-user-principal", caldavPrincipalPath),
+		caldavHref("D:principal-URL", caldavPrincipalPath),
+		caldavHref("C:calendar-home-set", caldavHomePath),
+		caldavHref("C:calendar-user-address-set", "mailto:"+user.Email),
+	}
+}
+
+// collectionProps adalah properti koleksi task. Hak tulis hanya diiklankan
+// jika API key-nya punya write:tasks supaya client tidak menawarkan edit
+// yang akan ditolak.
+func (h *CalDAVHandler) collectionProps(c *gin.Context, objects []CalDAVObject) []string {
+	privileges := `<D:privilege><D:read/></D:privilege>`
+	if scopeAllowed(c.Request.Context(), ScopeWriteTasks) {
+		privileges += `<D:privilege><D:write/></D:privilege><D:privilege><D:write-content/></D:privilege>` +
+			`<D:privilege><D:bind/></D:privilege><D:privilege><D:unbind/></D:privilege>`
+	}
+	return []string{
+		`<D:resourcetype><D:collection/><C:calendar/></D:resourcetype>`,
+		caldavProp("D:displayname", "Tasks"),
+		`<C:supported-calendar-component-set><C:comp name="VTODO"/></C:supported-calendar-component-set>`,
+		`<D:supported-report-set>` +
+			`<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>` +
+			`<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>` +
+			`</D:supported-report-set>`,
+		`<D:current-user-privilege-set>` + privileges + `</D:current-user-privilege-set>`,
+		caldavHref("D:owner", caldavPrincipalPath),
+		caldavHref("D:current-user-principal", caldavPrincipalPath),
+		caldavProp("CS:getctag", caldavCTag(objects)),
+	}
+}
+
+func caldavObjectProps(object *CalDAVObject, withData bool) []string {
+	props := []string{
+		`<D:resourcetype/>`,
+		caldavProp("D:getetag", object.ETag()),
+		caldavProp("D:getcontenttype", "text/calendar; charset=utf-8; component=VTODO"),
+		caldavProp("D:getlastmodified", object.Task.UpdatedAt.UTC().Format(http.TimeFormat)),
+	}
+	if withData {
+		props = append(props, caldavProp("C:calendar-data", renderCalDAVObject(object, time.Now())))
+	}
+	return props
+}
+
+func caldavObjectHref(object *CalDAVObject) string {
+	return caldavCollectionPath + url.PathEscape(object.Name)
+}
+
+// caldavObjectName mengambil nama resource dari href di calendar-multiget,
+// yang bisa berupa path atau URL lengkap.
+func caldavObjectName(href string) string {
+	if parsed, err := url.Parse(href); err == nil {
+		href = parsed.Path
+	}
+	name, ok := strings.CutPrefix(href, caldavCollectionPath)
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+func caldavError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCalDAVObjectNotFound):
+		problem(c, http.StatusNotFound, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
+
+func caldavProp(name, value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return "<" + name + ">" + b.String() + "</" + name + ">"
+}
+
+func caldavHref(name, href string) string {
+	return "<" + name + ">" + caldavProp("D:href", href) + "</" + name + ">"
+}
+
+// multistatus membangun body 207 Multi-Status (RFC 4918). Setiap properti
+// sudah berupa potongan XML dengan prefix D:, C:, atau CS:.
+type multistatus struct {
+	b strings.Builder
+}
+
+func newMultistatus() *multistatus {
+	ms := &multistatus{}
+	ms.b.WriteString(xml.Header)
+	ms.b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`)
+	return ms
+}
+
+func (ms *multistatus) response(href string, props ...string) {
+	ms.b.WriteString("<D:response>" + caldavProp("D:href", href) + "<D:propstat><D:prop>")
+	for _, prop := range props {
+		ms.b.WriteString(prop)
+	}
+	ms.b.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>")
+}
+
+func (ms *multistatus) missing(href string) {
+	ms.b.WriteString("<D:response>" + caldavProp("D:href", href) + "<D:status>HTTP/1.1 404 Not Found</D:status></D:response>")
+}
+
+func (ms *multistatus) write(c *gin.Context) {
+	ms.b.WriteString("</D:multistatus>")
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", []byte(ms.b.String()))
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxCalendarTasks membatasi jumlah task di satu feed; yang jatuh tempo paling
+// akhir didahulukan.
+const maxCalendarTasks = 1000
+
+var (
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrInvalidCalendarFeed  = errors.New("calendar feed is invalid or has been revoked")
+)
+
+// CalendarFeed adalah feed iCalendar pribadi satu user. Seperti ShareLink,
+// token hanya ditampilkan saat dibuat; membuat feed baru mengganti token lama.
+type CalendarFeed struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"-" gorm:"not null;uniqueIndex"`
+	User          *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Prefix        string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	TokenHash     string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Interface untuk layanan feed kalender
+type CalendarService interface {
+	GetFeed(ctx context.Context) (*CalendarFeed, error)
+	CreateFeed(ctx context.Context) (*CalendarFeed, string, error)
+	DeleteFeed(ctx context.Context) error
+	OpenFeed(ctx context.Context, token string) (*User, error)
+	ListCalendarTasks(ctx context.Context, userID uint) ([]Task, error)
+}
+
+// Struct implementasi CalendarService dengan GORM
+type CalendarServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *CalendarServiceImpl) GetFeed(ctx context.Context) (*CalendarFeed, error) {
+	var feed CalendarFeed
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// CreateFeed membuat feed untuk user di context, menggantikan feed lamanya.
+func (s *CalendarServiceImpl) CreateFeed(ctx context.Context) (*CalendarFeed, string, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, "", err
+	}
+	feed := CalendarFeed{UserID: ownerID(ctx), Prefix: token[:6], TokenHash: hashToken(token)}
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(ownedByUser).Delete(&CalendarFeed{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&feed).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &feed, token, nil
+}
+
+func (s *CalendarServiceImpl) DeleteFeed(ctx context.Context) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&CalendarFeed{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// OpenFeed mencari pemilik feed dari token dan mencatat waktu diambil
+// terakhir, paling sering sekali per menit. User yang dinonaktifkan ditolak.
+func (s *CalendarServiceImpl) OpenFeed(ctx context.Context, token string) (*User, error) {
+	db := s.DB.WithContext(ctx)
+	var feed CalendarFeed
+	err := db.Preload("User").Where("token_hash = ?", hashToken(token)).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCalendarFeed
+	}
+	if err != nil {
+		return nil, err
+	}
+	if feed.User == nil || feed.User.Disabled() {
+		return nil, ErrInvalidCalendarFeed
+	}
+
+	now := time.Now()
+	err = db.Model(&CalendarFeed{}).
+		Where("id = ? AND (last_fetched_at IS NULL OR last_fetched_at < ?)", feed.ID, now.Add(-time.Minute)).
+		Update("last_fetched_at", now).Error
+	if err != nil {
+		return nil, err
+	}
+	return feed.User, nil
+}
+
+// ListCalendarTasks mengembalikan task pribadi userID dan task workspace yang
+// di-assign kepadanya yang punya due_at, tanpa task yang diarsipkan.
+func (s *CalendarServiceImpl) ListCalendarTasks(ctx context.Context, userID uint) ([]Task, error) {
+	var tasks []Task
+	err := TaskFilter{}.apply(s.DB.WithContext(ctx).Scopes(personalOrAssigned(userID))).
+		Where("due_at IS NOT NULL").
+		Order("due_at DESC, id").
+		Limit(maxCalendarTasks).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// personalOrAssigned membatasi query ke task pribadi userID dan task workspace
+// yang di-assign kepadanya, untuk job yang tidak berjalan atas nama request.
+func personalOrAssigned(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(tasks.workspace_id IS NULL AND tasks.user_id = ?) OR tasks.assignee_id = ?", userID, userID)
+	}
+}
+
+// CalendarComponent menentukan apakah task ditulis sebagai VEVENT (tampil di
+// Google Calendar dan kebanyakan aplikasi kalender) atau VTODO.
+type CalendarComponent string
+
+const (
+	CalendarEvents CalendarComponent = "event"
+	CalendarTodos  CalendarComponent = "todo"
+)
+
+func ParseCalendarComponent(value string) (CalendarComponent, error) {
+	switch component := CalendarComponent(strings.ToLower(strings.TrimSpace(value))); component {
+	case "":
+		return CalendarEvents, nil
+	case CalendarEvents, CalendarTodos:
+		return component, nil
+	}
+	return "", fmt.Errorf("invalid type %q: must be event or todo", value)
+}
+
+// renderCalendar menulis task sebagai dokumen iCalendar (RFC 5545). host
+// dipakai untuk UID supaya tetap sama di setiap pengambilan.
+func renderCalendar(tasks []Task, component CalendarComponent, host string, now time.Time) string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		writeICSLine(&b, fmt.Sprintf(format, args...))
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//todo-list//tasks//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Tasks")
+	for _, task := range tasks {
+		if task.DueAt == nil {
+			continue
+		}
+		if component == CalendarTodos {
+			writeICSTodo(&b, &task, icsTaskUID(task.ID, host), now)
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:%s", icsTaskUID(task.ID, host))
+		line("DTSTAMP:%s", now.UTC().Format(icsTime))
+		line("LAST-MODIFIED:%s", task.UpdatedAt.UTC().Format(icsTime))
+		line("SUMMARY:%s", icsEscape(task.Title))
+		if task.Description != "" {
+			line("DESCRIPTION:%s", icsEscape(task.Description))
+		}
+		line("DTSTART:%s", task.DueAt.UTC().Format(icsTime))
+		line("DURATION:PT30M")
+		if task.Status == StatusCancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// writeICSTodo menulis task sebagai VTODO. DUE hanya ditulis jika task punya due_at.
+func writeICSTodo(b *strings.Builder, task *Task, uid string, now time.Time) {
+	line := func(format string, args ...any) {
+		writeICSLine(b, fmt.Sprintf(format, args...))
+	}
+	line("BEGIN:VTODO")
+	line("UID:%s", icsEscape(uid))
+	line("DTSTAMP:%s", now.UTC().Format(icsTime))
+	line("CREATED:%s", task.CreatedAt.UTC().Format(icsTime))
+	line("LAST-MODIFIED:%s", task.UpdatedAt.UTC().Format(icsTime))
+	line("SUMMARY:%s", icsEscape(task.Title))
+	if task.Description != "" {
+		line("DESCRIPTION:%s", icsEscape(task.Description))
+	}
+	if task.DueAt != nil {
+		line("DUE:%s", task.DueAt.UTC().Format(icsTime))
+	}
+	line("STATUS:%s", icsTodoStatus(task.Status))
+	line("PRIORITY:%d", icsPriority(task.Priority))
+	if task.CompletedAt != nil {
+		line("COMPLETED:%s", task.CompletedAt.UTC().Format(icsTime))
+	}
+	line("END:VTODO")
+}
+
+// icsTaskUID adalah UID bawaan sebuah task, sama di feed kalender dan CalDAV.
+func icsTaskUID(id uint, host string) string {
+	return fmt.Sprintf("task-%d@%s", id, host)
+}
+
+// icsHost mengambil hostname dari APP_BASE_URL untuk UID.
+func icsHost(baseURL string) string {
+	if base, err := url.Parse(baseURL); err == nil && base.Hostname() != "" {
+		return base.Hostname()
+	}
+	return "todolist"
+}
+
+const icsTime = "20060102T150405Z"
+
+// writeICSLine menulis satu content line dengan CRLF dan melipat baris yang
+// lebih dari 75 oktet (termasuk spasi awal baris lanjutan) tanpa memotong
+// karakter UTF-8.
+func writeICSLine(b *strings.Builder, value string) {
+	limit := 75
+	for len(value) > limit {
+		cut := limit
+		for cut > 0 && value[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(value[:cut])
+		b.WriteString("\r\n ")
+		value = value[cut:]
+		limit = 74
+	}
+	b.WriteString(value)
+	b.WriteString("\r\n")
+}
+
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+func icsTodoStatus(status TaskStatus) string {
+	switch status {
+	case StatusInProgress:
+		return "IN-PROCESS"
+	case StatusDone:
+		return "COMPLETED"
+	case StatusCancelled:
+		return "CANCELLED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icsPriority memetakan prioritas ke skala iCalendar 1 (tertinggi) sampai 9.
+func icsPriority(priority Priority) int {
+	switch priority {
+	case PriorityUrgent:
+		return 1
+	case PriorityHigh:
+		return 3
+	case PriorityLow:
+		return 9
+	}
+	return 5
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const calendarPath = "/calendar.ics"
+
+// CalendarHandler berisi HTTP handler untuk /me/calendar dan feed publik
+// /calendar.ics?token=.
+type CalendarHandler struct {
+	Service CalendarService
+	BaseURL string
+}
+
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	feed, err := h.Service.GetFeed(c.Request.Context())
+	if err != nil {
+		calendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// CreateFeed membuat feed baru dan mencabut URL lama. URL lengkapnya hanya ada
+// di respons ini; tambahkan &type=todo untuk VTODO.
+func (h *CalendarHandler) CreateFeed(c *gin.Context) {
+	feed, token, err := h.Service.CreateFeed(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"calendar_feed": feed,
+		"url":           h.BaseURL + calendarPath + "?token=" + url.QueryEscape(token),
+	})
+}
+
+func (h *CalendarHandler) DeleteFeed(c *gin.Context) {
+	if err := h.Service.DeleteFeed(c.Request.Context()); err != nil {
+		calendarError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ShowCalendar menulis task yang punya due_at sebagai iCalendar untuk
+// di-subscribe dari aplikasi kalender, tanpa login.
+func (h *CalendarHandler) ShowCalendar(c *gin.Context) {
+	// Token ada di URL, jadi jangan sampai tersimpan di cache, terindeks, atau
+	// terkirim lewat header Referer.
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("X-Robots-Tag", "noindex")
+
+	component, err := ParseCalendarComponent(c.Query("type"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+	user, err := h.Service.OpenFeed(ctx, c.Query("token"))
+	if err != nil {
+		calendarError(c, err)
+		return
+	}
+	tasks, err := h.Service.ListCalendarTasks(ctx, user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderCalendar(tasks, component, icsHost(h.BaseURL), time.Now())))
+}
+
+func calendarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCalendarFeedNotFound), errors.Is(err, ErrInvalidCalendarFeed):
+		problem(c, http.StatusNotFound, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
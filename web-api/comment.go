@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxCommentLength = 10000
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("only the author can change this comment")
+)
+
+// Comment adalah diskusi di bawah sebuah task. Siapa saja yang bisa mengubah
+// task (pemilik, editor workspace, atau penerima share sebagai editor) bisa
+// berkomentar, tetapi komentar hanya bisa diubah dan dihapus oleh penulisnya.
+// AuthorName dan AuthorEmail diisi dari tabel users saat komentar dimuat.
+type Comment struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	TaskID      uint   `json:"task_id" gorm:"not null;index"`
+	Task        *Task  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UserID      uint   `json:"author_id" gorm:"not null;index"`
+	User        *User  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	AuthorName  string `json:"author_name" gorm:"->;-:migration"`
+	AuthorEmail string `json:"author_email" gorm:"->;-:migration"`
+	Body        string `json:"body" gorm:"type:text;not null"`
+	// BodyHTML hanya diisi jika client meminta ?render=html.
+	BodyHTML *string `json:"body_html,omitempty" gorm:"-"`
+	// Reactions berisi jumlah reaksi per emoji, diisi saat komentar dimuat.
+	Reactions []ReactionCount `json:"reactions" gorm:"-"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CommentPage adalah satu halaman hasil ListComments.
+type CommentPage struct {
+	Comments []Comment
+	Total    int64
+	Next     *Cursor
+}
+
+// Interface untuk layanan komentar
+type CommentService interface {
+	ListComments(ctx context.Context, taskID uint, page Page) (*CommentPage, error)
+	CreateComment(ctx context.Context, comment *Comment) error
+	UpdateComment(ctx context.Context, taskID, id uint, body string) (*Comment, error)
+	DeleteComment(ctx context.Context, taskID, id uint) error
+	AddReaction(ctx context.Context, taskID, commentID uint, emoji string) error
+	RemoveReaction(ctx context.Context, taskID, commentID uint, emoji string) error
+}
+
+// Struct implementasi CommentService dengan GORM
+type CommentServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListComments mengembalikan komentar task dari yang paling lama, supaya
+// diskusi terbaca berurutan. Mode cursor memakai urutan yang sama.
+func (s *CommentServiceImpl) ListComments(ctx context.Context, taskID uint, page Page) (*CommentPage, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return nil, err
+	}
+
+	result := &CommentPage{}
+	if err := db.Model(&Comment{}).Where("task_id = ?", taskID).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	query := withAuthor(db).Where("comments.task_id = ?", taskID)
+	if page.CursorMode {
+		if page.Cursor != nil {
+			query = query.Where("comments.created_at > ? OR (comments.created_at = ? AND comments.id > ?)",
+				page.Cursor.CreatedAt, page.Cursor.CreatedAt, page.Cursor.ID)
+		}
+		query = query.Limit(page.Limit + 1)
+	} else {
+		query = query.Limit(page.Limit).Offset(page.Offset)
+	}
+	if err := query.Order("comments.created_at, comments.id").Find(&result.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := loadReactions(db, result.Comments); err != nil {
+		return nil, err
+	}
+	if page.CursorMode && len(result.Comments) > page.Limit {
+		result.Comments = result.Comments[:page.Limit]
+		last := result.Comments[len(result.Comments)-1]
+		result.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+// CreateComment menambahkan komentar atas nama user di context dan memberi
+// notifikasi ke user yang di-mention dan watcher task.
+func (s *CommentServiceImpl) CreateComment(ctx context.Context, comment *Comment) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, comment.TaskID); err != nil {
+			return err
+		}
+		comment.UserID = ownerID(ctx)
+		comment.Reactions = []ReactionCount{}
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if err := notifyMentions(tx, comment, ""); err != nil {
+			return err
+		}
+		if err := notifyWatchers(tx, comment.TaskID, NotificationComment, &comment.ID); err != nil {
+			return err
+		}
+		return loadAuthor(tx, comment)
+	})
+}
+
+func (s *CommentServiceImpl) UpdateComment(ctx context.Context, taskID, id uint, body string) (*Comment, error) {
+	var comment Comment
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findOwnComment(tx, taskID, id, &comment); err != nil {
+			return err
+		}
+		previous := comment.Body
+		comment.Body = body
+		if err := tx.Save(&comment).Error; err != nil {
+			return err
+		}
+		// Hanya mention baru yang dinotifikasi supaya edit kecil tidak mengirim ulang.
+		if err := notifyMentions(tx, &comment, previous); err != nil {
+			return err
+		}
+		loaded := []Comment{comment}
+		if err := loadReactions(tx, loaded); err != nil {
+			return err
+		}
+		comment.Reactions = loaded[0].Reactions
+		return loadAuthor(tx, &comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (s *CommentServiceImpl) DeleteComment(ctx context.Context, taskID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment Comment
+		if err := findOwnComment(tx, taskID, id, &comment); err != nil {
+			return err
+		}
+		return tx.Delete(&comment).Error
+	})
+}
+
+// findOwnComment memuat komentar di task yang bisa diakses user, lalu
+// memastikan user di context adalah penulisnya.
+func findOwnComment(tx *gorm.DB, taskID, id uint, comment *Comment) error {
+	if err := ensureTaskExists(tx, taskID); err != nil {
+		return err
+	}
+	err := tx.Where("task_id = ?", taskID).First(comment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if comment.UserID != ownerID(tx.Statement.Context) {
+		return ErrNotCommentAuthor
+	}
+	return nil
+}
+
+// findTaskComment memastikan komentar id ada di task yang bisa diakses user.
+func findTaskComment(tx *gorm.DB, taskID, id uint) error {
+	if err := ensureTaskExists(tx, taskID); err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&Comment{}).Where("id = ? AND task_id = ?", id, taskID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// withAuthor menambahkan nama dan email penulis ke query komentar.
+func withAuthor(db *gorm.DB) *gorm.DB {
+	return db.Model(&Comment{}).
+		Select("comments.*, u.name AS author_name, u.email AS author_email").
+		Joins("JOIN users u ON u.id = comments.user_id")
+}
+
+func loadAuthor(tx *gorm.DB, comment *Comment) error {
+	var author User
+	if err := tx.Select("name", "email").First(&author, comment.UserID).Error; err != nil {
+		return err
+	}
+	comment.AuthorName = author.Name
+	comment.AuthorEmail = author.Email
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// CommentHandler berisi HTTP handler untuk /tasks/:id/comments.
+type CommentHandler struct {
+	Service CommentService
+}
+
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	html, ok := bindRender(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.ListComments(c.Request.Context(), taskID, page)
+	if err != nil {
+		commentError(c, err, taskID, 0)
+		return
+	}
+	if html {
+		for i := range result.Comments {
+			renderComment(&result.Comments[i])
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": result.Comments,
+		"meta":     page.Meta(result.Total, result.Next),
+	})
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	html, ok := bindRender(c)
+	if !ok {
+		return
+	}
+	body, ok := bindCommentBody(c)
+	if !ok {
+		return
+	}
+
+	comment := Comment{TaskID: taskID, Body: body}
+	if err := h.Service.CreateComment(c.Request.Context(), &comment); err != nil {
+		commentError(c, err, taskID, 0)
+		return
+	}
+	if html {
+		renderComment(&comment)
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateComment mengganti isi komentar; hanya penulisnya yang bisa.
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	html, ok := bindRender(c)
+	if !ok {
+		return
+	}
+	body, ok := bindCommentBody(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.Service.UpdateComment(c.Request.Context(), taskID, id, body)
+	if err != nil {
+		commentError(c, err, taskID, id)
+		return
+	}
+	if html {
+		renderComment(comment)
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteComment(c.Request.Context(), taskID, id); err != nil {
+		commentError(c, err, taskID, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddReaction memberi reaksi :emoji (emoji atau namanya, misalnya "+1") pada komentar.
+func (h *CommentHandler) AddReaction(c *gin.Context) {
+	h.changeReaction(c, h.Service.AddReaction)
+}
+
+func (h *CommentHandler) RemoveReaction(c *gin.Context) {
+	h.changeReaction(c, h.Service.RemoveReaction)
+}
+
+func (h *CommentHandler) changeReaction(c *gin.Context, change func(ctx context.Context, taskID, commentID uint, emoji string) error) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	emoji, err := ParseReaction(c.Param("emoji"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := change(c.Request.Context(), taskID, id, emoji); err != nil {
+		commentError(c, err, taskID, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func bindCommentBody(c *gin.Context) (string, bool) {
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return "", false
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("body must be between 1 and %d characters", maxCommentLength))
+		return "", false
+	}
+	return body, true
+}
+
+// bindRender membaca ?render=html dan menjawab 400 jika nilainya tidak dikenal.
+func bindRender(c *gin.Context) (bool, bool) {
+	html, err := parseRender(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return false, false
+	}
+	return html, true
+}
+
+func renderComment(comment *Comment) {
+	html := renderMarkdown(comment.Body)
+	comment.BodyHTML = &html
+}
+
+func parseCommentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("commentID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid comment id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func commentError(c *gin.Context, err error, taskID, id uint) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		taskNotFound(c, taskID)
+	case errors.Is(err, ErrCommentNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrNotCommentAuthor):
+		problem(c, http.StatusForbidden, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSRFToken(t *testing.T) {
+	a, b := csrfToken("session-a"), csrfToken("session-b")
+	if a != csrfToken("session-a") {
+		t.Error("csrfToken is not deterministic")
+	}
+	if a == b {
+		t.Error("different sessions share a CSRF token")
+	}
+	if a == hashToken("session-a") {
+		t.Error("CSRF token equals the session token hash")
+	}
+	if len(a) != 64 {
+		t.Errorf("len(csrfToken) = %d, want 64", len(a))
+	}
+}
+
+func TestCheckCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const session = "session-token"
+	valid := csrfToken(session)
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   bool
+	}{
+		{"GET without token", http.MethodGet, "", true},
+		{"HEAD without token", http.MethodHead, "", true},
+		{"OPTIONS without token", http.MethodOptions, "", true},
+		{"POST with token", http.MethodPost, valid, true},
+		{"DELETE with token", http.MethodDelete, valid, true},
+		{"POST without token", http.MethodPost, "", false},
+		{"PUT with wrong token", http.MethodPut, csrfToken("other-session"), false},
+		{"PATCH with session token", http.MethodPatch, session, false},
+		{"DELETE with truncated token", http.MethodDelete, valid[:32], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(tt.method, "/tasks", nil)
+			if tt.token != "" {
+				c.Request.Header.Set(csrfHeader, tt.token)
+			}
+			if got := checkCSRF(c, session); got != tt.want {
+				t.Fatalf("checkCSRF() = %v, want %v", got, tt.want)
+			}
+			if tt.want {
+				if c.IsAborted() {
+					t.Error("allowed request was aborted")
+				}
+				return
+			}
+			if w.Code != http.StatusForbidden || !c.IsAborted() {
+				t.Errorf("rejected request: status %d aborted %v, want 403 aborted", w.Code, c.IsAborted())
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CSVImportMode menentukan apa yang terjadi jika sebagian baris CSV tidak
+// valid: transactional (default) tidak menyimpan apa pun, best_effort
+// menyimpan baris yang valid.
+type CSVImportMode string
+
+const (
+	CSVImportTransactional CSVImportMode = "transactional"
+	CSVImportBestEffort    CSVImportMode = "best_effort"
+)
+
+func ParseCSVImportMode(value string) (CSVImportMode, error) {
+	switch mode := CSVImportMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return CSVImportTransactional, nil
+	case CSVImportTransactional, CSVImportBestEffort:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid mode %q: must be transactional or best_effort", value)
+}
+
+// csvImportFields adalah field task yang bisa diisi dari CSV. Tanpa mapping,
+// kolom yang judulnya sama dengan nama field dipakai, jadi file dari
+// GET /tasks/export.csv bisa diimpor kembali.
+var csvImportFields = []string{"title", "description", "status", "priority", "project", "tags", "due_at", "recurrence"}
+
+// ImportRowError adalah alasan satu baris CSV tidak valid. Row adalah nomor
+// baris di file (header adalah baris 1).
+type ImportRowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+// parseCSVMapping membaca mapping JSON berupa {"<field>": "<judul kolom>"}.
+// Field yang tidak disebut tetap memakai kolom dengan nama yang sama.
+func parseCSVMapping(value string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+		return nil, errors.New(`mapping must be a JSON object like {"title": "Name"}`)
+	}
+	for field := range mapping {
+		if !slices.Contains(csvImportFields, field) {
+			return nil, fmt.Errorf("invalid mapping field %q: must be one of %s", field, strings.Join(csvImportFields, ", "))
+		}
+	}
+	return mapping, nil
+}
+
+// parseTaskCSV membaca CSV task. Setiap baris divalidasi seperti POST /tasks;
+// baris yang tidak valid dikembalikan sebagai ImportRowError dan tidak
+// dimasukkan ke hasil. Kolom project berisi nama project: project yang sudah
+// ada dipakai, yang belum ada dibuat. tags berisi nama tag dipisah koma, dan
+// due_at berupa RFC 3339 atau tanggal (zona location jika tanpa offset).
+func parseTaskCSV(data []byte, mapping map[string]string, location *time.Location) ([]ImportProject, []ImportRowError, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read CSV header: %w", err)
+	}
+	headers := map[string]int{}
+	for i, name := range header {
+		headers[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	columns := map[string]int{}
+	for _, field := range csvImportFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		if i, ok := headers[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = i
+		} else if mapped {
+			return nil, nil, fmt.Errorf("column %q mapped to %s is not in the CSV header", name, field)
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, nil, errors.New(`CSV must have a "title" column, or map one with {"title": "<column>"}`)
+	}
+
+	var (
+		projects []ImportProject
+		errs     []ImportRowError
+		total    int
+	)
+	byName := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			errs = append(errs, ImportRowError{Row: row, Error: parseErr.Err.Error()})
+			continue
+		}
+		if slices.IndexFunc(record, func(value string) bool { return strings.TrimSpace(value) != "" }) < 0 {
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return csvUnescape(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+
+		task, column, err := csvImportTask(field, location)
+		if err != nil {
+			errs = append(errs, ImportRowError{Row: row, Column: column, Error: err.Error()})
+			continue
+		}
+		if total++; total > maxImportTasks {
+			return nil, nil, ErrImportTooLarge
+		}
+		name := field("project")
+		index, ok := byName[strings.ToLower(name)]
+		if !ok {
+			index = len(projects)
+			byName[strings.ToLower(name)] = index
+			projects = append(projects, ImportProject{Name: name, Reuse: true})
+		}
+		projects[index].Tasks = append(projects[index].Tasks, *task)
+	}
+	return projects, errs, nil
+}
+
+// csvImportTask membangun task dari satu baris. Jika tidak valid, kolom yang
+// salah dikembalikan bersama error-nya.
+func csvImportTask(field func(string) string, location *time.Location) (*ImportTask, string, error) {
+	task := &ImportTask{Title: field("title"), Description: field("description")}
+	if task.Title == "" {
+		return nil, "title", errors.New("title must not be empty")
+	}
+	var err error
+	if task.Status, err = ParseTaskStatus(field("status")); err != nil {
+		return nil, "status", err
+	}
+	if task.Priority, err = ParsePriority(field("priority")); err != nil {
+		return nil, "priority", err
+	}
+	if value := field("due_at"); value != "" {
+		dueAt, ok := parseImportDate(value, location)
+		if !ok {
+			return nil, "due_at", fmt.Errorf("invalid due_at %q: use RFC 3339 or YYYY-MM-DD", value)
+		}
+		task.DueAt = &dueAt
+	}
+	for _, name := range strings.Split(field("tags"), ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		tag, err := importTagName(name)
+		if err != nil {
+			return nil, "tags", err
+		}
+		task.Tags = append(task.Tags, tag)
+	}
+	task.Recurrence = normalizeRecurrence(field("recurrence"))
+	validated := Task{Priority: task.Priority, Recurrence: task.Recurrence, DueAt: task.DueAt}
+	if err := validated.Validate(); err != nil {
+		return nil, "recurrence", err
+	}
+	return task, "", nil
+}
+
+// csvUnescape membuang apostrof yang ditambahkan csvSafe saat export.
+func csvUnescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
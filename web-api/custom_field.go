@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxCustomFieldsPerWorkspace = 50
+	maxCustomFieldValueLength   = 1000
+)
+
+var (
+	ErrCustomFieldNotFound  = errors.New("custom field not found")
+	ErrCustomFieldNameTaken = errors.New("custom field name already exists")
+	ErrTooManyCustomFields  = fmt.Errorf("a workspace can have at most %d custom fields", maxCustomFieldsPerWorkspace)
+)
+
+// CustomFieldType menentukan nilai yang boleh disimpan di custom field.
+type CustomFieldType string
+
+const (
+	CustomFieldText   CustomFieldType = "text"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldDate   CustomFieldType = "date"
+	CustomFieldSelect CustomFieldType = "select"
+)
+
+func ParseCustomFieldType(value string) (CustomFieldType, error) {
+	switch t := CustomFieldType(strings.ToLower(strings.TrimSpace(value))); t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSelect:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid type %q: must be one of text, number, date, select", value)
+}
+
+// CustomField adalah kolom tambahan untuk semua task di satu workspace.
+// Options hanya dipakai tipe select dan berisi pilihan yang diizinkan.
+type CustomField struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint            `json:"workspace_id" gorm:"not null;uniqueIndex:idx_custom_fields_workspace_name,priority:1"`
+	Workspace   *Workspace      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name        string          `json:"name" gorm:"type:varchar(64);not null;uniqueIndex:idx_custom_fields_workspace_name,priority:2"`
+	Type        CustomFieldType `json:"type" gorm:"type:varchar(16);not null"`
+	Options     []string        `json:"options" gorm:"serializer:json"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Normalize memvalidasi value untuk tipe field dan mengembalikannya dalam
+// bentuk kanonik: angka desimal tanpa nol berlebih, tanggal YYYY-MM-DD, atau
+// salah satu Options. Bentuk yang sama dipakai saat memfilter.
+func (f *CustomField) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q for number field %q", value, f.Name)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case CustomFieldDate:
+		d, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q for date field %q: must be YYYY-MM-DD", value, f.Name)
+		}
+		return d.Format(time.DateOnly), nil
+	case CustomFieldSelect:
+		if !slices.Contains(f.Options, value) {
+			return "", fmt.Errorf("invalid value %q for select field %q: must be one of %s", value, f.Name, strings.Join(f.Options, ", "))
+		}
+		return value, nil
+	default:
+		if value == "" || len(value) > maxCustomFieldValueLength {
+			return "", fmt.Errorf("value for text field %q must be between 1 and %d characters", f.Name, maxCustomFieldValueLength)
+		}
+		return value, nil
+	}
+}
+
+// CustomFieldValue adalah nilai satu custom field di satu task.
+type CustomFieldValue struct {
+	TaskID  uint         `json:"-" gorm:"primaryKey"`
+	Task    *Task        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	FieldID uint         `json:"field_id" gorm:"primaryKey;index"`
+	Field   *CustomField `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Value   string       `json:"value" gorm:"type:text;not null"`
+}
+
+// Interface untuk layanan custom field
+type CustomFieldService interface {
+	ListFields(ctx context.Context) ([]CustomField, error)
+	CreateField(ctx context.Context, field *CustomField) error
+	UpdateField(ctx context.Context, id uint, name string, options []string) (*CustomField, error)
+	DeleteField(ctx context.Context, id uint) error
+	SetValue(ctx context.Context, taskID, fieldID uint, value string) (*CustomFieldValue, error)
+	DeleteValue(ctx context.Context, taskID, fieldID uint) error
+}
+
+// Struct implementasi CustomFieldService dengan GORM. Semua method bekerja
+// pada workspace di context (dari /workspaces/:id atau header X-Workspace-ID).
+type CustomFieldServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *CustomFieldServiceImpl) ListFields(ctx context.Context) ([]CustomField, error) {
+	var fields []CustomField
+	err := s.DB.WithContext(ctx).Scopes(inCurrentWorkspace).Order("name, id").Find(&fields).Error
+	return fields, err
+}
+
+func (s *CustomFieldServiceImpl) CreateField(ctx context.Context, field *CustomField) error {
+	workspace := workspaceID(ctx)
+	if workspace == nil {
+		return ErrCustomFieldNotFound
+	}
+	field.WorkspaceID = *workspace
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&CustomField{}).Scopes(inCurrentWorkspace).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxCustomFieldsPerWorkspace {
+			return ErrTooManyCustomFields
+		}
+		err := tx.Create(field).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrCustomFieldNameTaken
+		}
+		return err
+	})
+}
+
+// UpdateField mengganti nama dan pilihan field; tipe tidak bisa diubah karena
+// nilai yang sudah tersimpan belum tentu valid untuk tipe lain. Nilai select
+// yang pilihannya dihapus ikut terhapus.
+func (s *CustomFieldServiceImpl) UpdateField(ctx context.Context, id uint, name string, options []string) (*CustomField, error) {
+	var field CustomField
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findCustomField(tx, id, &field); err != nil {
+			return err
+		}
+		field.Name = name
+		if field.Type == CustomFieldSelect {
+			field.Options = options
+		}
+		err := tx.Save(&field).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrCustomFieldNameTaken
+		}
+		if err != nil {
+			return err
+		}
+		if field.Type != CustomFieldSelect {
+			return nil
+		}
+		return tx.Where("field_id = ? AND value NOT IN ?", field.ID, field.Options).Delete(&CustomFieldValue{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// DeleteField menghapus field beserta nilainya di semua task.
+func (s *CustomFieldServiceImpl) DeleteField(ctx context.Context, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var field CustomField
+		if err := findCustomField(tx, id, &field); err != nil {
+			return err
+		}
+		if err := tx.Where("field_id = ?", field.ID).Delete(&CustomFieldValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&field).Error
+	})
+}
+
+// SetValue menyimpan value untuk task di workspace yang sama dengan field.
+func (s *CustomFieldServiceImpl) SetValue(ctx context.Context, taskID, fieldID uint, value string) (*CustomFieldValue, error) {
+	var saved CustomFieldValue
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, taskID); err != nil {
+			return err
+		}
+		var field CustomField
+		if err := findCustomField(tx, fieldID, &field); err != nil {
+			return err
+		}
+		normalized, err := field.Normalize(value)
+		if err != nil {
+			return &CustomFieldValueError{Err: err}
+		}
+		saved = CustomFieldValue{TaskID: taskID, FieldID: field.ID, Value: normalized}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "task_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value"}),
+		}).Create(&saved).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (s *CustomFieldServiceImpl) DeleteValue(ctx context.Context, taskID, fieldID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, taskID); err != nil {
+			return err
+		}
+		var field CustomField
+		if err := findCustomField(tx, fieldID, &field); err != nil {
+			return err
+		}
+		return tx.Where("task_id = ? AND field_id = ?", taskID, field.ID).Delete(&CustomFieldValue{}).Error
+	})
+}
+
+// CustomFieldValueError dikembalikan SetValue jika value tidak cocok dengan tipe field.
+type CustomFieldValueError struct {
+	Err error
+}
+
+func (e *CustomFieldValueError) Error() string { return e.Err.Error() }
+func (e *CustomFieldValueError) Unwrap() error { return e.Err }
+
+// inCurrentWorkspace membatasi query custom field ke workspace di context;
+// tanpa workspace, tidak ada field yang cocok.
+func inCurrentWorkspace(db *gorm.DB) *gorm.DB {
+	workspace := workspaceID(db.Statement.Context)
+	if workspace == nil {
+		return db.Where("1 = 0")
+	}
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: "workspace_id"},
+		Value:  *workspace,
+	})
+}
+
+func findCustomField(tx *gorm.DB, id uint, field *CustomField) error {
+	err := tx.Scopes(inCurrentWorkspace).First(field, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCustomFieldNotFound
+	}
+	return err
+}
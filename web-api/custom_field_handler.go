@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxCustomFieldOptions      = 50
+	maxCustomFieldOptionLength = 100
+)
+
+type customFieldRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Type    string   `json:"type"`
+	Options []string `json:"options"`
+}
+
+// customFieldValueRequest menerima value berupa string atau angka JSON.
+type customFieldValueRequest struct {
+	Value any `json:"value" binding:"required"`
+}
+
+// CustomFieldHandler berisi HTTP handler untuk /workspaces/:id/fields dan
+// /tasks/:id/fields.
+type CustomFieldHandler struct {
+	Service CustomFieldService
+}
+
+func (h *CustomFieldHandler) ListFields(c *gin.Context) {
+	fields, err := h.Service.ListFields(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fields": fields})
+}
+
+func (h *CustomFieldHandler) CreateField(c *gin.Context) {
+	req, ok := bindCustomFieldRequest(c)
+	if !ok {
+		return
+	}
+	fieldType, err := ParseCustomFieldType(req.Type)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fieldType == CustomFieldSelect && len(req.Options) == 0 {
+		problem(c, http.StatusBadRequest, "select fields require options")
+		return
+	}
+	if fieldType != CustomFieldSelect {
+		req.Options = nil
+	}
+
+	field := CustomField{Name: req.Name, Type: fieldType, Options: req.Options}
+	if err := h.Service.CreateField(c.Request.Context(), &field); err != nil {
+		customFieldError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, field)
+}
+
+// UpdateField mengganti nama dan (untuk select) pilihan field. type di body
+// diabaikan karena tipe field tidak bisa diubah.
+func (h *CustomFieldHandler) UpdateField(c *gin.Context) {
+	id, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+	req, ok := bindCustomFieldRequest(c)
+	if !ok {
+		return
+	}
+
+	field, err := h.Service.UpdateField(c.Request.Context(), id, req.Name, req.Options)
+	if err != nil {
+		customFieldError(c, err, id)
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+func (h *CustomFieldHandler) DeleteField(c *gin.Context) {
+	id, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteField(c.Request.Context(), id); err != nil {
+		customFieldError(c, err, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetTaskField mengisi nilai custom field di task workspace yang dipilih
+// lewat header X-Workspace-ID.
+func (h *CustomFieldHandler) SetTaskField(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	fieldID, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+	var req customFieldValueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	var value string
+	switch v := req.Value.(type) {
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		problem(c, http.StatusBadRequest, "value must be a string or a number")
+		return
+	}
+
+	saved, err := h.Service.SetValue(c.Request.Context(), taskID, fieldID, value)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		customFieldError(c, err, fieldID)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *CustomFieldHandler) DeleteTaskField(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	fieldID, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.DeleteValue(c.Request.Context(), taskID, fieldID)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		customFieldError(c, err, fieldID)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func bindCustomFieldRequest(c *gin.Context) (customFieldRequest, bool) {
+	var req customFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 64 {
+		problem(c, http.StatusBadRequest, "name must be between 1 and 64 characters")
+		return req, false
+	}
+	if len(req.Options) > maxCustomFieldOptions {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("a select field can have at most %d options", maxCustomFieldOptions))
+		return req, false
+	}
+	options := make([]string, 0, len(req.Options))
+	for _, option := range req.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || len(option) > maxCustomFieldOptionLength {
+			problem(c, http.StatusBadRequest, fmt.Sprintf("options must be between 1 and %d characters", maxCustomFieldOptionLength))
+			return req, false
+		}
+		if !slices.Contains(options, option) {
+			options = append(options, option)
+		}
+	}
+	req.Options = options
+	return req, true
+}
+
+func parseCustomFieldID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("fieldID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid field id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func customFieldError(c *gin.Context, err error, id uint) {
+	var invalid *CustomFieldValueError
+	switch {
+	case errors.Is(err, ErrCustomFieldNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrCustomFieldNameTaken), errors.Is(err, ErrTooManyCustomFields):
+		problem(c, http.StatusConflict, err.Error())
+	case errors.As(err, &invalid):
+		problem(c, http.StatusBadRequest, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDigestHour = 7
+	digestBatch       = 100
+	// maxDigestItems membatasi jumlah task per bagian digest.
+	maxDigestItems = 20
+)
+
+// DigestScheduler berjalan di background dan mengirim email ringkasan harian
+// ke user yang mengaktifkan preferensi daily_digest: task yang jatuh tempo
+// hari ini, task yang terlambat, dan task yang selesai kemarin. Digest dikirim
+// sekali sehari setelah jam Hour di zona waktu preferensi user.
+type DigestScheduler struct {
+	DB       *gorm.DB
+	Mailer   Mailer
+	Hour     int
+	Interval time.Duration
+}
+
+// Digest adalah isi email ringkasan harian satu user.
+type Digest struct {
+	DueToday  []Task
+	Overdue   []Task
+	Completed []Task
+}
+
+func (d *Digest) Empty() bool {
+	return len(d.DueToday) == 0 && len(d.Overdue) == 0 && len(d.Completed) == 0
+}
+
+// parseDigestHour membaca DIGEST_HOUR (jam lokal 0-23, default 7).
+func parseDigestHour() (int, error) {
+	value := getEnv("DIGEST_HOUR", "")
+	if value == "" {
+		return defaultDigestHour, nil
+	}
+	hour, err := strconv.Atoi(value)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid DIGEST_HOUR %q (want 0-23)", value)
+	}
+	return hour, nil
+}
+
+// Run mengirim digest yang sudah waktunya setiap Interval sampai ctx dibatalkan.
+func (s *DigestScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sendDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("digest scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDue memeriksa semua user yang mengaktifkan digest dan belum dikirimi
+// dalam 20 jam terakhir; apakah jam lokalnya sudah lewat Hour dicek per user.
+func (s *DigestScheduler) sendDue(ctx context.Context) error {
+	db := s.DB.WithContext(ctx)
+	now := time.Now()
+	lastID := uint(0)
+	for {
+		var users []User
+		err := db.Where("pref_daily_digest = ? AND disabled_at IS NULL AND id > ?", true, lastID).
+			Where("digest_sent_at IS NULL OR digest_sent_at < ?", now.Add(-20*time.Hour)).
+			Order("id").
+			Limit(digestBatch).
+			Find(&users).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range users {
+			if err := s.send(ctx, &users[i], now); err != nil {
+				log.Printf("digest scheduler: user %d: %v", users[i].ID, err)
+			}
+		}
+		if len(users) < digestBatch {
+			return nil
+		}
+		lastID = users[len(users)-1].ID
+	}
+}
+
+// send mengirim digest hari ini untuk user jika jam lokalnya sudah lewat Hour
+// dan digest hari ini belum dikirim. Penanda dipasang sebelum email dikirim
+// (hanya jika belum dipasang instance lain) dan dikembalikan jika email gagal.
+func (s *DigestScheduler) send(ctx context.Context, user *User, now time.Time) error {
+	location, err := time.LoadLocation(user.Preferences.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	sendAt := today.Add(time.Duration(s.Hour) * time.Hour)
+	if local.Before(sendAt) || (user.DigestSentAt != nil && !user.DigestSentAt.Before(sendAt)) {
+		return nil
+	}
+
+	db := s.DB.WithContext(ctx)
+	claim := db.Model(&User{}).
+		Where("id = ? AND (digest_sent_at IS NULL OR digest_sent_at < ?)", user.ID, sendAt).
+		UpdateColumn("digest_sent_at", now)
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	digest, err := s.buildDigest(ctx, user.ID, today)
+	if err == nil && digest.Empty() {
+		return nil
+	}
+	if err == nil {
+		err = s.Mailer.Send(ctx, digestEmail(user, digest, local))
+	}
+	if err != nil {
+		if reset := db.Model(&User{}).Where("id = ?", user.ID).UpdateColumn("digest_sent_at", user.DigestSentAt).Error; reset != nil {
+			log.Printf("digest scheduler: user %d: %v", user.ID, reset)
+		}
+		return err
+	}
+	return nil
+}
+
+// buildDigest memuat task pribadi user dan task workspace yang di-assign ke
+// user. today adalah tengah malam hari ini di zona waktu user.
+func (s *DigestScheduler) buildDigest(ctx context.Context, userID uint, today time.Time) (*Digest, error) {
+	db := s.DB.WithContext(ctx)
+	tomorrow := today.AddDate(0, 0, 1).UTC()
+	yesterday := today.AddDate(0, 0, -1).UTC()
+	today = today.UTC()
+	tasks := func() *gorm.DB {
+		return TaskFilter{}.apply(db.Model(&Task{}).Scopes(personalOrAssigned(userID))).Limit(maxDigestItems)
+	}
+	closed := []TaskStatus{StatusDone, StatusCancelled}
+
+	digest := &Digest{}
+	err := tasks().Where("due_at >= ? AND due_at < ? AND status NOT IN ?", today, tomorrow, closed).
+		Order("due_at, id").Find(&digest.DueToday).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tasks().Where("due_at < ? AND status NOT IN ?", today, closed).
+		Order("due_at, id").Find(&digest.Overdue).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tasks().Where("status = ? AND completed_at >= ? AND completed_at < ?", StatusDone, yesterday, today).
+		Order("completed_at, id").Find(&digest.Completed).Error
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// digestEmail menulis digest sebagai teks biasa dengan waktu di zona waktu user.
+func digestEmail(user *User, digest *Digest, local time.Time) Email {
+	var b strings.Builder
+	section := func(title string, tasks []Task, when func(Task) string) {
+		if len(tasks) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", title, len(tasks))
+		for _, task := range tasks {
+			fmt.Fprintf(&b, "- #%d %s%s\n", task.ID, task.Title, when(task))
+		}
+		b.WriteString("\n")
+	}
+	due := func(task Task) string {
+		return ", due " + task.DueAt.In(local.Location()).Format("Mon 02 Jan 15:04")
+	}
+	section("Due today", digest.DueToday, due)
+	section("Overdue", digest.Overdue, due)
+	section("Completed yesterday", digest.Completed, func(Task) string { return "" })
+
+	return Email{
+		To:      user.Email,
+		Subject: "Your tasks for " + local.Format("Monday, 02 January 2006"),
+		Body:    strings.TrimSpace(b.String()),
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const defaultVerificationTTL = 48 * time.Hour
+
+var (
+	ErrVerificationTokenInvalid = errors.New("invalid or expired verification token")
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+)
+
+// VerificationMode menentukan apa yang diblokir sebelum email user terverifikasi.
+type VerificationMode string
+
+const (
+	// VerifyNone tidak memblokir apa pun (default).
+	VerifyNone VerificationMode = "none"
+	// VerifyWrite hanya mengizinkan request baca (GET/HEAD) untuk user yang belum verifikasi.
+	VerifyWrite VerificationMode = "write"
+	// VerifyLogin menolak login dan refresh token untuk user yang belum verifikasi.
+	VerifyLogin VerificationMode = "login"
+)
+
+func parseVerificationMode(value string) (VerificationMode, error) {
+	switch mode := VerificationMode(value); mode {
+	case "":
+		return VerifyNone, nil
+	case VerifyNone, VerifyWrite, VerifyLogin:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid EMAIL_VERIFICATION %q (want none, write or login)", value)
+	}
+}
+
+// EmailVerificationToken disimpan sebagai hash dan hanya bisa dipakai sekali.
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Interface untuk layanan verifikasi email
+type EmailVerificationService interface {
+	CreateVerificationToken(ctx context.Context, userID uint) (string, *EmailVerificationToken, error)
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+}
+
+// Struct implementasi EmailVerificationService dengan GORM
+type EmailVerificationServiceImpl struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+func (s *EmailVerificationServiceImpl) CreateVerificationToken(ctx context.Context, userID uint) (string, *EmailVerificationToken, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := EmailVerificationToken{
+		UserID:    userID,
+		TokenHash: hashToken(value),
+		ExpiresAt: time.Now().Add(s.TTL),
+	}
+	if err := s.DB.WithContext(ctx).Create(&token).Error; err != nil {
+		return "", nil, err
+	}
+	return value, &token, nil
+}
+
+// VerifyEmail memakai token lalu menandai email user sebagai terverifikasi.
+func (s *EmailVerificationServiceImpl) VerifyEmail(ctx context.Context, token string) (*User, error) {
+	var user User
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var verification EmailVerificationToken
+		err := tx.Where("token_hash = ?", hashToken(token)).First(&verification).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVerificationTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		claim := tx.Model(&EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL", verification.ID).
+			Update("used_at", now)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 || now.After(verification.ExpiresAt) {
+			return ErrVerificationTokenInvalid
+		}
+
+		err = tx.Model(&User{}).
+			Where("id = ? AND email_verified_at IS NULL", verification.UserID).
+			Update("email_verified_at", now).Error
+		if err != nil {
+			return err
+		}
+		return tx.First(&user, verification.UserID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// requireVerifiedEmail memblokir request tulis dari user yang belum verifikasi
+// email saat mode VerifyWrite. Harus dipasang setelah requireAuth.
+func requireVerifiedEmail(mode VerificationMode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mode != VerifyWrite || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		if user := currentUser(c); user != nil && !user.EmailVerified() {
+			problem(c, http.StatusForbidden, ErrEmailNotVerified.Error())
+			return
+		}
+		c.Next()
+	}
+}
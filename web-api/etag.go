@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagWriter menahan body respons supaya ETag bisa dihitung sebelum header
+// dikirim, kecuali handler memanggil streamResponse.
+type etagWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// streamResponse mematikan etagMiddleware untuk request ini supaya body
+// langsung dikirim ke client, untuk respons besar yang ditulis bertahap.
+// Harus dipanggil sebelum body ditulis.
+func streamResponse(c *gin.Context) {
+	if writer, ok := c.Writer.(*etagWriter); ok {
+		writer.streaming = true
+	}
+}
+
+// etagMiddleware menambahkan ETag pada respons 200 dari GET dan mengembalikan
+// 304 tanpa body jika If-None-Match cocok, sehingga client yang polling tidak
+// mengunduh ulang data yang sama. ETag dihitung dari isi body, kecuali handler
+// sudah mengisi ETag sendiri (misalnya dari versi resource).
+func etagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &etagWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.streaming {
+			return
+		}
+		if original.Status() != http.StatusOK {
+			original.Write(writer.body.Bytes())
+			return
+		}
+
+		etag := original.Header().Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(writer.body.Bytes())
+			etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			original.Header().Set("ETag", etag)
+		}
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			original.Header().Del("Content-Type")
+			original.Header().Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(writer.body.Bytes())
+	}
+}
+
+// etagMatches membandingkan If-None-Match secara weak seperti RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// exportVersion naik jika struktur dokumen export berubah tanpa kompatibel.
+const exportVersion = 1
+
+// exportTask adalah task di dokumen export beserta komentarnya.
+type exportTask struct {
+	Task
+	Comments []Comment `json:"comments"`
+}
+
+// MarshalJSON menimpa Task.MarshalJSON yang ikut ter-embed supaya comments
+// tetap tertulis. Task di dokumen export tidak memuat _links.
+func (t exportTask) MarshalJSON() ([]byte, error) {
+	type task Task
+	return json.Marshal(struct {
+		task
+		Comments []Comment `json:"comments"`
+	}{task(t.Task), t.Comments})
+}
+
+// Interface untuk layanan export akun
+type ExportService interface {
+	Export(ctx context.Context, w io.Writer, now time.Time) error
+}
+
+// Struct implementasi ExportService dengan GORM
+type ExportServiceImpl struct {
+	DB *gorm.DB
+}
+
+// Export menulis semua data pribadi user di context sebagai satu dokumen JSON:
+// profil, project, tag, dan task (termasuk yang diarsipkan, tanpa trash)
+// beserta subtask, tag, lampiran (metadata saja), custom field, dependency,
+// dan komentarnya. Data dimuat per exportBatch dan langsung ditulis ke w,
+// jadi akun yang besar tidak dimuat sekaligus ke memori.
+func (s *ExportServiceImpl) Export(ctx context.Context, w io.Writer, now time.Time) error {
+	db := s.DB.WithContext(ctx)
+	var user User
+	if err := db.First(&user, ownerID(ctx)).Error; err != nil {
+		return err
+	}
+
+	out := &jsonStream{w: bufio.NewWriter(w)}
+	out.raw(`{"version":`)
+	out.value(exportVersion)
+	out.raw(`,"exported_at":`)
+	out.value(now.UTC())
+	out.raw(`,"user":`)
+	out.value(user)
+
+	out.begin("projects")
+	var projects []Project
+	err := db.Scopes(ownedByWorkspace).FindInBatches(&projects, exportBatch, func(tx *gorm.DB, batch int) error {
+		for i := range projects {
+			out.item(projects[i])
+		}
+		return out.flush()
+	}).Error
+	if err != nil {
+		return err
+	}
+	out.end()
+
+	out.begin("tags")
+	var tags []Tag
+	err = db.Scopes(ownedByUser).FindInBatches(&tags, exportBatch, func(tx *gorm.DB, batch int) error {
+		for i := range tags {
+			out.item(tags[i])
+		}
+		return out.flush()
+	}).Error
+	if err != nil {
+		return err
+	}
+	out.end()
+
+	out.begin("tasks")
+	var tasks []Task
+	err = preloadTaskRelations(db).Scopes(ownedByWorkspace).FindInBatches(&tasks, exportBatch, func(tx *gorm.DB, batch int) error {
+		if err := loadDependencies(db, tasks); err != nil {
+			return err
+		}
+		ids := make([]uint, len(tasks))
+		for i := range tasks {
+			ids[i] = tasks[i].ID
+		}
+		var comments []Comment
+		err := withAuthor(db).Where("comments.task_id IN ?", ids).Order("comments.created_at, comments.id").Find(&comments).Error
+		if err != nil {
+			return err
+		}
+		byTask := map[uint][]Comment{}
+		for _, comment := range comments {
+			byTask[comment.TaskID] = append(byTask[comment.TaskID], comment)
+		}
+		for i := range tasks {
+			task := exportTask{Task: tasks[i], Comments: byTask[tasks[i].ID]}
+			if task.Comments == nil {
+				task.Comments = []Comment{}
+			}
+			out.item(task)
+		}
+		return out.flush()
+	}).Error
+	if err != nil {
+		return err
+	}
+	out.end()
+
+	out.raw("}\n")
+	return out.flush()
+}
+
+// jsonStream menulis dokumen JSON sepotong demi sepotong. Error pertama
+// disimpan dan dikembalikan oleh flush; penulisan setelahnya diabaikan.
+type jsonStream struct {
+	w     *bufio.Writer
+	err   error
+	first bool
+}
+
+func (s *jsonStream) raw(text string) {
+	if s.err == nil {
+		_, s.err = s.w.WriteString(text)
+	}
+}
+
+func (s *jsonStream) value(v any) {
+	if s.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	_, s.err = s.w.Write(data)
+}
+
+// begin membuka array dengan kunci name di object yang sedang ditulis.
+func (s *jsonStream) begin(name string) {
+	s.raw(",")
+	s.value(name)
+	s.raw(":[")
+	s.first = true
+}
+
+func (s *jsonStream) item(v any) {
+	if !s.first {
+		s.raw(",")
+	}
+	s.first = false
+	s.value(v)
+}
+
+func (s *jsonStream) end() {
+	s.raw("]")
+}
+
+func (s *jsonStream) flush() error {
+	if s.err == nil {
+		s.err = s.w.Flush()
+	}
+	return s.err
+}
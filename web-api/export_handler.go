@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler berisi HTTP handler untuk GET /export.
+type ExportHandler struct {
+	Service ExportService
+}
+
+// Export mengunduh backup JSON semua data pribadi user. Body dikirim
+// bertahap tanpa ETag; karena status 200 sudah terkirim, error di tengah
+// export hanya dicatat ke log dan unduhan berakhir dengan JSON yang tidak
+// lengkap.
+func (h *ExportHandler) Export(c *gin.Context) {
+	now := time.Now()
+	streamResponse(c)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="todo-export-`+now.UTC().Format("2006-01-02")+`.json"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := h.Service.Export(c.Request.Context(), c.Writer, now); err != nil {
+		log.Printf("export: user %d: %v", currentUser(c).ID, err)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxFeedEntries adalah jumlah kejadian terbaru di satu feed.
+const maxFeedEntries = 50
+
+// FeedFormat adalah format feed aktivitas: Atom (default) atau RSS 2.0.
+type FeedFormat string
+
+const (
+	FeedAtom FeedFormat = "atom"
+	FeedRSS  FeedFormat = "rss"
+)
+
+func ParseFeedFormat(value string) (FeedFormat, error) {
+	switch format := FeedFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FeedAtom, nil
+	case FeedAtom, FeedRSS:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q: must be atom or rss", value)
+}
+
+// FeedEntry adalah satu kejadian di feed: task dibuat atau diselesaikan.
+type FeedEntry struct {
+	Kind string
+	At   time.Time
+	Task Task
+}
+
+// feedEntries menggabungkan task yang baru dibuat dan yang baru selesai
+// menjadi kejadian, dari yang terbaru, maksimal maxFeedEntries.
+func feedEntries(created, completed []Task) []FeedEntry {
+	entries := make([]FeedEntry, 0, len(created)+len(completed))
+	for _, task := range created {
+		entries = append(entries, FeedEntry{Kind: "created", At: task.CreatedAt, Task: task})
+	}
+	for _, task := range completed {
+		if task.CompletedAt != nil {
+			entries = append(entries, FeedEntry{Kind: "completed", At: *task.CompletedAt, Task: task})
+		}
+	}
+	slices.SortFunc(entries, func(a, b FeedEntry) int {
+		if c := b.At.Compare(a.At); c != 0 {
+			return c
+		}
+		return int(b.Task.ID) - int(a.Task.ID)
+	})
+	if len(entries) > maxFeedEntries {
+		entries = entries[:maxFeedEntries]
+	}
+	return entries
+}
+
+func (e *FeedEntry) Title() string {
+	if e.Kind == "completed" {
+		return "Completed: " + e.Task.Title
+	}
+	return "Created: " + e.Task.Title
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        rssGUID `xml:"guid"`
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// renderFeed menulis kejadian project sebagai XML. selfURL adalah URL feed
+// ini, dan baseURL dipakai untuk link ke task dan ID yang stabil.
+func renderFeed(format FeedFormat, project *Project, entries []FeedEntry, baseURL, selfURL string, now time.Time) ([]byte, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	title := "Project " + project.Name
+	updated := now
+	if len(entries) > 0 {
+		updated = entries[0].At
+	}
+	taskURL := func(task Task) string {
+		return fmt.Sprintf("%s/tasks/%d", baseURL, task.ID)
+	}
+	entryID := func(entry FeedEntry) string {
+		return fmt.Sprintf("%s/projects/%d/feed#task-%d-%s", baseURL, project.ID, entry.Task.ID, entry.Kind)
+	}
+
+	var doc any
+	if format == FeedRSS {
+		feed := rssFeed{Version: "2.0", Channel: rssChannel{
+			Title:         title,
+			Link:          fmt.Sprintf("%s/projects/%d", baseURL, project.ID),
+			Description:   "Recently created and completed tasks in " + project.Name,
+			LastBuildDate: updated.UTC().Format(time.RFC1123Z),
+		}}
+		for _, entry := range entries {
+			feed.Channel.Items = append(feed.Channel.Items, rssItem{
+				GUID:        rssGUID{Value: entryID(entry)},
+				Title:       entry.Title(),
+				Link:        taskURL(entry.Task),
+				Description: entry.Task.Description,
+				PubDate:     entry.At.UTC().Format(time.RFC1123Z),
+			})
+		}
+		doc = feed
+	} else {
+		feed := atomFeed{
+			ID:      fmt.Sprintf("%s/projects/%d/feed", baseURL, project.ID),
+			Title:   title,
+			Updated: updated.UTC().Format(time.RFC3339),
+			Author:  atomAuthor{Name: "todolist"},
+			Links:   []atomLink{{Rel: "self", Href: selfURL}},
+		}
+		for _, entry := range entries {
+			feed.Entries = append(feed.Entries, atomEntry{
+				ID:      entryID(entry),
+				Title:   entry.Title(),
+				Updated: entry.At.UTC().Format(time.RFC3339),
+				Link:    atomLink{Href: taskURL(entry.Task)},
+				Summary: entry.Task.Description,
+			})
+		}
+		doc = feed
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
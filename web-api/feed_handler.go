@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectFeed menampilkan task yang baru dibuat dan baru diselesaikan di
+// project sebagai feed Atom, atau RSS 2.0 dengan ?format=rss. Feed reader dan
+// tool otomasi bisa memakai header X-API-Key dengan scope read:tasks.
+func (h *ProjectHandler) ProjectFeed(c *gin.Context) {
+	format, err := ParseFeedFormat(c.Query("format"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	project, ok := h.loadProject(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	page := Page{Limit: maxFeedEntries}
+	created, err := h.Tasks.ListTasks(ctx, TaskFilter{
+		ProjectID: &project.ID,
+		Sort:      []SortField{{Field: "created_at", Desc: true}},
+	}, page, TaskInclude{})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	completed, err := h.Tasks.ListTasks(ctx, TaskFilter{
+		ProjectID: &project.ID,
+		Statuses:  []TaskStatus{StatusDone},
+		Sort:      []SortField{{Field: "completed_at", Desc: true}},
+	}, page, TaskInclude{})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	selfURL := strings.TrimSuffix(h.BaseURL, "/") + c.Request.URL.RequestURI()
+	body, err := renderFeed(format, project, feedEntries(created.Tasks, completed.Tasks), h.BaseURL, selfURL, time.Now())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	contentType := "application/atom+xml; charset=utf-8"
+	if format == FeedRSS {
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
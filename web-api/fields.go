@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// taskFields adalah nama field JSON Task yang boleh dipilih lewat ?fields=.
+var taskFields = jsonFieldNames(reflect.TypeOf(Task{}))
+
+// fieldSet berisi field yang diminta lewat ?fields=. nil berarti semua field.
+type fieldSet map[string]bool
+
+// taskView menentukan bentuk task di respons: field dari ?fields=, relasi dari
+// ?include=, dan apakah deskripsi dirender menjadi HTML (?render=html).
+type taskView struct {
+	fields  fieldSet
+	include TaskInclude
+	html    bool
+}
+
+func parseTaskView(c *gin.Context) (taskView, error) {
+	fields, err := parseFields(c)
+	if err != nil {
+		return taskView{}, err
+	}
+	include, err := parseInclude(c)
+	if err != nil {
+		return taskView{}, err
+	}
+	html, err := parseRender(c)
+	if err != nil {
+		return taskView{}, err
+	}
+	return taskView{fields: fields, include: include, html: html}, nil
+}
+
+// parseRender membaca ?render=html. Tanpa parameter, teks Markdown dikirim apa adanya.
+func parseRender(c *gin.Context) (bool, error) {
+	switch value := c.Query("render"); value {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid render %q: must be html", value)
+	}
+}
+
+// parseFields membaca ?fields=id,title,due_at. Field id dan _links selalu ikut
+// supaya client tetap bisa mengenali task dan membuka aksinya.
+func parseFields(c *gin.Context) (fieldSet, error) {
+	value := c.Query("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	fields := fieldSet{"id": true, "_links": true}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !taskFields[name] {
+			return nil, fmt.Errorf("invalid fields %q: unknown field %q", value, name)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// task mengembalikan task dengan hanya field yang diminta. Relasi yang
+// diminta lewat ?include= selalu ikut walaupun tidak disebut di ?fields=.
+func (v taskView) task(task *Task) (any, error) {
+	v.render(task)
+	if v.fields == nil && v.include == defaultTaskInclude {
+		return task, nil
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	relations := v.include.relationFields()
+	for name := range all {
+		if included, ok := relations[name]; ok {
+			if !included {
+				delete(all, name)
+			}
+			continue
+		}
+		// description_html ikut jika description dipilih.
+		if name == "description_html" && v.fields["description"] {
+			continue
+		}
+		if v.fields != nil && !v.fields[name] {
+			delete(all, name)
+		}
+	}
+	if _, ok := all["project"]; v.include.Project && !ok {
+		all["project"] = json.RawMessage("null")
+	}
+	return all, nil
+}
+
+func (v taskView) tasks(tasks []Task) (any, error) {
+	if v.fields == nil && v.include == defaultTaskInclude {
+		for i := range tasks {
+			v.render(&tasks[i])
+		}
+		return tasks, nil
+	}
+
+	selected := make([]any, len(tasks))
+	for i := range tasks {
+		var err error
+		if selected[i], err = v.task(&tasks[i]); err != nil {
+			return nil, err
+		}
+	}
+	return selected, nil
+}
+
+// render mengisi DescriptionHTML jika diminta lewat ?render=html.
+func (v taskView) render(task *Task) {
+	if v.html {
+		html := renderMarkdown(task.Description)
+		task.DescriptionHTML = &html
+	}
+}
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+	"gorm.io/gorm"
+)
+
+const (
+	googleTasksAPIURL       = "https://tasks.googleapis.com/tasks/v1"
+	googleTasksScope        = "https://www.googleapis.com/auth/tasks"
+	googleTasksCallbackPath = "/integrations/google-tasks/callback"
+	googleTasksAuthStateTTL = 10 * time.Minute
+)
+
+var (
+	ErrGoogleTasksNotConnected = errors.New("google tasks is not connected")
+	ErrGoogleTasksStateInvalid = errors.New("invalid or expired google tasks authorization state")
+	ErrGoogleTasksSyncRunning  = errors.New("a google tasks sync is already running")
+	ErrGoogleTasksUnavailable  = errors.New("google tasks request failed")
+)
+
+// GoogleTasksConnection menyimpan token OAuth user untuk Google Tasks. Task
+// pribadi user disinkronkan dengan daftar TaskListID.
+type GoogleTasksConnection struct {
+	UserID       uint      `json:"user_id" gorm:"primaryKey"`
+	User         *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	AccessToken  string    `json:"-" gorm:"not null"`
+	RefreshToken string    `json:"-" gorm:"not null;default:''"`
+	TokenExpiry  time.Time `json:"-"`
+	TaskListID   string    `json:"task_list_id" gorm:"type:varchar(255);not null;default:'@default'"`
+	// SyncStartedAt diisi selama sync berjalan supaya dua sync tidak jalan bersamaan.
+	SyncStartedAt *time.Time `json:"-"`
+	LastSyncedAt  *time.Time `json:"last_synced_at"`
+	LastSyncError string     `json:"last_sync_error" gorm:"not null;default:''"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (c *GoogleTasksConnection) token() *oauth2.Token {
+	return &oauth2.Token{AccessToken: c.AccessToken, RefreshToken: c.RefreshToken, Expiry: c.TokenExpiry, TokenType: "Bearer"}
+}
+
+// GoogleTasksAuthState adalah state OAuth yang dibuat POST /me/google-tasks/connect.
+// Callback dari Google tidak membawa login user, jadi state (disimpan sebagai
+// hash) menunjukkan user yang menghubungkan akunnya. Hanya bisa dipakai sekali.
+type GoogleTasksAuthState struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	StateHash string    `gorm:"type:char(64);not null;uniqueIndex"`
+	Verifier  string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// GoogleTaskLink memetakan satu task lokal ke satu task Google. LocalVersion
+// dan RemoteUpdated adalah keadaan kedua sisi saat terakhir disinkronkan;
+// sisi yang berbeda dari itu sudah berubah sejak sync terakhir.
+type GoogleTaskLink struct {
+	ID            uint      `gorm:"primaryKey"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_google_task_links_user_google,priority:1"`
+	User          *User     `gorm:"constraint:OnDelete:CASCADE"`
+	TaskID        uint      `gorm:"not null;uniqueIndex"`
+	GoogleID      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_google_task_links_user_google,priority:2"`
+	LocalVersion  int       `gorm:"not null"`
+	RemoteUpdated time.Time `gorm:"not null"`
+	SyncedAt      time.Time `gorm:"not null"`
+}
+
+// Interface untuk layanan integrasi Google Tasks
+type GoogleTasksService interface {
+	GetConnection(ctx context.Context) (*GoogleTasksConnection, error)
+	AuthURL(ctx context.Context) (string, error)
+	Connect(ctx context.Context, state, code string) (*GoogleTasksConnection, error)
+	Disconnect(ctx context.Context) error
+	Sync(ctx context.Context, now time.Time) (*GoogleTasksSyncResult, error)
+}
+
+// Struct implementasi GoogleTasksService dengan GORM. Tasks dipakai untuk
+// mengubah task lokal supaya riwayat, undo, dan webhook tetap tercatat.
+type GoogleTasksServiceImpl struct {
+	DB     *gorm.DB
+	Tasks  TaskService
+	Config *oauth2.Config
+	APIURL string
+}
+
+// newGoogleTasksConfig memakai client OAuth yang sama dengan login Google
+// (GOOGLE_CLIENT_ID dan GOOGLE_CLIENT_SECRET), atau nil jika belum di-set.
+// Redirect URL-nya <baseURL>/integrations/google-tasks/callback, yang juga harus
+// didaftarkan di Google Cloud Console.
+func newGoogleTasksConfig(baseURL string) *oauth2.Config {
+	id, secret := getEnv("GOOGLE_CLIENT_ID", ""), getEnv("GOOGLE_CLIENT_SECRET", "")
+	if id == "" || secret == "" {
+		return nil
+	}
+	return &oauth2.Config{
+		ClientID:     id,
+		ClientSecret: secret,
+		Endpoint:     endpoints.Google,
+		RedirectURL:  strings.TrimSuffix(baseURL, "/") + googleTasksCallbackPath,
+		Scopes:       []string{googleTasksScope},
+	}
+}
+
+func (s *GoogleTasksServiceImpl) GetConnection(ctx context.Context) (*GoogleTasksConnection, error) {
+	var connection GoogleTasksConnection
+	err := s.DB.WithContext(ctx).Where("user_id = ?", ownerID(ctx)).First(&connection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGoogleTasksNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// AuthURL membuat state baru dan mengembalikan URL halaman izin Google.
+// access_type=offline dan prompt=consent supaya Google selalu memberi refresh
+// token, karena sync berjalan lama setelah access token kedaluwarsa.
+func (s *GoogleTasksServiceImpl) AuthURL(ctx context.Context) (string, error) {
+	value, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	state := GoogleTasksAuthState{
+		UserID:    ownerID(ctx),
+		StateHash: hashToken(value),
+		Verifier:  oauth2.GenerateVerifier(),
+		ExpiresAt: time.Now().Add(googleTasksAuthStateTTL),
+	}
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", state.UserID).Delete(&GoogleTasksAuthState{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&state).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.Config.AuthCodeURL(value, oauth2.AccessTypeOffline, oauth2.ApprovalForce, oauth2.S256ChallengeOption(state.Verifier)), nil
+}
+
+// Connect memakai state dari callback, menukar code dengan token, lalu
+// menyimpan koneksi pemilik state. Koneksi dan mapping lama diganti karena
+// akun Google-nya bisa berbeda.
+func (s *GoogleTasksServiceImpl) Connect(ctx context.Context, state, code string) (*GoogleTasksConnection, error) {
+	db := s.DB.WithContext(ctx)
+	var authState GoogleTasksAuthState
+	err := db.Where("state_hash = ? AND expires_at > ?", hashToken(state), time.Now()).First(&authState).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGoogleTasksStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Delete(&authState).Error; err != nil {
+		return nil, err
+	}
+
+	token, err := s.Config.Exchange(ctx, code, oauth2.VerifierOption(authState.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGoogleTasksUnavailable, err)
+	}
+	connection := GoogleTasksConnection{
+		UserID:       authState.UserID,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenExpiry:  token.Expiry,
+		TaskListID:   "@default",
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&GoogleTasksConnection{}, &GoogleTaskLink{}} {
+			if err := tx.Where("user_id = ?", connection.UserID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&connection).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// Disconnect menghapus token dan mapping. Task lokal dan task di Google
+// tidak diubah.
+func (s *GoogleTasksServiceImpl) Disconnect(ctx context.Context) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", ownerID(ctx)).Delete(&GoogleTasksConnection{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrGoogleTasksNotConnected
+		}
+		return tx.Where("user_id = ?", ownerID(ctx)).Delete(&GoogleTaskLink{}).Error
+	})
+}
+
+// googleTask adalah resource Task di Google Tasks API. Due hanya berisi
+// tanggal; bagian jamnya selalu 00:00 UTC.
+type googleTask struct {
+	ID        string     `json:"id,omitempty"`
+	Title     string     `json:"title"`
+	Notes     string     `json:"notes"`
+	Status    string     `json:"status"`
+	Due       *string    `json:"due"`
+	Completed *time.Time `json:"completed,omitempty"`
+	Updated   time.Time  `json:"updated,omitzero"`
+	Parent    string     `json:"parent,omitempty"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	Hidden    bool       `json:"hidden,omitempty"`
+}
+
+const (
+	googleTaskNeedsAction = "needsAction"
+	googleTaskCompleted   = "completed"
+)
+
+// googleTasksClient memanggil Google Tasks API untuk satu daftar task.
+type googleTasksClient struct {
+	HTTP   *http.Client
+	APIURL string
+	ListID string
+}
+
+func (c *googleTasksClient) tasksURL(id string) string {
+	u := strings.TrimSuffix(c.APIURL, "/") + "/lists/" + url.PathEscape(c.ListID) + "/tasks"
+	if id != "" {
+		u += "/" + url.PathEscape(id)
+	}
+	return u
+}
+
+// list mengembalikan semua task di daftar, termasuk yang selesai,
+// disembunyikan, dan dihapus supaya penghapusan bisa disinkronkan.
+func (c *googleTasksClient) list(ctx context.Context) ([]googleTask, error) {
+	var tasks []googleTask
+	query := url.Values{"showCompleted": {"true"}, "showHidden": {"true"}, "showDeleted": {"true"}, "maxResults": {"100"}}
+	for {
+		var page struct {
+			Items         []googleTask `json:"items"`
+			NextPageToken string       `json:"nextPageToken"`
+		}
+		if err := c.do(ctx, http.MethodGet, c.tasksURL("")+"?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Items...)
+		if page.NextPageToken == "" {
+			return tasks, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+func (c *googleTasksClient) insert(ctx context.Context, task *googleTask) (*googleTask, error) {
+	var created googleTask
+	if err := c.do(ctx, http.MethodPost, c.tasksURL(""), task, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *googleTasksClient) patch(ctx context.Context, id string, task *googleTask) (*googleTask, error) {
+	var updated googleTask
+	if err := c.do(ctx, http.MethodPatch, c.tasksURL(id), task, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *googleTasksClient) delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, c.tasksURL(id), nil, nil)
+}
+
+// do mengirim request JSON. Task yang sudah tidak ada (404) saat dihapus
+// dianggap berhasil.
+func (c *googleTasksClient) do(ctx context.Context, method, url string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGoogleTasksUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s %s: %s", ErrGoogleTasksUnavailable, method, url, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoogleTasksHandler berisi HTTP handler untuk /me/google-tasks dan callback
+// OAuth Google Tasks.
+type GoogleTasksHandler struct {
+	Service GoogleTasksService
+}
+
+func (h *GoogleTasksHandler) GetConnection(c *gin.Context) {
+	connection, err := h.Service.GetConnection(c.Request.Context())
+	if err != nil {
+		googleTasksError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+// Connect mengembalikan URL halaman izin Google. Client membuka URL itu di
+// browser; setelah user setuju, Google mengarahkannya ke Callback.
+func (h *GoogleTasksHandler) Connect(c *gin.Context) {
+	url, err := h.Service.AuthURL(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"url": url, "expires_at": time.Now().Add(googleTasksAuthStateTTL)})
+}
+
+// Callback menerima redirect dari Google. Route ini publik karena browser
+// tidak membawa access token; user ditentukan dari state.
+func (h *GoogleTasksHandler) Callback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		problem(c, http.StatusUnauthorized, "authorization denied: "+reason)
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		problem(c, http.StatusBadRequest, "state and code are required")
+		return
+	}
+
+	connection, err := h.Service.Connect(c.Request.Context(), state, code)
+	if errors.Is(err, ErrGoogleTasksUnavailable) {
+		log.Printf("google tasks: exchange: %v", err)
+		problem(c, http.StatusBadGateway, "could not connect google tasks")
+		return
+	}
+	if err != nil {
+		googleTasksError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+func (h *GoogleTasksHandler) Disconnect(c *gin.Context) {
+	if err := h.Service.Disconnect(c.Request.Context()); err != nil {
+		googleTasksError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Sync menjalankan sync dua arah sekarang dan mengembalikan ringkasannya.
+func (h *GoogleTasksHandler) Sync(c *gin.Context) {
+	result, err := h.Service.Sync(c.Request.Context(), time.Now())
+	if err != nil {
+		googleTasksError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func googleTasksError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrGoogleTasksNotConnected):
+		problem(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrGoogleTasksStateInvalid):
+		problem(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrGoogleTasksSyncRunning):
+		problem(c, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrGoogleTasksUnavailable):
+		log.Printf("google tasks: %v", err)
+		problem(c, http.StatusBadGateway, "google tasks request failed; try again later")
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// googleTasksSyncTimeout adalah batas umur klaim sync_started_at. Sync yang
+// berhenti di tengah jalan (misalnya proses mati) tidak mengunci koneksi
+// lebih lama dari ini.
+const googleTasksSyncTimeout = 10 * time.Minute
+
+// Resolusi konflik di GoogleTasksConflict.
+const (
+	GoogleConflictLocalWins      = "local_wins"
+	GoogleConflictRemoteWins     = "remote_wins"
+	GoogleConflictLocalRestored  = "local_restored"
+	GoogleConflictRemoteRestored = "remote_restored"
+)
+
+// GoogleTasksSyncResult adalah ringkasan satu kali sync. Local* adalah
+// perubahan pada task di aplikasi ini, Remote* perubahan di Google Tasks.
+type GoogleTasksSyncResult struct {
+	LocalCreated  int                   `json:"local_created"`
+	LocalUpdated  int                   `json:"local_updated"`
+	LocalDeleted  int                   `json:"local_deleted"`
+	RemoteCreated int                   `json:"remote_created"`
+	RemoteUpdated int                   `json:"remote_updated"`
+	RemoteDeleted int                   `json:"remote_deleted"`
+	Skipped       int                   `json:"skipped"`
+	Conflicts     []GoogleTasksConflict `json:"conflicts"`
+	SyncedAt      time.Time             `json:"synced_at"`
+}
+
+// GoogleTasksConflict adalah task yang berubah di kedua sisi sejak sync
+// terakhir, atau dihapus di satu sisi dan diubah di sisi lain. Perubahan yang
+// lebih baru menang, dan task yang dihapus dibuat ulang daripada membuang
+// perubahan di sisi lain.
+type GoogleTasksConflict struct {
+	TaskID     uint   `json:"task_id"`
+	GoogleID   string `json:"google_id"`
+	Title      string `json:"title"`
+	Resolution string `json:"resolution"`
+}
+
+// Sync menyinkronkan task pribadi user di context dengan daftar Google
+// Tasks-nya dalam dua arah:
+//
+//   - task yang sudah dipetakan: sisi yang berubah sejak sync terakhir
+//     disalin ke sisi lain; jika keduanya berubah, yang lebih baru menang
+//   - task yang dihapus di satu sisi juga dihapus di sisi lain
+//   - task Google baru yang belum selesai dibuat di sini (subtask Google dilewati)
+//   - task pribadi baru yang belum selesai dan tidak diarsipkan dibuat di Google
+//
+// Hanya judul, deskripsi (notes), status selesai, dan tanggal due yang
+// disinkronkan. Google hanya menyimpan tanggal due, jadi due dari Google
+// menjadi pukul 23:59 di zona user, dan jam lokal dipertahankan selama
+// tanggalnya sama. Mapping disimpan setelah setiap task, jadi sync yang gagal
+// di tengah bisa diulang tanpa menggandakan task.
+func (s *GoogleTasksServiceImpl) Sync(ctx context.Context, now time.Time) (*GoogleTasksSyncResult, error) {
+	userID := ownerID(ctx)
+	db := s.DB.WithContext(ctx)
+	claim := db.Model(&GoogleTasksConnection{}).
+		Where("user_id = ? AND (sync_started_at IS NULL OR sync_started_at < ?)", userID, now.Add(-googleTasksSyncTimeout).UTC()).
+		UpdateColumn("sync_started_at", now.UTC())
+	if claim.Error != nil {
+		return nil, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		if _, err := s.GetConnection(ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrGoogleTasksSyncRunning
+	}
+	connection, err := s.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if user := userFromContext(ctx); user != nil {
+		if loaded, err := time.LoadLocation(user.Preferences.Timezone); err == nil {
+			location = loaded
+		}
+	}
+	source := s.Config.TokenSource(ctx, connection.token())
+	run := &googleTasksSync{
+		db:       db,
+		tasks:    s.Tasks,
+		client:   &googleTasksClient{HTTP: oauth2.NewClient(ctx, source), APIURL: s.APIURL, ListID: connection.TaskListID},
+		userID:   userID,
+		location: location,
+		now:      now,
+		result:   &GoogleTasksSyncResult{Conflicts: []GoogleTasksConflict{}, SyncedAt: now},
+	}
+	syncErr := run.run(ctx)
+	if err := s.finishSync(ctx, connection, source, now, syncErr); err != nil {
+		return nil, err
+	}
+	if syncErr != nil {
+		return nil, syncErr
+	}
+	return run.result, nil
+}
+
+// finishSync melepas klaim, mencatat hasil sync, dan menyimpan token yang
+// mungkin sudah di-refresh selama sync. Dijalankan walaupun request sudah
+// dibatalkan supaya koneksi tidak tetap terkunci.
+func (s *GoogleTasksServiceImpl) finishSync(ctx context.Context, connection *GoogleTasksConnection, source oauth2.TokenSource, now time.Time, syncErr error) error {
+	updates := map[string]any{"sync_started_at": nil, "last_sync_error": ""}
+	if syncErr != nil {
+		updates["last_sync_error"] = syncErr.Error()
+	} else {
+		updates["last_synced_at"] = now
+	}
+	if token, err := source.Token(); err == nil && token.AccessToken != connection.AccessToken {
+		updates["access_token"] = token.AccessToken
+		updates["token_expiry"] = token.Expiry
+		if token.RefreshToken != "" {
+			updates["refresh_token"] = token.RefreshToken
+		}
+	}
+	return s.DB.WithContext(context.WithoutCancel(ctx)).Model(connection).UpdateColumns(updates).Error
+}
+
+// googleTasksSync adalah state satu kali sync.
+type googleTasksSync struct {
+	db       *gorm.DB
+	tasks    TaskService
+	client   *googleTasksClient
+	userID   uint
+	location *time.Location
+	now      time.Time
+	result   *GoogleTasksSyncResult
+}
+
+func (s *googleTasksSync) run(ctx context.Context) error {
+	remoteTasks, err := s.client.list(ctx)
+	if err != nil {
+		return err
+	}
+	remote := make(map[string]*googleTask, len(remoteTasks))
+	for i := range remoteTasks {
+		remote[remoteTasks[i].ID] = &remoteTasks[i]
+	}
+
+	var links []GoogleTaskLink
+	if err := s.db.Where("user_id = ?", s.userID).Order("id").Find(&links).Error; err != nil {
+		return err
+	}
+	ids := make([]uint, len(links))
+	for i, link := range links {
+		ids[i] = link.TaskID
+	}
+	var tasks []Task
+	if len(ids) > 0 {
+		if err := s.db.Scopes(ownedByWorkspace).Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+			return err
+		}
+	}
+	local := make(map[uint]*Task, len(tasks))
+	for i := range tasks {
+		local[tasks[i].ID] = &tasks[i]
+	}
+
+	linked := make(map[string]bool, len(links))
+	for i := range links {
+		linked[links[i].GoogleID] = true
+		if err := s.syncLink(ctx, &links[i], local[links[i].TaskID], remote[links[i].GoogleID]); err != nil {
+			return err
+		}
+	}
+
+	for i := range remoteTasks {
+		r := &remoteTasks[i]
+		if linked[r.ID] || r.Deleted || r.Hidden || r.Status == googleTaskCompleted {
+			continue
+		}
+		if r.Parent != "" || strings.TrimSpace(r.Title) == "" {
+			s.result.Skipped++
+			continue
+		}
+		if err := s.createLocal(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	var unlinked []Task
+	err = s.db.Scopes(ownedByWorkspace).
+		Where("archived_at IS NULL AND status NOT IN ?", []TaskStatus{StatusDone, StatusCancelled}).
+		Where("id NOT IN (?)", s.db.Model(&GoogleTaskLink{}).Select("task_id").Where("user_id = ?", s.userID)).
+		Order("position, id").
+		Find(&unlinked).Error
+	if err != nil {
+		return err
+	}
+	for i := range unlinked {
+		if err := s.createRemote(ctx, &unlinked[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncLink menyinkronkan satu pasangan task. task nil jika task lokal sudah
+// dihapus, r nil jika task Google sudah dihapus.
+func (s *googleTasksSync) syncLink(ctx context.Context, link *GoogleTaskLink, task *Task, r *googleTask) error {
+	if r != nil && r.Deleted {
+		r = nil
+	}
+	localChanged := task != nil && task.Version != link.LocalVersion
+	remoteChanged := r != nil && r.Updated.After(link.RemoteUpdated)
+
+	switch {
+	case task == nil && r == nil:
+		return s.db.Delete(link).Error
+	case task == nil:
+		if err := s.db.Delete(link).Error; err != nil {
+			return err
+		}
+		if remoteChanged {
+			s.conflict(link.TaskID, r.ID, r.Title, GoogleConflictLocalRestored)
+			return s.createLocal(ctx, r)
+		}
+		if err := s.client.delete(ctx, r.ID); err != nil {
+			return err
+		}
+		s.result.RemoteDeleted++
+		return nil
+	case r == nil:
+		if err := s.db.Delete(link).Error; err != nil {
+			return err
+		}
+		if localChanged {
+			s.conflict(task.ID, link.GoogleID, task.Title, GoogleConflictRemoteRestored)
+			return s.createRemote(ctx, task)
+		}
+		if err := s.tasks.DeleteTask(ctx, task.ID); err != nil && !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+		s.result.LocalDeleted++
+		return nil
+	case localChanged && remoteChanged:
+		if r.Updated.After(task.UpdatedAt) {
+			s.conflict(task.ID, r.ID, r.Title, GoogleConflictRemoteWins)
+			return s.pull(ctx, link, task, r)
+		}
+		s.conflict(task.ID, r.ID, task.Title, GoogleConflictLocalWins)
+		return s.push(ctx, link, task, r)
+	case localChanged:
+		return s.push(ctx, link, task, r)
+	case remoteChanged:
+		return s.pull(ctx, link, task, r)
+	}
+	return nil
+}
+
+// push menyalin task lokal ke Google. Perubahan lokal yang tidak
+// disinkronkan (misalnya prioritas) hanya memperbarui mapping.
+func (s *googleTasksSync) push(ctx context.Context, link *GoogleTaskLink, task *Task, r *googleTask) error {
+	want := s.remoteTask(task)
+	if !s.sameTask(want, r) {
+		updated, err := s.client.patch(ctx, r.ID, want)
+		if err != nil {
+			return err
+		}
+		r = updated
+		s.result.RemoteUpdated++
+	}
+	return s.saveLink(link, task, r)
+}
+
+// pull menyalin task Google ke task lokal lewat TaskService.UpdateTask. Jika
+// task lokal diubah request lain selama sync, task itu dilewati dan
+// disinkronkan lagi pada sync berikutnya.
+func (s *googleTasksSync) pull(ctx context.Context, link *GoogleTaskLink, task *Task, r *googleTask) error {
+	if s.applyRemote(task, r) {
+		err := s.tasks.UpdateTask(ctx, task)
+		if errors.Is(err, ErrTaskVersionConflict) {
+			s.result.Skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.result.LocalUpdated++
+	}
+	return s.saveLink(link, task, r)
+}
+
+func (s *googleTasksSync) createLocal(ctx context.Context, r *googleTask) error {
+	task := Task{Status: StatusTodo, Priority: PriorityMedium, Version: 1}
+	s.applyRemote(&task, r)
+	if task.Status == StatusDone && r.Completed != nil {
+		task.CompletedAt = r.Completed
+	}
+	if err := s.tasks.CreateTask(ctx, &task); err != nil {
+		return err
+	}
+	s.result.LocalCreated++
+	return s.saveLink(&GoogleTaskLink{UserID: s.userID, GoogleID: r.ID}, &task, r)
+}
+
+func (s *googleTasksSync) createRemote(ctx context.Context, task *Task) error {
+	created, err := s.client.insert(ctx, s.remoteTask(task))
+	if err != nil {
+		return err
+	}
+	s.result.RemoteCreated++
+	return s.saveLink(&GoogleTaskLink{UserID: s.userID, GoogleID: created.ID}, task, created)
+}
+
+func (s *googleTasksSync) saveLink(link *GoogleTaskLink, task *Task, r *googleTask) error {
+	link.TaskID = task.ID
+	link.LocalVersion = task.Version
+	link.RemoteUpdated = r.Updated
+	link.SyncedAt = s.now
+	return s.db.Save(link).Error
+}
+
+func (s *googleTasksSync) conflict(taskID uint, googleID, title, resolution string) {
+	s.result.Conflicts = append(s.result.Conflicts, GoogleTasksConflict{TaskID: taskID, GoogleID: googleID, Title: title, Resolution: resolution})
+}
+
+// remoteTask membangun task Google dari task lokal. done dan cancelled
+// sama-sama menjadi completed karena Google hanya punya dua status.
+func (s *googleTasksSync) remoteTask(task *Task) *googleTask {
+	r := &googleTask{Title: task.Title, Notes: task.Description, Status: googleTaskNeedsAction}
+	if task.DueAt != nil {
+		local := task.DueAt.In(s.location)
+		due := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		r.Due = &due
+	}
+	if task.Status.Closed() {
+		r.Status = googleTaskCompleted
+		r.Completed = task.CompletedAt
+	}
+	return r
+}
+
+// applyRemote mengubah task lokal sesuai task Google dan mengembalikan true
+// jika ada yang berubah. Status lokal dipertahankan selama masih sepadan
+// (in_progress tetap in_progress, cancelled tetap cancelled).
+func (s *googleTasksSync) applyRemote(task *Task, r *googleTask) bool {
+	changed := false
+	if title := strings.TrimSpace(r.Title); title != "" && title != task.Title {
+		task.Title, changed = title, true
+	}
+	if r.Notes != task.Description {
+		task.Description, changed = r.Notes, true
+	}
+
+	due, ok := googleDueDate(r.Due)
+	switch {
+	case !ok && task.DueAt != nil && task.Recurrence == "":
+		task.DueAt, changed = nil, true
+	case ok && (task.DueAt == nil || task.DueAt.In(s.location).Format(time.DateOnly) != due.Format(time.DateOnly)):
+		dueAt := time.Date(due.Year(), due.Month(), due.Day(), 23, 59, 0, 0, s.location)
+		task.DueAt, changed = &dueAt, true
+	}
+
+	completed := r.Status == googleTaskCompleted
+	if completed != task.Status.Closed() {
+		status := StatusTodo
+		if completed {
+			status = StatusDone
+		}
+		if task.SetStatus(status) == nil {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// sameTask bernilai true jika r sudah sama dengan want pada field yang disinkronkan.
+func (s *googleTasksSync) sameTask(want, r *googleTask) bool {
+	wantDue, _ := googleDueDate(want.Due)
+	remoteDue, _ := googleDueDate(r.Due)
+	return want.Title == r.Title && want.Notes == r.Notes && want.Status == r.Status && wantDue.Equal(remoteDue)
+}
+
+// googleDueDate membaca tanggal dari field due Google (RFC 3339 dengan jam
+// 00:00 UTC) sebagai tengah malam UTC.
+func googleDueDate(value *string) (time.Time, bool) {
+	if value == nil || *value == "" {
+		return time.Time{}, false
+	}
+	parsed, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	parsed = parsed.UTC()
+	return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), true
+}
@@ -0,0 +1,541 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// graphQLResolver mengimplementasikan ResolverRoot dari graphql_generated.go
+// dengan service yang sama seperti handler REST, jadi aturan akses, webhook,
+// dan riwayat perubahan tetap berlaku.
+type graphQLResolver struct {
+	Tasks     TaskService
+	Projects  ProjectService
+	Tags      TagService
+	Users     UserService
+	Scheduler *RecurrenceScheduler
+}
+
+func (r *graphQLResolver) Query() QueryResolver       { return queryResolver{r} }
+func (r *graphQLResolver) Mutation() MutationResolver { return mutationResolver{r} }
+func (r *graphQLResolver) Project() ProjectResolver   { return projectResolver{r} }
+func (r *graphQLResolver) Tag() TagResolver           { return tagResolver{r} }
+
+type queryResolver struct{ root *graphQLResolver }
+
+func (r queryResolver) Me(ctx context.Context) (*User, error) {
+	user, err := r.root.Users.GetUser(ctx, ownerID(ctx))
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return user, nil
+}
+
+func (r queryResolver) Tasks(ctx context.Context, filter *TaskFilterInput, first *int, after *string) (*TaskConnection, error) {
+	return r.root.listTasks(ctx, filter, first, after, nil)
+}
+
+func (r queryResolver) Task(ctx context.Context, id uint) (*Task, error) {
+	task, err := r.root.Tasks.GetTask(ctx, id, selectedTaskInclude(ctx))
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return task, nil
+}
+
+func (r queryResolver) Projects(ctx context.Context, archived *bool) ([]*Project, error) {
+	projects, err := r.root.Projects.ListProjects(ctx, archived != nil && *archived)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return pointers(projects), nil
+}
+
+func (r queryResolver) Project(ctx context.Context, id uint) (*Project, error) {
+	project, err := r.root.Projects.GetProject(ctx, id)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return project, nil
+}
+
+func (r queryResolver) Tags(ctx context.Context) ([]*Tag, error) {
+	tags, err := r.root.Tags.ListTags(ctx)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return pointers(tags), nil
+}
+
+func (r queryResolver) Tag(ctx context.Context, id uint) (*Tag, error) {
+	tag, err := r.root.Tags.GetTag(ctx, id)
+	if errors.Is(err, ErrTagNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return tag, nil
+}
+
+type projectResolver struct{ root *graphQLResolver }
+
+func (r projectResolver) Tasks(ctx context.Context, project *Project, filter *TaskFilterInput, first *int, after *string) (*TaskConnection, error) {
+	return r.root.listTasks(ctx, filter, first, after, func(f *TaskFilter) {
+		f.ProjectID = &project.ID
+	})
+}
+
+type tagResolver struct{ root *graphQLResolver }
+
+func (r tagResolver) Tasks(ctx context.Context, tag *Tag, filter *TaskFilterInput, first *int, after *string) (*TaskConnection, error) {
+	return r.root.listTasks(ctx, filter, first, after, func(f *TaskFilter) {
+		if !slices.Contains(f.Tags, tag.Name) {
+			f.Tags = append(f.Tags, tag.Name)
+		}
+	})
+}
+
+// listTasks menjalankan ListTasks dalam mode cursor. scope dipakai field
+// bersarang seperti Project.tasks untuk mempersempit filter dari client.
+func (r *graphQLResolver) listTasks(ctx context.Context, input *TaskFilterInput, first *int, after *string, scope func(*TaskFilter)) (*TaskConnection, error) {
+	filter, err := input.taskFilter()
+	if err != nil {
+		return nil, userInputError(ctx, err)
+	}
+	if scope != nil {
+		scope(&filter)
+	}
+
+	page := Page{Limit: defaultPageLimit, CursorMode: true}
+	if first != nil {
+		if *first < 1 || *first > maxPageLimit {
+			return nil, userInputError(ctx, errors.New("first must be between 1 and "+strconv.Itoa(maxPageLimit)))
+		}
+		page.Limit = *first
+	}
+	if after != nil && *after != "" {
+		if page.Cursor, err = decodeCursor(*after); err != nil {
+			return nil, userInputError(ctx, err)
+		}
+	}
+
+	result, err := r.Tasks.ListTasks(ctx, filter, page, selectedTaskInclude(ctx, "nodes"))
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+
+	connection := &TaskConnection{
+		Nodes:      pointers(result.Tasks),
+		TotalCount: int(result.Total),
+		PageInfo:   &PageInfo{HasNextPage: result.Next != nil},
+	}
+	if result.Next != nil {
+		cursor := result.Next.Encode()
+		connection.PageInfo.EndCursor = &cursor
+	}
+	return connection, nil
+}
+
+type mutationResolver struct{ root *graphQLResolver }
+
+func (r mutationResolver) CreateTask(ctx context.Context, input CreateTaskInput) (*Task, error) {
+	req := taskRequest{
+		Title:       input.Title,
+		Description: deref(input.Description),
+		ProjectID:   input.ProjectID,
+		AssigneeID:  input.AssigneeID,
+		Status:      string(deref(input.Status)),
+		Priority:    string(deref(input.Priority)),
+		DueAt:       input.DueAt,
+		Recurrence:  deref(input.Recurrence),
+	}
+	if err := validateTaskRequest(&req); err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	task := req.toTask()
+	if err := task.Validate(); err != nil {
+		return nil, userInputError(ctx, err)
+	}
+	if err := r.root.Tasks.CreateTask(ctx, &task); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return r.root.saved(ctx, &task)
+}
+
+func (r mutationResolver) UpdateTask(ctx context.Context, id uint, input UpdateTaskInput, force *bool) (*Task, error) {
+	req := patchTaskRequest{
+		Title:       input.Title,
+		Description: input.Description,
+		ProjectID:   omittable(input.ProjectID),
+		AssigneeID:  omittable(input.AssigneeID),
+		Status:      (*string)(input.Status),
+		Priority:    (*string)(input.Priority),
+		DueAt:       omittable(input.DueAt),
+		Recurrence:  input.Recurrence,
+	}
+	if err := req.validate(); err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	task, err := r.root.Tasks.GetTask(ctx, id, defaultTaskInclude)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	if req.completes(task) {
+		if err := r.root.checkBlockers(ctx, task.ID, force); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.apply(task); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	if err := task.Validate(); err != nil {
+		return nil, userInputError(ctx, err)
+	}
+	if err := r.root.Tasks.UpdateTask(ctx, task); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return r.root.saved(ctx, task)
+}
+
+func (r mutationResolver) CompleteTask(ctx context.Context, id uint, force *bool) (*Task, error) {
+	return r.root.setTaskStatus(ctx, id, StatusDone, force)
+}
+
+func (r mutationResolver) ReopenTask(ctx context.Context, id uint) (*Task, error) {
+	return r.root.setTaskStatus(ctx, id, StatusTodo, nil)
+}
+
+func (r mutationResolver) DeleteTask(ctx context.Context, id uint) (bool, error) {
+	if err := r.root.Tasks.DeleteTask(ctx, id); err != nil {
+		return false, graphQLError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r mutationResolver) CreateProject(ctx context.Context, input ProjectInput) (*Project, error) {
+	name, err := input.name()
+	if err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	project := Project{Name: name, Description: deref(input.Description)}
+	if err := r.root.Projects.CreateProject(ctx, &project); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return &project, nil
+}
+
+func (r mutationResolver) UpdateProject(ctx context.Context, id uint, input ProjectInput) (*Project, error) {
+	name, err := input.name()
+	if err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	project, err := r.root.Projects.GetProject(ctx, id)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	project.Name = name
+	project.Description = deref(input.Description)
+	if err := r.root.Projects.UpdateProject(ctx, project); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return project, nil
+}
+
+func (r mutationResolver) DeleteProject(ctx context.Context, id uint) (bool, error) {
+	if err := r.root.Projects.DeleteProject(ctx, id); err != nil {
+		return false, graphQLError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r mutationResolver) CreateTag(ctx context.Context, input TagInput) (*Tag, error) {
+	name, err := input.name()
+	if err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	tag := Tag{Name: name, Color: deref(input.Color)}
+	if err := r.root.Tags.CreateTag(ctx, &tag); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return &tag, nil
+}
+
+func (r mutationResolver) UpdateTag(ctx context.Context, id uint, input TagInput) (*Tag, error) {
+	name, err := input.name()
+	if err != nil {
+		return nil, userInputError(ctx, err)
+	}
+
+	tag, err := r.root.Tags.GetTag(ctx, id)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	tag.Name = name
+	tag.Color = deref(input.Color)
+	if err := r.root.Tags.UpdateTag(ctx, tag); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return tag, nil
+}
+
+func (r mutationResolver) DeleteTag(ctx context.Context, id uint) (bool, error) {
+	if err := r.root.Tags.DeleteTag(ctx, id); err != nil {
+		return false, graphQLError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r mutationResolver) AttachTag(ctx context.Context, taskID uint, tagID uint) (*Task, error) {
+	return r.root.changeTaskTag(ctx, taskID, tagID, r.root.Tags.AttachTag)
+}
+
+func (r mutationResolver) DetachTag(ctx context.Context, taskID uint, tagID uint) (*Task, error) {
+	return r.root.changeTaskTag(ctx, taskID, tagID, r.root.Tags.DetachTag)
+}
+
+func (r *graphQLResolver) setTaskStatus(ctx context.Context, id uint, status TaskStatus, force *bool) (*Task, error) {
+	task, err := r.Tasks.GetTask(ctx, id, defaultTaskInclude)
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	if status == StatusDone && task.Status != StatusDone {
+		if err := r.checkBlockers(ctx, task.ID, force); err != nil {
+			return nil, err
+		}
+	}
+	if err := task.SetStatus(status); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	if err := r.Tasks.UpdateTask(ctx, task); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return r.saved(ctx, task)
+}
+
+func (r *graphQLResolver) changeTaskTag(ctx context.Context, taskID, tagID uint, change func(ctx context.Context, taskID, tagID uint) error) (*Task, error) {
+	if err := change(ctx, taskID, tagID); err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	task, err := r.Tasks.GetTask(ctx, taskID, selectedTaskInclude(ctx))
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return task, nil
+}
+
+// checkBlockers sama dengan allowCompletion di REST: task yang masih punya
+// blocker terbuka hanya boleh diselesaikan dengan force: true.
+func (r *graphQLResolver) checkBlockers(ctx context.Context, id uint, force *bool) error {
+	if force != nil && *force {
+		return nil
+	}
+	blockers, err := r.Tasks.OpenBlockers(ctx, []uint{id})
+	if err != nil {
+		return graphQLError(ctx, err)
+	}
+	if refs, ok := blockers[id]; ok {
+		return &gqlerror.Error{
+			Message: "task is blocked by open tasks; pass force: true to complete anyway",
+			Path:    graphql.GetPath(ctx),
+			Extensions: map[string]any{
+				"code":       "CONFLICT",
+				"blocked_by": refs,
+			},
+		}
+	}
+	return nil
+}
+
+// saved dipanggil setelah task disimpan: membangunkan scheduler seperti
+// TaskHandler.afterSave, lalu memuat ulang task dengan relasi yang diminta.
+func (r *graphQLResolver) saved(ctx context.Context, task *Task) (*Task, error) {
+	if task.Status == StatusDone && task.Recurrence != "" {
+		r.Scheduler.Notify()
+	}
+	reloaded, err := r.Tasks.GetTask(ctx, task.ID, selectedTaskInclude(ctx))
+	if err != nil {
+		return nil, graphQLError(ctx, err)
+	}
+	return reloaded, nil
+}
+
+// taskFilter menerjemahkan filter GraphQL ke parameter query string REST
+// supaya validasinya sama persis dengan GET /tasks dan saved filter.
+func (input *TaskFilterInput) taskFilter() (TaskFilter, error) {
+	if input == nil {
+		return TaskFilter{}, nil
+	}
+
+	values := url.Values{}
+	if len(input.Status) > 0 {
+		statuses := make([]string, len(input.Status))
+		for i, status := range input.Status {
+			statuses[i] = string(status)
+		}
+		values.Set("status", strings.Join(statuses, ","))
+	}
+	if len(input.Priority) > 0 {
+		priorities := make([]string, len(input.Priority))
+		for i, priority := range input.Priority {
+			priorities[i] = string(priority)
+		}
+		values.Set("priority", strings.Join(priorities, ","))
+	}
+	if len(input.Tags) > 0 {
+		values.Set("tag", strings.Join(input.Tags, ","))
+	}
+	if input.ProjectID != nil {
+		values.Set("project_id", strconv.FormatUint(uint64(*input.ProjectID), 10))
+	}
+	if input.Assignee != nil {
+		values.Set("assignee", *input.Assignee)
+	}
+	if input.Overdue != nil {
+		values.Set("overdue", strconv.FormatBool(*input.Overdue))
+	}
+	if input.Archived != nil {
+		values.Set("archived", strconv.FormatBool(*input.Archived))
+	}
+	if input.DueBefore != nil {
+		values.Set("due_before", input.DueBefore.Format(time.RFC3339Nano))
+	}
+	if input.DueAfter != nil {
+		values.Set("due_after", input.DueAfter.Format(time.RFC3339Nano))
+	}
+	return parseTaskFilterValues(values)
+}
+
+func (input ProjectInput) name() (string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return "", errors.New("name must not be empty")
+	}
+	return name, nil
+}
+
+func (input TagInput) name() (string, error) {
+	name := normalizeTagName(input.Name)
+	if name == "" {
+		return "", errors.New("name must not be empty")
+	}
+	if len(name) > 64 {
+		return "", errors.New("name must be at most 64 characters")
+	}
+	return name, nil
+}
+
+// selectedTaskInclude memilih relasi yang dimuat dari field yang diminta
+// query, supaya daftar task memakai preload dan bukan satu query per task.
+// path menunjuk ke field Task di bawah field saat ini, misalnya "nodes"
+// untuk TaskConnection.
+func selectedTaskInclude(ctx context.Context, path ...string) TaskInclude {
+	opCtx := graphql.GetOperationContext(ctx)
+	fields := graphql.CollectFieldsCtx(ctx, nil)
+	for _, name := range path {
+		var next []graphql.CollectedField
+		for _, field := range fields {
+			if field.Name == name {
+				next = append(next, graphql.CollectFields(opCtx, field.Selections, nil)...)
+			}
+		}
+		fields = next
+	}
+
+	var include TaskInclude
+	for _, field := range fields {
+		switch field.Name {
+		case "subtasks":
+			include.Subtasks = true
+		case "tags":
+			include.Tags = true
+		case "project":
+			include.Project = true
+		case "owner":
+			include.Owner = true
+		case "assignee":
+			include.Assignee = true
+		}
+	}
+	return include
+}
+
+// graphQLError memetakan error service ke error GraphQL dengan
+// extensions.code, seperti saveTaskError dan tagError untuk REST.
+func graphQLError(ctx context.Context, err error) error {
+	var transition *InvalidTransitionError
+	code := ""
+	switch {
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrTagNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, ErrInvalidAssignee):
+		code = "BAD_USER_INPUT"
+	case errors.Is(err, ErrTaskVersionConflict), errors.Is(err, ErrTagNameTaken), errors.As(err, &transition):
+		code = "CONFLICT"
+	default:
+		log.Printf("graphql %s: %v", graphql.GetPath(ctx), err)
+		return &gqlerror.Error{
+			Message:    "internal server error",
+			Path:       graphql.GetPath(ctx),
+			Extensions: map[string]any{"code": "INTERNAL_SERVER_ERROR"},
+		}
+	}
+	return &gqlerror.Error{
+		Message:    err.Error(),
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": code},
+	}
+}
+
+func userInputError(ctx context.Context, err error) error {
+	return &gqlerror.Error{
+		Message:    err.Error(),
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": "BAD_USER_INPUT"},
+	}
+}
+
+// omittable mengubah Omittable dari gqlgen ke optional milik patchTaskRequest.
+func omittable[T any](value graphql.Omittable[*T]) optional[T] {
+	v, set := value.ValueOK()
+	if !set {
+		return optional[T]{}
+	}
+	return optional[T]{Set: true, Value: v}
+}
+
+func deref[T any](ptr *T) T {
+	var zero T
+	if ptr == nil {
+		return zero
+	}
+	return *ptr
+}
+
+func pointers[T any](items []T) []*T {
+	result := make([]*T, len(items))
+	for i := range items {
+		result[i] = &items[i]
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// graphQLComplexityLimit membatasi ukuran query supaya field bersarang seperti
+// projects { tasks { nodes { tags { tasks ... } } } } tidak bisa dipakai untuk
+// membebani database.
+const graphQLComplexityLimit = 500
+
+// GraphQLHandler melayani POST /graphql. Scope API key dan role share/workspace
+// dicek per operasi, bukan per HTTP method seperti route REST: query butuh
+// read:tasks dan cukup viewer, mutation butuh write:tasks dan editor.
+type GraphQLHandler struct {
+	server *handler.Server
+}
+
+func NewGraphQLHandler(resolver *graphQLResolver) *GraphQLHandler {
+	server := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(graphQLComplexityLimit))
+	server.AroundOperations(authorizeOperation)
+	return &GraphQLHandler{server: server}
+}
+
+func (h *GraphQLHandler) Serve(c *gin.Context) {
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
+
+// useGraphQLWorkspace memuat workspace dari header seperti useWorkspace, tapi
+// cukup dengan role viewer; role editor untuk mutation dicek authorizeOperation.
+func useGraphQLWorkspace(workspaces WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(workspaceHeader)
+		if value == "" || loadWorkspace(c, workspaces, value, WorkspaceViewer) {
+			c.Next()
+		}
+	}
+}
+
+func authorizeOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	scope, share, role := ScopeReadTasks, ShareViewer, WorkspaceViewer
+	if graphql.GetOperationContext(ctx).Operation.Operation == ast.Mutation {
+		scope, share, role = ScopeWriteTasks, ShareEditor, WorkspaceEditor
+	}
+
+	if !scopeAllowed(ctx, scope) {
+		return graphql.OneShot(forbiddenResponse("api key is missing the required scope", map[string]any{"required_scope": scope}))
+	}
+	if member := workspaceFromContext(ctx); member != nil && !member.Role.AtLeast(role) {
+		return graphql.OneShot(forbiddenResponse("requires workspace "+string(role)+" role", nil))
+	}
+	return next(context.WithValue(ctx, shareRoleContextKey{}, share))
+}
+
+func forbiddenResponse(message string, extensions map[string]any) *graphql.Response {
+	if extensions == nil {
+		extensions = map[string]any{}
+	}
+	extensions["code"] = "FORBIDDEN"
+	return &graphql.Response{Errors: gqlerror.List{{Message: message, Extensions: extensions}}}
+}
@@ -0,0 +1,74 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package main
+
+import (
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type CreateTaskInput struct {
+	Title       string      `json:"title"`
+	Description *string     `json:"description,omitempty"`
+	ProjectID   *uint       `json:"projectId,omitempty"`
+	AssigneeID  *uint       `json:"assigneeId,omitempty"`
+	Status      *TaskStatus `json:"status,omitempty"`
+	Priority    *Priority   `json:"priority,omitempty"`
+	DueAt       *time.Time  `json:"dueAt,omitempty"`
+	Recurrence  *string     `json:"recurrence,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
+type ProjectInput struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+type Query struct {
+}
+
+type TagInput struct {
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+}
+
+// A page of tasks using cursor pagination, like ?cursor= in REST.
+type TaskConnection struct {
+	Nodes      []*Task   `json:"nodes"`
+	TotalCount int       `json:"totalCount"`
+	PageInfo   *PageInfo `json:"pageInfo"`
+}
+
+type TaskFilterInput struct {
+	Status   []TaskStatus `json:"status,omitempty"`
+	Priority []Priority   `json:"priority,omitempty"`
+	// Tag names; a task must have all of them.
+	Tags      []string `json:"tags,omitempty"`
+	ProjectID *uint    `json:"projectId,omitempty"`
+	// me, none, or a user id.
+	Assignee  *string    `json:"assignee,omitempty"`
+	Overdue   *bool      `json:"overdue,omitempty"`
+	DueBefore *time.Time `json:"dueBefore,omitempty"`
+	DueAfter  *time.Time `json:"dueAfter,omitempty"`
+	Archived  *bool      `json:"archived,omitempty"`
+}
+
+// Omitted fields are left unchanged; null clears projectId, assigneeId, and dueAt.
+type UpdateTaskInput struct {
+	Title       *string                       `json:"title,omitempty"`
+	Description *string                       `json:"description,omitempty"`
+	ProjectID   graphql.Omittable[*uint]      `json:"projectId,omitempty"`
+	AssigneeID  graphql.Omittable[*uint]      `json:"assigneeId,omitempty"`
+	Status      *TaskStatus                   `json:"status,omitempty"`
+	Priority    *Priority                     `json:"priority,omitempty"`
+	DueAt       graphql.Omittable[*time.Time] `json:"dueAt,omitempty"`
+	Recurrence  *string                       `json:"recurrence,omitempty"`
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"todo-list-basic/web-api/todopb"
+)
+
+// TodoServer mengimplementasikan todopb.TodoServiceServer untuk pemanggil
+// internal dengan service yang sama seperti REST. User, scope, dan workspace
+// sudah dipasang ke context oleh grpcAuth.
+type TodoServer struct {
+	todopb.UnimplementedTodoServiceServer
+	Tasks     TaskService
+	Projects  ProjectService
+	Users     UserService
+	Scheduler *RecurrenceScheduler
+}
+
+var (
+	taskStatusProto = map[TaskStatus]todopb.TaskStatus{
+		StatusTodo:       todopb.TaskStatus_TASK_STATUS_TODO,
+		StatusInProgress: todopb.TaskStatus_TASK_STATUS_IN_PROGRESS,
+		StatusDone:       todopb.TaskStatus_TASK_STATUS_DONE,
+		StatusCancelled:  todopb.TaskStatus_TASK_STATUS_CANCELLED,
+	}
+	priorityProto = map[Priority]todopb.Priority{
+		PriorityLow:    todopb.Priority_PRIORITY_LOW,
+		PriorityMedium: todopb.Priority_PRIORITY_MEDIUM,
+		PriorityHigh:   todopb.Priority_PRIORITY_HIGH,
+		PriorityUrgent: todopb.Priority_PRIORITY_URGENT,
+	}
+)
+
+func (s *TodoServer) ListTasks(ctx context.Context, req *todopb.ListTasksRequest) (*todopb.ListTasksResponse, error) {
+	filter, err := protoTaskFilter(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page := Page{Limit: defaultPageLimit, CursorMode: true}
+	if req.PageSize != 0 {
+		if req.PageSize < 1 || req.PageSize > maxPageLimit {
+			return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = int(req.PageSize)
+	}
+	if req.PageToken != "" {
+		if page.Cursor, err = decodeCursor(req.PageToken); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	result, err := s.Tasks.ListTasks(ctx, filter, page, TaskInclude{Tags: true})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	resp := &todopb.ListTasksResponse{TotalSize: result.Total}
+	for i := range result.Tasks {
+		resp.Tasks = append(resp.Tasks, taskToProto(&result.Tasks[i]))
+	}
+	if result.Next != nil {
+		resp.NextPageToken = result.Next.Encode()
+	}
+	return resp, nil
+}
+
+func (s *TodoServer) GetTask(ctx context.Context, req *todopb.GetTaskRequest) (*todopb.Task, error) {
+	task, err := s.Tasks.GetTask(ctx, uint(req.Id), TaskInclude{Tags: true})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *TodoServer) CreateTask(ctx context.Context, req *todopb.CreateTaskRequest) (*todopb.Task, error) {
+	input := req.GetTask()
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+	body := taskRequest{
+		Title:       input.Title,
+		Description: input.Description,
+		ProjectID:   protoID(input.ProjectId),
+		AssigneeID:  protoID(input.AssigneeId),
+		Status:      string(taskStatusFromProto(input.Status)),
+		Priority:    string(priorityFromProto(input.Priority)),
+		DueAt:       protoTime(input.DueAt),
+		Recurrence:  input.Recurrence,
+	}
+	if err := validateTaskRequest(&body); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	task := body.toTask()
+	if err := task.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.Tasks.CreateTask(ctx, &task); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	s.afterSave(&task)
+	return taskToProto(&task), nil
+}
+
+// UpdateTask hanya mengubah field di update_mask, seperti PATCH /tasks/:id.
+func (s *TodoServer) UpdateTask(ctx context.Context, req *todopb.UpdateTaskRequest) (*todopb.Task, error) {
+	input := req.GetTask()
+	if input == nil || input.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "task.id is required")
+	}
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	var patch patchTaskRequest
+	for _, path := range req.UpdateMask.Paths {
+		switch path {
+		case "title":
+			patch.Title = &input.Title
+		case "description":
+			patch.Description = &input.Description
+		case "project_id":
+			patch.ProjectID = optional[uint]{Set: true, Value: protoID(input.ProjectId)}
+		case "assignee_id":
+			patch.AssigneeID = optional[uint]{Set: true, Value: protoID(input.AssigneeId)}
+		case "status":
+			value := string(taskStatusFromProto(input.Status))
+			patch.Status = &value
+		case "priority":
+			value := string(priorityFromProto(input.Priority))
+			patch.Priority = &value
+		case "due_at":
+			patch.DueAt = optional[time.Time]{Set: true, Value: protoTime(input.DueAt)}
+		case "recurrence":
+			patch.Recurrence = &input.Recurrence
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update_mask path %q", path)
+		}
+	}
+	if err := patch.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	task, err := s.Tasks.GetTask(ctx, uint(input.Id), TaskInclude{Tags: true})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if input.Version != 0 && int(input.Version) != task.Version {
+		return nil, status.Error(codes.Aborted, ErrTaskVersionConflict.Error())
+	}
+	if patch.completes(task) && !req.Force {
+		blockers, err := s.Tasks.OpenBlockers(ctx, []uint{task.ID})
+		if err != nil {
+			return nil, grpcError(ctx, err)
+		}
+		if len(blockers[task.ID]) > 0 {
+			return nil, status.Error(codes.FailedPrecondition, "task is blocked by open tasks; set force to complete anyway")
+		}
+	}
+	if err := patch.apply(task); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if err := task.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.Tasks.UpdateTask(ctx, task); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	s.afterSave(task)
+	return taskToProto(task), nil
+}
+
+func (s *TodoServer) DeleteTask(ctx context.Context, req *todopb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if err := s.Tasks.DeleteTask(ctx, uint(req.Id)); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TodoServer) ListProjects(ctx context.Context, req *todopb.ListProjectsRequest) (*todopb.ListProjectsResponse, error) {
+	projects, err := s.Projects.ListProjects(ctx, req.Archived)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	resp := &todopb.ListProjectsResponse{}
+	for i := range projects {
+		resp.Projects = append(resp.Projects, projectToProto(&projects[i]))
+	}
+	return resp, nil
+}
+
+func (s *TodoServer) GetProject(ctx context.Context, req *todopb.GetProjectRequest) (*todopb.Project, error) {
+	project, err := s.Projects.GetProject(ctx, uint(req.Id))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return projectToProto(project), nil
+}
+
+// GetUser mengembalikan user pemanggil. User lain hanya boleh dibaca admin
+// dengan scope admin, sama dengan GET /admin/users.
+func (s *TodoServer) GetUser(ctx context.Context, req *todopb.GetUserRequest) (*todopb.User, error) {
+	caller := userFromContext(ctx)
+	id := uint(req.Id)
+	if id == 0 {
+		id = caller.ID
+	}
+	if id != caller.ID && (caller.Role != RoleAdmin || !scopeAllowed(ctx, ScopeAdmin)) {
+		return nil, status.Error(codes.PermissionDenied, "only admins can read other users")
+	}
+
+	user, err := s.Users.GetUser(ctx, id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return userToProto(user), nil
+}
+
+// afterSave membangunkan scheduler seperti TaskHandler.afterSave.
+func (s *TodoServer) afterSave(task *Task) {
+	if task.Status == StatusDone && task.Recurrence != "" {
+		s.Scheduler.Notify()
+	}
+}
+
+// protoTaskFilter menerjemahkan ListTasksRequest ke parameter query string
+// REST supaya validasinya sama dengan GET /tasks.
+func protoTaskFilter(req *todopb.ListTasksRequest) (TaskFilter, error) {
+	values := url.Values{}
+	var statuses, priorities []string
+	for _, value := range req.Statuses {
+		if taskStatus := taskStatusFromProto(value); taskStatus != "" {
+			statuses = append(statuses, string(taskStatus))
+		}
+	}
+	for _, value := range req.Priorities {
+		if priority := priorityFromProto(value); priority != "" {
+			priorities = append(priorities, string(priority))
+		}
+	}
+	if len(statuses) > 0 {
+		values.Set("status", strings.Join(statuses, ","))
+	}
+	if len(priorities) > 0 {
+		values.Set("priority", strings.Join(priorities, ","))
+	}
+	if len(req.Tags) > 0 {
+		values.Set("tag", strings.Join(req.Tags, ","))
+	}
+	if req.ProjectId != nil {
+		values.Set("project_id", strconv.FormatUint(*req.ProjectId, 10))
+	}
+	if req.Assignee != "" {
+		values.Set("assignee", req.Assignee)
+	}
+	if req.Archived {
+		values.Set("archived", "true")
+	}
+	return parseTaskFilterValues(values)
+}
+
+func taskToProto(task *Task) *todopb.Task {
+	msg := &todopb.Task{
+		Id:          uint64(task.ID),
+		WorkspaceId: idProto(task.WorkspaceID),
+		Title:       task.Title,
+		Description: task.Description,
+		ProjectId:   idProto(task.ProjectID),
+		AssigneeId:  idProto(task.AssigneeID),
+		Status:      taskStatusProto[task.Status],
+		Priority:    priorityProto[task.Priority],
+		Position:    int32(task.Position),
+		DueAt:       timeProto(task.DueAt),
+		CompletedAt: timeProto(task.CompletedAt),
+		ArchivedAt:  timeProto(task.ArchivedAt),
+		Recurrence:  task.Recurrence,
+		Version:     int32(task.Version),
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+	}
+	for _, tag := range task.Tags {
+		msg.Tags = append(msg.Tags, tag.Name)
+	}
+	return msg
+}
+
+func projectToProto(project *Project) *todopb.Project {
+	return &todopb.Project{
+		Id:          uint64(project.ID),
+		WorkspaceId: idProto(project.WorkspaceID),
+		Name:        project.Name,
+		Description: project.Description,
+		ArchivedAt:  timeProto(project.ArchivedAt),
+		CreatedAt:   timestamppb.New(project.CreatedAt),
+		UpdatedAt:   timestamppb.New(project.UpdatedAt),
+	}
+}
+
+func userToProto(user *User) *todopb.User {
+	return &todopb.User{
+		Id:        uint64(user.ID),
+		Name:      user.Name,
+		Email:     user.Email,
+		Username:  deref(user.Username),
+		AvatarUrl: user.AvatarURL,
+	}
+}
+
+// taskStatusFromProto mengembalikan string kosong untuk UNSPECIFIED, yang
+// oleh ParseTaskStatus dianggap status default.
+func taskStatusFromProto(value todopb.TaskStatus) TaskStatus {
+	for taskStatus, msg := range taskStatusProto {
+		if msg == value {
+			return taskStatus
+		}
+	}
+	return ""
+}
+
+func priorityFromProto(value todopb.Priority) Priority {
+	for priority, msg := range priorityProto {
+		if msg == value {
+			return priority
+		}
+	}
+	return ""
+}
+
+func idProto(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	value := uint64(*id)
+	return &value
+}
+
+func protoID(id *uint64) *uint {
+	if id == nil {
+		return nil
+	}
+	value := uint(*id)
+	return &value
+}
+
+func timeProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func protoTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// grpcError memetakan error service ke status gRPC, seperti saveTaskError
+// untuk REST.
+func grpcError(ctx context.Context, err error) error {
+	var transition *InvalidTransitionError
+	switch {
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidAssignee):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrTaskVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &transition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	method, _ := grpc.Method(ctx)
+	log.Printf("grpc %s: %v", method, err)
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"todo-list-basic/web-api/todopb"
+)
+
+// grpcReadMethods adalah RPC yang hanya membaca. RPC lain butuh write:tasks,
+// role editor, dan email terverifikasi, sama dengan method non-GET di REST.
+var grpcReadMethods = map[string]bool{
+	todopb.TodoService_ListTasks_FullMethodName:    true,
+	todopb.TodoService_GetTask_FullMethodName:      true,
+	todopb.TodoService_ListProjects_FullMethodName: true,
+	todopb.TodoService_GetProject_FullMethodName:   true,
+	todopb.TodoService_GetUser_FullMethodName:      true,
+}
+
+// grpcAuth memvalidasi metadata x-api-key atau authorization: Bearer <token>
+// seperti requireAuth, lalu memasang scope, workspace (x-workspace-id), dan
+// role share ke context. Cookie session tidak didukung.
+type grpcAuth struct {
+	Tokens       *TokenService
+	Users        UserService
+	Keys         APIKeyService
+	Workspaces   WorkspaceService
+	Verification VerificationMode
+}
+
+// NewGRPCServer membuat server gRPC dengan TodoService dan reflection supaya
+// bisa dicoba dengan grpcurl. limiter sebaiknya sama dengan milik REST supaya
+// batas per user dan API key tidak bisa dilewati dengan pindah protokol.
+func NewGRPCServer(auth *grpcAuth, limiter *RateLimiter, todo *TodoServer) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(auth.unary, grpcRateLimit(limiter)))
+	todopb.RegisterTodoServiceServer(server, todo)
+	reflection.Register(server)
+	return server
+}
+
+func (a *grpcAuth) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, "/"+todopb.TodoService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx, user, err := a.authenticate(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, share, role := ScopeWriteTasks, ShareEditor, WorkspaceEditor
+	read := grpcReadMethods[info.FullMethod]
+	if read {
+		scope, share, role = ScopeReadTasks, ShareViewer, WorkspaceViewer
+	}
+	if !scopeAllowed(ctx, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key is missing the required scope %s", scope)
+	}
+	if !read && a.Verification == VerifyWrite && !user.EmailVerified() {
+		return nil, status.Error(codes.PermissionDenied, ErrEmailNotVerified.Error())
+	}
+
+	if values := md.Get(strings.ToLower(workspaceHeader)); len(values) > 0 && values[0] != "" {
+		id, err := strconv.ParseUint(values[0], 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid workspace id")
+		}
+		member, err := a.Workspaces.GetMembership(ctx, uint(id), user.ID)
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if err != nil {
+			return nil, grpcError(ctx, err)
+		}
+		if !member.Role.AtLeast(role) {
+			return nil, status.Error(codes.PermissionDenied, "requires workspace "+string(role)+" role")
+		}
+		ctx = withWorkspace(ctx, member)
+	}
+
+	return handler(context.WithValue(ctx, shareRoleContextKey{}, share), req)
+}
+
+// grpcRateLimit menerapkan RateLimiter setelah grpcAuth, dengan identitas
+// yang sama seperti rateLimitKey; IP-nya alamat koneksi karena gRPC internal
+// tidak lewat proxy HTTP. Header x-ratelimit-* dikirim sebagai metadata, dan
+// request yang melewati batas ditolak dengan ResourceExhausted.
+func grpcRateLimit(limiter *RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		key, authenticated := rateLimitIdentity(ctx, grpcPeerIP(ctx))
+		limit := limiter.limitFor(authenticated)
+		if limit.Limit <= 0 {
+			return handler(ctx, req)
+		}
+
+		result := limiter.take(key, limit, time.Now())
+		md := metadata.Pairs(
+			"x-ratelimit-limit", strconv.Itoa(limit.Limit),
+			"x-ratelimit-remaining", strconv.Itoa(result.remaining),
+			"x-ratelimit-reset", strconv.FormatInt(result.reset.Unix(), 10),
+		)
+		if !result.allowed {
+			md.Set("retry-after", strconv.Itoa(int(math.Ceil(result.retryAfter.Seconds()))))
+			grpc.SetHeader(ctx, md)
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		grpc.SetHeader(ctx, md)
+		return handler(ctx, req)
+	}
+}
+
+func grpcPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func (a *grpcAuth) authenticate(ctx context.Context, md metadata.MD) (context.Context, *User, error) {
+	var user *User
+	if values := md.Get("x-api-key"); len(values) > 0 && values[0] != "" {
+		apiKey, err := a.Keys.Authenticate(ctx, values[0])
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return ctx, nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if err != nil {
+			return ctx, nil, grpcError(ctx, err)
+		}
+		user = apiKey.User
+		ctx = withScopes(ctx, apiKey.Scopes)
+		ctx = context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+	} else {
+		var header string
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+		scheme, value, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || value == "" {
+			return ctx, nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		id, version, err := a.Tokens.ParseAccessToken(strings.TrimSpace(value))
+		if err != nil {
+			return ctx, nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		user, err = a.Users.GetUser(ctx, id)
+		if errors.Is(err, ErrUserNotFound) || (err == nil && version != user.TokenVersion) {
+			return ctx, nil, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+		}
+		if err != nil {
+			return ctx, nil, grpcError(ctx, err)
+		}
+	}
+
+	if user.Disabled() {
+		return ctx, nil, status.Error(codes.PermissionDenied, ErrUserDisabled.Error())
+	}
+	return withUser(ctx, user), user, nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxImportBytes membatasi ukuran file yang diunggah dan total isi arsip
+	// setelah diekstrak.
+	maxImportBytes = 10 << 20
+	// maxImportTasks membatasi jumlah task dalam satu import.
+	maxImportTasks   = 5000
+	maxTagNameLength = 64
+)
+
+var ErrImportTooLarge = fmt.Errorf("an import can contain at most %d tasks", maxImportTasks)
+
+// importDateLayouts adalah format tanggal yang dikenali saat import. Tanggal
+// bahasa alami seperti "tomorrow" atau "every monday" tidak diimpor.
+var importDateLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{time.RFC3339, false},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02 15:04", false},
+	{"2006-01-02", true},
+	{"2 Jan 2006 15:04", false},
+	{"2 Jan 2006", true},
+	{"Jan 2 2006 15:04", false},
+	{"Jan 2 2006", true},
+}
+
+// ImportProject adalah satu project dari aplikasi lain, sudah diterjemahkan ke
+// model di sini tetapi belum disimpan. Name kosong berarti task tanpa project.
+// Jika Reuse diisi, project dengan nama yang sama yang sudah ada dipakai
+// alih-alih membuat project baru.
+type ImportProject struct {
+	Name  string
+	Reuse bool
+	Tasks []ImportTask
+}
+
+// ImportTask adalah task dari aplikasi lain. Tags berisi nama tag; tag yang
+// belum ada dibuat saat import. CreatedAt dan CompletedAt boleh kosong; task
+// done tanpa CompletedAt dianggap selesai saat diimpor.
+type ImportTask struct {
+	Title       string
+	Description string
+	Status      TaskStatus
+	Priority    Priority
+	DueAt       *time.Time
+	Recurrence  string
+	Tags        []string
+	Subtasks    []Subtask
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// ImportSkip mencatat bagian file yang tidak diimpor. Source menunjuk lokasinya
+// di file asal (misalnya "Inbox.csv:12"), Item jenisnya.
+type ImportSkip struct {
+	Source string `json:"source"`
+	Item   string `json:"item"`
+	Reason string `json:"reason"`
+}
+
+// ImportCounts adalah jumlah data yang dibuat oleh import. Tag yang sudah ada
+// dan dipakai ulang tidak dihitung.
+type ImportCounts struct {
+	Projects int `json:"projects"`
+	Tasks    int `json:"tasks"`
+	Subtasks int `json:"subtasks"`
+	Tags     int `json:"tags"`
+}
+
+// ImportReport adalah hasil import yang dikembalikan ke client. Errors hanya
+// dipakai import CSV, untuk baris yang tidak lolos validasi.
+type ImportReport struct {
+	Imported ImportCounts     `json:"imported"`
+	Skipped  []ImportSkip     `json:"skipped"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+func (r *ImportReport) skip(source, item, format string, args ...any) {
+	r.Skipped = append(r.Skipped, ImportSkip{Source: source, Item: item, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Interface untuk layanan import
+type ImportService interface {
+	Import(ctx context.Context, projects []ImportProject, report *ImportReport) error
+}
+
+// Struct implementasi ImportService dengan GORM
+type ImportServiceImpl struct {
+	DB *gorm.DB
+}
+
+// Import menyimpan semua project beserta task, subtask dan tag-nya dalam satu
+// transaksi, di data pribadi user atau workspace di context. Jika satu gagal,
+// tidak ada yang disimpan. Jumlah yang dibuat ditambahkan ke report.
+func (s *ImportServiceImpl) Import(ctx context.Context, projects []ImportProject, report *ImportReport) error {
+	total := 0
+	for _, project := range projects {
+		total += len(project.Tasks)
+	}
+	if total > maxImportTasks {
+		return ErrImportTooLarge
+	}
+
+	owner := ownerID(ctx)
+	workspace := workspaceID(ctx)
+	var counts ImportCounts
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tags := map[string]*Tag{}
+		position, err := nextPosition(tx, &owner, workspace)
+		if err != nil {
+			return err
+		}
+		for _, imported := range projects {
+			projectID, created, err := importProjectID(tx, &owner, workspace, imported)
+			if err != nil {
+				return err
+			}
+			if created {
+				counts.Projects++
+			}
+
+			for _, item := range imported.Tasks {
+				task := Task{
+					UserID:      &owner,
+					WorkspaceID: workspace,
+					ProjectID:   projectID,
+					Title:       item.Title,
+					Description: item.Description,
+					Status:      item.Status,
+					Priority:    item.Priority,
+					Position:    position,
+					DueAt:       item.DueAt,
+					Recurrence:  item.Recurrence,
+					Subtasks:    item.Subtasks,
+					CreatedAt:   item.CreatedAt,
+					CompletedAt: item.CompletedAt,
+				}
+				if task.Status == StatusDone && task.CompletedAt == nil {
+					completedAt := time.Now()
+					task.CompletedAt = &completedAt
+				}
+				for _, name := range item.Tags {
+					tag, created, err := findOrCreateTag(tx, tags, owner, name)
+					if err != nil {
+						return err
+					}
+					if created {
+						counts.Tags++
+					}
+					if !slices.ContainsFunc(task.Tags, func(t Tag) bool { return t.ID == tag.ID }) {
+						task.Tags = append(task.Tags, *tag)
+					}
+				}
+				if err := tx.Omit("Tags.*").Create(&task).Error; err != nil {
+					return err
+				}
+				position++
+				counts.Tasks++
+				counts.Subtasks += len(task.Subtasks)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	report.Imported = counts
+	return nil
+}
+
+// importProjectID mengembalikan project tujuan task dari imported, membuatnya
+// jika perlu. Nilai bool menandai project yang baru dibuat.
+func importProjectID(tx *gorm.DB, owner, workspace *uint, imported ImportProject) (*uint, bool, error) {
+	if imported.Name == "" {
+		return nil, false, nil
+	}
+	var project Project
+	if imported.Reuse {
+		err := inSpace(tx.Where("name = ?", imported.Name), owner, workspace).Order("id").First(&project).Error
+		if err == nil {
+			return &project.ID, false, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+	}
+	project = Project{UserID: owner, WorkspaceID: workspace, Name: imported.Name}
+	if err := tx.Create(&project).Error; err != nil {
+		return nil, false, err
+	}
+	return &project.ID, true, nil
+}
+
+// findOrCreateTag mencari tag milik owner berdasarkan nama tanpa memperhatikan
+// huruf besar/kecil, dan membuatnya jika belum ada. cache mencegah query
+// berulang untuk tag yang sama dalam satu import.
+func findOrCreateTag(tx *gorm.DB, cache map[string]*Tag, owner uint, name string) (*Tag, bool, error) {
+	key := strings.ToLower(name)
+	if tag, ok := cache[key]; ok {
+		return tag, false, nil
+	}
+	var tag Tag
+	err := tx.Where("user_id = ? AND LOWER(name) = ?", owner, key).First(&tag).Error
+	created := false
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tag = Tag{UserID: &owner, Name: name}
+		err = tx.Create(&tag).Error
+		created = true
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	cache[key] = &tag
+	return &tag, created, nil
+}
+
+// importTagName merapikan nama tag dari file import; nama kosong atau terlalu
+// panjang dikembalikan sebagai error.
+func importTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("tag name is empty")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", fmt.Errorf("tag name is longer than %d characters", maxTagNameLength)
+	}
+	return name, nil
+}
+
+// parseImportDate menerima tanggal dengan atau tanpa jam. Tanggal tanpa jam
+// menjadi akhir hari itu (23:59) di location, seperti task Todoist yang jatuh
+// tempo sepanjang hari.
+func parseImportDate(value string, location *time.Location) (time.Time, bool) {
+	value = strings.Join(strings.Fields(strings.ReplaceAll(value, ",", " ")), " ")
+	for _, format := range importDateLayouts {
+		parsed, err := time.ParseInLocation(format.layout, value, location)
+		if err != nil {
+			continue
+		}
+		if format.dateOnly {
+			parsed = parsed.Add(23*time.Hour + 59*time.Minute)
+		}
+		return parsed, true
+	}
+	return time.Time{}, false
+}
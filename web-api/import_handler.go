@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportHandler berisi HTTP handler untuk /import. Data diimpor ke data
+// pribadi user, atau ke workspace jika header X-Workspace-ID dikirim.
+type ImportHandler struct {
+	Service ImportService
+}
+
+// ImportTodoist menerima multipart "file" berisi backup Todoist (zip) atau CSV
+// satu project. Setiap CSV menjadi project baru; yang tidak bisa diimpor
+// dicantumkan di "skipped".
+func (h *ImportHandler) ImportTodoist(c *gin.Context) {
+	filename, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+	report := &ImportReport{Skipped: []ImportSkip{}}
+	projects, err := parseTodoistBackup(filename, data, importLocation(c), report)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.save(c, projects, report)
+}
+
+// ImportTrello menerima multipart "file" berisi export JSON satu board Trello.
+// Setiap list yang belum diarsipkan menjadi project baru.
+func (h *ImportHandler) ImportTrello(c *gin.Context) {
+	_, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	report := &ImportReport{Skipped: []ImportSkip{}}
+	projects, err := parseTrelloBoard(data, report)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.save(c, projects, report)
+}
+
+// ImportCSV menerima multipart "file" berisi CSV task, dengan field form
+// opsional "mapping" (JSON {"<field>": "<judul kolom>"}) dan "mode"
+// (transactional atau best_effort). Baris yang tidak valid dicantumkan di
+// "errors"; dalam mode transactional, satu baris yang tidak valid membatalkan
+// seluruh import.
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	_, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+	mode, err := ParseCSVImportMode(c.PostForm("mode"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	mapping, err := parseCSVMapping(c.PostForm("mapping"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	projects, errs, err := parseTaskCSV(data, mapping, importLocation(c))
+	if errors.Is(err, ErrImportTooLarge) {
+		importError(c, err)
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(errs) > 0 && mode == CSVImportTransactional {
+		problem(c, http.StatusBadRequest, "one or more rows are invalid; nothing was imported", gin.H{
+			"rows": errs,
+		})
+		return
+	}
+	h.save(c, projects, &ImportReport{Skipped: []ImportSkip{}, Errors: errs})
+}
+
+// ImportTodoTxt menerima multipart "file" berisi daftar todo.txt; lihat
+// parseTodoTxt untuk format yang dikenali.
+func (h *ImportHandler) ImportTodoTxt(c *gin.Context) {
+	_, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	report := &ImportReport{Skipped: []ImportSkip{}}
+	projects, err := parseTodoTxt(data, importLocation(c), report)
+	if errors.Is(err, ErrImportTooLarge) {
+		importError(c, err)
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.save(c, projects, report)
+}
+
+func (h *ImportHandler) save(c *gin.Context, projects []ImportProject, report *ImportReport) {
+	if err := h.Service.Import(c.Request.Context(), projects, report); err != nil {
+		importError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// readImportFile membaca multipart "file" ke memori, maksimal maxImportBytes.
+func readImportFile(c *gin.Context) (string, []byte, bool) {
+	// Ditambah 64 KiB untuk header multipart di luar isi file.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes+64<<10)
+	file, header, err := c.Request.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || (err == nil && header.Size > maxImportBytes) {
+		if file != nil {
+			file.Close()
+		}
+		problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("import file must be at most %d bytes", maxImportBytes))
+		return "", nil, false
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, "multipart field \"file\" is required")
+		return "", nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		internalError(c, err)
+		return "", nil, false
+	}
+	return header.Filename, data, true
+}
+
+// importLocation adalah zona waktu preferensi user, dipakai untuk tanggal
+// tanpa zona waktu di file import.
+func importLocation(c *gin.Context) *time.Location {
+	if location, err := time.LoadLocation(currentUser(c).Preferences.Timezone); err == nil {
+		return location
+	}
+	return time.UTC
+}
+
+func importError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrImportTooLarge):
+		problem(c, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TaskInclude menentukan relasi task yang dimuat dan dikirim ke client.
+type TaskInclude struct {
+	Subtasks     bool
+	Tags         bool
+	Project      bool
+	Dependencies bool
+	Attachments  bool
+	CustomFields bool
+	// Owner dan Assignee hanya dipakai GraphQL; relasinya tidak dikirim di JSON.
+	Owner    bool
+	Assignee bool
+}
+
+// defaultTaskInclude dipakai jika ?include= tidak dikirim, sama dengan respons sebelum ada ?include=.
+var defaultTaskInclude = TaskInclude{Subtasks: true, Tags: true, Dependencies: true, Attachments: true, CustomFields: true}
+
+// parseInclude membaca ?include=tags,subtasks,project,dependencies,attachments,custom_fields. Jika
+// parameter dikirim, hanya relasi yang disebut yang dimuat; ?include= kosong
+// berarti tanpa relasi.
+func parseInclude(c *gin.Context) (TaskInclude, error) {
+	value, ok := c.GetQuery("include")
+	if !ok {
+		return defaultTaskInclude, nil
+	}
+
+	var include TaskInclude
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "subtasks":
+			include.Subtasks = true
+		case "tags":
+			include.Tags = true
+		case "project":
+			include.Project = true
+		case "dependencies":
+			include.Dependencies = true
+		case "attachments":
+			include.Attachments = true
+		case "custom_fields":
+			include.CustomFields = true
+		default:
+			return include, fmt.Errorf("invalid include %q: must be a list of subtasks, tags, project, dependencies, attachments, custom_fields", value)
+		}
+	}
+	return include, nil
+}
+
+func (i TaskInclude) preload(tx *gorm.DB) *gorm.DB {
+	if i.Subtasks {
+		tx = tx.Preload("Subtasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		})
+	}
+	if i.Tags {
+		tx = tx.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		})
+	}
+	if i.Project {
+		tx = tx.Preload("Project")
+	}
+	if i.Attachments {
+		tx = tx.Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		})
+	}
+	if i.CustomFields {
+		tx = tx.Preload("CustomFields", func(db *gorm.DB) *gorm.DB {
+			return db.Order("field_id")
+		})
+	}
+	if i.Owner {
+		tx = tx.Preload("User")
+	}
+	if i.Assignee {
+		tx = tx.Preload("Assignee")
+	}
+	return tx
+}
+
+// relationFields memetakan field JSON relasi ke apakah relasi tersebut diminta.
+func (i TaskInclude) relationFields() map[string]bool {
+	return map[string]bool{
+		"subtasks":      i.Subtasks,
+		"tags":          i.Tags,
+		"project":       i.Project,
+		"blocked_by":    i.Dependencies,
+		"blocks":        i.Dependencies,
+		"attachments":   i.Attachments,
+		"custom_fields": i.CustomFields,
+	}
+}
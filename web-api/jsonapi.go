@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const jsonAPIMediaType = "application/vnd.api+json"
+
+// jsonAPITypes menimpa nama type JSON:API yang dibentuk dari nama struct.
+var jsonAPITypes = map[reflect.Type]string{
+	reflect.TypeOf(TaskRef{}): "tasks",
+}
+
+// renderJSONAPI mengubah respons JSON menjadi dokumen JSON:API
+// (data/included/meta atau errors). Bentuk resource dibaca dari Response
+// operasinya.
+func renderJSONAPI(op apiOperation, status int, body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	if status >= http.StatusBadRequest {
+		document = jsonAPIErrors(status, value)
+	} else {
+		document = jsonAPIDocument(op.Response, value)
+	}
+	document["jsonapi"] = map[string]any{"version": "1.1"}
+	return json.Marshal(document)
+}
+
+// jsonAPIErrors mengubah body Problem menjadi objek errors: satu per field
+// error dengan source.pointer, atau satu untuk seluruh problem. Member
+// tambahan (misalnya blocked_by) dipindah ke meta.
+func jsonAPIErrors(status int, value any) map[string]any {
+	object, _ := value.(map[string]any)
+	base := map[string]any{"status": strconv.Itoa(status), "title": http.StatusText(status)}
+	if title, ok := object["title"].(string); ok {
+		base["title"] = title
+	}
+	meta := map[string]any{}
+	for key, value := range object {
+		switch key {
+		case "type", "title", "status", "instance", "errors":
+		case "detail":
+			base["detail"] = value
+		default:
+			meta[key] = value
+		}
+	}
+	if len(meta) > 0 {
+		base["meta"] = meta
+	}
+
+	fields, _ := object["errors"].([]any)
+	if len(fields) == 0 {
+		return map[string]any{"errors": []any{base}}
+	}
+	errs := make([]any, 0, len(fields))
+	for _, item := range fields {
+		field, _ := item.(map[string]any)
+		err := make(map[string]any, len(base)+1)
+		for key, value := range base {
+			err[key] = value
+		}
+		err["detail"] = field["message"]
+		if name, ok := field["field"].(string); ok {
+			err["source"] = map[string]any{"pointer": jsonPointer(name)}
+		}
+		errs = append(errs, err)
+	}
+	return map[string]any{"errors": errs}
+}
+
+// jsonPointer mengubah nama field seperti items[0].title menjadi
+// /items/0/title.
+func jsonPointer(field string) string {
+	field = strings.NewReplacer("[", ".", "]", "").Replace(field)
+	return "/" + strings.ReplaceAll(field, ".", "/")
+}
+
+// jsonAPIDocument membentuk dokumen dari body sukses dengan schema Response
+// operasinya. Struct resource (punya field id) menjadi data. Untuk gin.H,
+// field berisi daftar resource (atau resource pertama jika tidak ada daftar)
+// menjadi data, resource lain masuk included, dan sisanya ke meta.
+func jsonAPIDocument(schema, value any) map[string]any {
+	object, _ := value.(map[string]any)
+	if options, ok := schema.(oneOf); ok {
+		schema = matchOneOf(options, object)
+	}
+
+	builder := &jsonAPIBuilder{seen: map[string]bool{}}
+	document := map[string]any{}
+	meta := map[string]any{}
+	if fields, ok := schema.(jsonObject); ok {
+		primary := jsonAPIPrimaryKey(fields, object)
+		if primary != "" {
+			t, _ := jsonAPIResource(reflect.TypeOf(fields[primary]))
+			document["data"] = builder.primary(t, object[primary])
+		}
+		for _, key := range sortedKeys(object) {
+			if key == primary {
+				continue
+			}
+			if t, ok := jsonAPIResource(reflect.TypeOf(fields[key])); ok {
+				builder.include(t, object[key])
+			} else if nested, ok := object[key].(map[string]any); ok && key == "meta" {
+				for name, v := range nested {
+					meta[name] = v
+				}
+			} else {
+				meta[key] = object[key]
+			}
+		}
+	} else if t, ok := jsonAPIResource(reflect.TypeOf(schema)); ok && object != nil {
+		document["data"] = builder.primary(t, object)
+	} else if object != nil {
+		meta = object
+	}
+
+	if len(builder.included) > 0 {
+		document["included"] = builder.included
+	}
+	if len(meta) > 0 || document["data"] == nil {
+		document["meta"] = meta
+	}
+	return document
+}
+
+// matchOneOf memilih bentuk yang paling banyak field-nya ada di object.
+func matchOneOf(options oneOf, object map[string]any) any {
+	var best any
+	bestCount := -1
+	for _, option := range options {
+		count := 0
+		if fields, ok := option.(jsonObject); ok {
+			for key := range fields {
+				if _, ok := object[key]; ok {
+					count++
+				}
+			}
+		}
+		if count > bestCount {
+			best, bestCount = option, count
+		}
+	}
+	return best
+}
+
+func jsonAPIPrimaryKey(fields jsonObject, object map[string]any) string {
+	var single string
+	for _, key := range sortedKeys(object) {
+		t := reflect.TypeOf(fields[key])
+		if _, ok := jsonAPIResource(t); !ok {
+			continue
+		}
+		if t.Kind() == reflect.Slice {
+			return key
+		}
+		if single == "" {
+			single = key
+		}
+	}
+	return single
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonAPIBuilder mengumpulkan included tanpa duplikat type dan id.
+type jsonAPIBuilder struct {
+	included []any
+	seen     map[string]bool
+}
+
+func (b *jsonAPIBuilder) primary(t reflect.Type, value any) any {
+	switch value := value.(type) {
+	case []any:
+		data := make([]any, 0, len(value))
+		for _, item := range value {
+			if object, ok := item.(map[string]any); ok {
+				b.seen[jsonAPIKey(t, object)] = true
+				data = append(data, b.resource(t, object))
+			}
+		}
+		return data
+	case map[string]any:
+		b.seen[jsonAPIKey(t, value)] = true
+		return b.resource(t, value)
+	default:
+		return nil
+	}
+}
+
+func (b *jsonAPIBuilder) include(t reflect.Type, value any) {
+	objects, _ := value.([]any)
+	if object, ok := value.(map[string]any); ok {
+		objects = []any{object}
+	}
+	for _, item := range objects {
+		object, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		key := jsonAPIKey(t, object)
+		if b.seen[key] {
+			continue
+		}
+		b.seen[key] = true
+		b.included = append(b.included, b.resource(t, object))
+	}
+}
+
+// resource membentuk resource object. Field berisi resource lain dan foreign
+// key seperti project_id (jika struct punya field Project) menjadi
+// relationships; field lain menjadi attributes.
+func (b *jsonAPIBuilder) resource(t reflect.Type, object map[string]any) map[string]any {
+	fields := jsonAPIFields(t)
+	attributes := map[string]any{}
+	relationships := map[string]any{}
+	var resourceLinks map[string]any
+	for key, value := range object {
+		if key == "id" {
+			continue
+		}
+		if key == "_links" {
+			if links := jsonAPILinks(value); links != nil {
+				resourceLinks = links
+			}
+			continue
+		}
+		field, ok := fields[key]
+		if !ok {
+			attributes[key] = value
+			continue
+		}
+		if related, ok := jsonAPIResource(field.Type); ok {
+			// Relasi to-many yang kosong tetap berupa array.
+			if value == nil && field.Type.Kind() == reflect.Slice {
+				value = []any{}
+			}
+			relationships[key] = map[string]any{"data": jsonAPILinkage(related, value)}
+			b.include(related, value)
+		} else if name, related, ok := jsonAPIForeignKey(t, field, key); ok {
+			relationships[name] = map[string]any{"data": jsonAPILinkage(related, value)}
+		} else {
+			attributes[key] = value
+		}
+	}
+
+	resource := map[string]any{"type": jsonAPITypeName(t), "id": jsonAPIID(object["id"])}
+	if len(attributes) > 0 {
+		resource["attributes"] = attributes
+	}
+	if len(relationships) > 0 {
+		resource["relationships"] = relationships
+	}
+	if resourceLinks != nil {
+		resource["links"] = resourceLinks
+	}
+	return resource
+}
+
+// jsonAPILinks mengubah _links menjadi links JSON:API; method yang bukan GET
+// disimpan di meta link-nya.
+func jsonAPILinks(value any) map[string]any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	links := make(map[string]any, len(object))
+	for name, value := range object {
+		link, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		if method, ok := link["method"]; ok {
+			links[name] = map[string]any{"href": link["href"], "meta": map[string]any{"method": method}}
+		} else {
+			links[name] = link["href"]
+		}
+	}
+	return links
+}
+
+// jsonAPIForeignKey mengenali field XxxID yang punya pasangan field Xxx
+// berisi resource, misalnya AssigneeID dan Assignee *User.
+func jsonAPIForeignKey(t reflect.Type, field reflect.StructField, key string) (string, reflect.Type, bool) {
+	name, ok := strings.CutSuffix(field.Name, "ID")
+	if !ok || name == "" || !strings.HasSuffix(key, "_id") {
+		return "", nil, false
+	}
+	target, ok := t.FieldByName(name)
+	if !ok {
+		return "", nil, false
+	}
+	related, ok := jsonAPIResource(target.Type)
+	return strings.TrimSuffix(key, "_id"), related, ok
+}
+
+func jsonAPILinkage(t reflect.Type, value any) any {
+	switch value := value.(type) {
+	case []any:
+		data := make([]any, 0, len(value))
+		for _, item := range value {
+			data = append(data, jsonAPILinkage(t, item))
+		}
+		return data
+	case map[string]any:
+		return map[string]any{"type": jsonAPITypeName(t), "id": jsonAPIID(value["id"])}
+	case nil:
+		return nil
+	default:
+		// Nilai foreign key.
+		return map[string]any{"type": jsonAPITypeName(t), "id": jsonAPIID(value)}
+	}
+}
+
+func jsonAPIKey(t reflect.Type, object map[string]any) string {
+	return jsonAPITypeName(t) + ":" + jsonAPIID(object["id"])
+}
+
+func jsonAPIID(value any) string {
+	switch value := value.(type) {
+	case json.Number:
+		return value.String()
+	case string:
+		return value
+	default:
+		return ""
+	}
+}
+
+// jsonAPIResource mengembalikan struct dari t (boleh pointer atau slice) jika
+// struct itu punya field json id.
+func jsonAPIResource(t reflect.Type) (reflect.Type, bool) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	_, ok := jsonAPIFields(t)["id"]
+	return t, ok
+}
+
+// jsonAPIFields memetakan nama JSON ke field struct, dengan struct embedded
+// digabung seperti encoding/json.
+func jsonAPIFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for key, embedded := range jsonAPIFields(field.Type) {
+				fields[key] = embedded
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// jsonAPITypeName membentuk type dari nama struct: Task menjadi tasks,
+// APIKey menjadi api_keys.
+func jsonAPITypeName(t reflect.Type) string {
+	if name, ok := jsonAPITypes[t]; ok {
+		return name
+	}
+	singular := snakeCase(t.Name())
+	switch {
+	case strings.HasSuffix(singular, "y") && !strings.ContainsAny(singular[len(singular)-2:len(singular)-1], "aeiou"):
+		return strings.TrimSuffix(singular, "y") + "ies"
+	case strings.HasSuffix(singular, "s") || strings.HasSuffix(singular, "x") || strings.HasSuffix(singular, "ch") || strings.HasSuffix(singular, "sh"):
+		return singular + "es"
+	default:
+		return singular + "s"
+	}
+}
+
+// snakeCase mengubah nama Go seperti APIKey menjadi api_key.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var snake strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			snake.WriteByte('_')
+		}
+		snake.WriteRune(unicode.ToLower(r))
+	}
+	return snake.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Link adalah satu tautan di _links. Method kosong berarti GET.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// Links berisi tautan ke aksi dan resource terkait, dengan path di bawah
+// apiV1Prefix, supaya client tidak perlu menyusun URL sendiri.
+type Links map[string]Link
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+	t.Links = t.links()
+	return json.Marshal(task(t))
+}
+
+func (t *Task) links() Links {
+	if t.ID == 0 {
+		return nil
+	}
+	self := fmt.Sprintf("%s/tasks/%d", apiV1Prefix, t.ID)
+	// Task di trash hanya bisa dipulihkan.
+	if t.DeletedAt.Valid {
+		return Links{"restore": {Href: fmt.Sprintf("%s/trash/%d/restore", apiV1Prefix, t.ID), Method: http.MethodPost}}
+	}
+
+	links := Links{
+		"self":     {Href: self},
+		"update":   {Href: self, Method: http.MethodPatch},
+		"comments": {Href: self + "/comments"},
+	}
+	if t.Status == StatusDone {
+		links["reopen"] = Link{Href: self + "/reopen", Method: http.MethodPost}
+	} else {
+		links["complete"] = Link{Href: self + "/complete", Method: http.MethodPost}
+	}
+	if t.ProjectID != nil {
+		links["project"] = Link{Href: fmt.Sprintf("%s/projects/%d", apiV1Prefix, *t.ProjectID)}
+	}
+	return links
+}
+
+func (p Project) MarshalJSON() ([]byte, error) {
+	type project Project
+	p.Links = p.links()
+	return json.Marshal(project(p))
+}
+
+func (p *Project) links() Links {
+	if p.ID == 0 {
+		return nil
+	}
+	self := fmt.Sprintf("%s/projects/%d", apiV1Prefix, p.ID)
+	links := Links{
+		"self":   {Href: self},
+		"update": {Href: self, Method: http.MethodPut},
+		"tasks":  {Href: self + "/tasks"},
+	}
+	if p.ArchivedAt == nil {
+		links["archive"] = Link{Href: self + "/archive", Method: http.MethodPost}
+	} else {
+		links["unarchive"] = Link{Href: self + "/unarchive", Method: http.MethodPost}
+	}
+	return links
+}
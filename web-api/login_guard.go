@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoginMaxFailures      = 5
+	defaultLoginMaxFailuresPerIP = 20
+	defaultLoginLockout          = 15 * time.Minute
+	// loginLockoutBase adalah jeda setelah batas gagal pertama kali terlampaui;
+	// setiap kegagalan berikutnya menggandakannya sampai Lockout.
+	loginLockoutBase = time.Second
+)
+
+type loginFailures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// LoginGuard memperlambat tebakan password. Setelah MaxFailures kegagalan
+// berturut-turut untuk satu email, atau MaxFailuresPerIP dari satu IP, login
+// ditolak selama 1 detik, lalu 2, 4, dan seterusnya sampai paling lama Lockout.
+// Catatan kegagalan hilang setelah tidak ada kegagalan baru selama Lockout.
+// Seperti RateLimiter, datanya disimpan di memori per instance server.
+type LoginGuard struct {
+	MaxFailures      int
+	MaxFailuresPerIP int
+	Lockout          time.Duration
+
+	mu       sync.Mutex
+	failures map[string]*loginFailures
+}
+
+func accountKey(email string) string { return "account:" + normalizeEmail(email) }
+
+// ipKey dipanggil dengan c.ClientIP(), yang hanya membaca X-Forwarded-For dari
+// TRUSTED_PROXIES, sehingga client tidak bisa mengganti IP lewat header. IPv6
+// dihitung per /64 karena satu client biasanya memegang seluruh prefix itu.
+func ipKey(ip string) string {
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Is6() && !addr.Is4In6() {
+		prefix, _ := addr.Prefix(64)
+		return "ip:" + prefix.String()
+	}
+	return "ip:" + ip
+}
+
+// RetryAfter mengembalikan sisa waktu kunci untuk email atau IP; 0 berarti
+// login boleh dicoba.
+func (g *LoginGuard) RetryAfter(email, ip string, now time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		if f := g.lookup(key, now); f != nil && f.lockedUntil.After(now) {
+			wait = max(wait, f.lockedUntil.Sub(now))
+		}
+	}
+	return wait
+}
+
+// Fail mencatat satu login gagal. Email yang tidak terdaftar ikut dihitung
+// supaya penguncian tidak membocorkan email mana yang ada.
+func (g *LoginGuard) Fail(email, ip string, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.failures == nil {
+		g.failures = map[string]*loginFailures{}
+	}
+	g.fail(accountKey(email), g.MaxFailures, now)
+	g.fail(ipKey(ip), g.MaxFailuresPerIP, now)
+}
+
+// Succeed menghapus catatan kegagalan email setelah login berhasil. Catatan
+// IP tetap ada supaya satu akun milik penyerang tidak bisa mereset hitungan
+// tebakan ke akun lain.
+func (g *LoginGuard) Succeed(email string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, accountKey(email))
+}
+
+func (g *LoginGuard) fail(key string, limit int, now time.Time) {
+	if limit <= 0 {
+		return
+	}
+	f := g.lookup(key, now)
+	if f == nil {
+		f = &loginFailures{}
+		g.failures[key] = f
+	}
+	f.count++
+	f.last = now
+	if over := f.count - limit; over >= 0 {
+		delay := g.Lockout
+		if over < 32 {
+			delay = min(loginLockoutBase<<over, g.Lockout)
+		}
+		f.lockedUntil = now.Add(delay)
+	}
+}
+
+// lookup mengembalikan catatan key, atau nil jika belum ada atau sudah lewat
+// masa berlakunya (catatan lama sekalian dihapus).
+func (g *LoginGuard) lookup(key string, now time.Time) *loginFailures {
+	f, ok := g.failures[key]
+	if !ok {
+		return nil
+	}
+	if now.Sub(f.last) > g.Lockout && now.After(f.lockedUntil) {
+		delete(g.failures, key)
+		return nil
+	}
+	return f
+}
+
+// parseLoginGuard membaca LOGIN_MAX_FAILURES, LOGIN_MAX_FAILURES_PER_IP
+// (0 mematikan batas tersebut), dan LOGIN_LOCKOUT.
+func parseLoginGuard() (*LoginGuard, error) {
+	perAccount, err := parseCountEnv("LOGIN_MAX_FAILURES", defaultLoginMaxFailures)
+	if err != nil {
+		return nil, err
+	}
+	perIP, err := parseCountEnv("LOGIN_MAX_FAILURES_PER_IP", defaultLoginMaxFailuresPerIP)
+	if err != nil {
+		return nil, err
+	}
+	lockout, err := parseDurationEnv("LOGIN_LOCKOUT", defaultLoginLockout)
+	if err != nil {
+		return nil, err
+	}
+	if lockout < loginLockoutBase {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT %q: must be at least %s", lockout, loginLockoutBase)
+	}
+	return &LoginGuard{MaxFailures: perAccount, MaxFailuresPerIP: perIP, Lockout: lockout}, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginGuardAccountBackoff(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := &LoginGuard{MaxFailures: 3, Lockout: 10 * time.Second}
+
+	// Jeda mulai setelah kegagalan ke-3, lalu berlipat dua sampai Lockout.
+	wantWaits := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, want := range wantWaits {
+		guard.Fail("User@Example.com", "198.51.100.1", now)
+		if got := guard.RetryAfter("user@example.com", "198.51.100.2", now); got != want {
+			t.Errorf("after failure %d: RetryAfter = %s, want %s", i+1, got, want)
+		}
+	}
+
+	if got := guard.RetryAfter("other@example.com", "198.51.100.2", now); got != 0 {
+		t.Errorf("other account is locked for %s", got)
+	}
+	if got := guard.RetryAfter("user@example.com", "198.51.100.2", now.Add(10*time.Second)); got != 0 {
+		t.Errorf("lock did not expire: RetryAfter = %s", got)
+	}
+
+	guard.Succeed("USER@example.com")
+	if got := guard.RetryAfter("user@example.com", "198.51.100.2", now); got != 0 {
+		t.Errorf("Succeed did not clear the account: RetryAfter = %s", got)
+	}
+}
+
+func TestLoginGuardPerIP(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := &LoginGuard{MaxFailures: 100, MaxFailuresPerIP: 3, Lockout: time.Minute}
+
+	// Password spraying: setiap akun hanya dicoba sekali dari IP yang sama.
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		guard.Fail(email, "198.51.100.1", now)
+	}
+	if got := guard.RetryAfter("d@example.com", "198.51.100.1", now); got != time.Second {
+		t.Errorf("spraying IP: RetryAfter = %s, want 1s", got)
+	}
+	if got := guard.RetryAfter("d@example.com", "198.51.100.2", now); got != 0 {
+		t.Errorf("other IP is locked for %s", got)
+	}
+
+	// Login berhasil ke akun milik penyerang tidak mereset hitungan IP.
+	guard.Succeed("a@example.com")
+	if got := guard.RetryAfter("d@example.com", "198.51.100.1", now); got != time.Second {
+		t.Errorf("Succeed reset the IP: RetryAfter = %s, want 1s", got)
+	}
+}
+
+func TestLoginGuardIPv6Prefix(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := &LoginGuard{MaxFailures: 100, MaxFailuresPerIP: 2, Lockout: time.Minute}
+
+	guard.Fail("a@example.com", "2001:db8:1:2::1", now)
+	guard.Fail("b@example.com", "2001:db8:1:2:ffff::9", now)
+	if got := guard.RetryAfter("c@example.com", "2001:db8:1:2::abcd", now); got != time.Second {
+		t.Errorf("same /64: RetryAfter = %s, want 1s", got)
+	}
+	if got := guard.RetryAfter("c@example.com", "2001:db8:1:3::1", now); got != 0 {
+		t.Errorf("other /64 is locked for %s", got)
+	}
+}
+
+func TestLoginGuardDisabled(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := &LoginGuard{Lockout: time.Minute}
+	for range 50 {
+		guard.Fail("a@example.com", "198.51.100.1", now)
+	}
+	if got := guard.RetryAfter("a@example.com", "198.51.100.1", now); got != 0 {
+		t.Errorf("limits of 0 still locked for %s", got)
+	}
+}
+
+func TestLoginGuardForgetsOldFailures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := &LoginGuard{MaxFailures: 2, Lockout: time.Minute}
+	guard.Fail("a@example.com", "198.51.100.1", now)
+	guard.Fail("a@example.com", "198.51.100.1", now.Add(2*time.Minute))
+	if got := guard.RetryAfter("a@example.com", "198.51.100.1", now.Add(2*time.Minute)); got != 0 {
+		t.Errorf("failure older than Lockout still counted: RetryAfter = %s", got)
+	}
+}
+
+func TestIPKey(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"198.51.100.7", "ip:198.51.100.7"},
+		{"2001:db8::1", "ip:2001:db8::/64"},
+		{"2001:db8:0:0:ffff:ffff:ffff:ffff", "ip:2001:db8::/64"},
+		{"::ffff:198.51.100.7", "ip:::ffff:198.51.100.7"},
+		{"", "ip:"},
+	}
+	for _, tt := range tests {
+		if got := ipKey(tt.ip); got != tt.want {
+			t.Errorf("ipKey(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Email adalah pesan teks sederhana yang dikirim lewat Mailer.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Interface untuk pengiriman email
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// SMTPMailer mengirim email lewat server SMTP memakai PLAIN auth jika Username diisi.
+type SMTPMailer struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, email Email) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{email.To}, formatEmail(m.From, email))
+}
+
+// LogMailer hanya menulis email ke log. Dipakai saat SMTP_ADDR tidak di-set (development).
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, email Email) error {
+	log.Printf("mail to %s: %s\n%s", email.To, email.Subject, email.Body)
+	return nil
+}
+
+// sendInBackground mengirim email lewat goroutine; kegagalan hanya dicatat ke log.
+func sendInBackground(mailer Mailer, email Email) {
+	go func() {
+		if err := mailer.Send(context.Background(), email); err != nil {
+			log.Printf("sending %q to %s: %v", email.Subject, email.To, err)
+		}
+	}()
+}
+
+// newMailer memilih SMTPMailer jika SMTP_ADDR di-set, selain itu LogMailer.
+func newMailer() Mailer {
+	addr := getEnv("SMTP_ADDR", "")
+	if addr == "" {
+		log.Println("SMTP_ADDR is not set; emails will be written to the log")
+		return LogMailer{}
+	}
+	return &SMTPMailer{
+		Addr:     addr,
+		Username: getEnv("SMTP_USERNAME", ""),
+		Password: getEnv("SMTP_PASSWORD", ""),
+		From:     getEnv("MAIL_FROM", "todolist@localhost"),
+	}
+}
+
+func formatEmail(from string, email Email) []byte {
+	// Header tidak boleh berisi baris baru supaya tidak bisa disisipi header lain.
+	clean := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", clean.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", clean.Replace(email.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", clean.Replace(email.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
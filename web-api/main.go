@@ -0,0 +1,560 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var shortGolang = "Watch Go crash course"
+var fullGolang = "Watch Nana's Golang Full Course"
+var rewardDessert = "Reward myself with a donut"
+
+const defaultDSN = "host=localhost user=postgres password=yourpassword dbname=testdb port=5432 sslmode=disable TimeZone=Asia/Jakarta"
+
+func main() {
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	// Konfigurasi koneksi PostgreSQL, bisa di-override lewat DATABASE_URL
+	dsn := getEnv("DATABASE_URL", defaultDSN)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	if err := migrate(db); err != nil {
+		log.Fatalf("failed to migrate database: %v", err)
+	}
+
+	undoWindow, err := parseUndoWindow()
+	if err != nil {
+		log.Fatal(err)
+	}
+	taskService := &TaskServiceImpl{DB: db, UndoWindow: undoWindow}
+	if err := taskService.SeedTasks(context.Background(), shortGolang, fullGolang, rewardDessert); err != nil {
+		log.Fatalf("failed to seed tasks: %v", err)
+	}
+
+	scheduler := NewRecurrenceScheduler(db, time.Minute)
+	go scheduler.Run(context.Background())
+
+	allowPrivateWebhooks, err := strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE", "false"))
+	if err != nil {
+		log.Fatalf("invalid WEBHOOK_ALLOW_PRIVATE %q", getEnv("WEBHOOK_ALLOW_PRIVATE", ""))
+	}
+	dispatcher := &WebhookDispatcher{DB: db, Client: newWebhookClient(allowPrivateWebhooks), Interval: 5 * time.Second}
+	go dispatcher.Run(context.Background())
+
+	webPush, err := newWebPush(newWebhookClient(allowPrivateWebhooks))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if webPush != nil {
+		pushDispatcher := &PushDispatcher{DB: db, Push: webPush, Interval: 5 * time.Second}
+		go pushDispatcher.Run(context.Background())
+	}
+	taskStream := &TaskStreamHub{DB: db, Interval: time.Second}
+	go taskStream.Run(context.Background())
+	pushHandler := &PushHandler{Service: &PushSubscriptionServiceImpl{DB: db}, Push: webPush}
+
+	taskHandler := &TaskHandler{Service: taskService, Scheduler: scheduler}
+	subtaskHandler := &SubtaskHandler{Service: &SubtaskServiceImpl{DB: db}}
+	commentHandler := &CommentHandler{Service: &CommentServiceImpl{DB: db}}
+	historyHandler := &TaskEventHandler{Service: &TaskEventServiceImpl{DB: db}}
+	watcherHandler := &WatcherHandler{Service: &WatcherServiceImpl{DB: db}}
+	notificationHandler := &NotificationHandler{Service: &NotificationServiceImpl{DB: db}}
+	undoHandler := &UndoHandler{Service: &UndoServiceImpl{DB: db}}
+	templateHandler := &TemplateHandler{Service: &TaskTemplateServiceImpl{DB: db}}
+	customFieldHandler := &CustomFieldHandler{Service: &CustomFieldServiceImpl{DB: db}}
+	webhookHandler := &WebhookHandler{Service: &WebhookServiceImpl{DB: db}}
+	slackSigningSecret := getEnv("SLACK_SIGNING_SECRET", "")
+	tagHandler := &TagHandler{Service: &TagServiceImpl{DB: db}}
+	filterHandler := &FilterHandler{Service: &SavedFilterServiceImpl{DB: db}, Tasks: taskService}
+	workspaceService := &WorkspaceServiceImpl{DB: db}
+	workspaceHandler := &WorkspaceHandler{Service: workspaceService}
+	baseURL := getEnv("APP_BASE_URL", "http://localhost:8080")
+	slackConfig := newSlackConfig(baseURL)
+	slackHandler := &SlackHandler{
+		Service:       &SlackServiceImpl{DB: db, Config: slackConfig},
+		Workspaces:    workspaceService,
+		Tasks:         taskService,
+		SigningSecret: slackSigningSecret,
+	}
+	permissionHandler := &PermissionHandler{Service: &PermissionServiceImpl{DB: db}}
+	accessTTL, err := parseDurationEnv("JWT_ACCESS_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	secret := getEnv("JWT_SECRET", "")
+	if secret == "" {
+		log.Println("JWT_SECRET is not set; using a random secret, tokens will not survive a restart")
+	}
+	tokens, err := NewTokenService(secret, accessTTL)
+	if err != nil {
+		log.Fatalf("failed to create token service: %v", err)
+	}
+
+	refreshTTL, err := parseDurationEnv("JWT_REFRESH_TTL", defaultRefreshTokenTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resetTTL, err := parseDurationEnv("PASSWORD_RESET_TTL", defaultPasswordResetTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	verificationTTL, err := parseDurationEnv("EMAIL_VERIFICATION_TTL", defaultVerificationTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	verification, err := parseVerificationMode(getEnv("EMAIL_VERIFICATION", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	invitationTTL, err := parseDurationEnv("WORKSPACE_INVITATION_TTL", defaultInvitationTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	userRateLimit, err := parseRateLimitEnv("RATE_LIMIT", defaultRateLimit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	anonymousRateLimit, err := parseRateLimitEnv("RATE_LIMIT_ANONYMOUS", defaultAnonymousRateLimit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passwordPolicy, err := parsePasswordPolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	loginGuard, err := parseLoginGuard()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	maxAttachmentBytes, err := parseCountEnv("ATTACHMENT_MAX_BYTES", defaultMaxAttachmentBytes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentStore, err := newAttachmentStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sessionTTL, err := parseDurationEnv("SESSION_TTL", defaultSessionTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authMode, err := parseAuthMode(getEnv("AUTH_MODE", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projectHandler := &ProjectHandler{Service: &ProjectServiceImpl{DB: db}, Tasks: taskService, BaseURL: baseURL}
+	avatarDir := getEnv("AVATAR_DIR", "uploads/avatars")
+	attachmentHandler := &AttachmentHandler{
+		Service:  &AttachmentServiceImpl{DB: db},
+		Files:    attachmentStore,
+		MaxBytes: int64(maxAttachmentBytes),
+	}
+	exportHandler := &ExportHandler{Service: &ExportServiceImpl{DB: db}}
+	importHandler := &ImportHandler{Service: &ImportServiceImpl{DB: db}}
+	trashHandler := &TrashHandler{Service: &TrashServiceImpl{DB: db}, Files: attachmentStore}
+	trashRetention, err := parseTrashRetention()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if trashRetention > 0 {
+		purger := &TrashPurger{DB: db, Files: attachmentStore, Retention: trashRetention, Interval: time.Hour}
+		go purger.Run(context.Background())
+	}
+	userService := &UserServiceImpl{DB: db}
+	graphQLHandler := NewGraphQLHandler(&graphQLResolver{
+		Tasks:     taskService,
+		Projects:  &ProjectServiceImpl{DB: db},
+		Tags:      &TagServiceImpl{DB: db},
+		Users:     userService,
+		Scheduler: scheduler,
+	})
+	apiKeyService := &APIKeyServiceImpl{DB: db}
+	sessionService := &SessionServiceImpl{DB: db, TTL: sessionTTL}
+	apiKeyHandler := &APIKeyHandler{Service: apiKeyService}
+	authHandler := &AuthHandler{
+		Users:    userService,
+		Tokens:   tokens,
+		Refresh:  &RefreshTokenServiceImpl{DB: db, TTL: refreshTTL},
+		Resets:   &PasswordResetServiceImpl{DB: db, TTL: resetTTL},
+		Mailer:   newMailer(),
+		ResetURL: getEnv("PASSWORD_RESET_URL", ""),
+
+		Verifications: &EmailVerificationServiceImpl{DB: db, TTL: verificationTTL},
+		Verification:  verification,
+		BaseURL:       baseURL,
+
+		TwoFactor: &TwoFactorServiceImpl{DB: db},
+		Issuer:    getEnv("TOTP_ISSUER", "Todolist"),
+		OAuth:     &OAuthServiceImpl{DB: db, Users: userService},
+		Providers: newOAuthProviders(baseURL),
+		Mode:      authMode,
+		Sessions:  sessionService,
+		Avatars:   &LocalFileStore{Dir: avatarDir},
+		Events:    &AuthEventServiceImpl{DB: db},
+		Passwords: passwordPolicy,
+		Logins:    loginGuard,
+	}
+
+	invitationHandler := &InvitationHandler{
+		Service: &WorkspaceInvitationServiceImpl{DB: db, TTL: invitationTTL},
+		Mailer:  authHandler.Mailer,
+		URL:     getEnv("INVITATION_URL", ""),
+	}
+	telegramBot := newTelegramBot()
+	telegramHandler := &TelegramHandler{
+		Service: &TelegramServiceImpl{DB: db},
+		Tasks:   taskService,
+		Bot:     telegramBot,
+		Secret:  getEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+	}
+	if telegramBot != nil && telegramHandler.Secret == "" {
+		log.Fatal("TELEGRAM_WEBHOOK_SECRET is required when TELEGRAM_BOT_TOKEN is set")
+	}
+	reminderLead, err := parseReminderLead()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if reminderLead > 0 {
+		reminders := &ReminderScheduler{DB: db, Mailer: authHandler.Mailer, Telegram: telegramBot, Push: webPush, Lead: reminderLead, Interval: time.Minute}
+		go reminders.Run(context.Background())
+	}
+	digestHour, err := parseDigestHour()
+	if err != nil {
+		log.Fatal(err)
+	}
+	digests := &DigestScheduler{DB: db, Mailer: authHandler.Mailer, Hour: digestHour, Interval: 5 * time.Minute}
+	go digests.Run(context.Background())
+	shareLinkHandler := &ShareLinkHandler{Service: &ShareLinkServiceImpl{DB: db}, BaseURL: baseURL}
+	calendarHandler := &CalendarHandler{Service: &CalendarServiceImpl{DB: db}, BaseURL: baseURL}
+	caldavHandler := &CalDAVHandler{Service: &CalDAVServiceImpl{DB: db, Tasks: taskService, Host: icsHost(baseURL)}}
+	googleTasksConfig := newGoogleTasksConfig(baseURL)
+	googleTasksHandler := &GoogleTasksHandler{
+		Service: &GoogleTasksServiceImpl{DB: db, Tasks: taskService, Config: googleTasksConfig, APIURL: googleTasksAPIURL},
+	}
+	adminHandler := &AdminHandler{
+		Users:     userService,
+		TwoFactor: authHandler.TwoFactor,
+		Avatars:   authHandler.Avatars,
+	}
+
+	useJSONFieldNames()
+	router := gin.Default()
+	if err := router.SetTrustedProxies(parseTrustedProxies()); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	router.NoRoute(problemNotFound)
+	router.Use(etagMiddleware(), negotiateResponse(), captureClientInfo())
+
+	// Route tanpa login dibatasi per IP; route lain per user atau API key.
+	limiter := &RateLimiter{Authenticated: userRateLimit, Anonymous: anonymousRateLimit}
+	limit := rateLimit(limiter)
+	public := router.Group("", limit)
+	public.GET("/", helloUser)
+	docsHandler := &DocsHandler{}
+	public.GET("/openapi.json", docsHandler.OpenAPI)
+	public.GET("/docs", docsHandler.SwaggerUI)
+	public.Static("/avatars", avatarDir)
+	public.GET("/auth/oauth/:provider", authHandler.StartOAuth)
+	public.GET("/auth/oauth/:provider/callback", authHandler.OAuthCallback)
+	public.GET(shareLinkPath+":token", shareLinkHandler.ShowSharedProject)
+	public.GET(calendarPath, calendarHandler.ShowCalendar)
+	// Slash command hanya aktif jika signing secret app Slack di-set.
+	if slackSigningSecret != "" {
+		public.POST("/integrations/slack/commands", slackHandler.Command)
+	}
+	if slackConfig != nil {
+		public.GET(slackCallbackPath, slackHandler.Callback)
+	}
+	if telegramBot != nil {
+		public.POST("/integrations/telegram/webhook", telegramHandler.Webhook)
+	}
+	if googleTasksConfig != nil {
+		public.GET(googleTasksCallbackPath, googleTasksHandler.Callback)
+	}
+
+	// Semua route di bawah ini membutuhkan access token atau API key.
+	authenticate := requireAuth(tokens, userService, apiKeyService, sessionService)
+	verified := requireVerifiedEmail(verification)
+
+	// CalDAV login dengan email dan API key lewat HTTP Basic.
+	public.GET("/.well-known/caldav", caldavHandler.WellKnown)
+	public.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
+	caldav := router.Group(caldavPath, requireCalDAVAuth(apiKeyService), limit, verified)
+	for _, method := range caldavMethods {
+		caldav.Handle(method, "/*path", caldavHandler.Serve)
+	}
+
+	// Route API diberi versi di bawah apiV1Prefix supaya perubahan yang tidak
+	// kompatibel bisa dirilis sebagai /api/v2 tanpa mengganggu client v1. URL
+	// yang disimpan pihak luar (OAuth, share link, feed, CalDAV, webhook
+	// integrasi) tetap di root di atas.
+	registerV1 := func(base *gin.RouterGroup) {
+		public := base.Group("", limit)
+		public.POST("/auth/register", authHandler.Register)
+		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/refresh", authHandler.RefreshTokens)
+		public.POST("/auth/logout", authHandler.Logout)
+		public.POST("/auth/forgot-password", authHandler.ForgotPassword)
+		public.POST("/auth/reset-password", authHandler.ResetPassword)
+		public.GET("/auth/verify", authHandler.VerifyEmail)
+		public.POST("/auth/resend-verification", authHandler.ResendVerification)
+
+		// Mengelola kredensial butuh scope admin jika memakai API key.
+		account := base.Group("", authenticate, limit, verified, requireScope(ScopeAdmin))
+		account.GET("/me", authHandler.GetMe)
+		account.PUT("/me", authHandler.UpdateMe)
+		account.DELETE("/me", authHandler.DeleteMe)
+		account.PUT("/me/avatar", authHandler.UploadAvatar)
+		account.DELETE("/me/avatar", authHandler.DeleteAvatar)
+		account.GET("/me/security-events", authHandler.ListSecurityEvents)
+		account.GET("/export", exportHandler.Export)
+		account.GET("/me/calendar", calendarHandler.GetFeed)
+		account.POST("/me/calendar", calendarHandler.CreateFeed)
+		account.DELETE("/me/calendar", calendarHandler.DeleteFeed)
+		if telegramBot != nil {
+			account.GET("/me/telegram", telegramHandler.GetLink)
+			account.POST("/me/telegram/link", telegramHandler.CreateLinkCode)
+			account.DELETE("/me/telegram", telegramHandler.Unlink)
+		}
+		if googleTasksConfig != nil {
+			account.GET("/me/google-tasks", googleTasksHandler.GetConnection)
+			account.POST("/me/google-tasks/connect", googleTasksHandler.Connect)
+			account.DELETE("/me/google-tasks", googleTasksHandler.Disconnect)
+			account.POST("/me/google-tasks/sync", googleTasksHandler.Sync)
+		}
+		if webPush != nil {
+			account.GET("/me/push/key", pushHandler.PublicKey)
+			account.GET("/me/push/subscriptions", pushHandler.ListSubscriptions)
+			account.POST("/me/push/subscriptions", pushHandler.Subscribe)
+			account.DELETE("/me/push/subscriptions/:id", pushHandler.Unsubscribe)
+		}
+		account.POST("/auth/logout-all", authHandler.LogoutAll)
+		account.GET("/auth/csrf", authHandler.CSRFToken)
+		account.GET("/auth/2fa", authHandler.ShowTwoFactor)
+		account.POST("/auth/2fa/enroll", authHandler.EnrollTwoFactor)
+		account.POST("/auth/2fa/confirm", authHandler.ConfirmTwoFactor)
+		account.POST("/auth/2fa/backup-codes", authHandler.RegenerateBackupCodes)
+		account.POST("/auth/2fa/disable", authHandler.DisableTwoFactor)
+
+		account.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+		account.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+		account.DELETE("/api-keys/:id", apiKeyHandler.DeleteAPIKey)
+
+		// Endpoint manajemen hanya untuk user dengan role admin.
+		admin := base.Group("/admin", authenticate, limit, verified, requireScope(ScopeAdmin), requireRole(RoleAdmin))
+		admin.GET("/users", adminHandler.ListUsers)
+		admin.GET("/users/:id", adminHandler.GetUser)
+		admin.DELETE("/users/:id", adminHandler.DeleteUser)
+		admin.PUT("/users/:id/role", adminHandler.SetUserRole)
+		admin.POST("/users/:id/disable", adminHandler.DisableUser)
+		admin.POST("/users/:id/enable", adminHandler.EnableUser)
+		admin.DELETE("/users/:id/2fa", adminHandler.ResetTwoFactor)
+		admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+
+		workspaces := base.Group("/workspaces", authenticate, limit, verified, requireTaskScope())
+		workspaces.GET("", workspaceHandler.ListWorkspaces)
+		workspaces.POST("", workspaceHandler.CreateWorkspace)
+		workspaces.POST("/invitations/accept", invitationHandler.AcceptInvitation)
+		member := requireWorkspaceRole(workspaceService, WorkspaceViewer)
+		owner := requireWorkspaceRole(workspaceService, WorkspaceOwner)
+		workspaces.GET("/:id", member, workspaceHandler.GetWorkspace)
+		workspaces.PUT("/:id", owner, workspaceHandler.UpdateWorkspace)
+		workspaces.DELETE("/:id", owner, workspaceHandler.DeleteWorkspace)
+		workspaces.POST("/:id/members", owner, workspaceHandler.AddMember)
+		workspaces.PUT("/:id/members/:user_id", owner, workspaceHandler.UpdateMember)
+		workspaces.DELETE("/:id/members/:user_id", member, workspaceHandler.RemoveMember)
+		workspaces.GET("/:id/invitations", owner, invitationHandler.ListInvitations)
+		workspaces.POST("/:id/invitations", owner, invitationHandler.CreateInvitation)
+		workspaces.DELETE("/:id/invitations/:invitationID", owner, invitationHandler.RevokeInvitation)
+		workspaces.GET("/:id/fields", member, customFieldHandler.ListFields)
+		workspaces.POST("/:id/fields", owner, customFieldHandler.CreateField)
+		workspaces.PUT("/:id/fields/:fieldID", owner, customFieldHandler.UpdateField)
+		workspaces.DELETE("/:id/fields/:fieldID", owner, customFieldHandler.DeleteField)
+		workspaces.GET("/:id/webhooks", owner, webhookHandler.ListWebhooks)
+		workspaces.POST("/:id/webhooks", owner, webhookHandler.CreateWebhook)
+		workspaces.DELETE("/:id/webhooks/:webhookID", owner, webhookHandler.DeleteWebhook)
+		workspaces.GET("/:id/webhooks/:webhookID/deliveries", owner, webhookHandler.ListDeliveries)
+		workspaces.GET("/:id/slack", owner, slackHandler.GetConnection)
+		if slackConfig != nil {
+			workspaces.POST("/:id/slack/connect", owner, slackHandler.Connect)
+		}
+		workspaces.DELETE("/:id/slack", owner, slackHandler.Disconnect)
+
+		// Header X-Workspace-ID memindahkan route di bawah ini ke task dan project workspace.
+		api := base.Group("", authenticate, limit, verified, requireTaskScope(), useWorkspace(workspaceService), shareAccess())
+		api.GET("/show-tasks", taskHandler.ShowTasks)
+		api.GET("/tasks", taskHandler.ShowTasks)
+		api.GET("/tasks/search", taskHandler.SearchTasks)
+		api.GET("/tasks/summary", taskHandler.SummarizeTasks)
+		api.GET("/tasks/agenda", taskHandler.ShowAgenda)
+		api.GET("/tasks/export.csv", taskHandler.ExportTasksCSV)
+		api.GET("/tasks/export.txt", taskHandler.ExportTasksTodoTxt)
+		api.POST("/tasks", taskHandler.CreateTask)
+		api.POST("/tasks/bulk", taskHandler.CreateTasksBulk)
+		api.POST("/tasks/bulk-update", taskHandler.BulkUpdateTasks)
+		api.POST("/tasks/bulk-delete", taskHandler.BulkDeleteTasks)
+		api.POST("/undo", undoHandler.Undo)
+		api.GET("/trash", trashHandler.ListTrash)
+		api.POST("/trash/:id/restore", trashHandler.RestoreTask)
+		api.DELETE("/trash/:id", trashHandler.PurgeTask)
+		api.GET("/tasks/:id", taskHandler.GetTask)
+		api.PUT("/tasks/:id", taskHandler.ReplaceTask)
+		api.PATCH("/tasks/:id", taskHandler.PatchTask)
+		api.DELETE("/tasks/:id", taskHandler.DeleteTask)
+		api.PATCH("/tasks/:id/move", taskHandler.MoveTask)
+		api.POST("/tasks/:id/duplicate", taskHandler.DuplicateTask)
+		api.GET("/tasks/:id/history", historyHandler.ListTaskHistory)
+		api.POST("/tasks/:id/template", templateHandler.CreateTemplateFromTask)
+		api.POST("/tasks/:id/dependencies", taskHandler.AddDependency)
+		api.DELETE("/tasks/:id/dependencies/:blockerID", taskHandler.RemoveDependency)
+
+		api.GET("/tasks/:id/subtasks", subtaskHandler.ListSubtasks)
+		api.POST("/tasks/:id/subtasks", subtaskHandler.CreateSubtask)
+		api.GET("/tasks/:id/subtasks/:subtaskID", subtaskHandler.GetSubtask)
+		api.PATCH("/tasks/:id/subtasks/:subtaskID", subtaskHandler.PatchSubtask)
+		api.DELETE("/tasks/:id/subtasks/:subtaskID", subtaskHandler.DeleteSubtask)
+		api.GET("/tasks/:id/comments", commentHandler.ListComments)
+		api.POST("/tasks/:id/comments", commentHandler.CreateComment)
+		api.PUT("/tasks/:id/comments/:commentID", commentHandler.UpdateComment)
+		api.DELETE("/tasks/:id/comments/:commentID", commentHandler.DeleteComment)
+		api.PUT("/tasks/:id/comments/:commentID/reactions/:emoji", commentHandler.AddReaction)
+		api.DELETE("/tasks/:id/comments/:commentID/reactions/:emoji", commentHandler.RemoveReaction)
+		api.GET("/tasks/:id/watchers", watcherHandler.ListWatchers)
+		api.PUT("/tasks/:id/watch", watcherHandler.Watch)
+		api.DELETE("/tasks/:id/watch", watcherHandler.Unwatch)
+		api.GET("/tasks/:id/attachments", attachmentHandler.ListAttachments)
+		api.POST("/tasks/:id/attachments", attachmentHandler.UploadAttachment)
+		api.GET("/tasks/:id/attachments/:attachmentID", attachmentHandler.DownloadAttachment)
+		api.DELETE("/tasks/:id/attachments/:attachmentID", attachmentHandler.DeleteAttachment)
+		api.PUT("/tasks/:id/fields/:fieldID", customFieldHandler.SetTaskField)
+		api.DELETE("/tasks/:id/fields/:fieldID", customFieldHandler.DeleteTaskField)
+		api.PUT("/tasks/:id/tags/:tagID", tagHandler.AttachTag)
+		api.DELETE("/tasks/:id/tags/:tagID", tagHandler.DetachTag)
+		api.GET("/tasks/:id/permissions", permissionHandler.ListPermissions(taskShareTarget))
+		api.POST("/tasks/:id/permissions", permissionHandler.Share(taskShareTarget))
+		api.DELETE("/tasks/:id/permissions/:user_id", permissionHandler.Unshare(taskShareTarget))
+
+		api.GET("/tags", tagHandler.ListTags)
+		api.POST("/tags", tagHandler.CreateTag)
+		api.GET("/tags/:id", tagHandler.GetTag)
+		api.PUT("/tags/:id", tagHandler.UpdateTag)
+		api.DELETE("/tags/:id", tagHandler.DeleteTag)
+
+		api.GET("/stats", taskHandler.ShowStats)
+
+		api.GET("/notifications", notificationHandler.ListNotifications)
+		api.GET("/notifications/unread-count", notificationHandler.UnreadCount)
+		api.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		api.POST("/notifications/:id/read", notificationHandler.MarkRead)
+
+		api.GET("/projects", projectHandler.ListProjects)
+		api.POST("/projects", projectHandler.CreateProject)
+		api.GET("/projects/:id", projectHandler.GetProject)
+		api.PUT("/projects/:id", projectHandler.UpdateProject)
+		api.DELETE("/projects/:id", projectHandler.DeleteProject)
+		api.POST("/projects/:id/archive", projectHandler.ArchiveProject)
+		api.POST("/projects/:id/unarchive", projectHandler.UnarchiveProject)
+		api.GET("/projects/:id/tasks", projectHandler.ListProjectTasks)
+		api.GET("/projects/:id/feed", projectHandler.ProjectFeed)
+		api.GET("/projects/:id/permissions", permissionHandler.ListPermissions(projectShareTarget))
+		api.POST("/projects/:id/permissions", permissionHandler.Share(projectShareTarget))
+		api.DELETE("/projects/:id/permissions/:user_id", permissionHandler.Unshare(projectShareTarget))
+		api.GET("/projects/:id/share-links", shareLinkHandler.ListShareLinks)
+		api.POST("/projects/:id/share-links", shareLinkHandler.CreateShareLink)
+		api.DELETE("/projects/:id/share-links/:linkID", shareLinkHandler.DeleteShareLink)
+		api.GET("/shared", permissionHandler.ListShared)
+		api.POST("/import/todoist", importHandler.ImportTodoist)
+		api.POST("/import/trello", importHandler.ImportTrello)
+		api.POST("/import/csv", importHandler.ImportCSV)
+		api.POST("/import/todotxt", importHandler.ImportTodoTxt)
+
+		api.GET("/templates", templateHandler.ListTemplates)
+		api.POST("/templates", templateHandler.CreateTemplate)
+		api.GET("/templates/:id", templateHandler.GetTemplate)
+		api.PUT("/templates/:id", templateHandler.UpdateTemplate)
+		api.DELETE("/templates/:id", templateHandler.DeleteTemplate)
+		api.POST("/templates/:id/instantiate", templateHandler.InstantiateTemplate)
+
+		api.GET("/filters", filterHandler.ListFilters)
+		api.POST("/filters", filterHandler.CreateFilter)
+		api.GET("/filters/:id", filterHandler.GetFilter)
+		api.PUT("/filters/:id", filterHandler.UpdateFilter)
+		api.DELETE("/filters/:id", filterHandler.DeleteFilter)
+		api.GET("/filters/:id/tasks", filterHandler.ListFilterTasks)
+		api.POST("/tasks/:id/complete", taskHandler.CompleteTask)
+		api.POST("/tasks/:id/reopen", taskHandler.ReopenTask)
+		api.POST("/tasks/:id/archive", taskHandler.ArchiveTask)
+		api.POST("/tasks/:id/unarchive", taskHandler.UnarchiveTask)
+	}
+	registerV1(router.Group(apiV1Prefix))
+	// Path lama tanpa prefix tetap dilayani sebagai alias v1 yang deprecated.
+	registerV1(router.Group("", deprecatedAlias(apiV1Prefix)))
+
+	// GraphQL, WebSocket, dan SSE baru ada setelah versioning, jadi hanya
+	// dilayani di bawah apiV1Prefix.
+	graphQL := router.Group(apiV1Prefix+"/graphql", authenticate, limit, verified, useGraphQLWorkspace(workspaceService))
+	graphQL.POST("", graphQLHandler.Serve)
+	taskStreamHandler := &TaskStreamHandler{Hub: taskStream}
+	stream := router.Group(apiV1Prefix, authenticate, limit, requireScope(ScopeReadTasks))
+	stream.GET("/ws", taskStreamHandler.WebSocket)
+	stream.GET("/events", taskStreamHandler.Events)
+
+	// Dokumen OpenAPI dibentuk dari route yang sudah terdaftar di atas.
+	if docsHandler.Spec, err = buildOpenAPI(router.Routes(), baseURL); err != nil {
+		log.Fatalf("failed to build openapi document: %v", err)
+	}
+
+	// gRPC untuk pemanggil internal dilayani di port kedua dengan service yang sama.
+	grpcServer := NewGRPCServer(&grpcAuth{
+		Tokens:       tokens,
+		Users:        userService,
+		Keys:         apiKeyService,
+		Workspaces:   workspaceService,
+		Verification: verification,
+	}, limiter, &TodoServer{
+		Tasks:     taskService,
+		Projects:  &ProjectServiceImpl{DB: db},
+		Users:     userService,
+		Scheduler: scheduler,
+	})
+	grpcListener, err := net.Listen("tcp", getEnv("GRPC_ADDR", ":9090"))
+	if err != nil {
+		log.Fatalf("failed to listen for grpc: %v", err)
+	}
+	go grpcServer.Serve(grpcListener)
+
+	router.Run(":8080")
+}
+
+func helloUser(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hello user. Welcome to our Todolist App!",
+	})
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
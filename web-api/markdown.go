@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// renderMarkdown mengubah subset Markdown (paragraf, heading, list termasuk
+// checklist "- [ ]", blockquote, code block berpagar, garis, tebal, miring,
+// coret, kode inline, link, dan URL polos) menjadi HTML. Baris baru di dalam
+// paragraf menjadi <br>, seperti komentar di GitHub. Hasilnya aman
+// ditampilkan apa adanya: semua teks di-escape, HTML mentah di input tidak
+// pernah diteruskan, dan link hanya dibuat untuk skema http, https, dan mailto.
+func renderMarkdown(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	// \x00 dipakai sebagai penanda token inline.
+	src = strings.ReplaceAll(src, "\x00", "�")
+	var out strings.Builder
+	renderBlocks(&out, strings.Split(src, "\n"))
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+var (
+	headingPattern     = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t#]*$`)
+	rulePattern        = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fencePattern       = regexp.MustCompile("^ {0,3}(```+|~~~+)[ \t]*([A-Za-z0-9_+-]*)")
+	bulletPattern      = regexp.MustCompile(`^ {0,3}[-*+][ \t]+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)][ \t]+(.*)$`)
+	quotePattern       = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	checkboxPattern    = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	strongPattern      = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	strongUnderPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_])__(\S(?:.*?\S)?)__($|[^A-Za-z0-9_])`)
+	emPattern          = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`)
+	emUnderPattern     = regexp.MustCompile(`(^|[^A-Za-z0-9_])_(\S(?:.*?\S)?)_($|[^A-Za-z0-9_])`)
+	strikePattern      = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	tokenPattern       = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+func renderBlocks(out *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			m := fencePattern.FindStringSubmatch(line)
+			fence := m[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimLeft(lines[i], " "), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // pagar penutup; jika tidak ada, code block berlanjut sampai akhir
+			if m[2] != "" {
+				fmt.Fprintf(out, "<pre><code class=\"language-%s\">", m[2])
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			for _, l := range code {
+				out.WriteString(html.EscapeString(l) + "\n")
+			}
+			out.WriteString("</code></pre>\n")
+
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			fmt.Fprintf(out, "<h%d>%s</h%d>\n", len(m[1]), renderInline(m[2]), len(m[1]))
+			i++
+
+		case rulePattern.MatchString(line):
+			out.WriteString("<hr>\n")
+			i++
+
+		case quotePattern.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			out.WriteString("<blockquote>\n")
+			renderBlocks(out, quoted)
+			out.WriteString("</blockquote>\n")
+
+		case bulletPattern.MatchString(line), orderedPattern.MatchString(line):
+			i = renderList(out, lines, i)
+
+		default:
+			var paragraph []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			if len(paragraph) == 0 {
+				// Baris yang memulai blok tetapi tidak tertangani di atas.
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+				i++
+			}
+			out.WriteString("<p>" + strings.Join(renderLines(paragraph), "<br>\n") + "</p>\n")
+		}
+	}
+}
+
+// renderList menulis satu list (berurutan atau tidak) mulai dari lines[start]
+// dan mengembalikan indeks baris sesudahnya. Baris yang bukan item baru dan
+// tidak kosong disambung ke item sebelumnya; list bertingkat tidak didukung.
+func renderList(out *strings.Builder, lines []string, start int) int {
+	ordered := orderedPattern.MatchString(lines[start])
+	pattern := bulletPattern
+	if ordered {
+		pattern = orderedPattern
+		first, _ := strconv.Atoi(orderedPattern.FindStringSubmatch(lines[start])[1])
+		if first != 1 {
+			fmt.Fprintf(out, "<ol start=\"%d\">\n", first)
+		} else {
+			out.WriteString("<ol>\n")
+		}
+	} else {
+		out.WriteString("<ul>\n")
+	}
+
+	var items [][]string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := pattern.FindStringSubmatch(line); m != nil {
+			items = append(items, []string{m[len(m)-1]})
+			continue
+		}
+		if strings.TrimSpace(line) == "" || startsBlock(line) {
+			break
+		}
+		last := len(items) - 1
+		items[last] = append(items[last], strings.TrimSpace(line))
+	}
+
+	for _, item := range items {
+		out.WriteString("<li>")
+		if m := checkboxPattern.FindStringSubmatch(item[0]); m != nil {
+			if m[1] == " " {
+				out.WriteString(`<input type="checkbox" disabled> `)
+			} else {
+				out.WriteString(`<input type="checkbox" checked disabled> `)
+			}
+			item[0] = item[0][len(m[0]):]
+		}
+		out.WriteString(strings.Join(renderLines(item), "<br>\n") + "</li>\n")
+	}
+	if ordered {
+		out.WriteString("</ol>\n")
+	} else {
+		out.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func startsBlock(line string) bool {
+	return fencePattern.MatchString(line) || headingPattern.MatchString(line) || rulePattern.MatchString(line) ||
+		quotePattern.MatchString(line) || bulletPattern.MatchString(line) || orderedPattern.MatchString(line)
+}
+
+func renderLines(lines []string) []string {
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		rendered[i] = renderInline(line)
+	}
+	return rendered
+}
+
+// renderInline merender format di dalam satu baris. Kode, link, dan karakter
+// yang di-escape dengan "\" diganti penanda \x00n\x00 dulu supaya isinya tidak
+// ikut diproses sebagai tebal atau miring, lalu dikembalikan di akhir.
+func renderInline(s string) string {
+	var tokens []string
+	token := func(rendered string) string {
+		tokens = append(tokens, rendered)
+		return fmt.Sprintf("\x00%d\x00", len(tokens)-1)
+	}
+
+	var text strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!~>|", rune(rest[1])):
+			text.WriteString(token(html.EscapeString(rest[1:2])))
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:n]
+			if end := strings.Index(rest[n:], fence); end >= 0 {
+				code := strings.TrimSpace(rest[n : n+end])
+				text.WriteString(token("<code>" + html.EscapeString(code) + "</code>"))
+				i += n + end + n
+				continue
+			}
+			text.WriteString(fence)
+			i += n
+			continue
+
+		case rest[0] == '[':
+			if label, href, n, ok := parseLink(rest); ok {
+				text.WriteString(token(linkHTML(href, renderInline(label))))
+				i += n
+				continue
+			}
+
+		case rest[0] == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				if href := rest[1:end]; safeURL(href) && !strings.ContainsAny(href, " \t") {
+					text.WriteString(token(linkHTML(href, html.EscapeString(href))))
+					i += end + 1
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+			if href := bareURL(rest); (i == 0 || !isWordByte(s[i-1])) && safeURL(href) {
+				text.WriteString(token(linkHTML(href, html.EscapeString(href))))
+				i += len(href)
+				continue
+			}
+		}
+		text.WriteByte(s[i])
+		i++
+	}
+
+	rendered := html.EscapeString(text.String())
+	rendered = strongPattern.ReplaceAllString(rendered, "<strong>$1</strong>")
+	rendered = strongUnderPattern.ReplaceAllString(rendered, "$1<strong>$2</strong>$3")
+	rendered = emPattern.ReplaceAllString(rendered, "<em>$1</em>")
+	rendered = emUnderPattern.ReplaceAllString(rendered, "$1<em>$2</em>$3")
+	rendered = strikePattern.ReplaceAllString(rendered, "<del>$1</del>")
+	return tokenPattern.ReplaceAllStringFunc(rendered, func(m string) string {
+		n, _ := strconv.Atoi(strings.Trim(m, "\x00"))
+		return tokens[n]
+	})
+}
+
+// parseLink membaca [label](url) di awal s dan mengembalikan panjangnya.
+// Link dengan skema yang tidak aman dianggap teks biasa.
+func parseLink(s string) (label, href string, n int, ok bool) {
+	closing := strings.Index(s, "](")
+	if closing < 0 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[closing+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	label = s[1:closing]
+	href = strings.TrimSpace(s[closing+2 : closing+2+end])
+	if strings.ContainsAny(href, " \t") || !safeURL(href) {
+		return "", "", 0, false
+	}
+	return label, href, closing + 2 + end + 1, true
+}
+
+func linkHTML(href, label string) string {
+	return `<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">` + label + "</a>"
+}
+
+func safeURL(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+// bareURL mengambil URL polos sampai spasi, tanpa tanda baca penutup kalimat.
+func bareURL(s string) string {
+	end := strings.IndexAny(s, " \t<")
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.TrimRight(s[:end], ".,:;!?'\")]*_~")
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
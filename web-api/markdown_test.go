@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	const rel = ` rel="nofollow noopener noreferrer"`
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"emphasis", "Hello **world** and *you*", "<p>Hello <strong>world</strong> and <em>you</em></p>"},
+		{"strikethrough", "~~gone~~", "<p><del>gone</del></p>"},
+		{"heading", "# Title", "<h1>Title</h1>"},
+		{"rule", "---", "<hr>"},
+		{"line break", "line1\r\nline2", "<p>line1<br>\nline2</p>"},
+		{"checklist", "- [ ] todo\n- [x] done", "<ul>\n<li><input type=\"checkbox\" disabled> todo</li>\n<li><input type=\"checkbox\" checked disabled> done</li>\n</ul>"},
+		{"ordered list", "1. a\n2. b", "<ol>\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"ordered list start", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"blockquote", "> quote", "<blockquote>\n<p>quote</p>\n</blockquote>"},
+		{"fenced code is escaped", "```go\n<b>x</b>\n```", "<pre><code class=\"language-go\">&lt;b&gt;x&lt;/b&gt;\n</code></pre>"},
+		{"inline code is not formatted", "`**x**`", "<p><code>**x**</code></p>"},
+		{"backslash escape", `\*not em\*`, "<p>*not em*</p>"},
+		{"underscores inside words", "snake_case_word", "<p>snake_case_word</p>"},
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com"` + rel + `>site</a></p>`},
+		{"mailto link", "[x](mailto:a@example.com)", `<p><a href="mailto:a@example.com"` + rel + `>x</a></p>`},
+		{"autolink", "<https://example.com>", `<p><a href="https://example.com"` + rel + `>https://example.com</a></p>`},
+		{"bare url without trailing period", "see https://example.com/a?b=1&c=2.", `<p>see <a href="https://example.com/a?b=1&amp;c=2"` + rel + `>https://example.com/a?b=1&amp;c=2</a>.</p>`},
+		{"html in link label", "[<b>label</b>](https://example.com)", `<p><a href="https://example.com"` + rel + `>&lt;b&gt;label&lt;/b&gt;</a></p>`},
+		{"raw html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>"},
+		{"protocol-relative link", "[x](//evil.example)", "<p>[x](//evil.example)</p>"},
+		{"nul is replaced", "a\x00b", "<p>a�b</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.src); got != tt.want {
+				t.Errorf("renderMarkdown(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderMarkdownSanitizes memastikan input berbahaya tidak pernah
+// menghasilkan tag, atribut, atau URL yang bisa menjalankan script.
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	inputs := []string{
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"<a href=\"javascript:alert(1)\">x</a>",
+		"[x](javascript:alert(1))",
+		"[x](JaVaScRiPt:alert(1))",
+		"[x]( javascript:alert(1) )",
+		"[x](data:text/html;base64,PHNjcmlwdD4=)",
+		"[x](vbscript:msgbox(1))",
+		"<javascript:alert(1)>",
+		"<data:text/html,<script>alert(1)</script>>",
+		"[x](https://example.com/\"onmouseover=\"alert(1))",
+		"[x](https://example.com/'onmouseover='alert(1))",
+		"https://example.com/\"><script>alert(1)</script>",
+		"<https://example.com/\"onclick=\"alert(1)>",
+		"```\"><script>alert(1)</script>\n```",
+		"```x\" onload=\"alert(1)\nbody\n```",
+		"`<script>alert(1)</script>`",
+		"**<script>alert(1)</script>**",
+		"# <iframe src=//evil.example>",
+		"> <svg onload=alert(1)>",
+		"- [ ] <img src=x onerror=alert(1)>",
+		"\\<script>alert(1)\\</script>",
+		"[<img src=x onerror=alert(1)>](https://example.com)",
+		"x\x00<script>",
+	}
+	forbidden := []string{"<script", "<img", "<iframe", "<svg", "javascript:", "vbscript:", "data:", `"on`, `'on`, ` on`}
+	for _, src := range inputs {
+		got := renderMarkdown(src)
+		for _, tag := range htmlTags(got) {
+			lower := strings.ToLower(tag)
+			for _, bad := range forbidden {
+				if strings.Contains(lower, bad) {
+					t.Errorf("renderMarkdown(%q) produced tag %q containing %q\nfull output: %q", src, tag, bad, got)
+				}
+			}
+		}
+	}
+}
+
+// htmlTags mengembalikan semua tag di s, misalnya `<a href="...">`. Karena
+// renderMarkdown meng-escape < di teks, setiap < yang tersisa adalah tag.
+func htmlTags(s string) []string {
+	var tags []string
+	for {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			return tags
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end < 0 {
+			return append(tags, s[start:])
+		}
+		tags = append(tags, s[start:start+end+1])
+		s = s[start+end+1:]
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		href string
+		want bool
+	}{
+		{"https://example.com", true},
+		{"HTTP://example.com/a", true},
+		{"mailto:a@example.com", true},
+		{"https://", false},
+		{"mailto:", false},
+		{"//example.com", false},
+		{"/relative", false},
+		{"javascript:alert(1)", false},
+		{"data:text/html,x", false},
+		{"ftp://example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := safeURL(tt.href); got != tt.want {
+			t.Errorf("safeURL(%q) = %v, want %v", tt.href, got, tt.want)
+		}
+	}
+}
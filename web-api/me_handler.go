@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+type updateMeRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email" binding:"required,email"`
+	// Username kosong menghapus username.
+	Username string `json:"username"`
+	// CurrentPassword wajib diisi jika email diganti.
+	CurrentPassword string           `json:"current_password"`
+	Preferences     *UserPreferences `json:"preferences"`
+}
+
+// GetMe mengembalikan profil user yang sedang login.
+func (h *AuthHandler) GetMe(c *gin.Context) {
+	c.JSON(http.StatusOK, currentUser(c))
+}
+
+// UpdateMe mengganti nama, email, dan preferensi user. Preferensi yang tidak
+// dikirim kembali ke default. Email baru harus diverifikasi ulang.
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	var req updateMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if len(req.Name) > 128 {
+		problem(c, http.StatusBadRequest, "name must be at most 128 characters")
+		return
+	}
+	username, err := normalizeUsername(req.Username)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	preferences := defaultUserPreferences
+	if req.Preferences != nil {
+		var err error
+		if preferences, err = normalizePreferences(*req.Preferences); err != nil {
+			problem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	user := currentUser(c)
+	emailChanged := normalizeEmail(req.Email) != user.Email
+	if emailChanged && !checkPassword(user, req.CurrentPassword) {
+		problem(c, http.StatusBadRequest, "current_password is required to change email")
+		return
+	}
+
+	user.Name = req.Name
+	user.Email = req.Email
+	user.Username = username
+	user.Preferences = preferences
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+	}
+	err = h.Users.UpdateUser(c.Request.Context(), user)
+	if errors.Is(err, ErrEmailTaken) || errors.Is(err, ErrUsernameTaken) {
+		problem(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if emailChanged {
+		if err := h.startVerification(c.Request.Context(), user); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ListSecurityEvents menampilkan audit log login, percobaan login gagal,
+// pergantian password, dan pemakaian API key milik user, terbaru dulu.
+func (h *AuthHandler) ListSecurityEvents(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "security events only support limit/offset pagination")
+		return
+	}
+
+	events, total, err := h.Events.ListAuthEvents(c.Request.Context(), page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"meta":   page.Meta(total, nil),
+	})
+}
+
+// normalizePreferences memvalidasi preferensi; nilai kosong diganti default.
+func normalizePreferences(p UserPreferences) (UserPreferences, error) {
+	p.Timezone = strings.TrimSpace(p.Timezone)
+	if p.Timezone == "" {
+		p.Timezone = defaultUserPreferences.Timezone
+	}
+	// "Local" ditolak karena artinya bergantung pada zona server.
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "Local" || len(p.Timezone) > 64 {
+		return p, fmt.Errorf("invalid timezone %q", p.Timezone)
+	}
+
+	p.Locale = strings.TrimSpace(p.Locale)
+	if p.Locale == "" {
+		p.Locale = defaultUserPreferences.Locale
+	}
+	tag, err := language.Parse(p.Locale)
+	if err != nil || len(p.Locale) > 35 {
+		return p, fmt.Errorf("invalid locale %q", p.Locale)
+	}
+	p.Locale = tag.String()
+	return p, nil
+}
+
+// deleteMeRequest berisi konfirmasi hapus akun: Password, atau Email untuk
+// akun OAuth yang tidak punya password, ditambah Code jika 2FA aktif.
+type deleteMeRequest struct {
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Code     string `json:"code"`
+}
+
+// DeleteMe menghapus akun beserta semua task, project, tag, filter, session,
+// dan avatar user. Password harus dikirim ulang sebagai konfirmasi.
+func (h *AuthHandler) DeleteMe(c *gin.Context) {
+	var req deleteMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	user := currentUser(c)
+	confirmed := checkPassword(user, req.Password)
+	if user.PasswordHash == "" {
+		confirmed = req.Email != "" && normalizeEmail(req.Email) == user.Email
+	}
+	if !confirmed {
+		problem(c, http.StatusBadRequest, "password (or email for accounts without a password) is required to delete the account")
+		return
+	}
+	if user.TwoFactorEnabled() {
+		if err := h.TwoFactor.VerifyCode(c.Request.Context(), user.ID, req.Code, time.Now()); err != nil {
+			twoFactorError(c, err)
+			return
+		}
+	}
+
+	if err := h.Users.DeleteUser(c.Request.Context(), user.ID); err != nil {
+		internalError(c, err)
+		return
+	}
+	deleteAvatarFile(c.Request.Context(), h.Avatars, user.AvatarURL)
+	h.clearSessionCookie(c)
+
+	c.Status(http.StatusNoContent)
+}
+
+// avatarURLPrefix adalah path tempat file avatar disajikan (lihat main.go).
+const avatarURLPrefix = "/avatars/"
+
+// UploadAvatar menerima file multipart "avatar", mengubahnya menjadi PNG
+// persegi berukuran avatarSize, lalu mengganti avatar lama.
+func (h *AuthHandler) UploadAvatar(c *gin.Context) {
+	// Ditambah 64 KiB untuk header multipart di luar isi file.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAvatarBytes+64<<10)
+	file, _, err := c.Request.FormFile("avatar")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		problem(c, http.StatusRequestEntityTooLarge, "avatar must be at most 2 MiB")
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusBadRequest, "multipart field \"avatar\" is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxAvatarBytes+1))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if len(data) > maxAvatarBytes {
+		problem(c, http.StatusRequestEntityTooLarge, "avatar must be at most 2 MiB")
+		return
+	}
+	avatar, err := processAvatar(data)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := currentUser(c)
+	suffix, err := randomToken(9)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	name := fmt.Sprintf("%d-%s.png", user.ID, suffix)
+	if err := h.Avatars.Save(c.Request.Context(), name, bytes.NewReader(avatar)); err != nil {
+		internalError(c, err)
+		return
+	}
+	if !h.replaceAvatar(c, user, avatarURLPrefix+name) {
+		h.Avatars.Delete(c.Request.Context(), name)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandler) DeleteAvatar(c *gin.Context) {
+	if !h.replaceAvatar(c, currentUser(c), "") {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// replaceAvatar menyimpan URL avatar baru lalu menghapus file avatar lama.
+func (h *AuthHandler) replaceAvatar(c *gin.Context, user *User, url string) bool {
+	if err := h.Users.SetAvatar(c.Request.Context(), user.ID, url); err != nil {
+		internalError(c, err)
+		return false
+	}
+
+	deleteAvatarFile(c.Request.Context(), h.Avatars, user.AvatarURL)
+	user.AvatarURL = url
+	return true
+}
+
+// deleteAvatarFile menghapus file di balik URL avatar. Kegagalan hanya dicatat
+// di log karena data user sudah tersimpan.
+func deleteAvatarFile(ctx context.Context, store FileStore, url string) {
+	name, ok := strings.CutPrefix(url, avatarURLPrefix)
+	if !ok {
+		return
+	}
+	if err := store.Delete(ctx, name); err != nil {
+		log.Printf("deleting avatar %s: %v", name, err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// mentionPattern mencari @username yang tidak didahului huruf atau angka,
+// supaya alamat email seperti budi@example.com tidak dianggap mention.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+
+// parseMentions mengembalikan username unik yang di-mention di body. Titik
+// atau tanda hubung di akhir (misalnya akhir kalimat) tidak ikut.
+func parseMentions(body string) []string {
+	seen := map[string]bool{}
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if !seen[username] && usernamePattern.MatchString(username) {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// notifyMentions membuat notifikasi untuk user yang di-mention di komentar dan
+// bisa melihat task-nya. Username yang sudah ada di previous (isi komentar
+// sebelum diedit), username yang tidak ada, penulis sendiri, dan user tanpa
+// akses ke task dilewati tanpa error supaya keberadaan akun tidak bocor.
+func notifyMentions(tx *gorm.DB, comment *Comment, previous string) error {
+	already := map[string]bool{}
+	for _, username := range parseMentions(previous) {
+		already[username] = true
+	}
+	var usernames []string
+	for _, username := range parseMentions(comment.Body) {
+		if !already[username] {
+			usernames = append(usernames, username)
+		}
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	var task Task
+	if err := tx.Select("id", "user_id", "workspace_id", "project_id").First(&task, comment.TaskID).Error; err != nil {
+		return err
+	}
+	var users []User
+	if err := tx.Select("id").Where("username IN ? AND id <> ?", usernames, comment.UserID).Find(&users).Error; err != nil {
+		return err
+	}
+
+	var notifications []Notification
+	for _, user := range users {
+		ok, err := canSeeTask(tx, &task, user.ID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			notifications = append(notifications, Notification{
+				UserID:    user.ID,
+				Type:      NotificationMention,
+				ActorID:   &comment.UserID,
+				TaskID:    &comment.TaskID,
+				CommentID: &comment.ID,
+			})
+		}
+	}
+	return notify(tx, notifications)
+}
+
+// canSeeTask mengecek userID bisa membuka task: member workspace untuk task
+// workspace, atau pemilik dan penerima share (task atau project-nya) untuk
+// task pribadi. Aturannya sama dengan accessibleTasks, tetapi untuk user lain.
+func canSeeTask(tx *gorm.DB, task *Task, userID uint) (bool, error) {
+	db := tx.Session(&gorm.Session{NewDB: true})
+	var count int64
+	if task.WorkspaceID != nil {
+		err := db.Model(&WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", *task.WorkspaceID, userID).
+			Count(&count).Error
+		return count > 0, err
+	}
+	if task.UserID != nil && *task.UserID == userID {
+		return true, nil
+	}
+
+	query := db.Model(&TaskPermission{}).Where("user_id = ?", userID)
+	if task.ProjectID != nil {
+		query = query.Where("task_id = ? OR project_id = ?", task.ID, *task.ProjectID)
+	} else {
+		query = query.Where("task_id = ?", task.ID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
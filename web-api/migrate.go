@@ -0,0 +1,57 @@
+package main
+
+import "gorm.io/gorm"
+
+// migrate menjalankan AutoMigrate, memindahkan data dari kolom lama, lalu
+// menambah kolom yang tidak bisa dibuat AutoMigrate.
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&User{}, &Workspace{}, &WorkspaceMember{}, &WorkspaceInvitation{}, &Project{}, &Task{}, &Subtask{}, &Comment{}, &CommentReaction{}, &TaskWatcher{}, &TaskEvent{}, &UndoAction{}, &Attachment{}, &TaskDependency{}, &Tag{}, &SavedFilter{}, &TaskTemplate{}, &TemplateSubtask{}, TaskTemplate{}, &TemplateSubtask{}, &CustomField{}, &CustomFieldValue{}, &RefreshToken{}, &PasswordResetToken{}, &EmailVerificationToken{}, &BackupCode{}, &OAuthIdentity{}, &APIKey{}, &Session{}, &TaskPermission{}, &ShareLink{}, &AuthEvent{}, &Notification{}, &Webhook{}, &WebhookDelivery{}, &SlackConnection{}, &SlackAuthState{}, &TelegramLink{}, &TelegramLinkCode{}, &PushSubscription{}, &CalendarFeed{}, &GoogleTasksConnection{}, &GoogleTasksAuthState{}, &GoogleTaskLink{}, &CalDAVResource{}, &TaskStreamEvent{}); err != nil {
+		return err
+	}
+	if err := migrateDoneToStatus(db); err != nil {
+		return err
+	}
+	if err := dropLegacyIndexes(db); err != nil {
+		return err
+	}
+	if err := ensureAdminExists(db); err != nil {
+		return err
+	}
+	return migrateTaskSearch(db)
+}
+
+// migrateDoneToStatus mengubah kolom boolean done menjadi kolom status lalu menghapusnya.
+func migrateDoneToStatus(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&Task{}, "done") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE tasks SET status = ? WHERE done = ?", StatusDone, true).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&Task{}, "done")
+	})
+}
+
+// dropLegacyIndexes menghapus unique index nama tag dan saved filter yang
+// global; sekarang nama hanya unik per user.
+func dropLegacyIndexes(db *gorm.DB) error {
+	legacy := []struct {
+		model any
+		index string
+	}{
+		{&Tag{}, "idx_tags_name"},
+		{&SavedFilter{}, "idx_saved_filters_name"},
+	}
+	for _, l := range legacy {
+		if !db.Migrator().HasIndex(l.model, l.index) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(l.model, l.index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
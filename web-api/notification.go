@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationType adalah alasan sebuah notifikasi dibuat.
+type NotificationType string
+
+const (
+	// NotificationMention dibuat saat user di-mention di komentar.
+	NotificationMention NotificationType = "mention"
+	// NotificationStatusChange dibuat untuk watcher saat status task berubah.
+	NotificationStatusChange NotificationType = "status_change"
+	// NotificationComment dibuat untuk watcher saat ada komentar baru di task.
+	NotificationComment NotificationType = "comment"
+	// NotificationAssignment dibuat saat task di-assign ke user oleh orang lain.
+	NotificationAssignment NotificationType = "assignment"
+	// NotificationDueReminder dibuat ReminderScheduler saat task hampir jatuh tempo.
+	NotificationDueReminder NotificationType = "due_reminder"
+)
+
+var notificationTypes = []NotificationType{NotificationMention, NotificationStatusChange, NotificationComment, NotificationAssignment, NotificationDueReminder}
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Notification adalah pemberitahuan untuk UserID tentang kejadian yang
+// melibatkannya. ActorID adalah user yang memicunya; nilainya NULL jika akun
+// tersebut sudah dihapus. PushedAt diisi PushDispatcher saat notifikasi sudah
+// dikirim lewat Web Push. ActorName dan TaskTitle diisi saat notifikasi
+// dimuat lewat withNotificationContext.
+type Notification struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"-" gorm:"not null;index"`
+	User      *User            `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Type      NotificationType `json:"type" gorm:"type:varchar(32);not null"`
+	ActorID   *uint            `json:"actor_id"`
+	Actor     *User            `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	TaskID    *uint            `json:"task_id,omitempty" gorm:"index"`
+	Task      *Task            `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CommentID *uint            `json:"comment_id,omitempty"`
+	Comment   *Comment         `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	ReadAt    *time.Time       `json:"read_at"`
+	PushedAt  *time.Time       `json:"-"`
+	CreatedAt time.Time        `json:"created_at"`
+	ActorName string           `json:"actor_name" gorm:"->;-:migration"`
+	TaskTitle string           `json:"task_title" gorm:"->;-:migration"`
+}
+
+// notify menyimpan notifikasi di transaksi yang sama dengan kejadiannya.
+func notify(tx *gorm.DB, notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return tx.Create(&notifications).Error
+}
+
+// notifyAssignee memberi notifikasi ke assignee baru task (before nil untuk
+// task baru), kecuali jika ia sendiri yang meng-assign.
+func notifyAssignee(tx *gorm.DB, before, after *Task) error {
+	if after.AssigneeID == nil {
+		return nil
+	}
+	if before != nil && before.AssigneeID != nil && *before.AssigneeID == *after.AssigneeID {
+		return nil
+	}
+	actor := ownerID(tx.Statement.Context)
+	if *after.AssigneeID == actor {
+		return nil
+	}
+	notification := Notification{UserID: *after.AssigneeID, Type: NotificationAssignment, TaskID: &after.ID}
+	if actor != 0 {
+		notification.ActorID = &actor
+	}
+	return notify(tx.Session(&gorm.Session{NewDB: true}), []Notification{notification})
+}
+
+// ParseNotificationTypes membaca ?type= berupa daftar tipe dipisah koma.
+func ParseNotificationTypes(value string) ([]NotificationType, error) {
+	var types []NotificationType
+	for _, part := range strings.Split(value, ",") {
+		kind := NotificationType(strings.ToLower(strings.TrimSpace(part)))
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(notificationTypes, kind) {
+			return nil, fmt.Errorf("invalid type %q: must be one of mention, assignment, due_reminder, comment, status_change", part)
+		}
+		types = append(types, kind)
+	}
+	return types, nil
+}
+
+// NotificationFilter membatasi daftar notifikasi; nilai kosong berarti semua.
+type NotificationFilter struct {
+	UnreadOnly bool
+	Types      []NotificationType
+}
+
+func (f NotificationFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.UnreadOnly {
+		db = db.Where("notifications.read_at IS NULL")
+	}
+	if len(f.Types) > 0 {
+		db = db.Where("notifications.type IN ?", f.Types)
+	}
+	return db
+}
+
+// NotificationPage adalah satu halaman hasil ListNotifications.
+type NotificationPage struct {
+	Notifications []Notification
+	Total         int64
+	Next          *Cursor
+}
+
+// Interface untuk layanan notifikasi
+type NotificationService interface {
+	ListNotifications(ctx context.Context, filter NotificationFilter, page Page) (*NotificationPage, error)
+	UnreadCount(ctx context.Context) (int64, error)
+	MarkRead(ctx context.Context, id uint) (*Notification, error)
+	MarkAllRead(ctx context.Context) (int64, error)
+}
+
+// Struct implementasi NotificationService dengan GORM
+type NotificationServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListNotifications mengembalikan notifikasi user di context dari yang
+// terbaru. Mode cursor memakai urutan yang sama.
+func (s *NotificationServiceImpl) ListNotifications(ctx context.Context, filter NotificationFilter, page Page) (*NotificationPage, error) {
+	db := s.DB.WithContext(ctx)
+	result := &NotificationPage{}
+	if err := filter.apply(db.Model(&Notification{}).Scopes(ownedByUser)).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	query := filter.apply(withNotificationContext(db).Where("notifications.user_id = ?", ownerID(ctx)))
+	if page.CursorMode {
+		if page.Cursor != nil {
+			query = query.Where("notifications.created_at < ? OR (notifications.created_at = ? AND notifications.id < ?)",
+				page.Cursor.CreatedAt, page.Cursor.CreatedAt, page.Cursor.ID)
+		}
+		query = query.Limit(page.Limit + 1)
+	} else {
+		query = query.Limit(page.Limit).Offset(page.Offset)
+	}
+	if err := query.Order("notifications.created_at DESC, notifications.id DESC").Find(&result.Notifications).Error; err != nil {
+		return nil, err
+	}
+	if page.CursorMode && len(result.Notifications) > page.Limit {
+		result.Notifications = result.Notifications[:page.Limit]
+		last := result.Notifications[len(result.Notifications)-1]
+		result.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+func (s *NotificationServiceImpl) UnreadCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&Notification{}).Scopes(ownedByUser).Where("read_at IS NULL").Count(&count).Error
+	return count, err
+}
+
+// MarkRead menandai satu notifikasi sudah dibaca. Notifikasi yang sudah
+// dibaca tidak diubah read_at-nya.
+func (s *NotificationServiceImpl) MarkRead(ctx context.Context, id uint) (*Notification, error) {
+	db := s.DB.WithContext(ctx)
+	err := db.Model(&Notification{}).Scopes(ownedByUser).
+		Where("id = ? AND read_at IS NULL", id).
+		UpdateColumn("read_at", time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+	var notification Notification
+	err = withNotificationContext(db).Where("notifications.user_id = ?", ownerID(ctx)).First(&notification, "notifications.id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead menandai semua notifikasi yang belum dibaca dan mengembalikan jumlahnya.
+func (s *NotificationServiceImpl) MarkAllRead(ctx context.Context) (int64, error) {
+	result := s.DB.WithContext(ctx).Model(&Notification{}).Scopes(ownedByUser).
+		Where("read_at IS NULL").
+		UpdateColumn("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// withNotificationContext menambahkan nama pemicu dan judul task ke query
+// notifikasi. Task di trash tetap disertakan judulnya.
+func withNotificationContext(db *gorm.DB) *gorm.DB {
+	return db.Model(&Notification{}).
+		Select("notifications.*, COALESCE(a.name, '') AS actor_name, COALESCE(t.title, '') AS task_title").
+		Joins("LEFT JOIN users a ON a.id = notifications.actor_id").
+		Joins("LEFT JOIN tasks t ON t.id = notifications.task_id")
+}
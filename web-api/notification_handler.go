@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler berisi HTTP handler untuk /notifications.
+type NotificationHandler struct {
+	Service NotificationService
+}
+
+// ListNotifications mengembalikan notifikasi user dari yang terbaru. Filter:
+// ?unread=true dan ?type= (daftar tipe dipisah koma). Pagination sama dengan
+// GET /tasks, termasuk mode cursor.
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	var filter NotificationFilter
+	if value := c.Query("unread"); value != "" {
+		if filter.UnreadOnly, err = strconv.ParseBool(value); err != nil {
+			problem(c, http.StatusBadRequest, fmt.Sprintf("invalid unread %q", value))
+			return
+		}
+	}
+	if filter.Types, err = ParseNotificationTypes(c.Query("type")); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.Service.ListNotifications(c.Request.Context(), filter, page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": result.Notifications,
+		"meta":          page.Meta(result.Total, result.Next),
+	})
+}
+
+// UnreadCount dipakai client untuk badge tanpa memuat daftar notifikasi.
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	count, err := h.Service.UnreadCount(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	notification, err := h.Service.MarkRead(c.Request.Context(), uint(id))
+	if errors.Is(err, ErrNotificationNotFound) {
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	marked, err := h.Service.MarkAllRead(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+	"gorm.io/gorm"
+)
+
+var ErrOAuthEmailUnverified = errors.New("the provider did not return a verified email address")
+
+// OAuthProfile adalah data user yang dibaca dari provider setelah login.
+type OAuthProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OAuthProvider membungkus konfigurasi OAuth2 satu provider dan cara membaca profilnya.
+type OAuthProvider struct {
+	Name    string
+	Config  *oauth2.Config
+	profile func(ctx context.Context, client *http.Client) (*OAuthProfile, error)
+}
+
+func (p *OAuthProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*OAuthProfile, error) {
+	return p.profile(ctx, p.Config.Client(ctx, token))
+}
+
+// newOAuthProviders membuat provider yang client ID dan secret-nya di-set.
+// Redirect URL selalu <baseURL>/auth/oauth/<provider>/callback.
+func newOAuthProviders(baseURL string) map[string]*OAuthProvider {
+	providers := make(map[string]*OAuthProvider)
+	add := func(name, prefix string, endpoint oauth2.Endpoint, scopes []string, profile func(context.Context, *http.Client) (*OAuthProfile, error)) {
+		id, secret := getEnv(prefix+"_CLIENT_ID", ""), getEnv(prefix+"_CLIENT_SECRET", "")
+		if id == "" || secret == "" {
+			return
+		}
+		providers[name] = &OAuthProvider{
+			Name: name,
+			Config: &oauth2.Config{
+				ClientID:     id,
+				ClientSecret: secret,
+				Endpoint:     endpoint,
+				RedirectURL:  strings.TrimSuffix(baseURL, "/") + "/auth/oauth/" + name + "/callback",
+				Scopes:       scopes,
+			},
+			profile: profile,
+		}
+	}
+	add("google", "GOOGLE", endpoints.Google, []string{"openid", "email", "profile"}, googleProfile)
+	add("github", "GITHUB", endpoints.GitHub, []string{"read:user", "user:email"}, githubProfile)
+	return providers
+}
+
+func googleProfile(ctx context.Context, client *http.Client) (*OAuthProfile, error) {
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := getJSON(ctx, client, "https://openidconnect.googleapis.com/v1/userinfo", &info); err != nil {
+		return nil, err
+	}
+	return &OAuthProfile{Subject: info.Sub, Email: info.Email, EmailVerified: info.EmailVerified, Name: info.Name}, nil
+}
+
+// githubProfile memakai email utama yang sudah diverifikasi dari /user/emails,
+// karena email di /user bisa kosong atau tidak terverifikasi.
+func githubProfile(ctx context.Context, client *http.Client) (*OAuthProfile, error) {
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user", &user); err != nil {
+		return nil, err
+	}
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user/emails", &emails); err != nil {
+		return nil, err
+	}
+
+	profile := &OAuthProfile{Subject: strconv.FormatInt(user.ID, 10), Name: user.Name}
+	if profile.Name == "" {
+		profile.Name = user.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			profile.Email, profile.EmailVerified = e.Email, e.Verified
+		}
+	}
+	return profile, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// OAuthIdentity menghubungkan akun di provider (provider + subject) dengan user lokal.
+type OAuthIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;index"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Provider  string    `json:"provider" gorm:"type:varchar(32);not null;uniqueIndex:idx_oauth_identities_provider_subject,priority:1"`
+	Subject   string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_oauth_identities_provider_subject,priority:2"`
+	Email     string    `json:"email" gorm:"type:varchar(254);not null;default:''"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Interface untuk layanan login lewat OAuth
+type OAuthService interface {
+	SignIn(ctx context.Context, provider string, profile *OAuthProfile) (*User, error)
+}
+
+// Struct implementasi OAuthService dengan GORM
+type OAuthServiceImpl struct {
+	DB    *gorm.DB
+	Users UserService
+}
+
+// SignIn mencari user dari identity yang sudah terhubung. Jika belum ada,
+// identity dihubungkan ke user dengan email yang sama, atau user baru dibuat
+// (tanpa password). Email hanya dipakai jika provider menyatakan sudah
+// terverifikasi, supaya akun orang lain tidak bisa diambil alih.
+func (s *OAuthServiceImpl) SignIn(ctx context.Context, provider string, profile *OAuthProfile) (*User, error) {
+	db := s.DB.WithContext(ctx)
+	var identity OAuthIdentity
+	err := db.Preload("User").
+		Where("provider = ? AND subject = ?", provider, profile.Subject).
+		First(&identity).Error
+	if err == nil {
+		return identity.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if profile.Email == "" || !profile.EmailVerified {
+		return nil, ErrOAuthEmailUnverified
+	}
+
+	user, err := s.Users.GetUserByEmail(ctx, profile.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		now := time.Now()
+		user = &User{Name: profile.Name, Email: profile.Email, EmailVerifiedAt: &now}
+		err = s.Users.CreateUser(ctx, user)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	identity = OAuthIdentity{UserID: user.ID, Provider: provider, Subject: profile.Subject, Email: normalizeEmail(profile.Email)}
+	if err := db.Create(&identity).Error; err != nil {
+		return nil, err
+	}
+	if !user.EmailVerified() {
+		if err := s.takeOverUnverified(db, user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// takeOverUnverified menandai email user terverifikasi oleh provider. Password,
+// 2FA, dan sesi lama dihapus karena akun yang belum diverifikasi bisa saja didaftarkan
+// orang lain dengan email ini sebelum pemiliknya login lewat provider.
+func (s *OAuthServiceImpl) takeOverUnverified(db *gorm.DB, user *User) error {
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(user).Updates(map[string]any{
+			"email_verified_at": now,
+			"password_hash":     "",
+			"totp_secret":       "",
+			"totp_enabled_at":   nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return revokeCredentials(tx, user.ID, now)
+	})
+}
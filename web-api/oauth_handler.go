@@ -0,0 +1,115 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oauthStateCookie    = "oauth_state"
+	oauthVerifierCookie = "oauth_verifier"
+	oauthCookieMaxAge   = 10 * 60
+)
+
+// StartOAuth mengarahkan browser ke halaman login provider. State dan PKCE
+// verifier disimpan di cookie untuk dicek saat callback.
+func (h *AuthHandler) StartOAuth(c *gin.Context) {
+	provider, ok := h.oauthProvider(c)
+	if !ok {
+		return
+	}
+
+	state, err := randomToken(16)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+	h.setOAuthCookie(c, oauthStateCookie, state, oauthCookieMaxAge)
+	h.setOAuthCookie(c, oauthVerifierCookie, verifier, oauthCookieMaxAge)
+
+	c.Redirect(http.StatusFound, provider.Config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)))
+}
+
+// OAuthCallback menukar authorization code dengan profil user di provider,
+// lalu login (atau membuat akun) dan mengembalikan token seperti POST /auth/login.
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	provider, ok := h.oauthProvider(c)
+	if !ok {
+		return
+	}
+
+	state, _ := c.Cookie(oauthStateCookie)
+	verifier, _ := c.Cookie(oauthVerifierCookie)
+	h.setOAuthCookie(c, oauthStateCookie, "", -1)
+	h.setOAuthCookie(c, oauthVerifierCookie, "", -1)
+	if reason := c.Query("error"); reason != "" {
+		problem(c, http.StatusUnauthorized, "authorization denied: "+reason)
+		return
+	}
+	if state == "" || verifier == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		problem(c, http.StatusBadRequest, "invalid oauth state")
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		problem(c, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := provider.Config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		log.Printf("oauth %s: exchange: %v", provider.Name, err)
+		problem(c, http.StatusUnauthorized, "could not sign in with "+provider.Name)
+		return
+	}
+	profile, err := provider.FetchProfile(ctx, token)
+	if err != nil {
+		log.Printf("oauth %s: profile: %v", provider.Name, err)
+		problem(c, http.StatusBadGateway, "could not read profile from "+provider.Name)
+		return
+	}
+
+	user, err := h.OAuth.SignIn(ctx, provider.Name, profile)
+	if errors.Is(err, ErrOAuthEmailUnverified) {
+		problem(c, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if !h.allowLogin(c, user) {
+		return
+	}
+	// Kode TOTP tidak bisa dikirim lewat redirect provider, jadi user dengan 2FA
+	// tetap harus login memakai password dan kode.
+	if user.TwoFactorEnabled() {
+		problem(c, http.StatusForbidden, "two-factor accounts must sign in with password and code", gin.H{
+			"two_factor_required": true,
+		})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+func (h *AuthHandler) oauthProvider(c *gin.Context) (*OAuthProvider, bool) {
+	provider, ok := h.Providers[c.Param("provider")]
+	if !ok {
+		problem(c, http.StatusNotFound, "unknown or unconfigured oauth provider")
+		return nil, false
+	}
+	return provider, true
+}
+
+func (h *AuthHandler) setOAuthCookie(c *gin.Context, name, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(name, value, maxAge, "/auth/oauth", "", h.secureCookies(), true)
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// apiAuth adalah cara login yang diterima sebuah operasi di dokumen OpenAPI.
+type apiAuth int
+
+const (
+	// apiAuthToken menerima access token, API key, atau session cookie.
+	apiAuthToken apiAuth = iota
+	apiAuthPublic
+	// apiAuthBasic adalah HTTP Basic dengan email dan API key, dipakai CalDAV.
+	apiAuthBasic
+)
+
+// apiOperation mendeskripsikan satu route di dokumen OpenAPI. Body dan
+// Response berisi nilai contoh yang skemanya dibaca lewat reflection; nil
+// berarti tanpa body. Status default 200, atau 204 jika tidak ada Response.
+type apiOperation struct {
+	Tag         string
+	Summary     string
+	Description string
+	Auth        apiAuth
+	Query       []apiParam
+	Body        any
+	Status      int
+	Response    any
+	// ContentType diisi jika respons sukses bukan JSON, misalnya CSV atau iCalendar.
+	ContentType string
+}
+
+// jsonObject adalah objek yang dibentuk handler lewat gin.H. Nilainya adalah
+// contoh untuk setiap field.
+type jsonObject map[string]any
+
+// oneOf dipakai untuk field yang bentuknya bergantung pada request, misalnya
+// meta pagination offset atau cursor.
+type oneOf []any
+
+// multipartForm adalah body multipart/form-data. Nilainya adalah deskripsi
+// field; field dengan nama di apiFileFields dikirim sebagai file.
+type multipartForm map[string]string
+
+// rawBody adalah body non-JSON; nilainya content type-nya.
+type rawBody string
+
+// apiFileFields adalah field multipart yang berisi file.
+var apiFileFields = []string{"file", "avatar"}
+
+// apiParam adalah parameter query sebuah operasi. Type kosong berarti string.
+type apiParam struct {
+	Name        string
+	Description string
+	Type        string
+	Format      string
+}
+
+// openAPIMethods adalah method yang punya operasi di path item OpenAPI.
+// Method lain (misalnya PROPFIND di CalDAV) hanya disebut di deskripsi path.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// buildOpenAPI menyusun dokumen OpenAPI 3 dari route yang terdaftar di
+// router, dengan keterangan dari apiOperations. Route yang belum punya
+// keterangan tetap dicantumkan supaya dokumen tidak pernah kurang dari route
+// yang sebenarnya. Alias lama tanpa prefix versi tidak dicantumkan.
+func buildOpenAPI(routes gin.RoutesInfo, baseURL string) ([]byte, error) {
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	schemas := &schemaRegistry{schemas: map[string]any{}}
+	paths := map[string]map[string]any{}
+	for _, route := range routes {
+		if registered[route.Method+" "+apiV1Prefix+route.Path] {
+			continue
+		}
+		path, params := openAPIPath(route.Path)
+		item := paths[path]
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		method := strings.ToLower(route.Method)
+		if !slices.Contains(openAPIMethods, method) {
+			description, _ := item["description"].(string)
+			if description == "" {
+				description = "Also accepts"
+			} else {
+				description += ","
+			}
+			item["description"] = description + " " + route.Method
+			continue
+		}
+
+		op, ok := apiOperations[route.Method+" "+strings.TrimPrefix(route.Path, apiV1Prefix)]
+		if !ok {
+			log.Printf("openapi: no description for %s %s", route.Method, route.Path)
+			op.Summary = route.Method + " " + route.Path
+		}
+		item[method] = op.build(schemas, route.Method, path, params)
+	}
+
+	problemSchema := schemas.valueSchema(Problem{})
+	return json.Marshal(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Todolist API",
+			"version": "1.0.0",
+			"description": "Send an access token (`Authorization: Bearer ...`), an API key (`X-API-Key`), or the session cookie " +
+				"with `X-CSRF-Token`. Task, project, and tag routes accept `X-Workspace-ID` to work inside a workspace. " +
+				"Errors are returned as RFC 7807 `application/problem+json`. Send `Accept: application/vnd.api+json`, `application/xml`, or " +
+				"`application/yaml` to receive JSON responses as JSON:API documents, XML, or YAML instead.",
+		},
+		"servers": []any{map[string]any{"url": baseURL}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth":    map[string]any{"type": "http", "scheme": "bearer"},
+				"apiKey":        map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"sessionCookie": map[string]any{"type": "apiKey", "in": "cookie", "name": sessionCookie},
+				"basicAuth":     map[string]any{"type": "http", "scheme": "basic", "description": "Email and API key."},
+			},
+			"parameters": map[string]any{
+				"WorkspaceID": map[string]any{
+					"name": "X-Workspace-ID", "in": "header",
+					"description": "Work with the tasks and projects of this workspace instead of personal ones.",
+					"schema":      map[string]any{"type": "integer"},
+				},
+			},
+			"responses": map[string]any{
+				"Error": map[string]any{
+					"description": "Error",
+					"content":     map[string]any{problemContentType: map[string]any{"schema": problemSchema}},
+				},
+			},
+		},
+	})
+}
+
+// openAPIPath mengubah path gin (/tasks/:id, /caldav/*path) menjadi path
+// OpenAPI (/tasks/{id}) dan mengembalikan nama parameternya.
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func (op apiOperation) build(schemas *schemaRegistry, method, path string, params []string) map[string]any {
+	// Tag, operationId, dan X-Workspace-ID ditentukan dari path tanpa prefix versi.
+	local := strings.TrimPrefix(path, apiV1Prefix)
+	tag := op.Tag
+	if tag == "" {
+		tag, _, _ = strings.Cut(strings.TrimPrefix(local, "/"), "/")
+	}
+	operation := map[string]any{
+		"tags":        []string{tag},
+		"summary":     op.Summary,
+		"operationId": openAPIOperationID(method, local),
+	}
+	if op.Description != "" {
+		operation["description"] = op.Description
+	}
+
+	var parameters []any
+	for _, name := range params {
+		schema := map[string]any{"type": "string"}
+		if name == "id" || strings.HasSuffix(name, "ID") || strings.HasSuffix(name, "_id") {
+			schema = map[string]any{"type": "integer", "minimum": 1}
+		}
+		parameters = append(parameters, map[string]any{"name": name, "in": "path", "required": true, "schema": schema})
+	}
+	if op.Auth == apiAuthToken && workspaceScopedPath(local) {
+		parameters = append(parameters, map[string]any{"$ref": "#/components/parameters/WorkspaceID"})
+	}
+	for _, param := range op.Query {
+		schema := map[string]any{"type": param.Type}
+		if param.Type == "" {
+			schema["type"] = "string"
+		}
+		if param.Format != "" {
+			schema["format"] = param.Format
+		}
+		parameters = append(parameters, map[string]any{"name": param.Name, "in": "query", "description": param.Description, "schema": schema})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if body := op.requestBody(schemas); body != nil {
+		operation["requestBody"] = body
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+		if op.Response == nil && op.ContentType == "" {
+			status = http.StatusNoContent
+		}
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case op.ContentType != "":
+		success["content"] = map[string]any{op.ContentType: map[string]any{"schema": map[string]any{"type": "string"}}}
+	case op.Response != nil:
+		success["content"] = map[string]any{"application/json": map[string]any{"schema": schemas.valueSchema(op.Response)}}
+	}
+	operation["responses"] = map[string]any{
+		strconv.Itoa(status): success,
+		"default":            map[string]any{"$ref": "#/components/responses/Error"},
+	}
+
+	switch op.Auth {
+	case apiAuthToken:
+		operation["security"] = []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"apiKey": []string{}},
+			map[string]any{"sessionCookie": []string{}},
+		}
+	case apiAuthBasic:
+		operation["security"] = []any{map[string]any{"basicAuth": []string{}}}
+	}
+	return operation
+}
+
+func (op apiOperation) requestBody(schemas *schemaRegistry) map[string]any {
+	switch body := op.Body.(type) {
+	case nil:
+		return nil
+	case multipartForm:
+		properties := map[string]any{}
+		for name, description := range body {
+			property := map[string]any{"type": "string", "description": description}
+			if slices.Contains(apiFileFields, name) {
+				property["format"] = "binary"
+			}
+			properties[name] = property
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		return map[string]any{"required": true, "content": map[string]any{"multipart/form-data": map[string]any{"schema": schema}}}
+	case rawBody:
+		return map[string]any{"required": true, "content": map[string]any{string(body): map[string]any{"schema": map[string]any{"type": "string"}}}}
+	default:
+		return map[string]any{"required": true, "content": map[string]any{"application/json": map[string]any{"schema": schemas.valueSchema(body)}}}
+	}
+}
+
+// openAPIOperationID membentuk operationId yang unik dari method dan path,
+// misalnya get_tasks_id_comments untuk GET /tasks/{id}/comments.
+func openAPIOperationID(method, path string) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(method)+path)
+	for strings.Contains(id, "__") {
+		id = strings.ReplaceAll(id, "__", "_")
+	}
+	return strings.TrimSuffix(id, "_")
+}
+
+// workspaceScopedPrefixes adalah awal path route yang membaca X-Workspace-ID.
+var workspaceScopedPrefixes = []string{"/tasks", "/show-tasks", "/undo", "/trash", "/tags", "/stats", "/projects", "/shared", "/import", "/templates", "/filters", "/graphql"}
+
+func workspaceScopedPath(path string) bool {
+	for _, prefix := range workspaceScopedPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") || strings.HasPrefix(path, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRegistry membentuk JSON Schema dari tipe Go. Struct bernama disimpan
+// sekali di components/schemas dan dirujuk lewat $ref.
+type schemaRegistry struct {
+	schemas map[string]any
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// apiEnums adalah nilai yang valid untuk tipe string yang terbatas.
+var apiEnums = map[reflect.Type][]string{
+	reflect.TypeOf(StatusTodo):          enumValues([]TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusCancelled}),
+	reflect.TypeOf(PriorityLow):         enumValues(priorities),
+	reflect.TypeOf(RoleMember):          enumValues(roles),
+	reflect.TypeOf(ScopeAdmin):          enumValues(scopes),
+	reflect.TypeOf(NotificationMention): enumValues(notificationTypes),
+	reflect.TypeOf(WebhookTaskCreated):  enumValues(webhookEvents),
+}
+
+func enumValues[T ~string](values []T) []string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = string(value)
+	}
+	return names
+}
+
+// valueSchema membentuk skema dari nilai contoh di apiOperation.
+func (r *schemaRegistry) valueSchema(value any) map[string]any {
+	switch value := value.(type) {
+	case jsonObject:
+		properties := map[string]any{}
+		for name, field := range value {
+			properties[name] = r.valueSchema(field)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	case oneOf:
+		variants := make([]any, len(value))
+		for i, variant := range value {
+			variants[i] = r.valueSchema(variant)
+		}
+		return map[string]any{"oneOf": variants}
+	default:
+		return r.typeSchema(reflect.TypeOf(value))
+	}
+}
+
+func (r *schemaRegistry) typeSchema(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	if values, ok := apiEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case deletedAtType:
+		return map[string]any{"type": "string", "format": "date-time", "nullable": true}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(r.typeSchema(t.Elem()))
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": r.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": r.typeSchema(t.Elem())}
+	case reflect.Struct:
+		// optional[T] di request PATCH dikirim sebagai T atau null.
+		if strings.HasPrefix(t.Name(), "optional[") {
+			field, _ := t.FieldByName("Value")
+			return r.typeSchema(field.Type)
+		}
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.schemas[t.Name()]; !ok {
+			// Isi sementara supaya tipe yang merujuk dirinya sendiri berhenti di $ref.
+			r.schemas[t.Name()] = map[string]any{}
+			r.schemas[t.Name()] = r.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema mengikuti aturan encoding/json: field tanpa export dan json:"-"
+// dilewati, dan struct embedded tanpa nama JSON digabung ke induknya.
+func (r *schemaRegistry) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := r.structSchema(field.Type)
+			for key, value := range embedded["properties"].(map[string]any) {
+				properties[key] = value
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = r.typeSchema(field.Type)
+		if slices.Contains(strings.Split(field.Tag.Get("binding"), ","), "required") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func nullable(schema map[string]any) map[string]any {
+	if _, ok := schema["$ref"]; ok {
+		return map[string]any{"allOf": []any{schema}, "nullable": true}
+	}
+	result := make(map[string]any, len(schema)+1)
+	for key, value := range schema {
+		result[key] = value
+	}
+	result["nullable"] = true
+	return result
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// apiQueryParams adalah parameter query yang dipakai di banyak route.
+var apiQueryParams = map[string]apiParam{
+	"limit":      {Name: "limit", Type: "integer", Description: "Page size, 1-200 (default 50)."},
+	"offset":     {Name: "offset", Type: "integer", Description: "Rows to skip. Cannot be combined with cursor."},
+	"cursor":     {Name: "cursor", Description: "Cursor pagination; send it empty for the first page, then meta.next_cursor."},
+	"fields":     {Name: "fields", Description: "Comma-separated task fields to return; id is always included."},
+	"include":    {Name: "include", Description: "Relations to load: subtasks, tags, project, dependencies, attachments, custom_fields. Empty loads none."},
+	"render":     {Name: "render", Description: "html renders Markdown descriptions to description_html."},
+	"overdue":    {Name: "overdue", Type: "boolean", Description: "Only open tasks whose due date has passed."},
+	"due_before": {Name: "due_before", Format: "date-time", Description: "Tasks due before this time (RFC 3339)."},
+	"due_after":  {Name: "due_after", Format: "date-time", Description: "Tasks due after this time (RFC 3339)."},
+	"status":     {Name: "status", Description: "Comma-separated statuses: todo, in_progress, done, cancelled."},
+	"priority":   {Name: "priority", Description: "Comma-separated priorities: low, medium, high, urgent."},
+	"project_id": {Name: "project_id", Type: "integer", Description: "Tasks of this project."},
+	"assignee":   {Name: "assignee", Description: "me, none, or a user id."},
+	"archived":   {Name: "archived", Type: "boolean", Description: "Show archived items instead of active ones."},
+	"tag":        {Name: "tag", Description: "Comma-separated tag names; a task matches if it has any of them. Custom fields filter with field.<id>=value."},
+	"sort":       {Name: "sort", Description: "Comma-separated sort fields, prefix - for descending, e.g. -priority,due_at."},
+	"tz":         {Name: "tz", Description: "IANA time zone such as Asia/Jakarta; defaults to the user's preference."},
+	"force":      {Name: "force", Type: "boolean", Description: "Complete the task even if blockers are still open."},
+}
+
+func queryParams(names ...[]string) []apiParam {
+	var params []apiParam
+	for _, name := range slices.Concat(names...) {
+		params = append(params, apiQueryParams[name])
+	}
+	return params
+}
+
+var (
+	pageQuery     = []string{"limit", "offset", "cursor"}
+	taskViewQuery = []string{"fields", "include", "render"}
+	pageMeta      = oneOf{PageMeta{}, CursorMeta{}}
+
+	tokenResponse = jsonObject{
+		"access_token": "", "token_type": "Bearer", "expires_at": time.Time{},
+		"refresh_token": "", "refresh_expires_at": time.Time{}, "user": User{},
+	}
+	// loginResponse bergantung pada AUTH_MODE: token atau session cookie.
+	loginResponse = oneOf{tokenResponse, jsonObject{"expires_at": time.Time{}, "csrf_token": "", "user": User{}}}
+)
+
+// apiOperations berisi keterangan setiap route untuk buildOpenAPI, dengan key
+// "METHOD path" persis seperti saat route didaftarkan di main.
+var apiOperations = map[string]apiOperation{
+	"GET /":                   {Tag: "general", Summary: "Welcome message", Auth: apiAuthPublic, Response: jsonObject{"message": ""}},
+	"GET /docs":               {Tag: "general", Summary: "Swagger UI", Auth: apiAuthPublic, ContentType: "text/html"},
+	"GET /openapi.json":       {Tag: "general", Summary: "This OpenAPI document", Auth: apiAuthPublic, Response: jsonObject{}},
+	"GET /avatars/*filepath":  {Tag: "me", Summary: "Download an avatar image", Auth: apiAuthPublic, ContentType: "image/png"},
+	"HEAD /avatars/*filepath": {Tag: "me", Summary: "Check an avatar image", Auth: apiAuthPublic, ContentType: "image/png"},
+	"GET /.well-known/caldav": {Tag: "caldav", Summary: "Redirect to the CalDAV root", Auth: apiAuthPublic, Status: http.StatusMovedPermanently},
+
+	"POST /auth/register":            {Summary: "Register an account", Auth: apiAuthPublic, Body: registerRequest{}, Status: http.StatusCreated, Response: User{}},
+	"POST /auth/login":               {Summary: "Sign in with email and password", Description: "code is required when two-factor authentication is enabled.", Auth: apiAuthPublic, Body: loginRequest{}, Response: loginResponse},
+	"POST /auth/refresh":             {Summary: "Rotate a refresh token", Auth: apiAuthPublic, Body: refreshRequest{}, Response: tokenResponse},
+	"POST /auth/logout":              {Summary: "Revoke a refresh token or end the session", Auth: apiAuthPublic, Body: logoutRequest{}},
+	"POST /auth/forgot-password":     {Summary: "Email a password reset link", Auth: apiAuthPublic, Body: forgotPasswordRequest{}, Status: http.StatusAccepted, Response: jsonObject{"message": ""}},
+	"POST /auth/reset-password":      {Summary: "Set a new password with a reset token", Auth: apiAuthPublic, Body: resetPasswordRequest{}},
+	"GET /auth/verify":               {Summary: "Verify an email address", Auth: apiAuthPublic, Query: []apiParam{{Name: "token", Description: "Token from the verification email."}}, Response: User{}},
+	"POST /auth/resend-verification": {Summary: "Send the verification email again", Auth: apiAuthPublic, Body: resendVerificationRequest{}, Status: http.StatusAccepted, Response: jsonObject{"message": ""}},
+	"GET /auth/oauth/:provider":      {Summary: "Start OAuth sign-in", Description: "Redirects the browser to the provider (google or github).", Auth: apiAuthPublic, Status: http.StatusFound},
+	"GET /auth/oauth/:provider/callback": {
+		Summary: "Finish OAuth sign-in", Auth: apiAuthPublic, Response: loginResponse,
+		Query: []apiParam{{Name: "state"}, {Name: "code"}, {Name: "error"}},
+	},
+	"POST /auth/logout-all":       {Summary: "Sign out everywhere"},
+	"GET /auth/csrf":              {Summary: "CSRF token for the current session", Response: jsonObject{"csrf_token": ""}},
+	"GET /auth/2fa":               {Summary: "Two-factor status", Response: jsonObject{"enabled": true, "enabled_at": (*time.Time)(nil), "backup_codes_remaining": 0}},
+	"POST /auth/2fa/enroll":       {Summary: "Start two-factor enrollment", Response: jsonObject{"secret": "", "otpauth_uri": ""}},
+	"POST /auth/2fa/confirm":      {Summary: "Confirm enrollment with a TOTP code", Body: twoFactorCodeRequest{}, Response: jsonObject{"backup_codes": []string{}}},
+	"POST /auth/2fa/backup-codes": {Summary: "Regenerate backup codes", Body: twoFactorCodeRequest{}, Response: jsonObject{"backup_codes": []string{}}},
+	"POST /auth/2fa/disable":      {Summary: "Disable two-factor authentication", Body: disableTwoFactorRequest{}},
+	"GET " + shareLinkPath + ":token": {
+		Tag: "public", Summary: "Open a shared project", Auth: apiAuthPublic, Query: queryParams(pageQuery),
+		Response: jsonObject{"project": jsonObject{"name": "", "description": ""}, "tasks": []Task{}, "meta": pageMeta},
+	},
+	"GET " + calendarPath: {
+		Tag: "calendar", Summary: "iCalendar feed", Auth: apiAuthPublic, ContentType: "text/calendar",
+		Query: []apiParam{{Name: "token", Description: "Feed token from POST /me/calendar."}, {Name: "type", Description: "event (default) or todo."}},
+	},
+	"POST /integrations/slack/commands": {
+		Tag: "integrations", Summary: "Slack slash command", Description: "Signed with X-Slack-Signature.", Auth: apiAuthPublic,
+		Body: rawBody("application/x-www-form-urlencoded"), Response: jsonObject{"response_type": "ephemeral", "text": ""},
+	},
+	"GET " + slackCallbackPath: {
+		Tag: "integrations", Summary: "Slack OAuth callback", Auth: apiAuthPublic, Response: SlackConnection{},
+		Query: []apiParam{{Name: "state"}, {Name: "code"}, {Name: "error"}},
+	},
+	"POST /integrations/telegram/webhook": {
+		Tag: "integrations", Summary: "Telegram bot webhook", Auth: apiAuthPublic,
+		Body: telegramUpdate{}, Response: jsonObject{"method": "sendMessage", "chat_id": int64(0), "text": ""},
+	},
+	"GET " + googleTasksCallbackPath: {
+		Tag: "integrations", Summary: "Google Tasks OAuth callback", Auth: apiAuthPublic, Response: GoogleTasksConnection{},
+		Query: []apiParam{{Name: "state"}, {Name: "code"}, {Name: "error"}},
+	},
+
+	"OPTIONS " + caldavPath + "/*path": {Tag: "caldav", Summary: "CalDAV capabilities", Auth: apiAuthBasic, Status: http.StatusOK},
+	"GET " + caldavPath + "/*path":     {Tag: "caldav", Summary: "Download a VTODO", Auth: apiAuthBasic, ContentType: "text/calendar"},
+	"HEAD " + caldavPath + "/*path":    {Tag: "caldav", Summary: "Check a VTODO", Auth: apiAuthBasic, ContentType: "text/calendar"},
+	"PUT " + caldavPath + "/*path": {
+		Tag: "caldav", Summary: "Create or update a VTODO", Description: "Honours If-Match and If-None-Match.",
+		Auth: apiAuthBasic, Body: rawBody("text/calendar"), Status: http.StatusCreated,
+	},
+	"DELETE " + caldavPath + "/*path": {Tag: "caldav", Summary: "Delete a VTODO and its task", Auth: apiAuthBasic},
+
+	"GET /me":                           {Summary: "Current user", Response: User{}},
+	"PUT /me":                           {Summary: "Update the profile", Body: updateMeRequest{}, Response: User{}},
+	"DELETE /me":                        {Summary: "Delete the account", Body: deleteMeRequest{}},
+	"PUT /me/avatar":                    {Summary: "Upload an avatar", Body: multipartForm{"avatar": "JPEG, PNG, GIF, or WebP, at most 2 MiB."}, Response: User{}},
+	"DELETE /me/avatar":                 {Summary: "Remove the avatar"},
+	"GET /me/security-events":           {Summary: "Sign-in and security history", Query: queryParams(pageQuery), Response: jsonObject{"events": []AuthEvent{}, "meta": pageMeta}},
+	"GET /export":                       {Tag: "me", Summary: "Export all personal data", Response: jsonObject{"version": 0, "exported_at": time.Time{}, "user": User{}, "projects": []Project{}, "tags": []Tag{}, "tasks": []exportTask{}}},
+	"GET /me/calendar":                  {Summary: "Calendar feed status", Response: CalendarFeed{}},
+	"POST /me/calendar":                 {Summary: "Create or rotate the calendar feed", Status: http.StatusCreated, Response: jsonObject{"calendar_feed": CalendarFeed{}, "url": ""}},
+	"DELETE /me/calendar":               {Summary: "Revoke the calendar feed"},
+	"GET /me/telegram":                  {Summary: "Linked Telegram chat", Response: TelegramLink{}},
+	"POST /me/telegram/link":            {Summary: "Create a Telegram link code", Status: http.StatusCreated, Response: jsonObject{"code": "", "expires_at": time.Time{}, "url": ""}},
+	"DELETE /me/telegram":               {Summary: "Unlink Telegram"},
+	"GET /me/google-tasks":              {Summary: "Google Tasks connection", Response: GoogleTasksConnection{}},
+	"POST /me/google-tasks/connect":     {Summary: "Google consent URL", Status: http.StatusCreated, Response: jsonObject{"url": "", "expires_at": time.Time{}}},
+	"DELETE /me/google-tasks":           {Summary: "Disconnect Google Tasks"},
+	"POST /me/google-tasks/sync":        {Summary: "Sync with Google Tasks now", Response: GoogleTasksSyncResult{}},
+	"GET /me/push/key":                  {Summary: "VAPID public key", Response: jsonObject{"public_key": ""}},
+	"GET /me/push/subscriptions":        {Summary: "Web Push subscriptions", Response: jsonObject{"subscriptions": []PushSubscription{}}},
+	"POST /me/push/subscriptions":       {Summary: "Subscribe a browser to Web Push", Body: pushSubscriptionRequest{}, Status: http.StatusCreated, Response: PushSubscription{}},
+	"DELETE /me/push/subscriptions/:id": {Summary: "Remove a Web Push subscription"},
+
+	"GET /api-keys":        {Summary: "API keys", Response: jsonObject{"api_keys": []APIKey{}}},
+	"POST /api-keys":       {Summary: "Create an API key", Description: "The key is only shown once.", Body: apiKeyRequest{}, Status: http.StatusCreated, Response: jsonObject{"api_key": APIKey{}, "key": ""}},
+	"DELETE /api-keys/:id": {Summary: "Revoke an API key"},
+
+	"GET /admin/users":              {Summary: "All users", Query: queryParams(pageQuery), Response: jsonObject{"users": []User{}, "meta": pageMeta}},
+	"GET /admin/users/:id":          {Summary: "A user", Response: User{}},
+	"DELETE /admin/users/:id":       {Summary: "Delete a user"},
+	"PUT /admin/users/:id/role":     {Summary: "Change a user's role", Body: setRoleRequest{}, Response: User{}},
+	"POST /admin/users/:id/disable": {Summary: "Disable a user", Response: User{}},
+	"POST /admin/users/:id/enable":  {Summary: "Enable a user", Response: User{}},
+	"DELETE /admin/users/:id/2fa":   {Summary: "Reset a user's two-factor authentication"},
+	"GET /admin/metrics":            {Summary: "Runtime metrics (expvar)", Response: jsonObject{}},
+
+	"GET /workspaces":                                  {Summary: "Workspaces the user belongs to", Response: jsonObject{"workspaces": []Workspace{}}},
+	"POST /workspaces":                                 {Summary: "Create a workspace", Body: workspaceRequest{}, Status: http.StatusCreated, Response: Workspace{}},
+	"POST /workspaces/invitations/accept":              {Summary: "Accept an invitation", Body: acceptInvitationRequest{}, Response: Workspace{}},
+	"GET /workspaces/:id":                              {Summary: "A workspace and its members", Response: jsonObject{"workspace": Workspace{}, "members": []WorkspaceMember{}}},
+	"PUT /workspaces/:id":                              {Summary: "Rename a workspace", Body: workspaceRequest{}, Response: Workspace{}},
+	"DELETE /workspaces/:id":                           {Summary: "Delete a workspace"},
+	"POST /workspaces/:id/members":                     {Summary: "Add a member", Body: addMemberRequest{}, Status: http.StatusCreated, Response: WorkspaceMember{}},
+	"PUT /workspaces/:id/members/:user_id":             {Summary: "Change a member's role", Body: memberRoleRequest{}, Response: WorkspaceMember{}},
+	"DELETE /workspaces/:id/members/:user_id":          {Summary: "Remove a member or leave"},
+	"GET /workspaces/:id/invitations":                  {Summary: "Pending invitations", Response: jsonObject{"invitations": []WorkspaceInvitation{}}},
+	"POST /workspaces/:id/invitations":                 {Summary: "Invite by email", Body: addMemberRequest{}, Status: http.StatusCreated, Response: WorkspaceInvitation{}},
+	"DELETE /workspaces/:id/invitations/:invitationID": {Summary: "Revoke an invitation"},
+	"GET /workspaces/:id/fields":                       {Summary: "Custom fields", Response: jsonObject{"fields": []CustomField{}}},
+	"POST /workspaces/:id/fields":                      {Summary: "Create a custom field", Body: customFieldRequest{}, Status: http.StatusCreated, Response: CustomField{}},
+	"PUT /workspaces/:id/fields/:fieldID":              {Summary: "Update a custom field", Body: customFieldRequest{}, Response: CustomField{}},
+	"DELETE /workspaces/:id/fields/:fieldID":           {Summary: "Delete a custom field"},
+	"GET /workspaces/:id/webhooks":                     {Summary: "Webhooks", Response: jsonObject{"webhooks": []Webhook{}}},
+	"POST /workspaces/:id/webhooks":                    {Summary: "Create a webhook", Description: "The signing secret is only shown once.", Body: webhookRequest{}, Status: http.StatusCreated, Response: jsonObject{"webhook": Webhook{}, "secret": ""}},
+	"DELETE /workspaces/:id/webhooks/:webhookID":       {Summary: "Delete a webhook"},
+	"GET /workspaces/:id/webhooks/:webhookID/deliveries": {
+		Summary: "Webhook delivery log", Query: queryParams(pageQuery), Response: jsonObject{"deliveries": []WebhookDelivery{}, "meta": pageMeta},
+	},
+	"GET /workspaces/:id/slack":          {Summary: "Slack connection", Response: SlackConnection{}},
+	"POST /workspaces/:id/slack/connect": {Summary: "Slack install URL", Status: http.StatusCreated, Response: jsonObject{"url": "", "expires_at": time.Time{}}},
+	"DELETE /workspaces/:id/slack":       {Summary: "Disconnect Slack"},
+
+	"GET /show-tasks": {
+		Tag: "tasks", Summary: "List tasks (legacy alias of GET /tasks)", Query: queryParams(pageQuery, taskFilterKeys, taskViewQuery),
+		Response: jsonObject{"task": []Task{}, "meta": pageMeta},
+	},
+	"GET /tasks": {
+		Summary: "List tasks", Query: queryParams(pageQuery, taskFilterKeys, taskViewQuery),
+		Response: jsonObject{"task": []Task{}, "meta": pageMeta},
+	},
+	"GET /tasks/search": {
+		Summary: "Full-text search", Query: append([]apiParam{{Name: "q", Description: "Search terms."}}, queryParams(pageQuery, taskFilterKeys)...),
+		Response: jsonObject{"results": []TaskSearchResult{}, "meta": pageMeta},
+	},
+	"GET /tasks/summary": {Summary: "Task counts for a dashboard", Query: queryParams([]string{"tz"}, taskFilterKeys), Response: TaskSummary{}},
+	"GET /tasks/agenda": {
+		Summary: "Tasks grouped by due day",
+		Query: append([]apiParam{
+			{Name: "from", Format: "date", Description: "First day, YYYY-MM-DD (default today)."},
+			{Name: "to", Format: "date", Description: "Last day, inclusive."},
+		}, queryParams([]string{"tz"}, taskFilterKeys)...),
+		Response: jsonObject{"from": "", "to": "", "tz": "", "days": []AgendaDay{}},
+	},
+	"GET /tasks/export.csv": {Summary: "Export tasks as CSV", Query: queryParams([]string{"tz"}, taskFilterKeys), ContentType: "text/csv"},
+	"GET /tasks/export.txt": {Summary: "Export tasks as todo.txt", Query: queryParams([]string{"tz"}, taskFilterKeys), ContentType: "text/plain"},
+	"POST /tasks":           {Summary: "Create a task", Body: taskRequest{}, Status: http.StatusCreated, Response: Task{}},
+	"POST /tasks/bulk": {
+		Summary: "Create up to 100 tasks", Description: "Nothing is saved if any item is invalid.",
+		Body: []taskRequest{}, Status: http.StatusCreated, Response: jsonObject{"results": []bulkTaskResult{}},
+	},
+	"POST /tasks/bulk-update":                                {Summary: "Patch many tasks", Query: queryParams([]string{"force"}), Body: bulkUpdateRequest{}, Response: jsonObject{"tasks": []Task{}}},
+	"POST /tasks/bulk-delete":                                {Summary: "Move many tasks to the trash", Body: bulkDeleteRequest{}},
+	"POST /undo":                                             {Tag: "tasks", Summary: "Undo the last delete or bulk change", Response: jsonObject{"action": "", "tasks": []Task{}}},
+	"GET /trash":                                             {Summary: "Deleted tasks", Query: queryParams(pageQuery), Response: jsonObject{"tasks": []Task{}, "meta": pageMeta}},
+	"POST /trash/:id/restore":                                {Summary: "Restore a deleted task", Response: Task{}},
+	"DELETE /trash/:id":                                      {Summary: "Delete a task permanently"},
+	"GET /tasks/:id":                                         {Summary: "A task", Query: queryParams(taskViewQuery), Response: Task{}},
+	"PUT /tasks/:id":                                         {Summary: "Replace a task", Query: queryParams([]string{"force"}), Body: taskRequest{}, Response: Task{}},
+	"PATCH /tasks/:id":                                       {Summary: "Update some fields of a task", Query: queryParams([]string{"force"}), Body: patchTaskRequest{}, Response: Task{}},
+	"DELETE /tasks/:id":                                      {Summary: "Move a task to the trash"},
+	"PATCH /tasks/:id/move":                                  {Summary: "Reorder a task", Body: moveTaskRequest{}, Response: Task{}},
+	"POST /tasks/:id/duplicate":                              {Summary: "Duplicate a task", Status: http.StatusCreated, Response: Task{}},
+	"GET /tasks/:id/history":                                 {Summary: "Change history", Query: queryParams(pageQuery), Response: jsonObject{"events": []TaskEvent{}, "meta": pageMeta}},
+	"POST /tasks/:id/template":                               {Summary: "Save a task as a template", Body: templateFromTaskRequest{}, Status: http.StatusCreated, Response: TaskTemplate{}},
+	"POST /tasks/:id/dependencies":                           {Summary: "Add a blocker", Body: dependencyRequest{}, Response: Task{}},
+	"DELETE /tasks/:id/dependencies/:blockerID":              {Summary: "Remove a blocker"},
+	"POST /tasks/:id/complete":                               {Summary: "Mark a task done", Query: queryParams([]string{"force"}), Response: Task{}},
+	"POST /tasks/:id/reopen":                                 {Summary: "Reopen a task", Response: Task{}},
+	"POST /tasks/:id/archive":                                {Summary: "Archive a task", Response: Task{}},
+	"POST /tasks/:id/unarchive":                              {Summary: "Unarchive a task", Response: Task{}},
+	"GET /tasks/:id/subtasks":                                {Summary: "Subtasks", Response: jsonObject{"subtasks": []Subtask{}}},
+	"POST /tasks/:id/subtasks":                               {Summary: "Add a subtask", Body: subtaskRequest{}, Status: http.StatusCreated, Response: Subtask{}},
+	"GET /tasks/:id/subtasks/:subtaskID":                     {Summary: "A subtask", Response: Subtask{}},
+	"PATCH /tasks/:id/subtasks/:subtaskID":                   {Summary: "Update a subtask", Body: patchSubtaskRequest{}, Response: Subtask{}},
+	"DELETE /tasks/:id/subtasks/:subtaskID":                  {Summary: "Delete a subtask"},
+	"GET /tasks/:id/comments":                                {Summary: "Comments, oldest first", Query: queryParams(pageQuery), Response: jsonObject{"comments": []Comment{}, "meta": pageMeta}},
+	"POST /tasks/:id/comments":                               {Summary: "Add a comment", Description: "@username mentions notify the user.", Body: commentRequest{}, Status: http.StatusCreated, Response: Comment{}},
+	"PUT /tasks/:id/comments/:commentID":                     {Summary: "Edit your comment", Body: commentRequest{}, Response: Comment{}},
+	"DELETE /tasks/:id/comments/:commentID":                  {Summary: "Delete your comment"},
+	"PUT /tasks/:id/comments/:commentID/reactions/:emoji":    {Summary: "React to a comment"},
+	"DELETE /tasks/:id/comments/:commentID/reactions/:emoji": {Summary: "Remove your reaction"},
+	"GET /tasks/:id/watchers":                                {Summary: "Watchers", Response: jsonObject{"watchers": []TaskWatcher{}}},
+	"PUT /tasks/:id/watch":                                   {Summary: "Watch a task"},
+	"DELETE /tasks/:id/watch":                                {Summary: "Stop watching a task"},
+	"GET /tasks/:id/attachments":                             {Summary: "Attachments", Response: jsonObject{"attachments": []Attachment{}}},
+	"POST /tasks/:id/attachments":                            {Summary: "Upload an attachment", Body: multipartForm{"file": "The file to attach."}, Status: http.StatusCreated, Response: Attachment{}},
+	"GET /tasks/:id/attachments/:attachmentID": {
+		Summary: "Download an attachment", Description: "With S3 storage this redirects to a presigned URL.",
+		ContentType: "application/octet-stream",
+	},
+	"DELETE /tasks/:id/attachments/:attachmentID": {Summary: "Delete an attachment"},
+	"PUT /tasks/:id/fields/:fieldID":              {Summary: "Set a custom field value", Body: customFieldValueRequest{}, Response: CustomFieldValue{}},
+	"DELETE /tasks/:id/fields/:fieldID":           {Summary: "Clear a custom field value"},
+	"PUT /tasks/:id/tags/:tagID":                  {Summary: "Tag a task"},
+	"DELETE /tasks/:id/tags/:tagID":               {Summary: "Untag a task"},
+	"GET /tasks/:id/permissions":                  {Summary: "Users the task is shared with", Response: jsonObject{"permissions": []TaskPermission{}}},
+	"POST /tasks/:id/permissions":                 {Summary: "Share a task", Body: shareRequest{}, Response: TaskPermission{}},
+	"DELETE /tasks/:id/permissions/:user_id":      {Summary: "Stop sharing a task"},
+
+	"GET /tags":        {Summary: "Tags", Response: jsonObject{"tags": []Tag{}}},
+	"POST /tags":       {Summary: "Create a tag", Body: tagRequest{}, Status: http.StatusCreated, Response: Tag{}},
+	"GET /tags/:id":    {Summary: "A tag", Response: Tag{}},
+	"PUT /tags/:id":    {Summary: "Update a tag", Body: tagRequest{}, Response: Tag{}},
+	"DELETE /tags/:id": {Summary: "Delete a tag"},
+
+	"GET /stats": {
+		Tag: "tasks", Summary: "Completion statistics by week", Response: TaskStats{},
+		Query: append([]apiParam{{Name: "weeks", Type: "integer", Description: "Number of weeks (default 12)."}}, queryParams([]string{"tz"})...),
+	},
+
+	"GET /notifications": {
+		Summary: "Notifications, newest first",
+		Query: append([]apiParam{
+			{Name: "unread", Type: "boolean", Description: "Only unread notifications."},
+			{Name: "type", Description: "Comma-separated types: mention, assignment, due_reminder, comment, status_change."},
+		}, queryParams(pageQuery)...),
+		Response: jsonObject{"notifications": []Notification{}, "meta": pageMeta},
+	},
+	"GET /notifications/unread-count": {Summary: "Number of unread notifications", Response: jsonObject{"unread": int64(0)}},
+	"POST /notifications/read-all":    {Summary: "Mark every notification read", Response: jsonObject{"marked": int64(0)}},
+	"POST /notifications/:id/read":    {Summary: "Mark a notification read", Response: Notification{}},
+
+	"POST /graphql": {
+		Summary:     "Run a GraphQL query or mutation",
+		Description: "Schema: tasks, projects, tags, and users with nested fields. Queries need read:tasks, mutations need write:tasks. Errors are returned in `errors` with status 200.",
+		Body:        jsonObject{"query": "", "operationName": "", "variables": jsonObject{}},
+		Response:    jsonObject{"data": jsonObject{}, "errors": []jsonObject{}},
+	},
+
+	"GET /ws": {
+		Tag: "tasks", Summary: "Stream task changes over WebSocket", Status: http.StatusSwitchingProtocols,
+		Description: "Upgrade to a WebSocket that receives {event, occurred_at, task} messages for task.created, task.updated, and task.deleted " +
+			"in every workspace of the user and their personal tasks. Needs read:tasks.",
+	},
+	"GET /events": {
+		Tag: "tasks", Summary: "Stream task changes as Server-Sent Events", ContentType: "text/event-stream",
+		Description: "The same events as GET /ws, each with an id. Reconnect with Last-Event-ID to receive missed events; " +
+			"a reset event means they are no longer kept and the task list should be reloaded.",
+		Query: []apiParam{{Name: "last_event_id", Type: "integer", Description: "Alternative to the Last-Event-ID header."}},
+	},
+
+	"GET /projects":                {Summary: "Projects", Query: queryParams([]string{"archived"}), Response: jsonObject{"projects": []Project{}}},
+	"POST /projects":               {Summary: "Create a project", Body: projectRequest{}, Status: http.StatusCreated, Response: Project{}},
+	"GET /projects/:id":            {Summary: "A project", Response: Project{}},
+	"PUT /projects/:id":            {Summary: "Update a project", Body: projectRequest{}, Response: Project{}},
+	"DELETE /projects/:id":         {Summary: "Delete a project"},
+	"POST /projects/:id/archive":   {Summary: "Archive a project", Response: Project{}},
+	"POST /projects/:id/unarchive": {Summary: "Unarchive a project", Response: Project{}},
+	"GET /projects/:id/tasks": {
+		Summary: "Tasks of a project", Query: queryParams(pageQuery, taskFilterKeys, taskViewQuery),
+		Response: jsonObject{"project": Project{}, "tasks": []Task{}, "meta": pageMeta},
+	},
+	"GET /projects/:id/feed": {
+		Summary: "Atom or RSS feed of project activity", ContentType: "application/atom+xml",
+		Query: []apiParam{{Name: "format", Description: "atom (default) or rss."}},
+	},
+	"GET /projects/:id/permissions":             {Summary: "Users the project is shared with", Response: jsonObject{"permissions": []TaskPermission{}}},
+	"POST /projects/:id/permissions":            {Summary: "Share a project", Body: shareRequest{}, Response: TaskPermission{}},
+	"DELETE /projects/:id/permissions/:user_id": {Summary: "Stop sharing a project"},
+	"GET /projects/:id/share-links":             {Summary: "Public read-only links", Response: jsonObject{"share_links": []ShareLink{}}},
+	"POST /projects/:id/share-links":            {Summary: "Create a public link", Status: http.StatusCreated, Response: jsonObject{"share_link": ShareLink{}, "url": ""}},
+	"DELETE /projects/:id/share-links/:linkID":  {Summary: "Revoke a public link"},
+	"GET /shared": {Tag: "projects", Summary: "Tasks and projects shared with me", Response: jsonObject{"shared": []TaskPermission{}}},
+
+	"POST /import/todoist": {Summary: "Import a Todoist backup (zip) or CSV", Body: multipartForm{"file": "Todoist backup or project CSV."}, Status: http.StatusCreated, Response: ImportReport{}},
+	"POST /import/trello":  {Summary: "Import a Trello board export", Body: multipartForm{"file": "Trello board JSON."}, Status: http.StatusCreated, Response: ImportReport{}},
+	"POST /import/csv": {
+		Summary: "Import tasks from CSV", Status: http.StatusCreated, Response: ImportReport{},
+		Body: multipartForm{
+			"file":    "CSV with a header row.",
+			"mapping": `JSON {"<field>": "<column header>"}.`,
+			"mode":    "transactional (default) or best_effort.",
+		},
+	},
+	"POST /import/todotxt": {Summary: "Import a todo.txt file", Body: multipartForm{"file": "todo.txt file."}, Status: http.StatusCreated, Response: ImportReport{}},
+
+	"GET /templates":                  {Summary: "Task templates", Response: jsonObject{"templates": []TaskTemplate{}}},
+	"POST /templates":                 {Summary: "Create a template", Body: templateRequest{}, Status: http.StatusCreated, Response: TaskTemplate{}},
+	"GET /templates/:id":              {Summary: "A template", Response: TaskTemplate{}},
+	"PUT /templates/:id":              {Summary: "Update a template", Body: templateRequest{}, Response: TaskTemplate{}},
+	"DELETE /templates/:id":           {Summary: "Delete a template"},
+	"POST /templates/:id/instantiate": {Summary: "Create a task from a template", Body: instantiateRequest{}, Status: http.StatusCreated, Response: Task{}},
+
+	"GET /filters":        {Summary: "Saved filters", Response: jsonObject{"filters": []SavedFilter{}}},
+	"POST /filters":       {Summary: "Save a filter", Body: filterRequest{}, Status: http.StatusCreated, Response: SavedFilter{}},
+	"GET /filters/:id":    {Summary: "A saved filter", Response: SavedFilter{}},
+	"PUT /filters/:id":    {Summary: "Update a saved filter", Body: filterRequest{}, Response: SavedFilter{}},
+	"DELETE /filters/:id": {Summary: "Delete a saved filter"},
+	"GET /filters/:id/tasks": {
+		Summary: "Tasks matching a saved filter", Query: queryParams(pageQuery, taskViewQuery),
+		Response: jsonObject{"filter": SavedFilter{}, "tasks": []Task{}, "meta": pageMeta},
+	},
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ownerID mengembalikan id user yang sedang login dari context, atau 0 jika
+// tidak ada. Tidak ada baris dengan user_id 0, sehingga query tanpa user
+// tidak mengembalikan apa pun.
+func ownerID(ctx context.Context) uint {
+	if user := userFromContext(ctx); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// ownedByUser membatasi query ke baris milik user di context query. Dipakai
+// lewat Scopes pada query ke tabel yang punya kolom user_id; data user lain
+// diperlakukan seperti tidak ada (404), bukan 403, supaya keberadaannya tidak bocor.
+func ownedByUser(db *gorm.DB) *gorm.DB {
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: "user_id"},
+		Value:  ownerID(db.Statement.Context),
+	})
+}
+
+// workspaceID mengembalikan id workspace yang dipilih lewat useWorkspace, atau
+// nil jika request memakai data pribadi user.
+func workspaceID(ctx context.Context) *uint {
+	if member := workspaceFromContext(ctx); member != nil {
+		return &member.WorkspaceID
+	}
+	return nil
+}
+
+// ownedByWorkspace adalah ownedByUser untuk tabel yang bisa dibagi lewat
+// workspace (tasks dan projects): baris di workspace yang dipilih, atau data
+// pribadi user (workspace_id NULL) jika tidak ada workspace yang dipilih.
+func ownedByWorkspace(db *gorm.DB) *gorm.DB {
+	column := clause.Column{Table: clause.CurrentTable, Name: "workspace_id"}
+	if id := workspaceID(db.Statement.Context); id != nil {
+		return db.Where(clause.Eq{Column: column, Value: *id})
+	}
+	return ownedByUser(db).Where(clause.Eq{Column: column, Value: nil})
+}
+
+// inSpace membatasi query ke tempat yang sama dengan baris milik userID di
+// workspaceID, misalnya untuk menghitung position atau mengecek project task.
+func inSpace(query *gorm.DB, userID, workspaceID *uint) *gorm.DB {
+	if workspaceID != nil {
+		return query.Where("workspace_id = ?", *workspaceID)
+	}
+	query = query.Where("workspace_id IS NULL")
+	if userID != nil {
+		return query.Where("user_id = ?", *userID)
+	}
+	return query.Where("user_id IS NULL")
+}
+
+// claimOrphanedData memberikan data yang dibuat sebelum ada akun (user_id NULL)
+// kepada userID. Dipanggil saat user pertama mendaftar.
+func claimOrphanedData(tx *gorm.DB, userID uint) error {
+	for _, model := range []any{&Task{}, &Project{}, &Tag{}, &SavedFilter{}} {
+		err := tx.Model(model).Where("user_id IS NULL").Update("user_id", userID).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// Page berisi parameter pagination. Jika CursorMode aktif, Offset diabaikan dan
+// hasil diurutkan berdasarkan (created_at, id) mulai setelah Cursor.
+type Page struct {
+	Limit      int
+	Offset     int
+	CursorMode bool
+	Cursor     *Cursor
+}
+
+// Cursor menunjuk ke baris terakhir halaman sebelumnya.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+}
+
+// PageMeta dikirim bersama daftar supaya client tahu total data dan posisi halaman.
+type PageMeta struct {
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// CursorMeta adalah PageMeta untuk mode cursor; next_cursor null berarti sudah halaman terakhir.
+type CursorMeta struct {
+	Total      int64   `json:"total"`
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// parsePage membaca ?limit= dan ?offset=, atau ?cursor= untuk mode cursor
+// (kirim ?cursor= kosong untuk halaman pertama). Limit di atas maksimum ditolak,
+// bukan dipotong diam-diam.
+func parsePage(c *gin.Context) (Page, error) {
+	page := Page{Limit: defaultPageLimit}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return page, fmt.Errorf("invalid limit %q: must be between 1 and %d", value, maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if value, ok := c.GetQuery("cursor"); ok {
+		if c.Query("offset") != "" {
+			return page, errors.New("cursor and offset must not be used together")
+		}
+		page.CursorMode = true
+		if value != "" {
+			cursor, err := decodeCursor(value)
+			if err != nil {
+				return page, err
+			}
+			page.Cursor = cursor
+		}
+		return page, nil
+	}
+
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("invalid offset %q: must be zero or greater", value)
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
+
+// Meta membangun metadata halaman sesuai mode pagination.
+func (p Page) Meta(total int64, next *Cursor) any {
+	if !p.CursorMode {
+		return PageMeta{Total: total, Limit: p.Limit, Offset: p.Offset}
+	}
+
+	meta := CursorMeta{Total: total, Limit: p.Limit}
+	if next != nil {
+		encoded := next.Encode()
+		meta.NextCursor = &encoded
+	}
+	return meta
+}
+
+// Encode menghasilkan string opaque yang aman dipakai di query string.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
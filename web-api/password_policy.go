@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bcrypt hanya memakai 72 byte pertama password; password lebih panjang ditolak
+// supaya dua password berbeda tidak menghasilkan hash yang sama.
+const (
+	defaultPasswordMinLength = 8
+	maxPasswordLength        = 72
+)
+
+// PasswordPolicy adalah syarat minimum password baru saat register dan reset
+// password. MinClasses adalah jumlah jenis karakter berbeda (huruf kecil,
+// huruf besar, angka, simbol) yang harus ada.
+type PasswordPolicy struct {
+	MinLength  int
+	MinClasses int
+}
+
+// parsePasswordPolicy membaca PASSWORD_MIN_LENGTH dan PASSWORD_MIN_CLASSES.
+// Default-nya sama dengan aturan lama: minimal 8 karakter, jenis apa saja.
+func parsePasswordPolicy() (PasswordPolicy, error) {
+	policy := PasswordPolicy{MinLength: defaultPasswordMinLength, MinClasses: 1}
+	if value := getEnv("PASSWORD_MIN_LENGTH", ""); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPasswordLength {
+			return policy, fmt.Errorf("invalid PASSWORD_MIN_LENGTH %q: must be between 1 and %d", value, maxPasswordLength)
+		}
+		policy.MinLength = n
+	}
+	if value := getEnv("PASSWORD_MIN_CLASSES", ""); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 4 {
+			return policy, fmt.Errorf("invalid PASSWORD_MIN_CLASSES %q: must be between 1 and 4", value)
+		}
+		policy.MinClasses = n
+	}
+	return policy, nil
+}
+
+// Validate memeriksa password baru milik akun dengan email tersebut (boleh
+// kosong jika belum diketahui).
+func (p PasswordPolicy) Validate(password, email string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("password must be at most %d bytes", maxPasswordLength)
+	}
+	if passwordClasses(password) < p.MinClasses {
+		return fmt.Errorf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.MinClasses)
+	}
+	if email != "" && strings.EqualFold(password, normalizeEmail(email)) {
+		return errors.New("password must not be the same as the email address")
+	}
+	return nil
+}
+
+// passwordClasses menghitung jenis karakter yang dipakai password.
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
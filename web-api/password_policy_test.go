@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		email    string
+		wantErr  bool
+	}{
+		{"default policy", PasswordPolicy{MinLength: 8, MinClasses: 1}, "abcdefgh", "", false},
+		{"too short", PasswordPolicy{MinLength: 8, MinClasses: 1}, "abcdefg", "", true},
+		{"length counts runes", PasswordPolicy{MinLength: 4, MinClasses: 1}, "ääää", "", false},
+		{"multibyte still too short", PasswordPolicy{MinLength: 5, MinClasses: 1}, "ääää", "", true},
+		{"72 bytes", PasswordPolicy{MinLength: 8, MinClasses: 1}, strings.Repeat("a", maxPasswordLength), "", false},
+		{"73 bytes", PasswordPolicy{MinLength: 8, MinClasses: 1}, strings.Repeat("a", maxPasswordLength+1), "", true},
+		{"not enough classes", PasswordPolicy{MinLength: 8, MinClasses: 3}, "abcdEFGH", "", true},
+		{"three classes", PasswordPolicy{MinLength: 8, MinClasses: 3}, "abcdEF12", "", false},
+		{"symbols count", PasswordPolicy{MinLength: 8, MinClasses: 4}, "abC1 !?x", "", false},
+		{"same as email", PasswordPolicy{MinLength: 8, MinClasses: 1}, "user@example.com", "user@example.com", true},
+		{"same as email ignoring case", PasswordPolicy{MinLength: 8, MinClasses: 1}, "User@Example.COM", " user@example.com ", true},
+		{"contains email", PasswordPolicy{MinLength: 8, MinClasses: 1}, "user@example.com!", "user@example.com", false},
+		{"email unknown", PasswordPolicy{MinLength: 8, MinClasses: 1}, "user@example.com", "", false},
+	}
+	for _, tt := range tests {
+		err := tt.policy.Validate(tt.password, tt.email)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
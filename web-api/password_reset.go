@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultPasswordResetTTL = time.Hour
+
+var ErrResetTokenInvalid = errors.New("invalid or expired reset token")
+
+// PasswordResetToken disimpan sebagai hash dan hanya bisa dipakai sekali.
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Interface untuk layanan reset password
+type PasswordResetService interface {
+	CreateResetToken(ctx context.Context, userID uint) (string, *PasswordResetToken, error)
+	GetResetTokenUser(ctx context.Context, token string) (*User, error)
+	ResetPassword(ctx context.Context, token, passwordHash string) error
+}
+
+// Struct implementasi PasswordResetService dengan GORM
+type PasswordResetServiceImpl struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+func (s *PasswordResetServiceImpl) CreateResetToken(ctx context.Context, userID uint) (string, *PasswordResetToken, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashToken(value),
+		ExpiresAt: time.Now().Add(s.TTL),
+	}
+	if err := s.DB.WithContext(ctx).Create(&token).Error; err != nil {
+		return "", nil, err
+	}
+	return value, &token, nil
+}
+
+// GetResetTokenUser mengembalikan pemilik token yang masih berlaku tanpa
+// memakainya, supaya password baru bisa divalidasi terhadap email user.
+func (s *PasswordResetServiceImpl) GetResetTokenUser(ctx context.Context, token string) (*User, error) {
+	var reset PasswordResetToken
+	err := s.DB.WithContext(ctx).
+		Preload("User").
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(token), time.Now()).
+		First(&reset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && reset.User == nil {
+		return nil, ErrResetTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reset.User, nil
+}
+
+// ResetPassword memakai token lalu mengganti password user. Token reset lain
+// milik user dan semua kredensial login-nya ikut tidak berlaku, sehingga sesi
+// lama harus login ulang.
+func (s *PasswordResetServiceImpl) ResetPassword(ctx context.Context, token, passwordHash string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reset PasswordResetToken
+		err := tx.Where("token_hash = ?", hashToken(token)).First(&reset).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrResetTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		claim := tx.Model(&PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", now)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 || now.After(reset.ExpiresAt) {
+			return ErrResetTokenInvalid
+		}
+
+		err = tx.Model(&PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&User{}).Where("id = ?", reset.UserID).Update("password_hash", passwordHash).Error
+		if err != nil {
+			return err
+		}
+		if err := revokeCredentials(tx, reset.UserID, now); err != nil {
+			return err
+		}
+		return recordAuthEvent(tx, reset.UserID, AuthEventPasswordChanged, nil)
+	})
+}
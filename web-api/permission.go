@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrShareWithSelf      = errors.New("cannot share with yourself")
+	ErrPermissionNotFound = errors.New("permission not found")
+)
+
+// ShareRole menentukan apa yang boleh dilakukan user lain pada task atau
+// project yang dibagikan kepadanya.
+type ShareRole string
+
+const (
+	// ShareViewer hanya bisa membaca.
+	ShareViewer ShareRole = "viewer"
+	// ShareEditor bisa membaca dan mengubah, tetapi tidak bisa menghapus,
+	// memindahkan, menduplikasi, atau membagikan ulang.
+	ShareEditor ShareRole = "editor"
+)
+
+func (r ShareRole) Valid() bool {
+	return r == ShareViewer || r == ShareEditor
+}
+
+func ParseShareRole(value string) (ShareRole, error) {
+	if r := ShareRole(strings.ToLower(strings.TrimSpace(value))); r.Valid() {
+		return r, nil
+	}
+	return "", fmt.Errorf("invalid share role %q: must be one of viewer, editor", value)
+}
+
+// TaskPermission memberi UserID akses ke satu task, atau ke satu project
+// beserta semua task di dalamnya. Tepat satu dari TaskID dan ProjectID diisi.
+// Name dan Email diisi dari tabel users saat daftar permission dimuat.
+type TaskPermission struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TaskID    *uint     `json:"task_id,omitempty" gorm:"uniqueIndex:idx_task_permissions_task_user"`
+	Task      *Task     `json:"task,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	ProjectID *uint     `json:"project_id,omitempty" gorm:"uniqueIndex:idx_task_permissions_project_user"`
+	Project   *Project  `json:"project,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_task_permissions_task_user;uniqueIndex:idx_task_permissions_project_user"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Role      ShareRole `json:"role" gorm:"type:varchar(16);not null"`
+	Name      string    `json:"name,omitempty" gorm:"->;-:migration"`
+	Email     string    `json:"email,omitempty" gorm:"->;-:migration"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShareTarget menunjuk task atau project yang dibagikan.
+type ShareTarget struct {
+	TaskID    *uint
+	ProjectID *uint
+}
+
+// Interface untuk layanan berbagi task dan project
+type PermissionService interface {
+	ListPermissions(ctx context.Context, target ShareTarget) ([]TaskPermission, error)
+	Share(ctx context.Context, target ShareTarget, email string, role ShareRole) (*TaskPermission, error)
+	Unshare(ctx context.Context, target ShareTarget, userID uint) error
+	ListSharedWithMe(ctx context.Context) ([]TaskPermission, error)
+}
+
+// Struct implementasi PermissionService dengan GORM
+type PermissionServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListPermissions menampilkan siapa saja yang punya akses ke target. Hanya
+// pemilik target yang bisa melihatnya.
+func (s *PermissionServiceImpl) ListPermissions(ctx context.Context, target ShareTarget) ([]TaskPermission, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureOwnTarget(db, target); err != nil {
+		return nil, err
+	}
+
+	var permissions []TaskPermission
+	err := target.where(db.Model(&TaskPermission{})).
+		Select("task_permissions.*, u.name, u.email").
+		Joins("JOIN users u ON u.id = task_permissions.user_id").
+		Order("task_permissions.created_at, task_permissions.id").
+		Find(&permissions).Error
+	return permissions, err
+}
+
+// Share memberi user dengan email tersebut akses ke target, atau mengganti
+// role-nya jika target sudah dibagikan kepadanya.
+func (s *PermissionServiceImpl) Share(ctx context.Context, target ShareTarget, email string, role ShareRole) (*TaskPermission, error) {
+	var permission TaskPermission
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureOwnTarget(tx, target); err != nil {
+			return err
+		}
+		var user User
+		err := tx.Where("email = ?", normalizeEmail(email)).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if user.ID == ownerID(ctx) {
+			return ErrShareWithSelf
+		}
+
+		column := "task_id"
+		if target.ProjectID != nil {
+			column = "project_id"
+		}
+		permission = TaskPermission{TaskID: target.TaskID, ProjectID: target.ProjectID, UserID: user.ID, Role: role}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: column}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+		}).Create(&permission).Error
+		if err != nil {
+			return err
+		}
+		permission.Name = user.Name
+		permission.Email = user.Email
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (s *PermissionServiceImpl) Unshare(ctx context.Context, target ShareTarget, userID uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureOwnTarget(db, target); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := target.where(tx).Where("user_id = ?", userID).Delete(&TaskPermission{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPermissionNotFound
+		}
+		return unassignWithoutAccess(tx, userID)
+	})
+}
+
+// ListSharedWithMe mengembalikan semua task dan project yang dibagikan ke user di context.
+func (s *PermissionServiceImpl) ListSharedWithMe(ctx context.Context) ([]TaskPermission, error) {
+	var permissions []TaskPermission
+	err := s.DB.WithContext(ctx).Preload("Task").Preload("Project").
+		Where("user_id = ?", ownerID(ctx)).
+		Order("created_at DESC, id DESC").
+		Find(&permissions).Error
+	return permissions, err
+}
+
+func (t ShareTarget) where(db *gorm.DB) *gorm.DB {
+	if t.ProjectID != nil {
+		return db.Where("project_id = ?", *t.ProjectID)
+	}
+	return db.Where("task_id = ?", *t.TaskID)
+}
+
+// ensureOwnTarget mengecek target milik user (atau workspace) di context;
+// user yang hanya menerima share tidak bisa membagikan ulang.
+func ensureOwnTarget(db *gorm.DB, target ShareTarget) error {
+	var count int64
+	if target.ProjectID != nil {
+		err := db.Model(&Project{}).Scopes(ownedByWorkspace).Where("id = ?", *target.ProjectID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrProjectNotFound
+		}
+		return nil
+	}
+
+	err := db.Model(&Task{}).Scopes(ownedByWorkspace).Where("id = ?", *target.TaskID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// placeInSharedProject memindahkan task baru ke tempat pemilik project jika
+// project_id menunjuk project yang dibagikan ke user sebagai editor, supaya
+// task itu terlihat oleh pemilik dan semua penerima share project.
+func placeInSharedProject(tx *gorm.DB, task *Task) error {
+	if task.ProjectID == nil || task.WorkspaceID != nil {
+		return nil
+	}
+	var project Project
+	err := tx.Scopes(accessibleProjects).First(&project, *task.ProjectID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Dibiarkan; BeforeSave akan mengembalikan ErrProjectNotFound.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	task.UserID = project.UserID
+	task.WorkspaceID = project.WorkspaceID
+	return nil
+}
+
+// accessibleTasks adalah ownedByWorkspace ditambah task yang dibagikan ke user,
+// langsung atau lewat project-nya, dengan role yang cukup untuk request ini
+// (lihat shareAccess). Share hanya berlaku di data pribadi, bukan saat
+// workspace dipilih.
+func accessibleTasks(db *gorm.DB) *gorm.DB {
+	ctx := db.Statement.Context
+	roles := sharedRoles(ctx)
+	if len(roles) == 0 || workspaceID(ctx) != nil {
+		return ownedByWorkspace(db)
+	}
+
+	sub := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	return db.Where(ownedByWorkspace(sub).
+		Or("tasks.id IN (?)", sharedIDs(sub, "task_id", roles)).
+		Or("tasks.project_id IN (?)", sharedIDs(sub, "project_id", roles)))
+}
+
+// accessibleProjects adalah accessibleTasks untuk tabel projects.
+func accessibleProjects(db *gorm.DB) *gorm.DB {
+	ctx := db.Statement.Context
+	roles := sharedRoles(ctx)
+	if len(roles) == 0 || workspaceID(ctx) != nil {
+		return ownedByWorkspace(db)
+	}
+
+	sub := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	return db.Where(ownedByWorkspace(sub).Or("projects.id IN (?)", sharedIDs(sub, "project_id", roles)))
+}
+
+// sharedIDs adalah subquery id task atau project (column) yang dibagikan ke user di context.
+func sharedIDs(db *gorm.DB, column string, roles []ShareRole) *gorm.DB {
+	return db.Model(&TaskPermission{}).Select(column).
+		Where("user_id = ? AND role IN ? AND "+column+" IS NOT NULL", ownerID(db.Statement.Context), roles)
+}
+
+type shareRoleContextKey struct{}
+
+// sharedRoles mengembalikan role share yang cukup untuk request di context.
+// Tanpa shareAccess, share tidak berlaku sama sekali.
+func sharedRoles(ctx context.Context) []ShareRole {
+	switch minimum, _ := ctx.Value(shareRoleContextKey{}).(ShareRole); minimum {
+	case ShareViewer:
+		return []ShareRole{ShareViewer, ShareEditor}
+	case ShareEditor:
+		return []ShareRole{ShareEditor}
+	}
+	return nil
+}
+
+// shareAccess menentukan role share minimum dari method request: viewer untuk
+// GET/HEAD dan editor untuk method lain. Semua query lewat accessibleTasks dan
+// accessibleProjects memakai nilai ini.
+func shareAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		minimum := ShareEditor
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			minimum = ShareViewer
+		}
+		ctx := context.WithValue(c.Request.Context(), shareRoleContextKey{}, minimum)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type shareRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// PermissionHandler berisi HTTP handler untuk /tasks/:id/permissions,
+// /projects/:id/permissions, dan /shared.
+type PermissionHandler struct {
+	Service PermissionService
+}
+
+// shareTarget membaca :id sebagai task atau project tergantung route-nya.
+type shareTarget func(c *gin.Context) (ShareTarget, bool)
+
+func taskShareTarget(c *gin.Context) (ShareTarget, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid task id")
+		return ShareTarget{}, false
+	}
+	taskID := uint(id)
+	return ShareTarget{TaskID: &taskID}, true
+}
+
+func projectShareTarget(c *gin.Context) (ShareTarget, bool) {
+	id, ok := parseProjectID(c)
+	if !ok {
+		return ShareTarget{}, false
+	}
+	return ShareTarget{ProjectID: &id}, true
+}
+
+func (h *PermissionHandler) ListPermissions(target shareTarget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, ok := target(c)
+		if !ok {
+			return
+		}
+
+		permissions, err := h.Service.ListPermissions(c.Request.Context(), t)
+		if err != nil {
+			permissionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"permissions": permissions})
+	}
+}
+
+// Share membagikan task atau project ke user lain, atau mengganti role-nya.
+func (h *PermissionHandler) Share(target shareTarget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, ok := target(c)
+		if !ok {
+			return
+		}
+		var req shareRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		role, err := ParseShareRole(req.Role)
+		if err != nil {
+			problem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		permission, err := h.Service.Share(c.Request.Context(), t, req.Email, role)
+		if err != nil {
+			permissionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, permission)
+	}
+}
+
+func (h *PermissionHandler) Unshare(target shareTarget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, ok := target(c)
+		if !ok {
+			return
+		}
+		userID, ok := parseMemberID(c)
+		if !ok {
+			return
+		}
+
+		if err := h.Service.Unshare(c.Request.Context(), t, userID); err != nil {
+			permissionError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListShared menampilkan task dan project yang dibagikan ke user yang login.
+func (h *PermissionHandler) ListShared(c *gin.Context) {
+	permissions, err := h.Service.ListSharedWithMe(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shared": permissions})
+}
+
+func permissionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, ErrProjectNotFound),
+		errors.Is(err, ErrUserNotFound), errors.Is(err, ErrPermissionNotFound):
+		problem(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrShareWithSelf):
+		problem(c, http.StatusBadRequest, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority adalah tingkat prioritas task, dari rendah ke tinggi.
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+var priorities = []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+func (p Priority) Valid() bool {
+	for _, priority := range priorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePriority menerima nilai tanpa memperhatikan huruf besar/kecil; string kosong menjadi medium.
+func ParsePriority(value string) (Priority, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return PriorityMedium, nil
+	}
+	if p := Priority(value); p.Valid() {
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q: must be one of low, medium, high, urgent", value)
+}
+
+// priorityRankSQL mengurutkan kolom priority sesuai tingkatannya, bukan abjad.
+// Dibangun dari konstanta sehingga aman dipakai langsung di ORDER BY.
+func priorityRankSQL() string {
+	var b strings.Builder
+	b.WriteString("CASE priority")
+	for rank, priority := range priorities {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", priority, rank+1)
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
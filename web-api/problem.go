@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem adalah body error RFC 7807 untuk semua handler REST. Type selalu
+// about:blank karena jenis error sudah dibedakan lewat status; Extensions
+// berisi member tambahan seperti id atau blocked_by dan ditulis di level atas.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors berisi field request yang tidak valid, diisi invalidRequest.
+	Errors     []FieldError `json:"errors,omitempty"`
+	Extensions gin.H        `json:"-"`
+}
+
+// FieldError menjelaskan satu field request yang tidak valid. Field memakai
+// nama JSON, dengan titik dan indeks untuk field bersarang (items[0].title).
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON menulis member standar lebih dulu, lalu Extensions urut nama.
+// Extension tidak boleh menimpa member standar.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	data, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(p.Extensions))
+	for name := range p.Extensions {
+		if _, ok := members[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := bytes.NewBuffer(data[:len(data)-1])
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(p.Extensions[name])
+		if err != nil {
+			return nil, err
+		}
+		out.WriteByte(',')
+		out.Write(key)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// problem menulis respons application/problem+json lalu menghentikan handler
+// berikutnya. extensions (opsional) ikut ditulis sebagai member tambahan.
+func problem(c *gin.Context, status int, detail string, extensions ...gin.H) {
+	p := newProblem(c, status, detail)
+	for _, extension := range extensions {
+		if p.Extensions == nil {
+			p.Extensions = gin.H{}
+		}
+		for name, value := range extension {
+			p.Extensions[name] = value
+		}
+	}
+	writeProblem(c, p)
+}
+
+// invalidRequest menulis 400 untuk body atau query yang gagal di-bind. Error
+// dari tag binding dan tipe JSON yang salah dijadikan daftar errors per field.
+func invalidRequest(c *gin.Context, err error) {
+	p := newProblem(c, http.StatusBadRequest, err.Error())
+	var validation validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validation):
+		p.Detail = "request is invalid"
+		for _, field := range validation {
+			p.Errors = append(p.Errors, FieldError{Field: validationField(field), Message: validationMessage(field)})
+		}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		p.Detail = "request is invalid"
+		p.Errors = []FieldError{{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type)}}
+	}
+	writeProblem(c, p)
+}
+
+func newProblem(c *gin.Context, status int, detail string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+	}
+}
+
+func writeProblem(c *gin.Context, p Problem) {
+	// gin tidak menimpa Content-Type yang sudah diisi.
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// problemNotFound mengganti respons teks bawaan gin untuk path yang tidak
+// terdaftar.
+func problemNotFound(c *gin.Context) {
+	problem(c, http.StatusNotFound, "route not found")
+}
+
+// useJSONFieldNames membuat validator melaporkan nama field dari tag json
+// atau form, bukan nama field Go, supaya cocok dengan isi request.
+func useJSONFieldNames() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// validationField membuang nama struct request di awal namespace, misalnya
+// registerRequest.email menjadi email.
+func validationField(field validator.FieldError) string {
+	_, name, ok := strings.Cut(field.Namespace(), ".")
+	if !ok {
+		return field.Field()
+	}
+	return name
+}
+
+func validationMessage(field validator.FieldError) string {
+	switch field.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(field.Param(), " ", ", ")
+	case "min", "gte":
+		if field.Kind() == reflect.String || field.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at least %s characters or items", field.Param())
+		}
+		return "must be at least " + field.Param()
+	case "max", "lte":
+		if field.Kind() == reflect.String || field.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at most %s characters or items", field.Param())
+		}
+		return "must be at most " + field.Param()
+	case "len":
+		return fmt.Sprintf("must have exactly %s characters or items", field.Param())
+	default:
+		return fmt.Sprintf("failed the %q rule", field.Tag())
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem Problem
+		want    string
+	}{
+		{
+			"standard members only",
+			Problem{Type: "about:blank", Title: "Not Found", Status: 404, Detail: "task not found", Instance: "/tasks/1"},
+			`{"type":"about:blank","title":"Not Found","status":404,"detail":"task not found","instance":"/tasks/1"}`,
+		},
+		{
+			"extensions are sorted after standard members",
+			Problem{Type: "about:blank", Title: "Conflict", Status: 409, Extensions: gin.H{"zeta": 1, "blocked_by": []uint{2, 3}}},
+			`{"type":"about:blank","title":"Conflict","status":409,"blocked_by":[2,3],"zeta":1}`,
+		},
+		{
+			"extensions do not override standard members",
+			Problem{Type: "about:blank", Title: "Conflict", Status: 409, Extensions: gin.H{"status": 200, "title": "OK", "id": 5}},
+			`{"type":"about:blank","title":"Conflict","status":409,"id":5}`,
+		},
+		{
+			"field errors",
+			Problem{Type: "about:blank", Title: "Bad Request", Status: 400, Errors: []FieldError{{Field: "title", Message: "is required"}}},
+			`{"type":"about:blank","title":"Bad Request","status":400,"errors":[{"field":"title","message":"is required"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.problem)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/tasks/9", nil)
+
+	problem(c, http.StatusConflict, "task has dependents", gin.H{"blocked_by": []uint{4}}, gin.H{"id": 9})
+
+	if !c.IsAborted() {
+		t.Error("problem did not abort the handler chain")
+	}
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, problemContentType)
+	}
+	want := `{"type":"about:blank","title":"Conflict","status":409,"detail":"task has dependents","instance":"/tasks/9","blocked_by":[4],"id":9}`
+	if got := w.Body.String(); got != want {
+		t.Errorf("body:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useJSONFieldNames()
+
+	type item struct {
+		Title string `json:"title" binding:"required"`
+	}
+	type request struct {
+		Email    string `json:"email" binding:"required,email"`
+		Priority string `json:"priority" binding:"omitempty,oneof=low medium high"`
+		Count    int    `json:"count" binding:"max=10"`
+		Items    []item `json:"items" binding:"dive"`
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantDetail string
+		wantErrors []FieldError
+	}{
+		{
+			"validation errors use JSON names",
+			`{"email":"nope","priority":"urgent","count":11,"items":[{"title":"a"},{}]}`,
+			"request is invalid",
+			[]FieldError{
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "priority", Message: "must be one of: low, medium, high"},
+				{Field: "count", Message: "must be at most 10"},
+				{Field: "items[1].title", Message: "is required"},
+			},
+		},
+		{
+			"missing required field",
+			`{}`,
+			"request is invalid",
+			[]FieldError{{Field: "email", Message: "is required"}},
+		},
+		{
+			"wrong JSON type",
+			`{"email":"a@example.com","count":"many"}`,
+			"request is invalid",
+			[]FieldError{{Field: "count", Message: "must be an integer"}},
+		},
+		{
+			"malformed JSON",
+			`{"email":`,
+			"unexpected EOF",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var req request
+			err := c.ShouldBindJSON(&req)
+			if err == nil {
+				t.Fatal("ShouldBindJSON() succeeded")
+			}
+			invalidRequest(c, err)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			var got Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Detail != tt.wantDetail {
+				t.Errorf("detail = %q, want %q", got.Detail, tt.wantDetail)
+			}
+			if len(got.Errors) != len(tt.wantErrors) {
+				t.Fatalf("errors = %+v, want %+v", got.Errors, tt.wantErrors)
+			}
+			for i := range got.Errors {
+				if got.Errors[i] != tt.wantErrors[i] {
+					t.Errorf("errors[%d] = %+v, want %+v", i, got.Errors[i], tt.wantErrors[i])
+				}
+			}
+		})
+	}
+}
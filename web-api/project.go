@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrProjectNotFound = errors.New("project not found")
+
+// Project adalah list untuk mengelompokkan task.
+type Project struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      *uint      `json:"-" gorm:"index"`
+	User        *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	WorkspaceID *uint      `json:"workspace_id" gorm:"index"`
+	Workspace   *Workspace `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name        string     `json:"name" gorm:"not null"`
+	Description string     `json:"description"`
+	// ArchivedAt diisi saat project diarsipkan; project arsip dan task-nya
+	// hanya muncul di daftar dengan ?archived=true.
+	ArchivedAt *time.Time `json:"archived_at" gorm:"index"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	// Links diisi saat project di-encode ke JSON.
+	Links Links `json:"_links,omitempty" gorm:"-"`
+}
+
+// Interface untuk layanan project
+type ProjectService interface {
+	ListProjects(ctx context.Context, archived bool) ([]Project, error)
+	GetProject(ctx context.Context, id uint) (*Project, error)
+	CreateProject(ctx context.Context, project *Project) error
+	UpdateProject(ctx context.Context, project *Project) error
+	DeleteProject(ctx context.Context, id uint) error
+}
+
+// Struct implementasi ProjectService dengan GORM
+type ProjectServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListProjects mengembalikan project yang belum diarsipkan, atau hanya yang
+// sudah diarsipkan jika archived true.
+func (s *ProjectServiceImpl) ListProjects(ctx context.Context, archived bool) ([]Project, error) {
+	var projects []Project
+	query := s.DB.WithContext(ctx).Scopes(accessibleProjects)
+	if archived {
+		query = query.Where("archived_at IS NOT NULL")
+	} else {
+		query = query.Where("archived_at IS NULL")
+	}
+	err := query.Order("name, id").Find(&projects).Error
+	return projects, err
+}
+
+func (s *ProjectServiceImpl) GetProject(ctx context.Context, id uint) (*Project, error) {
+	var project Project
+	err := s.DB.WithContext(ctx).Scopes(accessibleProjects).First(&project, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (s *ProjectServiceImpl) CreateProject(ctx context.Context, project *Project) error {
+	owner := ownerID(ctx)
+	project.UserID = &owner
+	project.WorkspaceID = workspaceID(ctx)
+	return s.DB.WithContext(ctx).Create(project).Error
+}
+
+func (s *ProjectServiceImpl) UpdateProject(ctx context.Context, project *Project) error {
+	return s.DB.WithContext(ctx).Save(project).Error
+}
+
+// DeleteProject menghapus project; task di dalamnya tidak ikut terhapus, project_id-nya menjadi NULL.
+// Hanya pemilik yang bisa menghapus, bukan user yang menerima share.
+func (s *ProjectServiceImpl) DeleteProject(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByWorkspace).Delete(&Project{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// ensureProjectExists mengecek project ada di tempat yang sama dengan task yang
+// memakainya: milik userID, atau di workspaceID jika task ada di workspace.
+func ensureProjectExists(tx *gorm.DB, id uint, userID, workspaceID *uint) error {
+	var count int64
+	if err := inSpace(tx.Model(&Project{}).Where("id = ?", id), userID, workspaceID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type projectRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// ProjectHandler berisi HTTP handler untuk /projects. BaseURL dipakai untuk
+// link di feed aktivitas.
+type ProjectHandler struct {
+	Service ProjectService
+	Tasks   TaskService
+	BaseURL string
+}
+
+// ListProjects mengembalikan project yang belum diarsipkan, atau hanya project
+// arsip dengan ?archived=true.
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	archived := false
+	if value := c.Query("archived"); value != "" {
+		var err error
+		if archived, err = strconv.ParseBool(value); err != nil {
+			problem(c, http.StatusBadRequest, fmt.Sprintf("invalid archived %q", value))
+			return
+		}
+	}
+
+	projects, err := h.Service.ListProjects(c.Request.Context(), archived)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	req, ok := bindProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	project := Project{Name: req.Name, Description: req.Description}
+	if err := h.Service.CreateProject(c.Request.Context(), &project); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	project, ok := h.loadProject(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	project, ok := h.loadProject(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	project.Name = req.Name
+	project.Description = req.Description
+	if err := h.Service.UpdateProject(c.Request.Context(), project); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, true)
+}
+
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, false)
+}
+
+// setProjectArchived mengarsipkan atau mengembalikan project. Task di
+// dalamnya tidak diubah, tetapi ikut tersembunyi selama project diarsipkan.
+func (h *ProjectHandler) setProjectArchived(c *gin.Context, archived bool) {
+	project, ok := h.loadProject(c)
+	if !ok {
+		return
+	}
+
+	if archived == (project.ArchivedAt != nil) {
+		c.JSON(http.StatusOK, project)
+		return
+	}
+	project.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		project.ArchivedAt = &now
+	}
+	if err := h.Service.UpdateProject(c.Request.Context(), project); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, ok := parseProjectID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.DeleteProject(c.Request.Context(), id)
+	if errors.Is(err, ErrProjectNotFound) {
+		projectNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListProjectTasks mengembalikan task dalam project; filter query string task tetap berlaku.
+func (h *ProjectHandler) ListProjectTasks(c *gin.Context) {
+	project, ok := h.loadProject(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.ProjectID = &project.ID
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	view, err := parseTaskView(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if page.CursorMode && len(filter.Sort) > 0 {
+		problem(c, http.StatusBadRequest, "sort is not supported with cursor pagination")
+		return
+	}
+
+	result, err := h.Tasks.ListTasks(c.Request.Context(), filter, page, view.include)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	tasks, err := view.tasks(result.Tasks)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": project,
+		"tasks":   tasks,
+		"meta":    page.Meta(result.Total, result.Next),
+	})
+}
+
+func (h *ProjectHandler) loadProject(c *gin.Context) (*Project, bool) {
+	id, ok := parseProjectID(c)
+	if !ok {
+		return nil, false
+	}
+
+	project, err := h.Service.GetProject(c.Request.Context(), id)
+	if errors.Is(err, ErrProjectNotFound) {
+		projectNotFound(c, id)
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return project, true
+}
+
+func bindProjectRequest(c *gin.Context) (projectRequest, bool) {
+	var req projectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		problem(c, http.StatusBadRequest, "name must not be empty")
+		return req, false
+	}
+	return req, true
+}
+
+func parseProjectID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid project id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func projectNotFound(c *gin.Context, id uint) {
+	problem(c, http.StatusNotFound, "project not found", gin.H{
+		"id": id,
+	})
+}
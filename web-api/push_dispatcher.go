@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pushBatch = 100
+	// pushMaxAge membatasi notifikasi yang dikirim: notifikasi yang lebih lama
+	// (misalnya saat Web Push baru diaktifkan) tidak dikirim lagi.
+	pushMaxAge = time.Hour
+)
+
+// PushDispatcher berjalan di background dan mengirim notifikasi mention ke
+// browser yang berlangganan Web Push. Setiap notifikasi dikirim sekali.
+type PushDispatcher struct {
+	DB       *gorm.DB
+	Push     *WebPush
+	Interval time.Duration
+}
+
+// Run mengirim notifikasi baru setiap Interval sampai ctx dibatalkan.
+func (d *PushDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.pushPending(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("push dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *PushDispatcher) pushPending(ctx context.Context) error {
+	db := d.DB.WithContext(ctx)
+	for {
+		var notifications []Notification
+		err := db.Preload("Actor").Preload("Task").
+			Where("type = ? AND pushed_at IS NULL AND created_at > ?", NotificationMention, time.Now().Add(-pushMaxAge)).
+			Where("user_id IN (?)", db.Model(&PushSubscription{}).Select("user_id")).
+			Order("id").
+			Limit(pushBatch).
+			Find(&notifications).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range notifications {
+			if err := d.push(ctx, &notifications[i]); err != nil {
+				log.Printf("push dispatcher: notification %d: %v", notifications[i].ID, err)
+			}
+		}
+		if len(notifications) < pushBatch {
+			return nil
+		}
+	}
+}
+
+// push menandai notifikasi sudah dikirim (hanya jika belum ditandai instance
+// lain), lalu mengirimnya ke semua subscription penerima.
+func (d *PushDispatcher) push(ctx context.Context, notification *Notification) error {
+	db := d.DB.WithContext(ctx)
+	claim := db.Model(&Notification{}).
+		Where("id = ? AND pushed_at IS NULL", notification.ID).
+		UpdateColumn("pushed_at", time.Now())
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+	return d.Push.NotifyUser(ctx, d.DB, notification.UserID, mentionPushMessage(notification))
+}
+
+func mentionPushMessage(notification *Notification) PushMessage {
+	actor := "Someone"
+	if notification.Actor != nil {
+		actor = notification.Actor.Name
+		if actor == "" && notification.Actor.Username != nil {
+			actor = "@" + *notification.Actor.Username
+		}
+		if actor == "" {
+			actor = notification.Actor.Email
+		}
+	}
+	message := PushMessage{Type: string(NotificationMention), Title: actor + " mentioned you", TaskID: notification.TaskID}
+	if notification.Task != nil {
+		message.Body = fmt.Sprintf("In a comment on %q", notification.Task.Title)
+	}
+	return message
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pushSubscriptionRequest mengikuti bentuk PushSubscription.toJSON() di browser.
+type pushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+	Keys     struct {
+		P256DH string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys"`
+}
+
+// PushHandler berisi HTTP handler untuk /me/push.
+type PushHandler struct {
+	Service PushSubscriptionService
+	Push    *WebPush
+}
+
+// PublicKey mengembalikan kunci publik VAPID untuk applicationServerKey.
+func (h *PushHandler) PublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"public_key": h.Push.PublicKeyString()})
+}
+
+func (h *PushHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.Service.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// Subscribe mendaftarkan subscription dari pushManager.subscribe(). Kunci
+// browser dicek dengan mengenkripsi payload percobaan supaya subscription yang
+// rusak ditolak di sini, bukan saat pengiriman.
+func (h *PushHandler) Subscribe(c *gin.Context) {
+	var req pushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	endpoint, err := url.Parse(req.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" || len(req.Endpoint) > 2048 {
+		problem(c, http.StatusBadRequest, "endpoint must be an absolute https URL")
+		return
+	}
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	subscription := PushSubscription{
+		Endpoint:  req.Endpoint,
+		P256DH:    req.Keys.P256DH,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	if _, err := encryptPushPayload(&subscription, []byte("{}")); err != nil {
+		problem(c, http.StatusBadRequest, "invalid subscription keys")
+		return
+	}
+
+	if err := h.Service.Subscribe(c.Request.Context(), &subscription); err != nil {
+		pushError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *PushHandler) Unsubscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid subscription id")
+		return
+	}
+
+	if err := h.Service.Unsubscribe(c.Request.Context(), uint(id)); err != nil {
+		pushError(c, err, uint(id))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func pushError(c *gin.Context, err error, id uint) {
+	switch {
+	case errors.Is(err, ErrPushSubscriptionNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrTooManyPushSubscriptions):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
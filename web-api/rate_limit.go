@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRateLimit          = 300
+	defaultAnonymousRateLimit = 60
+	// rateLimitSweepInterval menentukan seberapa sering bucket yang sudah
+	// penuh kembali dibuang supaya map tidak tumbuh terus.
+	rateLimitSweepInterval = 5 * time.Minute
+)
+
+// RateLimit adalah batas request per menit. Bucket bisa menampung Limit token
+// sekaligus, jadi client boleh mengirim burst sebesar itu lalu dibatasi ke
+// Limit token per menit.
+type RateLimit struct {
+	Limit int
+}
+
+func (l RateLimit) perSecond() float64 {
+	return float64(l.Limit) / 60
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiter adalah token bucket di memori per identitas. Karena disimpan di
+// memori proses, batasnya berlaku per instance server.
+type RateLimiter struct {
+	// Authenticated dipakai untuk user dan API key, Anonymous untuk request
+	// tanpa login yang dibedakan berdasarkan IP. Limit 0 berarti tanpa batas.
+	Authenticated RateLimit
+	Anonymous     RateLimit
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// rateLimitResult adalah hasil take yang dipakai untuk header X-RateLimit-*.
+type rateLimitResult struct {
+	allowed    bool
+	remaining  int
+	reset      time.Time
+	retryAfter time.Duration
+}
+
+// take mengambil satu token dari bucket milik key.
+func (l *RateLimiter) take(key string, limit RateLimit, now time.Time) rateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = map[string]*bucket{}
+	}
+	capacity := float64(limit.Limit)
+	rate := limit.perSecond()
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	result := rateLimitResult{allowed: b.tokens >= 1}
+	if result.allowed {
+		b.tokens--
+	} else {
+		result.retryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	result.remaining = int(b.tokens)
+	result.reset = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return result
+}
+
+// sweep membuang bucket yang sudah terisi penuh lagi; hasilnya sama dengan
+// bucket baru sehingga aman dihapus. Bucket kosong selalu penuh lagi setelah
+// satu menit, berapa pun limit-nya.
+func (l *RateLimiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitKey memilih identitas request: API key (tiap key punya bucket
+// sendiri), lalu user, lalu IP untuk request tanpa login. IP diambil dari
+// ClientIP, yang hanya membaca X-Forwarded-For dari proxy di TRUSTED_PROXIES.
+func rateLimitKey(c *gin.Context) (string, bool) {
+	return rateLimitIdentity(c.Request.Context(), c.ClientIP())
+}
+
+// rateLimitIdentity dipakai REST dan gRPC supaya satu user atau API key
+// berbagi bucket yang sama di kedua protokol.
+func rateLimitIdentity(ctx context.Context, ip string) (string, bool) {
+	if key := apiKeyFromContext(ctx); key != nil {
+		return fmt.Sprintf("api_key:%d", key.ID), true
+	}
+	if user := userFromContext(ctx); user != nil {
+		return fmt.Sprintf("user:%d", user.ID), true
+	}
+	return "ip:" + ip, false
+}
+
+// limitFor mengembalikan batas untuk identitas hasil rateLimitIdentity.
+func (l *RateLimiter) limitFor(authenticated bool) RateLimit {
+	if authenticated {
+		return l.Authenticated
+	}
+	return l.Anonymous
+}
+
+// rateLimit menolak request dengan 429 jika bucket identitasnya kosong.
+// Middleware ini dipasang setelah requireAuth supaya user dan API key sudah
+// diketahui; tanpa login, batas anonim per IP yang berlaku.
+func rateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, authenticated := rateLimitKey(c)
+		limit := limiter.limitFor(authenticated)
+		if limit.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result := limiter.take(key, limit, time.Now())
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.reset.Unix(), 10))
+		if !result.allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.retryAfter.Seconds()))))
+			problem(c, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+// parseRateLimitEnv membaca batas request per menit dari environment
+// variable. Nilai 0 mematikan pembatasan.
+func parseRateLimitEnv(key string, fallback int) (RateLimit, error) {
+	limit, err := parseCountEnv(key, fallback)
+	return RateLimit{Limit: limit}, err
+}
+
+// parseTrustedProxies membaca TRUSTED_PROXIES: daftar IP atau CIDR reverse
+// proxy dipisah koma. Kosong (default) berarti tidak ada proxy yang dipercaya,
+// sehingga X-Forwarded-For dan X-Real-IP diabaikan dan IP client adalah alamat
+// koneksinya.
+func parseTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
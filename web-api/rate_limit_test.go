@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	limit := RateLimit{Limit: 3} // 3 token, terisi 1 token per 20 detik.
+	limiter := &RateLimiter{}
+
+	steps := []struct {
+		name          string
+		key           string
+		at            time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}{
+		{"first request", "a", 0, true, 2, 0},
+		{"burst", "a", 0, true, 1, 0},
+		{"burst until empty", "a", 0, true, 0, 0},
+		{"empty bucket", "a", 0, false, 0, 20 * time.Second},
+		{"other key has own bucket", "b", 0, true, 2, 0},
+		{"partially refilled", "a", 10 * time.Second, false, 0, 10 * time.Second},
+		{"one token refilled", "a", 20 * time.Second, true, 0, 0},
+		{"refill is capped at limit", "a", time.Hour, true, 2, 0},
+	}
+	for _, step := range steps {
+		now := start.Add(step.at)
+		got := limiter.take(step.key, limit, now)
+		if got.allowed != step.wantAllowed || got.remaining != step.wantRemaining {
+			t.Errorf("%s: take() = allowed %v remaining %d, want %v %d", step.name, got.allowed, got.remaining, step.wantAllowed, step.wantRemaining)
+		}
+		if diff := got.retryAfter - step.wantRetry; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("%s: retryAfter = %s, want %s", step.name, got.retryAfter, step.wantRetry)
+		}
+		if got.reset.Before(now) {
+			t.Errorf("%s: reset %s is before now", step.name, got.reset)
+		}
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := &RateLimiter{}
+	limiter.take("idle", RateLimit{Limit: 10}, now)
+	limiter.take("active", RateLimit{Limit: 10}, now.Add(50*time.Second))
+
+	limiter.sweep(now.Add(61 * time.Second))
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("sweep kept a bucket idle for more than a minute")
+	}
+	if _, ok := limiter.buckets["active"]; !ok {
+		t.Error("sweep removed a bucket used within the last minute")
+	}
+
+	// take menjalankan sweep sendiri setelah rateLimitSweepInterval.
+	limiter.take("new", RateLimit{Limit: 10}, now.Add(61*time.Second+rateLimitSweepInterval+time.Second))
+	if _, ok := limiter.buckets["active"]; ok {
+		t.Error("take did not sweep after rateLimitSweepInterval")
+	}
+}
+
+func TestRateLimitIdentity(t *testing.T) {
+	user := &User{ID: 4}
+	key := &APIKey{ID: 9, User: user}
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		wantKey           string
+		wantAuthenticated bool
+	}{
+		{"anonymous", context.Background(), "ip:203.0.113.5", false},
+		{"user", withUser(context.Background(), user), "user:4", true},
+		{"api key", context.WithValue(withUser(context.Background(), user), apiKeyContextKey{}, key), "api_key:9", true},
+	}
+	for _, tt := range tests {
+		got, authenticated := rateLimitIdentity(tt.ctx, "203.0.113.5")
+		if got != tt.wantKey || authenticated != tt.wantAuthenticated {
+			t.Errorf("%s: rateLimitIdentity() = (%q, %v), want (%q, %v)", tt.name, got, authenticated, tt.wantKey, tt.wantAuthenticated)
+		}
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"10.0.0.1", []string{"10.0.0.1"}},
+		{" 10.0.0.0/8 , ,192.168.1.1 ", []string{"10.0.0.0/8", "192.168.1.1"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("TRUSTED_PROXIES", tt.value)
+		if got := parseTrustedProxies(); !slices.Equal(got, tt.want) {
+			t.Errorf("TRUSTED_PROXIES=%q: got %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestRateLimitForwardedFor memastikan client tidak bisa mendapat bucket baru
+// dengan mengganti X-Forwarded-For, kecuali header itu datang dari proxy yang
+// dipercaya.
+func TestRateLimitForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		wantStatus []int
+	}{
+		{"no trusted proxy", nil, "198.51.100.7:5000", []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}},
+		{"untrusted peer", []string{"10.0.0.1"}, "198.51.100.7:5000", []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}},
+		{"trusted proxy", []string{"10.0.0.1"}, "10.0.0.1:5000", []int{http.StatusOK, http.StatusOK, http.StatusOK}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatal(err)
+			}
+			router.GET("/", rateLimit(&RateLimiter{Anonymous: RateLimit{Limit: 1}}), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			for i, want := range tt.wantStatus {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = tt.remoteAddr
+				req.Header.Set("X-Forwarded-For", "203.0.113."+strconv.Itoa(i+1))
+				req.Header.Set("X-Real-IP", "203.0.113."+strconv.Itoa(i+1))
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != want {
+					t.Errorf("request %d: status %d, want %d", i+1, w.Code, want)
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: 429 without Retry-After", i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reactionEmoji adalah emoji yang boleh dipakai sebagai reaksi, beserta nama
+// ASCII-nya (sama dengan GitHub) supaya mudah dikirim di URL.
+var reactionEmoji = []struct {
+	emoji string
+	name  string
+}{
+	{"👍", "+1"},
+	{"👎", "-1"},
+	{"😄", "laugh"},
+	{"🎉", "hooray"},
+	{"😕", "confused"},
+	{"❤️", "heart"},
+	{"🚀", "rocket"},
+	{"👀", "eyes"},
+}
+
+// ParseReaction menerima emoji atau namanya dan mengembalikan emojinya.
+func ParseReaction(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, r := range reactionEmoji {
+		if value == r.emoji || value == r.name || value+"\ufe0f" == r.emoji {
+			return r.emoji, nil
+		}
+	}
+	names := make([]string, len(reactionEmoji))
+	for i, r := range reactionEmoji {
+		names[i] = r.emoji + " (" + r.name + ")"
+	}
+	return "", fmt.Errorf("invalid reaction %q: must be one of %s", value, strings.Join(names, ", "))
+}
+
+// CommentReaction adalah satu reaksi UserID pada komentar CommentID. Satu user
+// bisa memberi beberapa emoji berbeda, tetapi setiap emoji hanya sekali.
+type CommentReaction struct {
+	CommentID uint      `json:"comment_id" gorm:"primaryKey"`
+	Comment   *Comment  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint      `json:"user_id" gorm:"primaryKey;index"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Emoji     string    `json:"emoji" gorm:"primaryKey;type:varchar(16)"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionCount adalah jumlah satu emoji di sebuah komentar. Reacted bernilai
+// true jika user yang meminta ikut memberi reaksi itu.
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+// AddReaction memberi reaksi emoji atas nama user di context. Reaksi yang sudah
+// ada tidak mengubah apa-apa.
+func (s *CommentServiceImpl) AddReaction(ctx context.Context, taskID, commentID uint, emoji string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findTaskComment(tx, taskID, commentID); err != nil {
+			return err
+		}
+		reaction := CommentReaction{CommentID: commentID, UserID: ownerID(ctx), Emoji: emoji}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error
+	})
+}
+
+// RemoveReaction menghapus reaksi emoji milik user di context, jika ada.
+func (s *CommentServiceImpl) RemoveReaction(ctx context.Context, taskID, commentID uint, emoji string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findTaskComment(tx, taskID, commentID); err != nil {
+			return err
+		}
+		return tx.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, ownerID(ctx), emoji).
+			Delete(&CommentReaction{}).Error
+	})
+}
+
+// loadReactions mengisi Reactions setiap komentar dengan jumlah per emoji,
+// diurutkan seperti reactionEmoji.
+func loadReactions(tx *gorm.DB, comments []Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+		comments[i].Reactions = []ReactionCount{}
+	}
+
+	var rows []struct {
+		CommentID uint
+		Emoji     string
+		Count     int
+		Reacted   int
+	}
+	err := tx.Session(&gorm.Session{NewDB: true}).Model(&CommentReaction{}).
+		Select("comment_id, emoji, COUNT(*) AS count, SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS reacted", ownerID(tx.Statement.Context)).
+		Where("comment_id IN ?", ids).
+		Group("comment_id, emoji").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		for _, r := range reactionEmoji {
+			for _, row := range rows {
+				if row.CommentID == comments[i].ID && row.Emoji == r.emoji {
+					comments[i].Reactions = append(comments[i].Reactions, ReactionCount{
+						Emoji:   row.Emoji,
+						Count:   row.Count,
+						Reacted: row.Reacted > 0,
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
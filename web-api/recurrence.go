@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecurrenceScheduler berjalan di background dan membuat kemunculan berikutnya
+// untuk task berulang yang sudah diselesaikan.
+type RecurrenceScheduler struct {
+	DB       *gorm.DB
+	Interval time.Duration
+	wake     chan struct{}
+}
+
+func NewRecurrenceScheduler(db *gorm.DB, interval time.Duration) *RecurrenceScheduler {
+	return &RecurrenceScheduler{
+		DB:       db,
+		Interval: interval,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Notify membangunkan scheduler tanpa menunggu tick berikutnya. Tidak pernah blocking.
+func (s *RecurrenceScheduler) Notify() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run memproses task berulang sampai ctx dibatalkan.
+func (s *RecurrenceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.scheduleCompleted(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("recurrence scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *RecurrenceScheduler) scheduleCompleted(ctx context.Context) error {
+	var tasks []Task
+	err := preloadTaskRelations(s.DB.WithContext(ctx)).
+		Where("status = ? AND recurrence <> '' AND recurrence_scheduled = ?", StatusDone, false).
+		Order("id").
+		Limit(100).
+		Find(&tasks).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if err := s.scheduleNext(ctx, &tasks[i]); err != nil {
+			log.Printf("recurrence scheduler: task %d: %v", tasks[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// scheduleNext menandai task sebagai sudah dijadwalkan lalu membuat kemunculan
+// berikutnya dalam satu transaksi, sehingga aman dijalankan di lebih dari satu instance.
+func (s *RecurrenceScheduler) scheduleNext(ctx context.Context, task *Task) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claim := tx.Model(&Task{}).
+			Where("id = ? AND recurrence_scheduled = ?", task.ID, false).
+			Update("recurrence_scheduled", true)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return nil
+		}
+
+		rule, err := ParseRRule(task.Recurrence)
+		if err != nil {
+			return err
+		}
+		if task.DueAt == nil {
+			return errors.New("recurring task has no due_at")
+		}
+		nextDue, ok := rule.Next(*task.DueAt, task.RecurrenceIndex)
+		if !ok {
+			return nil
+		}
+
+		position, err := nextPosition(tx, task.UserID, task.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		next := Task{
+			UserID:             task.UserID,
+			WorkspaceID:        task.WorkspaceID,
+			Title:              task.Title,
+			Description:        task.Description,
+			ProjectID:          task.ProjectID,
+			AssigneeID:         task.AssigneeID,
+			Status:             StatusTodo,
+			Priority:           task.Priority,
+			Position:           position,
+			DueAt:              &nextDue,
+			Recurrence:         task.Recurrence,
+			RecurrenceIndex:    task.RecurrenceIndex + 1,
+			RecurrenceParentID: &task.ID,
+			Tags:               task.Tags,
+		}
+		for _, subtask := range task.Subtasks {
+			next.Subtasks = append(next.Subtasks, Subtask{Title: subtask.Title, Position: subtask.Position})
+		}
+		return tx.Create(&next).Error
+	})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused berarti token yang sudah dipakai dikirim lagi;
+	// seluruh keluarga token dicabut karena kemungkinan token dicuri.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// RefreshToken disimpan sebagai hash. Setiap rotasi membuat token baru dengan
+// FamilyID yang sama, sehingga pemakaian ulang token lama bisa mencabut semuanya.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:char(64);not null;uniqueIndex"`
+	FamilyID  string    `gorm:"type:varchar(32);not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Interface untuk layanan refresh token
+type RefreshTokenService interface {
+	IssueRefreshToken(ctx context.Context, userID uint) (string, *RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, token string) (string, *RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, token string) error
+}
+
+// Struct implementasi RefreshTokenService dengan GORM
+type RefreshTokenServiceImpl struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+// IssueRefreshToken membuat refresh token pertama dari keluarga baru (saat login).
+func (s *RefreshTokenServiceImpl) IssueRefreshToken(ctx context.Context, userID uint) (string, *RefreshToken, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.create(s.DB.WithContext(ctx), userID, family)
+}
+
+// RotateRefreshToken menandai token sebagai terpakai lalu menerbitkan penggantinya.
+// Token yang sudah terpakai atau dicabut mencabut seluruh keluarganya.
+func (s *RefreshTokenServiceImpl) RotateRefreshToken(ctx context.Context, token string) (string, *RefreshToken, error) {
+	var value string
+	var issued *RefreshToken
+	reused := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current RefreshToken
+		err := tx.Where("token_hash = ?", hashToken(token)).First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		// Token yang sudah dicabut (logout) cukup ditolak, bukan dianggap dicuri.
+		if current.RevokedAt != nil {
+			return ErrRefreshTokenInvalid
+		}
+
+		now := time.Now()
+		claim := tx.Model(&RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("used_at", now)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			reused = true
+			return ErrRefreshTokenReused
+		}
+		if now.After(current.ExpiresAt) {
+			return ErrRefreshTokenInvalid
+		}
+
+		value, issued, err = s.create(tx, current.UserID, current.FamilyID)
+		return err
+	})
+	if reused {
+		// Dicabut di luar transaksi di atas karena transaksi itu di-rollback.
+		if err := s.revokeFamily(ctx, token); err != nil {
+			return "", nil, err
+		}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return value, issued, nil
+}
+
+// RevokeRefreshToken mencabut token beserta keluarganya (dipakai saat logout).
+// Token yang tidak dikenal diabaikan.
+func (s *RefreshTokenServiceImpl) RevokeRefreshToken(ctx context.Context, token string) error {
+	return s.revokeFamily(ctx, token)
+}
+
+func (s *RefreshTokenServiceImpl) revokeFamily(ctx context.Context, token string) error {
+	family := s.DB.WithContext(ctx).Model(&RefreshToken{}).
+		Select("family_id").
+		Where("token_hash = ?", hashToken(token))
+	return s.DB.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id IN (?) AND revoked_at IS NULL", family).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (s *RefreshTokenServiceImpl) create(tx *gorm.DB, userID uint, family string) (string, *RefreshToken, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(value),
+		FamilyID:  family,
+		ExpiresAt: time.Now().Add(s.TTL),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", nil, err
+	}
+	return value, &token, nil
+}
+
+// randomToken menghasilkan string acak base64url dari n byte.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken dipakai untuk token acak berentropi tinggi sehingga SHA-256 sudah cukup.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReminderLead = time.Hour
+	reminderBatch       = 100
+)
+
+// ReminderScheduler berjalan di background dan mengirim email pengingat untuk
+// task yang jatuh tempo dalam Lead ke depan. Pengingat dikirim sekali per
+// due_at; jika due_at diubah, pengingat dikirim lagi untuk waktu yang baru.
+// Pengingat juga dicatat sebagai notifikasi due_reminder. Jika Telegram atau
+// Push di-set, penerima yang sudah menghubungkan chat atau berlangganan push di
+// browser juga diingatkan lewat channel itu.
+type ReminderScheduler struct {
+	DB       *gorm.DB
+	Mailer   Mailer
+	Telegram *TelegramBot
+	Push     *WebPush
+	Lead     time.Duration
+	Interval time.Duration
+}
+
+// parseReminderLead membaca REMINDER_LEAD (default 1 jam); "0" mematikan pengingat.
+func parseReminderLead() (time.Duration, error) {
+	if getEnv("REMINDER_LEAD", "") == "0" {
+		return 0, nil
+	}
+	return parseDurationEnv("REMINDER_LEAD", defaultReminderLead)
+}
+
+// Run mengirim pengingat setiap Interval sampai ctx dibatalkan.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sendDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDue mengirim pengingat untuk task yang belum selesai, tidak diarsipkan,
+// dan jatuh tempo antara sekarang dan Lead ke depan.
+func (s *ReminderScheduler) sendDue(ctx context.Context) error {
+	db := s.DB.WithContext(ctx)
+	now := time.Now()
+	for {
+		var tasks []Task
+		err := TaskFilter{}.apply(db.Preload("User").Preload("Assignee")).
+			Where("due_at > ? AND due_at <= ?", now, now.Add(s.Lead)).
+			Where("status NOT IN ?", []TaskStatus{StatusDone, StatusCancelled}).
+			Where("reminded_due_at IS NULL OR reminded_due_at <> due_at").
+			Order("due_at, id").
+			Limit(reminderBatch).
+			Find(&tasks).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range tasks {
+			if err := s.remind(ctx, &tasks[i]); err != nil {
+				log.Printf("reminder scheduler: task %d: %v", tasks[i].ID, err)
+			}
+		}
+		if len(tasks) < reminderBatch {
+			return nil
+		}
+	}
+}
+
+// remind menandai due_at task sudah diingatkan (hanya jika belum ditandai
+// instance lain), lalu mengirim email ke assignee atau, tanpa assignee, ke
+// pembuat task. Jika email gagal dikirim, tandanya dihapus supaya dicoba lagi.
+func (s *ReminderScheduler) remind(ctx context.Context, task *Task) error {
+	db := s.DB.WithContext(ctx)
+	claim := db.Model(&Task{}).
+		Where("id = ? AND due_at = ? AND (reminded_due_at IS NULL OR reminded_due_at <> due_at)", task.ID, task.DueAt).
+		UpdateColumn("reminded_due_at", task.DueAt)
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	recipient := task.Assignee
+	if recipient == nil {
+		recipient = task.User
+	}
+	if recipient == nil || recipient.DisabledAt != nil {
+		return nil
+	}
+
+	email := reminderEmail(recipient, task)
+	if err := s.Mailer.Send(ctx, email); err != nil {
+		if reset := db.Model(&Task{}).Where("id = ?", task.ID).UpdateColumn("reminded_due_at", nil).Error; reset != nil {
+			log.Printf("reminder scheduler: task %d: %v", task.ID, reset)
+		}
+		return err
+	}
+	// Email sudah terkirim, jadi kegagalan channel lain tidak membuat
+	// pengingat diulang.
+	reminder := Notification{UserID: recipient.ID, Type: NotificationDueReminder, TaskID: &task.ID}
+	if err := notify(db, []Notification{reminder}); err != nil {
+		log.Printf("reminder scheduler: task %d: notification: %v", task.ID, err)
+	}
+	if err := s.remindOnTelegram(ctx, recipient.ID, email.Body); err != nil {
+		log.Printf("reminder scheduler: task %d: telegram: %v", task.ID, err)
+	}
+	if s.Push == nil {
+		return nil
+	}
+	return s.Push.NotifyUser(ctx, s.DB, recipient.ID, PushMessage{
+		Type:   "reminder",
+		Title:  email.Subject,
+		Body:   email.Body,
+		TaskID: &task.ID,
+	})
+}
+
+// remindOnTelegram mengirim pengingat ke chat Telegram userID jika ada.
+func (s *ReminderScheduler) remindOnTelegram(ctx context.Context, userID uint, text string) error {
+	if s.Telegram == nil {
+		return nil
+	}
+	var link TelegramLink
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.Telegram.SendMessage(ctx, link.ChatID, text)
+}
+
+// reminderEmail menulis waktu jatuh tempo di zona waktu preferensi penerima.
+func reminderEmail(recipient *User, task *Task) Email {
+	location, err := time.LoadLocation(recipient.Preferences.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	due := task.DueAt.In(location).Format("Mon, 02 Jan 2006 15:04 MST")
+	return Email{
+		To:      recipient.Email,
+		Subject: fmt.Sprintf("Reminder: %q is due %s", task.Title, due),
+		Body:    fmt.Sprintf("Your task %q (#%d) is due at %s and is still %s.", task.Title, task.ID, due, task.Status),
+	}
+}
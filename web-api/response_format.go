@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+// responseFormat adalah format respons selain JSON biasa yang bisa dipilih
+// client lewat header Accept.
+type responseFormat struct {
+	mediaTypes  []string
+	contentType string
+	// problemContentType dipakai untuk respons error jika formatnya punya
+	// media type problem sendiri (RFC 7807).
+	problemContentType string
+	// render mengubah body JSON handler; status dipakai untuk membedakan error.
+	render func(op apiOperation, status int, body []byte) ([]byte, error)
+}
+
+var responseFormats = []responseFormat{
+	{mediaTypes: []string{jsonAPIMediaType}, contentType: jsonAPIMediaType, render: renderJSONAPI},
+	{mediaTypes: []string{"application/xml", "text/xml"}, contentType: "application/xml; charset=utf-8", problemContentType: "application/problem+xml; charset=utf-8", render: renderXML},
+	{mediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"}, contentType: "application/yaml; charset=utf-8", render: renderYAML},
+}
+
+// formatWriter menahan body respons supaya bisa diubah ke format lain.
+type formatWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *formatWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *formatWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// negotiateResponse mengubah respons JSON ke format di responseFormats jika
+// header Accept memintanya. Hanya route dengan Response JSON di apiOperations
+// yang diubah; route lain dan body request tetap JSON biasa.
+func negotiateResponse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op, ok := apiOperations[c.Request.Method+" "+strings.TrimPrefix(c.FullPath(), apiV1Prefix)]
+		if !ok || op.Response == nil || op.ContentType != "" {
+			c.Next()
+			return
+		}
+		c.Header("Vary", "Accept")
+		format := negotiateFormat(c.GetHeader("Accept"))
+		if format == nil {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &formatWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		body := writer.body.Bytes()
+		contentType := original.Header().Get("Content-Type")
+		if len(body) == 0 || !strings.HasPrefix(contentType, "application/json") && !strings.HasPrefix(contentType, problemContentType) {
+			original.Write(body)
+			return
+		}
+		data, err := format.render(op, original.Status(), body)
+		if err != nil {
+			// Body yang tidak bisa diubah dikirim apa adanya sebagai JSON.
+			original.Write(body)
+			return
+		}
+		contentType = format.contentType
+		if original.Status() >= 400 && format.problemContentType != "" {
+			contentType = format.problemContentType
+		}
+		original.Header().Set("Content-Type", contentType)
+		original.Write(data)
+	}
+}
+
+// negotiateFormat memilih format dari Accept sesuai urutan q. nil berarti
+// JSON biasa, termasuk untuk application/json, */*, dan media type lain.
+func negotiateFormat(accept string) *responseFormat {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, candidate := range candidates {
+		switch candidate.mediaType {
+		case "application/json", "application/*", "*/*":
+			return nil
+		}
+		for i := range responseFormats {
+			if slices.Contains(responseFormats[i].mediaTypes, candidate.mediaType) {
+				return &responseFormats[i]
+			}
+		}
+	}
+	return nil
+}
+
+// renderYAML mempertahankan urutan field JSON.
+func renderYAML(_ apiOperation, _ int, body []byte) ([]byte, error) {
+	return yaml.JSONToYAML(body)
+}
+
+// renderXML menulis setiap field JSON sebagai elemen dengan urutan yang sama.
+// Root-nya nama struct Response (misalnya <task>), <response> untuk gin.H, atau
+// <problem> dengan namespace RFC 7807 untuk error. Item array memakai nama tipe elemennya (<tags><tag>), atau <item>
+// jika tidak diketahui, dan null ditulis sebagai elemen kosong nil="true".
+func renderXML(op apiOperation, status int, body []byte) ([]byte, error) {
+	root, rootType := xmlElement("response"), reflect.Type(nil)
+	object, _ := op.Response.(jsonObject)
+	if t := indirectType(reflect.TypeOf(op.Response)); t != nil && t.Kind() == reflect.Struct {
+		root, rootType = xmlElement(snakeCase(t.Name())), t
+	}
+	if status >= 400 {
+		root = xml.StartElement{Name: xml.Name{Space: "urn:ietf:rfc:7807", Local: "problem"}}
+		rootType, object = reflect.TypeOf(Problem{}), nil
+	}
+
+	var out bytes.Buffer
+	out.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&out)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := writeXMLValue(encoder, decoder, root, rootType, object); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// writeXMLValue membaca satu nilai JSON dari decoder dan menulisnya sebagai
+// elemen start. t (atau object untuk gin.H) dipakai untuk menamai item array.
+func writeXMLValue(encoder *xml.Encoder, decoder *json.Decoder, start xml.StartElement, t reflect.Type, object jsonObject) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		for decoder.More() {
+			if token == '[' {
+				err = writeXMLValue(encoder, decoder, xmlElement(xmlItemName(t)), xmlElemType(t), nil)
+			} else {
+				var key json.Token
+				if key, err = decoder.Token(); err != nil {
+					return err
+				}
+				field := fmt.Sprint(key)
+				err = writeXMLValue(encoder, decoder, xmlElement(field), xmlFieldType(t, object, field), nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// Token penutup ] atau }.
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		return encoder.EncodeToken(start.End())
+	case nil:
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "nil"}, Value: "true"})
+		return encoder.EncodeElement("", start)
+	default:
+		return encoder.EncodeElement(fmt.Sprint(token), start)
+	}
+}
+
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// xmlElement memakai name sebagai nama elemen, atau <entry key="..."> jika
+// name bukan nama XML yang valid (misalnya key map).
+func xmlElement(name string) xml.StartElement {
+	if xmlName.MatchString(name) && !strings.HasPrefix(strings.ToLower(name), "xml") {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{Name: xml.Name{Local: "entry"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}}}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func xmlFieldType(t reflect.Type, object jsonObject, key string) reflect.Type {
+	if object != nil {
+		return reflect.TypeOf(object[key])
+	}
+	t = indirectType(t)
+	switch {
+	case t == nil:
+		return nil
+	case t.Kind() == reflect.Struct:
+		if field, ok := jsonAPIFields(t)[key]; ok {
+			return field.Type
+		}
+	case t.Kind() == reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+func xmlElemType(t reflect.Type) reflect.Type {
+	if t = indirectType(t); t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		return t.Elem()
+	}
+	return nil
+}
+
+func xmlItemName(t reflect.Type) string {
+	if elem := indirectType(xmlElemType(t)); elem != nil && elem.PkgPath() != "" && elem.Name() != "" {
+		return snakeCase(elem.Name())
+	}
+	return "item"
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string // contentType format, "" untuk JSON biasa.
+	}{
+		{"", ""},
+		{"application/json", ""},
+		{"*/*", ""},
+		{"text/html", ""},
+		{"not a media type", ""},
+		{jsonAPIMediaType, jsonAPIMediaType},
+		{"application/xml", "application/xml; charset=utf-8"},
+		{"text/xml", "application/xml; charset=utf-8"},
+		{"application/x-yaml", "application/yaml; charset=utf-8"},
+		{"text/html, application/yaml", "application/yaml; charset=utf-8"},
+		{"application/json, application/xml", ""},
+		{"application/json;q=0.5, application/xml", "application/xml; charset=utf-8"},
+		{"application/xml;q=0.2, application/yaml;q=0.8", "application/yaml; charset=utf-8"},
+		{"application/xml;q=0.8, */*;q=0.9", ""},
+		{"application/xml;q=0, application/yaml;q=0.1", "application/yaml; charset=utf-8"},
+		{"application/xml;q=x, application/yaml;q=0.1", "application/yaml; charset=utf-8"},
+		{"application/xml;q=0", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if format := negotiateFormat(tt.accept); format != nil {
+			got = format.contentType
+		}
+		if got != tt.want {
+			t.Errorf("negotiateFormat(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestRenderXMLProblem(t *testing.T) {
+	body := []byte(`{"type":"about:blank","title":"Bad Request","status":400,"errors":[{"field":"title","message":"is required"}]}`)
+	got, err := renderXML(apiOperation{Response: Task{}}, http.StatusBadRequest, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Bad Request</title><status>400</status>` +
+		`<errors><field_error><field>title</field><message>is required</message></field_error></errors></problem>`
+	if string(got) != want {
+		t.Errorf("renderXML()\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestRenderXMLResource(t *testing.T) {
+	body := []byte(`{"id":3,"title":"a < b","tags":[{"name":"work"}],"due_at":null}`)
+	got, err := renderXML(apiOperation{Response: Task{}}, http.StatusOK, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<task><id>3</id><title>a &lt; b</title><tags><tag><name>work</name></tag></tags><due_at nil="true"></due_at></task>`
+	if !strings.HasSuffix(string(got), want) {
+		t.Errorf("renderXML()\n got %s\nwant suffix %s", got, want)
+	}
+}
+
+func TestJSONAPIErrors(t *testing.T) {
+	var value any
+	body := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request is invalid","instance":"/tasks",` +
+		`"errors":[{"field":"title","message":"is required"},{"field":"items[0].title","message":"is required"}],"id":7}`
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(jsonAPIErrors(http.StatusBadRequest, value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"errors":[` +
+		`{"detail":"is required","meta":{"id":7},"source":{"pointer":"/title"},"status":"400","title":"Bad Request"},` +
+		`{"detail":"is required","meta":{"id":7},"source":{"pointer":"/items/0/title"},"status":"400","title":"Bad Request"}]}`
+	if string(got) != want {
+		t.Errorf("jsonAPIErrors()\n got %s\nwant %s", got, want)
+	}
+
+	// Problem tanpa field error menjadi satu objek error.
+	got, err = json.Marshal(jsonAPIErrors(http.StatusNotFound, map[string]any{"title": "Not Found", "detail": "task not found"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"errors":[{"detail":"task not found","status":"404","title":"Not Found"}]}`; string(got) != want {
+		t.Errorf("jsonAPIErrors()\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestNegotiateResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(negotiateResponse())
+	router.GET(apiV1Prefix+"/tasks/:id", func(c *gin.Context) {
+		if c.Param("id") != "1" {
+			problem(c, http.StatusNotFound, "task not found")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": 1, "title": "Beli susu"})
+	})
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		wantContentType string
+		wantBody        string
+	}{
+		{"json", "/tasks/1", "application/json", "application/json; charset=utf-8", `{"id":1,"title":"Beli susu"}`},
+		{"yaml", "/tasks/1", "application/yaml", "application/yaml; charset=utf-8", "id: 1\ntitle: Beli susu\n"},
+		{"xml", "/tasks/1", "application/xml", "application/xml; charset=utf-8", "<task><id>1</id><title>Beli susu</title></task>"},
+		{"xml problem", "/tasks/2", "application/xml", "application/problem+xml; charset=utf-8", `<problem xmlns="urn:ietf:rfc:7807">`},
+		{"json:api problem", "/tasks/2", jsonAPIMediaType, jsonAPIMediaType, `"errors":[{"detail":"task not found"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, apiV1Prefix+tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if ct := w.Header().Get("Content-Type"); ct != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantContentType)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Error("missing Vary: Accept")
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Role menentukan endpoint mana yang boleh diakses user.
+type Role string
+
+const (
+	RoleMember Role = "member"
+	// RoleAdmin boleh mengakses endpoint manajemen di bawah /admin.
+	RoleAdmin Role = "admin"
+)
+
+var roles = []Role{RoleMember, RoleAdmin}
+
+func (r Role) Valid() bool {
+	for _, role := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseRole(value string) (Role, error) {
+	if r := Role(strings.ToLower(strings.TrimSpace(value))); r.Valid() {
+		return r, nil
+	}
+	return "", fmt.Errorf("invalid role %q: must be one of member, admin", value)
+}
+
+// requireRole menolak request dengan 403 jika user tidak punya role. Semua
+// pengecekan role dilakukan lewat middleware ini di main.go, bukan di handler.
+// Harus dipasang setelah requireAuth.
+func requireRole(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := currentUser(c); user == nil || user.Role != role {
+			problem(c, http.StatusForbidden, "requires "+string(role)+" role")
+			return
+		}
+		c.Next()
+	}
+}
+
+// ensureAdminExists menjadikan user paling lama sebagai admin jika belum ada
+// admin sama sekali, misalnya pada database dari sebelum ada kolom role.
+func ensureAdminExists(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&User{}).Where("role = ?", RoleAdmin).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	oldest := db.Model(&User{}).Select("MIN(id)")
+	return db.Model(&User{}).Where("id = (?)", oldest).Update("role", RoleAdmin).Error
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RRule adalah subset RFC 5545 RRULE yang didukung: FREQ (DAILY, WEEKLY,
+// MONTHLY, YEARLY), INTERVAL, BYDAY (hanya untuk WEEKLY), COUNT, dan UNTIL.
+type RRule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRRule mem-parsing string seperti "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR".
+// Prefix "RRULE:" boleh ada atau tidak.
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule must not be empty")
+	}
+
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch freq := strings.ToUpper(val); freq {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.Freq = freq
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseRRuleTime(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := rruleWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("rrule requires FREQ")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != "WEEKLY" {
+		return nil, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL must not be used together")
+	}
+	return rule, nil
+}
+
+// Next mengembalikan kemunculan setelah occurrence ke-index (dimulai dari 1) yang
+// jatuh pada waktu after. ok bernilai false jika seri sudah berakhir.
+func (r *RRule) Next(after time.Time, index int) (next time.Time, ok bool) {
+	if r.Count > 0 && index >= r.Count {
+		return time.Time{}, false
+	}
+
+	switch r.Freq {
+	case "DAILY":
+		next = after.AddDate(0, 0, r.Interval)
+	case "WEEKLY":
+		next = r.nextWeekly(after)
+	case "MONTHLY":
+		next = addMonthsKeepDay(after, r.Interval)
+	case "YEARLY":
+		next = addMonthsKeepDay(after, 12*r.Interval)
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *RRule) nextWeekly(after time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return after.AddDate(0, 0, 7*r.Interval)
+	}
+
+	days := make(map[time.Weekday]bool, len(r.ByDay))
+	for _, day := range r.ByDay {
+		days[day] = true
+	}
+
+	// Minggu dihitung mulai Senin (WKST=MO, default RFC 5545).
+	start := weekStart(after)
+	for offset := 1; offset <= 7*(r.Interval+1); offset++ {
+		candidate := after.AddDate(0, 0, offset)
+		weeks := int(weekStart(candidate).Sub(start).Hours()/24+0.5) / 7
+		if weeks%r.Interval == 0 && days[candidate.Weekday()] {
+			return candidate
+		}
+	}
+	return after.AddDate(0, 0, 7*r.Interval)
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -offset).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// addMonthsKeepDay menambah bulan tanpa overflow tanggal seperti time.AddDate;
+// bulan yang tidak punya tanggal tersebut (misal 31 Februari) dilewati sesuai RFC 5545.
+func addMonthsKeepDay(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	for step := months; ; step += months {
+		candidate := time.Date(year, month+time.Month(step), 1, hour, min, sec, t.Nanosecond(), t.Location())
+		if day <= daysIn(candidate.Year(), candidate.Month()) {
+			return candidate.AddDate(0, 0, day-1)
+		}
+	}
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	until := time.Date(2025, 3, 1, 23, 59, 59, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    RRule
+		wantErr bool
+	}{
+		{value: "FREQ=DAILY", want: RRule{Freq: "DAILY", Interval: 1}},
+		{value: "RRULE:FREQ=weekly;INTERVAL=2;BYDAY=MO,fr", want: RRule{Freq: "WEEKLY", Interval: 2, ByDay: []time.Weekday{time.Monday, time.Friday}}},
+		{value: "FREQ=MONTHLY;COUNT=3", want: RRule{Freq: "MONTHLY", Interval: 1, Count: 3}},
+		{value: "FREQ=YEARLY;UNTIL=20250301", want: RRule{Freq: "YEARLY", Interval: 1, Until: &until}},
+		{value: "FREQ=YEARLY;UNTIL=20250301T235959Z", want: RRule{Freq: "YEARLY", Interval: 1, Until: &until}},
+		{value: "", wantErr: true},
+		{value: "INTERVAL=2", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=x", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{value: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+		{value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20250301", wantErr: true},
+		{value: "FREQ=DAILY;BYMONTH=1", wantErr: true},
+		{value: "FREQ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRRule(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRRule(%q) = %+v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRRule(%q) error: %v", tt.value, err)
+			continue
+		}
+		if got.Freq != tt.want.Freq || got.Interval != tt.want.Interval || got.Count != tt.want.Count ||
+			!slices.Equal(got.ByDay, tt.want.ByDay) || !equalTimePtr(got.Until, tt.want.Until) {
+			t.Errorf("ParseRRule(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRRuleNext(t *testing.T) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		rule   string
+		after  time.Time
+		index  int
+		want   time.Time
+		wantOK bool
+	}{
+		{"daily", "FREQ=DAILY", at(2025, 1, 31), 1, at(2025, 2, 1), true},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", at(2025, 2, 27), 1, at(2025, 3, 2), true},
+		{"weekly without BYDAY", "FREQ=WEEKLY", at(2025, 1, 1), 1, at(2025, 1, 8), true},
+		// 6 Januari 2025 adalah Senin.
+		{"weekly BYDAY later this week", "FREQ=WEEKLY;BYDAY=MO,FR", at(2025, 1, 6), 1, at(2025, 1, 10), true},
+		{"weekly BYDAY next week", "FREQ=WEEKLY;BYDAY=MO,FR", at(2025, 1, 10), 1, at(2025, 1, 13), true},
+		{"biweekly BYDAY skips a week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", at(2025, 1, 10), 1, at(2025, 1, 20), true},
+		{"monthly", "FREQ=MONTHLY", at(2025, 1, 15), 1, at(2025, 2, 15), true},
+		{"monthly on the 31st skips short months", "FREQ=MONTHLY", at(2025, 1, 31), 1, at(2025, 3, 31), true},
+		{"monthly on the 30th skips february", "FREQ=MONTHLY", at(2025, 1, 30), 1, at(2025, 3, 30), true},
+		{"yearly on leap day", "FREQ=YEARLY", at(2024, 2, 29), 1, at(2028, 2, 29), true},
+		{"count not reached", "FREQ=DAILY;COUNT=3", at(2025, 1, 1), 2, at(2025, 1, 2), true},
+		{"count reached", "FREQ=DAILY;COUNT=3", at(2025, 1, 1), 3, time.Time{}, false},
+		{"before until", "FREQ=DAILY;UNTIL=20250102", at(2025, 1, 1), 1, at(2025, 1, 2), true},
+		{"after until", "FREQ=DAILY;UNTIL=20250102", at(2025, 1, 2), 1, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := rule.Next(tt.after, tt.index)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("Next(%s, %d) = (%s, %v), want (%s, %v)", tt.after, tt.index, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRRuleNextKeepsLocation(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	rule, err := ParseRRule("FREQ=MONTHLY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Date(2025, 1, 31, 23, 0, 0, 0, jakarta)
+	got, ok := rule.Next(after, 1)
+	want := time.Date(2025, 3, 31, 23, 0, 0, 0, jakarta)
+	if !ok || !got.Equal(want) || got.Location() != jakarta {
+		t.Errorf("Next() = %s, want %s", got, want)
+	}
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPresignTTL = 15 * time.Minute
+	s3Service         = "s3"
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+)
+
+// FileURLSigner dipenuhi FileStore yang bisa membuat URL unduhan sementara,
+// sehingga isi file tidak perlu lewat server ini.
+type FileURLSigner interface {
+	PresignedURL(ctx context.Context, name string, download FileDownload) (string, error)
+}
+
+// FileDownload adalah header yang dikirim storage saat URL presigned dibuka.
+type FileDownload struct {
+	FileName    string
+	ContentType string
+}
+
+// S3FileStore menyimpan file di bucket S3 atau layanan yang kompatibel
+// (MinIO, R2, dan sebagainya). Request ditandatangani dengan AWS Signature
+// Version 4. PathStyle memakai URL <endpoint>/<bucket>/<key> seperti yang
+// dibutuhkan MinIO; jika false, bucket menjadi subdomain endpoint.
+// PresignTTL adalah masa berlaku URL dari PresignedURL.
+type S3FileStore struct {
+	Endpoint        *url.URL
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+	PresignTTL      time.Duration
+	Client          *http.Client
+}
+
+// Save mengunggah file dengan PUT. Ukuran file harus diketahui di depan, jadi
+// reader yang tidak bisa di-seek dibaca dulu ke memori.
+func (s *S3FileStore) Save(ctx context.Context, name string, r io.Reader) error {
+	size, err := readerSize(r)
+	if err != nil {
+		return err
+	}
+	if size < 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r, size = bytes.NewReader(data), int64(len(data))
+	}
+	if size == 0 {
+		// Body kosong dengan ContentLength 0 dikirim chunked, yang ditolak S3.
+		r = http.NoBody
+	}
+
+	req, err := s.request(ctx, http.MethodPut, name, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	return s.do(req, http.StatusOK)
+}
+
+func (s *S3FileStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, time.Now())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete menghapus object; S3 sudah menganggap object yang tidak ada sebagai sukses.
+func (s *S3FileStore) Delete(ctx context.Context, name string) error {
+	req, err := s.request(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, http.StatusNoContent, http.StatusOK)
+}
+
+// PresignedURL membuat URL GET yang berlaku selama PresignTTL. Nama file dan
+// tipe diteruskan lewat parameter response-* supaya browser tetap mengunduhnya
+// sebagai lampiran.
+func (s *S3FileStore) PresignedURL(ctx context.Context, name string, download FileDownload) (string, error) {
+	return s.presign(name, download, time.Now())
+}
+
+func (s *S3FileStore) presign(name string, download FileDownload, now time.Time) (string, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return "", err
+	}
+	ttl := s.PresignTTL
+	if ttl <= 0 {
+		ttl = defaultPresignTTL
+	}
+
+	now = now.UTC()
+	query := map[string]string{
+		"X-Amz-Algorithm":     s3Algorithm,
+		"X-Amz-Credential":    s.AccessKeyID + "/" + s.scope(now),
+		"X-Amz-Date":          now.Format(s3TimeFormat),
+		"X-Amz-Expires":       strconv.Itoa(int(ttl.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if download.FileName != "" {
+		query["response-content-disposition"] = mime.FormatMediaType("attachment", map[string]string{"filename": download.FileName})
+	}
+	if download.ContentType != "" {
+		query["response-content-type"] = download.ContentType
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	u.RawQuery += "&X-Amz-Signature=" + s.signature(now, canonical)
+	return u.String(), nil
+}
+
+func (s *S3FileStore) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+func (s *S3FileStore) request(ctx context.Context, method, name string, body io.Reader) (*http.Request, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *S3FileStore) do(req *http.Request, ok ...int) error {
+	s.sign(req, time.Now())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	for _, status := range ok {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	return s3Error(resp)
+}
+
+// objectURL menolak nama yang sama dengan yang ditolak LocalFileStore supaya
+// kedua backend menerima key yang sama.
+func (s *S3FileStore) objectURL(name string) (*url.URL, error) {
+	if _, err := (&LocalFileStore{}).path(name); err != nil {
+		return nil, err
+	}
+	u := *s.Endpoint
+	if s.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.Bucket + "/" + name
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + name
+	}
+	u.RawPath = awsEscape(u.Path, false)
+	return &u, nil
+}
+
+// sign menambahkan header Authorization. Isi body tidak ikut di-hash
+// (UNSIGNED-PAYLOAD) supaya file besar tidak perlu dibaca dua kali; koneksi
+// ke endpoint diharapkan memakai HTTPS.
+func (s *S3FileStore) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": s3UnsignedPayload,
+		"x-amz-date":           now.Format(s3TimeFormat),
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKeyID, s.scope(now), signedHeaders, s.signature(now, canonical)))
+}
+
+func (s *S3FileStore) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.Region + "/" + s3Service + "/aws4_request"
+}
+
+func (s *S3FileStore) signature(now time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		s.scope(now),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), now.Format("20060102"))
+	for _, part := range []string{s.Region, s3Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery mengurutkan dan meng-encode parameter sesuai aturan SigV4
+// (spasi menjadi %20, bukan +).
+func canonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = awsEscape(key, true) + "=" + awsEscape(params[key], true)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape meng-encode semua karakter selain huruf, angka, dan -_.~; "/"
+// hanya di-encode jika encodeSlash true.
+func awsEscape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// readerSize mengembalikan sisa ukuran reader yang bisa di-seek, atau -1.
+func readerSize(r io.Reader) (int64, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return -1, nil
+	}
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1, nil
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := seeker.Seek(current, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - current, nil
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	return fmt.Errorf("s3 %s %s: %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, bytes.TrimSpace(body))
+}
+
+// newAttachmentStore memilih backend lampiran dari ATTACHMENT_STORAGE:
+// "local" (default, di ATTACHMENT_DIR) atau "s3" (S3_ENDPOINT, S3_REGION,
+// S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_PATH_STYLE, dan
+// S3_PRESIGN_TTL).
+func newAttachmentStore() (FileStore, error) {
+	switch backend := getEnv("ATTACHMENT_STORAGE", "local"); backend {
+	case "local":
+		return &LocalFileStore{Dir: getEnv("ATTACHMENT_DIR", "uploads/attachments")}, nil
+	case "s3":
+		endpoint, err := url.Parse(getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"))
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid S3_ENDPOINT %q", getEnv("S3_ENDPOINT", ""))
+		}
+		pathStyle, err := strconv.ParseBool(getEnv("S3_PATH_STYLE", "false"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3_PATH_STYLE %q", getEnv("S3_PATH_STYLE", ""))
+		}
+		presignTTL, err := parseDurationEnv("S3_PRESIGN_TTL", defaultPresignTTL)
+		if err != nil {
+			return nil, err
+		}
+		store := &S3FileStore{
+			Endpoint:        endpoint,
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       pathStyle,
+			PresignTTL:      presignTTL,
+			Client:          &http.Client{Timeout: 5 * time.Minute},
+		}
+		if store.Bucket == "" || store.AccessKeyID == "" || store.SecretAccessKey == "" {
+			return nil, errors.New("ATTACHMENT_STORAGE=s3 requires S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY")
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("invalid ATTACHMENT_STORAGE %q: must be local or s3", backend)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFilterNotFound  = errors.New("filter not found")
+	ErrFilterNameTaken = errors.New("filter name already exists")
+)
+
+// SavedFilter adalah filter task yang disimpan dengan nama ("smart list").
+// Query berisi query string yang sama dengan GET /tasks, misalnya
+// "overdue=true&priority=high,urgent".
+type SavedFilter struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    *uint     `json:"-" gorm:"uniqueIndex:idx_saved_filters_user_name,priority:1"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name      string    `json:"name" gorm:"type:varchar(128);not null;uniqueIndex:idx_saved_filters_user_name,priority:2"`
+	Query     string    `json:"query" gorm:"not null;default:''"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskFilter mengubah Query menjadi filter yang siap dipakai ListTasks.
+func (f SavedFilter) TaskFilter() (TaskFilter, error) {
+	values, err := url.ParseQuery(f.Query)
+	if err != nil {
+		return TaskFilter{}, fmt.Errorf("invalid query: %w", err)
+	}
+	return parseTaskFilterValues(values)
+}
+
+// normalizeFilterQuery memvalidasi query filter dan menuliskannya ulang dalam
+// bentuk kanonik. Parameter pagination dan parameter yang tidak dikenal ditolak.
+func normalizeFilterQuery(query string) (string, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid query: %w", err)
+	}
+	for key := range values {
+		if !isTaskFilterKey(key) {
+			return "", fmt.Errorf("invalid query: unsupported parameter %q", key)
+		}
+	}
+	if _, err := parseTaskFilterValues(values); err != nil {
+		return "", err
+	}
+	return values.Encode(), nil
+}
+
+// Interface untuk layanan saved filter
+type SavedFilterService interface {
+	ListFilters(ctx context.Context) ([]SavedFilter, error)
+	GetFilter(ctx context.Context, id uint) (*SavedFilter, error)
+	CreateFilter(ctx context.Context, filter *SavedFilter) error
+	UpdateFilter(ctx context.Context, filter *SavedFilter) error
+	DeleteFilter(ctx context.Context, id uint) error
+}
+
+// Struct implementasi SavedFilterService dengan GORM
+type SavedFilterServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *SavedFilterServiceImpl) ListFilters(ctx context.Context) ([]SavedFilter, error) {
+	var filters []SavedFilter
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).Order("name").Find(&filters).Error
+	return filters, err
+}
+
+func (s *SavedFilterServiceImpl) GetFilter(ctx context.Context, id uint) (*SavedFilter, error) {
+	var filter SavedFilter
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).First(&filter, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFilterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (s *SavedFilterServiceImpl) CreateFilter(ctx context.Context, filter *SavedFilter) error {
+	owner := ownerID(ctx)
+	filter.UserID = &owner
+	err := s.DB.WithContext(ctx).Create(filter).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrFilterNameTaken
+	}
+	return err
+}
+
+func (s *SavedFilterServiceImpl) UpdateFilter(ctx context.Context, filter *SavedFilter) error {
+	err := s.DB.WithContext(ctx).Save(filter).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrFilterNameTaken
+	}
+	return err
+}
+
+func (s *SavedFilterServiceImpl) DeleteFilter(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&SavedFilter{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFilterNotFound
+	}
+	return nil
+}
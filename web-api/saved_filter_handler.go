@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type filterRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Query string `json:"query"`
+}
+
+// FilterHandler berisi HTTP handler untuk /filters.
+type FilterHandler struct {
+	Service SavedFilterService
+	Tasks   TaskService
+}
+
+func (h *FilterHandler) ListFilters(c *gin.Context) {
+	filters, err := h.Service.ListFilters(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"filters": filters})
+}
+
+func (h *FilterHandler) CreateFilter(c *gin.Context) {
+	req, ok := bindFilterRequest(c)
+	if !ok {
+		return
+	}
+
+	filter := SavedFilter{Name: req.Name, Query: req.Query}
+	if err := h.Service.CreateFilter(c.Request.Context(), &filter); err != nil {
+		filterError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, filter)
+}
+
+func (h *FilterHandler) GetFilter(c *gin.Context) {
+	filter, ok := h.loadFilter(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
+
+func (h *FilterHandler) UpdateFilter(c *gin.Context) {
+	filter, ok := h.loadFilter(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindFilterRequest(c)
+	if !ok {
+		return
+	}
+
+	filter.Name = req.Name
+	filter.Query = req.Query
+	if err := h.Service.UpdateFilter(c.Request.Context(), filter); err != nil {
+		filterError(c, err, filter.ID)
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
+
+func (h *FilterHandler) DeleteFilter(c *gin.Context) {
+	id, ok := parseFilterID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteFilter(c.Request.Context(), id); err != nil {
+		filterError(c, err, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListFilterTasks menjalankan filter yang disimpan. Hanya limit/offset/cursor
+// yang dibaca dari query string request.
+func (h *FilterHandler) ListFilterTasks(c *gin.Context) {
+	saved, ok := h.loadFilter(c)
+	if !ok {
+		return
+	}
+
+	filter, err := saved.TaskFilter()
+	if err != nil {
+		// Filter tersimpan selalu divalidasi saat dibuat; error di sini berarti data rusak.
+		internalError(c, err)
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	view, err := parseTaskView(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if page.CursorMode && len(filter.Sort) > 0 {
+		problem(c, http.StatusBadRequest, "sort is not supported with cursor pagination")
+		return
+	}
+
+	result, err := h.Tasks.ListTasks(c.Request.Context(), filter, page, view.include)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	tasks, err := view.tasks(result.Tasks)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"filter": saved,
+		"tasks":  tasks,
+		"meta":   page.Meta(result.Total, result.Next),
+	})
+}
+
+func (h *FilterHandler) loadFilter(c *gin.Context) (*SavedFilter, bool) {
+	id, ok := parseFilterID(c)
+	if !ok {
+		return nil, false
+	}
+
+	filter, err := h.Service.GetFilter(c.Request.Context(), id)
+	if err != nil {
+		filterError(c, err, id)
+		return nil, false
+	}
+	return filter, true
+}
+
+func bindFilterRequest(c *gin.Context) (filterRequest, bool) {
+	var req filterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		problem(c, http.StatusBadRequest, "name must not be empty")
+		return req, false
+	}
+	if len(req.Name) > 128 {
+		problem(c, http.StatusBadRequest, "name must be at most 128 characters")
+		return req, false
+	}
+
+	query, err := normalizeFilterQuery(strings.TrimPrefix(strings.TrimSpace(req.Query), "?"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+	req.Query = query
+	return req, true
+}
+
+func parseFilterID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid filter id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func filterError(c *gin.Context, err error, id uint) {
+	switch {
+	case errors.Is(err, ErrFilterNotFound):
+		problem(c, http.StatusNotFound, "filter not found", gin.H{"id": id})
+	case errors.Is(err, ErrFilterNameTaken):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
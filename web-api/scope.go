@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope membatasi apa yang boleh dilakukan sebuah API key.
+type Scope string
+
+const (
+	// ScopeReadTasks mengizinkan membaca task, project, tag, filter, dan statistik.
+	ScopeReadTasks Scope = "read:tasks"
+	// ScopeWriteTasks mengizinkan mengubah data yang sama; sudah termasuk read:tasks.
+	ScopeWriteTasks Scope = "write:tasks"
+	// ScopeAdmin mengizinkan semuanya, termasuk mengelola API key dan 2FA.
+	ScopeAdmin Scope = "admin"
+)
+
+var scopes = []Scope{ScopeReadTasks, ScopeWriteTasks, ScopeAdmin}
+
+// defaultAPIKeyScopes dipakai jika scopes tidak dikirim saat membuat API key.
+var defaultAPIKeyScopes = ScopeSet{ScopeReadTasks, ScopeWriteTasks}
+
+func (s Scope) Valid() bool {
+	for _, scope := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeSet disimpan di database sebagai string yang dipisahkan spasi,
+// seperti parameter scope di OAuth2.
+type ScopeSet []Scope
+
+// Allows mengecek apakah set ini memberi akses untuk scope; admin mencakup
+// semua scope dan write:tasks mencakup read:tasks.
+func (s ScopeSet) Allows(scope Scope) bool {
+	for _, granted := range s {
+		if granted == scope || granted == ScopeAdmin || (granted == ScopeWriteTasks && scope == ScopeReadTasks) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ScopeSet) Value() (driver.Value, error) {
+	parts := make([]string, len(s))
+	for i, scope := range s {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (s *ScopeSet) Scan(value any) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into ScopeSet", value)
+	}
+
+	*s = nil
+	for _, part := range strings.Fields(raw) {
+		*s = append(*s, Scope(part))
+	}
+	return nil
+}
+
+// parseScopes memvalidasi dan membuang duplikat dari daftar scope.
+func parseScopes(values []string) (ScopeSet, error) {
+	var set ScopeSet
+	seen := make(map[Scope]bool)
+	for _, value := range values {
+		scope := Scope(strings.ToLower(strings.TrimSpace(value)))
+		if !scope.Valid() {
+			return nil, fmt.Errorf("invalid scope %q: must be one of read:tasks, write:tasks, admin", value)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			set = append(set, scope)
+		}
+	}
+	return set, nil
+}
+
+type scopesContextKey struct{}
+
+// withScopes menyimpan scope API key ke context. Request dengan access token
+// tidak punya scope di context dan tidak dibatasi.
+func withScopes(ctx context.Context, scopes ScopeSet) context.Context {
+	return context.WithValue(ctx, scopesContextKey{}, scopes)
+}
+
+// scopeAllowed bernilai true jika request tidak memakai API key atau API key-nya punya scope.
+func scopeAllowed(ctx context.Context, scope Scope) bool {
+	granted, ok := ctx.Value(scopesContextKey{}).(ScopeSet)
+	return !ok || granted.Allows(scope)
+}
+
+// requireScope menolak request dengan 403 jika API key yang dipakai tidak punya scope.
+// Harus dipasang setelah requireAuth.
+func requireScope(scope Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !scopeAllowed(c.Request.Context(), scope) {
+			insufficientScope(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireTaskScope membutuhkan read:tasks untuk GET/HEAD dan write:tasks untuk method lain.
+func requireTaskScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := ScopeWriteTasks
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = ScopeReadTasks
+		}
+		if !scopeAllowed(c.Request.Context(), scope) {
+			insufficientScope(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+func insufficientScope(c *gin.Context, scope Scope) {
+	problem(c, http.StatusForbidden, "api key is missing the required scope", gin.H{
+		"required_scope": scope,
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sessionCookie     = "session"
+	defaultSessionTTL = 7 * 24 * time.Hour
+)
+
+var ErrSessionInvalid = errors.New("invalid or expired session")
+
+// AuthMode menentukan apa yang diberikan POST /auth/login ke client.
+type AuthMode string
+
+const (
+	// AuthJWT mengembalikan access token dan refresh token di body (default).
+	AuthJWT AuthMode = "jwt"
+	// AuthSession menyimpan session di server dan mengirim id-nya lewat cookie
+	// HttpOnly, untuk web UI yang berjalan di browser.
+	AuthSession AuthMode = "session"
+)
+
+func parseAuthMode(value string) (AuthMode, error) {
+	switch mode := AuthMode(value); mode {
+	case "":
+		return AuthJWT, nil
+	case AuthJWT, AuthSession:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid AUTH_MODE %q (want jwt or session)", value)
+	}
+}
+
+// Session adalah login dari browser. Seperti token lain, yang disimpan hanya hash-nya.
+type Session struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index"`
+	User       *User     `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash  string    `gorm:"type:char(64);not null;uniqueIndex"`
+	UserAgent  string    `gorm:"type:varchar(255);not null;default:''"`
+	IP         string    `gorm:"type:varchar(64);not null;default:''"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastSeenAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+// Interface untuk layanan session
+type SessionService interface {
+	CreateSession(ctx context.Context, userID uint, userAgent, ip string) (string, *Session, error)
+	Authenticate(ctx context.Context, token string) (*Session, error)
+	DeleteSession(ctx context.Context, token string) error
+}
+
+// Struct implementasi SessionService dengan GORM
+type SessionServiceImpl struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+func (s *SessionServiceImpl) CreateSession(ctx context.Context, userID uint, userAgent, ip string) (string, *Session, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	session := Session{
+		UserID:     userID,
+		TokenHash:  hashToken(value),
+		UserAgent:  truncate(userAgent, 255),
+		IP:         truncate(ip, 64),
+		ExpiresAt:  now.Add(s.TTL),
+		LastSeenAt: now,
+	}
+	if err := s.DB.WithContext(ctx).Create(&session).Error; err != nil {
+		return "", nil, err
+	}
+	return value, &session, nil
+}
+
+// Authenticate mencari session beserta user-nya. last_seen_at paling sering
+// diperbarui sekali per menit, sama seperti API key.
+func (s *SessionServiceImpl) Authenticate(ctx context.Context, token string) (*Session, error) {
+	db := s.DB.WithContext(ctx)
+	var session Session
+	err := db.Preload("User").Where("token_hash = ?", hashToken(token)).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		return nil, ErrSessionInvalid
+	}
+	err = db.Model(&Session{}).
+		Where("id = ? AND last_seen_at < ?", session.ID, now.Add(-time.Minute)).
+		Update("last_seen_at", now).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *SessionServiceImpl) DeleteSession(ctx context.Context, token string) error {
+	return s.DB.WithContext(ctx).Where("token_hash = ?", hashToken(token)).Delete(&Session{}).Error
+}
+
+// truncate memotong s menjadi paling banyak n byte tanpa memotong karakter UTF-8.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
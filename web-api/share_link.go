@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrInvalidShareLink  = errors.New("share link is invalid or has been revoked")
+)
+
+// ShareLink memberi akses baca tanpa login ke project dan task di dalamnya
+// bagi siapa saja yang punya link. Seperti APIKey, token hanya ditampilkan
+// sekali saat dibuat; yang disimpan hanya hash dan beberapa karakter awalnya.
+type ShareLink struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ProjectID    uint       `json:"project_id" gorm:"not null;index"`
+	Project      *Project   `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Prefix       string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	TokenHash    string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Interface untuk layanan share link
+type ShareLinkService interface {
+	ListShareLinks(ctx context.Context, projectID uint) ([]ShareLink, error)
+	CreateShareLink(ctx context.Context, projectID uint) (*ShareLink, string, error)
+	DeleteShareLink(ctx context.Context, projectID, id uint) error
+	OpenShareLink(ctx context.Context, token string) (*Project, error)
+	ListSharedTasks(ctx context.Context, projectID uint, page Page) ([]Task, int64, error)
+}
+
+// Struct implementasi ShareLinkService dengan GORM
+type ShareLinkServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListShareLinks menampilkan link milik project. Seperti permission, hanya
+// pemilik project yang bisa mengelola link-nya.
+func (s *ShareLinkServiceImpl) ListShareLinks(ctx context.Context, projectID uint) ([]ShareLink, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureOwnTarget(db, ShareTarget{ProjectID: &projectID}); err != nil {
+		return nil, err
+	}
+
+	var links []ShareLink
+	err := db.Where("project_id = ?", projectID).Order("created_at DESC, id DESC").Find(&links).Error
+	return links, err
+}
+
+func (s *ShareLinkServiceImpl) CreateShareLink(ctx context.Context, projectID uint) (*ShareLink, string, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureOwnTarget(db, ShareTarget{ProjectID: &projectID}); err != nil {
+		return nil, "", err
+	}
+
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, "", err
+	}
+	link := ShareLink{ProjectID: projectID, Prefix: token[:6], TokenHash: hashToken(token)}
+	if err := db.Create(&link).Error; err != nil {
+		return nil, "", err
+	}
+	return &link, token, nil
+}
+
+// DeleteShareLink mencabut link; request berikutnya dengan token itu dijawab 404.
+func (s *ShareLinkServiceImpl) DeleteShareLink(ctx context.Context, projectID, id uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureOwnTarget(db, ShareTarget{ProjectID: &projectID}); err != nil {
+		return err
+	}
+
+	result := db.Where("project_id = ?", projectID).Delete(&ShareLink{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// OpenShareLink mencari project dari token dan mencatat waktu dibuka terakhir,
+// paling sering sekali per menit seperti last_used_at pada API key.
+func (s *ShareLinkServiceImpl) OpenShareLink(ctx context.Context, token string) (*Project, error) {
+	db := s.DB.WithContext(ctx)
+	var link ShareLink
+	err := db.Preload("Project").Where("token_hash = ?", hashToken(token)).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidShareLink
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = db.Model(&ShareLink{}).
+		Where("id = ? AND (last_viewed_at IS NULL OR last_viewed_at < ?)", link.ID, now.Add(-time.Minute)).
+		Update("last_viewed_at", now).Error
+	if err != nil {
+		return nil, err
+	}
+	return link.Project, nil
+}
+
+// ListSharedTasks mengembalikan task project tanpa memeriksa user di context;
+// pemanggil harus sudah memvalidasi token lewat OpenShareLink. Hanya subtask
+// yang dimuat; tag adalah label pribadi pemilik dan tidak ikut ditampilkan.
+func (s *ShareLinkServiceImpl) ListSharedTasks(ctx context.Context, projectID uint, page Page) ([]Task, int64, error) {
+	db := s.DB.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&Task{}).Where("project_id = ?", projectID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var tasks []Task
+	err := TaskInclude{Subtasks: true}.preload(db).Where("project_id = ?", projectID).
+		Order("position, id").Limit(page.Limit).Offset(page.Offset).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const shareLinkPath = "/public/projects/"
+
+// ShareLinkHandler berisi HTTP handler untuk /projects/:id/share-links dan
+// halaman publik /public/projects/:token.
+type ShareLinkHandler struct {
+	Service ShareLinkService
+	BaseURL string
+}
+
+func (h *ShareLinkHandler) ListShareLinks(c *gin.Context) {
+	projectID, ok := parseProjectID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.Service.ListShareLinks(c.Request.Context(), projectID)
+	if err != nil {
+		shareLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"share_links": links})
+}
+
+// CreateShareLink membuat link baru. URL lengkapnya hanya ada di respons ini.
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	projectID, ok := parseProjectID(c)
+	if !ok {
+		return
+	}
+
+	link, token, err := h.Service.CreateShareLink(c.Request.Context(), projectID)
+	if err != nil {
+		shareLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"share_link": link, "url": h.BaseURL + shareLinkPath + token})
+}
+
+func (h *ShareLinkHandler) DeleteShareLink(c *gin.Context) {
+	projectID, ok := parseProjectID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("linkID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid share link id")
+		return
+	}
+
+	if err := h.Service.DeleteShareLink(c.Request.Context(), projectID, uint(id)); err != nil {
+		shareLinkError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ShowSharedProject menampilkan project dan task-nya tanpa login. Hanya GET
+// yang tersedia, jadi pemegang link tidak bisa mengubah apa pun.
+func (h *ShareLinkHandler) ShowSharedProject(c *gin.Context) {
+	// Token ada di URL, jadi jangan sampai tersimpan di cache, terindeks, atau
+	// terkirim lewat header Referer.
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("X-Robots-Tag", "noindex")
+
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "shared projects only support limit/offset pagination")
+		return
+	}
+
+	ctx := c.Request.Context()
+	project, err := h.Service.OpenShareLink(ctx, c.Param("token"))
+	if errors.Is(err, ErrInvalidShareLink) {
+		problem(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	tasks, total, err := h.Service.ListSharedTasks(ctx, project.ID, page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": gin.H{"name": project.Name, "description": project.Description},
+		"tasks":   tasks,
+		"meta":    page.Meta(total, nil),
+	})
+}
+
+func shareLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrShareLinkNotFound):
+		problem(c, http.StatusNotFound, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
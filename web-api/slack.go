@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	// slackSignatureMaxAge membatasi umur X-Slack-Request-Timestamp supaya
+	// request lama yang tertangkap tidak bisa dikirim ulang.
+	slackSignatureMaxAge = 5 * time.Minute
+	slackCallbackPath    = "/integrations/slack/callback"
+	slackAuthStateTTL    = 10 * time.Minute
+)
+
+var (
+	ErrSlackNotConnected   = errors.New("slack is not connected to this workspace")
+	ErrSlackTeamTaken      = errors.New("this slack team is already connected to another workspace")
+	ErrSlackStateInvalid   = errors.New("invalid or expired slack authorization state")
+	ErrSlackUnavailable    = errors.New("slack request failed")
+	ErrSlackSignature      = errors.New("invalid slack signature")
+	ErrSlackUnknownCommand = errors.New("unknown slack command")
+)
+
+// SlackConnection menghubungkan satu workspace dengan satu team Slack. Slash
+// command dari team itu membuat task di workspace atas nama UserID, yaitu owner
+// yang menghubungkannya.
+type SlackConnection struct {
+	WorkspaceID uint       `json:"workspace_id" gorm:"primaryKey"`
+	Workspace   *Workspace `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	TeamID      string     `json:"team_id" gorm:"type:varchar(32);not null;uniqueIndex"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	User        *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SlackAuthState adalah state OAuth yang dibuat POST /workspaces/:id/slack/connect.
+// Callback dari Slack tidak membawa login user, jadi state (disimpan sebagai
+// hash) menunjukkan workspace dan owner yang memasang app. Hanya bisa dipakai
+// sekali.
+type SlackAuthState struct {
+	ID          uint       `gorm:"primaryKey"`
+	WorkspaceID uint       `gorm:"not null;index"`
+	Workspace   *Workspace `gorm:"constraint:OnDelete:CASCADE"`
+	UserID      uint       `gorm:"not null;index"`
+	User        *User      `gorm:"constraint:OnDelete:CASCADE"`
+	StateHash   string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt   time.Time  `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+// Interface untuk layanan integrasi Slack
+type SlackService interface {
+	GetConnection(ctx context.Context) (*SlackConnection, error)
+	AuthURL(ctx context.Context) (string, error)
+	Connect(ctx context.Context, state, code string) (*SlackConnection, error)
+	Disconnect(ctx context.Context) error
+	FindByTeam(ctx context.Context, teamID string) (*SlackConnection, error)
+}
+
+// Struct implementasi SlackService dengan GORM. Kecuali Connect dan
+// FindByTeam, semua method bekerja pada workspace di context (dari
+// /workspaces/:id). Config bernilai nil jika app Slack belum dikonfigurasi.
+type SlackServiceImpl struct {
+	DB     *gorm.DB
+	Config *oauth2.Config
+}
+
+// newSlackConfig membaca SLACK_CLIENT_ID dan SLACK_CLIENT_SECRET, atau nil
+// jika belum di-set. Redirect URL-nya <baseURL>/integrations/slack/callback,
+// yang juga harus didaftarkan di pengaturan OAuth app Slack. Scope commands
+// cukup untuk slash command; token bot dari Slack tidak disimpan.
+func newSlackConfig(baseURL string) *oauth2.Config {
+	id, secret := getEnv("SLACK_CLIENT_ID", ""), getEnv("SLACK_CLIENT_SECRET", "")
+	if id == "" || secret == "" {
+		return nil
+	}
+	return &oauth2.Config{
+		ClientID:     id,
+		ClientSecret: secret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   "https://slack.com/oauth/v2/authorize",
+			TokenURL:  "https://slack.com/api/oauth.v2.access",
+			AuthStyle: oauth2.AuthStyleInHeader,
+		},
+		RedirectURL: strings.TrimSuffix(baseURL, "/") + slackCallbackPath,
+		Scopes:      []string{"commands"},
+	}
+}
+
+func (s *SlackServiceImpl) GetConnection(ctx context.Context) (*SlackConnection, error) {
+	var connection SlackConnection
+	err := s.DB.WithContext(ctx).Scopes(inCurrentWorkspace).First(&connection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSlackNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// AuthURL membuat state baru untuk workspace di context dan mengembalikan URL
+// halaman pemasangan app Slack.
+func (s *SlackServiceImpl) AuthURL(ctx context.Context) (string, error) {
+	workspace := workspaceID(ctx)
+	if workspace == nil {
+		return "", ErrSlackNotConnected
+	}
+	value, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	state := SlackAuthState{
+		WorkspaceID: *workspace,
+		UserID:      ownerID(ctx),
+		StateHash:   hashToken(value),
+		ExpiresAt:   time.Now().Add(slackAuthStateTTL),
+	}
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ?", state.WorkspaceID).Delete(&SlackAuthState{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&state).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.Config.AuthCodeURL(value), nil
+}
+
+// Connect memakai state dari callback lalu menukar code lewat oauth.v2.access.
+// Team yang dihubungkan diambil dari jawaban Slack, bukan dari request, jadi
+// hanya admin yang benar-benar memasang app di team itu yang bisa memakainya.
+// Team sebelumnya di workspace yang sama diganti.
+func (s *SlackServiceImpl) Connect(ctx context.Context, state, code string) (*SlackConnection, error) {
+	db := s.DB.WithContext(ctx)
+	var authState SlackAuthState
+	err := db.Where("state_hash = ? AND expires_at > ?", hashToken(state), time.Now()).First(&authState).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSlackStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Delete(&authState).Error; err != nil {
+		return nil, err
+	}
+
+	token, err := s.Config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSlackUnavailable, err)
+	}
+	team, _ := token.Extra("team").(map[string]any)
+	teamID, _ := team["id"].(string)
+	if teamID == "" || len(teamID) > 32 {
+		return nil, fmt.Errorf("%w: oauth.v2.access returned no team id", ErrSlackUnavailable)
+	}
+
+	connection := SlackConnection{WorkspaceID: authState.WorkspaceID, TeamID: teamID, UserID: authState.UserID}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		err := tx.Model(&SlackConnection{}).
+			Where("team_id = ? AND workspace_id <> ?", teamID, connection.WorkspaceID).
+			Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrSlackTeamTaken
+		}
+		if err := tx.Where("workspace_id = ?", connection.WorkspaceID).Delete(&SlackConnection{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&connection).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+func (s *SlackServiceImpl) Disconnect(ctx context.Context) error {
+	result := s.DB.WithContext(ctx).Scopes(inCurrentWorkspace).Delete(&SlackConnection{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSlackNotConnected
+	}
+	return nil
+}
+
+// FindByTeam memuat koneksi team Slack beserta user yang menghubungkannya.
+func (s *SlackServiceImpl) FindByTeam(ctx context.Context, teamID string) (*SlackConnection, error) {
+	var connection SlackConnection
+	err := s.DB.WithContext(ctx).Preload("User").Where("team_id = ?", teamID).First(&connection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSlackNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// verifySlackSignature mengecek header X-Slack-Signature: "v0=" diikuti
+// HMAC-SHA256 dalam hex dari "v0:<timestamp>:<body>" dengan signing secret app.
+func verifySlackSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSlackSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return ErrSlackSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSlackSignature
+	}
+	return nil
+}
+
+// parseSlackCommand memecah teks slash command menjadi subcommand dan
+// argumennya, misalnya "add Beli susu" menjadi ("add", "Beli susu").
+func parseSlackCommand(text string) (string, string, error) {
+	command, argument, _ := strings.Cut(strings.TrimSpace(text), " ")
+	switch command = strings.ToLower(command); command {
+	case "add":
+		return command, strings.TrimSpace(argument), nil
+	case "", "help":
+		return "help", "", nil
+	}
+	return "", "", fmt.Errorf("%w %q", ErrSlackUnknownCommand, command)
+}
+
+// slackMessage adalah teks yang dikirim ke Slack incoming webhook untuk satu event.
+func slackMessage(payload *WebhookPayload) string {
+	var action string
+	switch payload.Event {
+	case WebhookTaskCreated:
+		action = "Task created"
+	case WebhookTaskCompleted:
+		action = "Task completed"
+	case WebhookTaskDeleted:
+		action = "Task deleted"
+	default:
+		action = "Task updated"
+	}
+	return fmt.Sprintf("%s: *%s* (#%d, %s)", action, slackEscape(payload.Task.Title), payload.Task.ID, payload.Task.Status)
+}
+
+// slackEscape meng-escape karakter kontrol format pesan Slack.
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSlackCommandBytes membatasi body slash command; Slack mengirim form kecil.
+const maxSlackCommandBytes = 64 << 10
+
+const slackUsage = "Usage: `/todo add <title>` creates a task in the connected workspace."
+
+// SlackHandler berisi HTTP handler untuk /workspaces/:id/slack dan slash
+// command Slack. SigningSecret adalah signing secret app Slack.
+type SlackHandler struct {
+	Service       SlackService
+	Workspaces    WorkspaceService
+	Tasks         TaskService
+	SigningSecret string
+}
+
+func (h *SlackHandler) GetConnection(c *gin.Context) {
+	connection, err := h.Service.GetConnection(c.Request.Context())
+	if err != nil {
+		slackError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+// Connect mengembalikan URL pemasangan app Slack. Owner membuka URL itu di
+// browser; setelah admin team Slack menyetujui, Slack mengarahkannya ke
+// Callback dan /todo dari team itu membuat task di workspace ini atas nama
+// owner yang menghubungkannya.
+func (h *SlackHandler) Connect(c *gin.Context) {
+	url, err := h.Service.AuthURL(c.Request.Context())
+	if err != nil {
+		slackError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"url": url, "expires_at": time.Now().Add(slackAuthStateTTL)})
+}
+
+// Callback menerima redirect dari Slack. Route ini publik karena browser
+// tidak membawa access token; workspace ditentukan dari state.
+func (h *SlackHandler) Callback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		problem(c, http.StatusUnauthorized, "authorization denied: "+reason)
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		problem(c, http.StatusBadRequest, "state and code are required")
+		return
+	}
+
+	connection, err := h.Service.Connect(c.Request.Context(), state, code)
+	if err != nil {
+		slackError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+func (h *SlackHandler) Disconnect(c *gin.Context) {
+	if err := h.Service.Disconnect(c.Request.Context()); err != nil {
+		slackError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Command menerima slash command /todo dari Slack. Tanda tangan request dicek
+// dengan SigningSecret; balasan dikirim sebagai pesan ephemeral yang hanya
+// terlihat oleh pengirim command.
+func (h *SlackHandler) Command(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSlackCommandBytes)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problem(c, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	timestamp := c.GetHeader("X-Slack-Request-Timestamp")
+	signature := c.GetHeader("X-Slack-Signature")
+	if err := verifySlackSignature(h.SigningSecret, timestamp, signature, body, time.Now()); err != nil {
+		problem(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid form body")
+		return
+	}
+
+	command, title, err := parseSlackCommand(form.Get("text"))
+	if err != nil || command == "help" || title == "" {
+		slackReply(c, slackUsage)
+		return
+	}
+
+	ctx := c.Request.Context()
+	connection, err := h.Service.FindByTeam(ctx, form.Get("team_id"))
+	if errors.Is(err, ErrSlackNotConnected) {
+		slackReply(c, "This Slack team is not connected to a workspace yet.")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if connection.User == nil || connection.User.Disabled() {
+		slackReply(c, "The account that connected this Slack team is disabled.")
+		return
+	}
+	member, err := h.Workspaces.GetMembership(ctx, connection.WorkspaceID, connection.UserID)
+	if err != nil && !errors.Is(err, ErrWorkspaceNotFound) {
+		internalError(c, err)
+		return
+	}
+	if member == nil || !member.Role.AtLeast(WorkspaceEditor) {
+		slackReply(c, "The account that connected this Slack team can no longer create tasks in the workspace.")
+		return
+	}
+
+	task := Task{Title: title, Status: StatusTodo, Priority: PriorityMedium}
+	if name := form.Get("user_name"); name != "" {
+		task.Description = "Created from Slack by @" + name
+	}
+	if err := task.Validate(); err != nil {
+		slackReply(c, err.Error())
+		return
+	}
+	ctx = withWorkspace(withUser(ctx, connection.User), member)
+	if err := h.Tasks.CreateTask(ctx, &task); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	slackReply(c, fmt.Sprintf("Created task #%d: %s", task.ID, slackEscape(task.Title)))
+}
+
+func slackReply(c *gin.Context, text string) {
+	c.JSON(http.StatusOK, gin.H{"response_type": "ephemeral", "text": text})
+}
+
+func slackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrSlackNotConnected):
+		problem(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrSlackTeamTaken):
+		problem(c, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrSlackStateInvalid):
+		problem(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrSlackUnavailable):
+		log.Printf("slack: %v", err)
+		problem(c, http.StatusBadGateway, "could not connect slack; try again later")
+	default:
+		internalError(c, err)
+	}
+}
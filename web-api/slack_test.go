@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func slackTestSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+	now := time.Unix(1531420618, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := []byte("token=xyz&team_id=T1DC2JH3J&command=%2Ftodo&text=add+Beli+susu")
+	valid := slackTestSignature(secret, timestamp, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   bool
+	}{
+		{"valid", secret, timestamp, valid, body, now, false},
+		{"valid at max age", secret, timestamp, valid, body, now.Add(slackSignatureMaxAge), false},
+		{"wrong secret", "other", timestamp, valid, body, now, true},
+		{"tampered body", secret, timestamp, valid, []byte(string(body) + "&x=1"), now, true},
+		{"signature for other timestamp", secret, timestamp, slackTestSignature(secret, "1531420000", body), body, now, true},
+		{"timestamp too old", secret, timestamp, valid, body, now.Add(slackSignatureMaxAge + time.Second), true},
+		{"timestamp in the future", secret, timestamp, valid, body, now.Add(-slackSignatureMaxAge - time.Second), true},
+		{"timestamp not a number", secret, "abc", slackTestSignature(secret, "abc", body), body, now, true},
+		{"missing version prefix", secret, timestamp, valid[len("v0="):], body, now, true},
+		{"empty signature", secret, timestamp, "", body, now, true},
+		{"uppercase hex", secret, timestamp, "v0=" + hexUpper(valid[len("v0="):]), body, now, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySlackSignature(tt.secret, tt.timestamp, tt.signature, tt.body, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifySlackSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrSlackSignature) {
+				t.Errorf("error = %v, want ErrSlackSignature", err)
+			}
+		})
+	}
+}
+
+func hexUpper(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'a' <= c && c <= 'f' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+func TestParseSlackCommand(t *testing.T) {
+	tests := []struct {
+		text        string
+		wantCommand string
+		wantArg     string
+		wantErr     bool
+	}{
+		{"add Beli susu", "add", "Beli susu", false},
+		{"  ADD   Beli susu  ", "add", "Beli susu", false},
+		{"add", "add", "", false},
+		{"", "help", "", false},
+		{"help", "help", "", false},
+		{"delete 12", "", "", true},
+	}
+	for _, tt := range tests {
+		command, arg, err := parseSlackCommand(tt.text)
+		if command != tt.wantCommand || arg != tt.wantArg || (err != nil) != tt.wantErr {
+			t.Errorf("parseSlackCommand(%q) = (%q, %q, %v), want (%q, %q, err %v)", tt.text, command, arg, err, tt.wantCommand, tt.wantArg, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrSlackUnknownCommand) {
+			t.Errorf("parseSlackCommand(%q) error = %v, want ErrSlackUnknownCommand", tt.text, err)
+		}
+	}
+}
+
+func TestSlackEscape(t *testing.T) {
+	got := slackEscape("<!channel> & <@U123>")
+	want := "&lt;!channel&gt; &amp; &lt;@U123&gt;"
+	if got != want {
+		t.Errorf("slackEscape() = %q, want %q", got, want)
+	}
+}
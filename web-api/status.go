@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskStatus adalah tahap workflow sebuah task.
+type TaskStatus string
+
+const (
+	StatusTodo       TaskStatus = "todo"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusDone       TaskStatus = "done"
+	StatusCancelled  TaskStatus = "cancelled"
+)
+
+// statusTransitions berisi perpindahan status yang diizinkan. Tetap di status
+// yang sama selalu diizinkan.
+var statusTransitions = map[TaskStatus][]TaskStatus{
+	StatusTodo:       {StatusInProgress, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusDone, StatusCancelled},
+	StatusDone:       {StatusTodo, StatusInProgress},
+	StatusCancelled:  {StatusTodo},
+}
+
+func (s TaskStatus) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// Closed bernilai true untuk status yang tidak lagi dikerjakan.
+func (s TaskStatus) Closed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTaskStatus menerima nilai tanpa memperhatikan huruf besar/kecil; string kosong menjadi todo.
+func ParseTaskStatus(value string) (TaskStatus, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return StatusTodo, nil
+	}
+	if status := TaskStatus(value); status.Valid() {
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid status %q: must be one of todo, in_progress, done, cancelled", value)
+}
+
+// InvalidTransitionError dikembalikan saat perpindahan status tidak diizinkan.
+type InvalidTransitionError struct {
+	TaskID uint
+	From   TaskStatus
+	To     TaskStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change status from %s to %s", e.From, e.To)
+}
+
+// statusRankSQL mengurutkan kolom status mengikuti alur kerja: todo, in_progress, done, cancelled.
+func statusRankSQL() string {
+	var b strings.Builder
+	b.WriteString("CASE status")
+	for rank, status := range []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusCancelled} {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", status, rank+1)
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
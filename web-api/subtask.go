@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSubtaskNotFound = errors.New("subtask not found")
+
+// Subtask adalah item checklist di bawah sebuah task.
+type Subtask struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ParentTaskID uint      `json:"parent_task_id" gorm:"not null;index"`
+	Title        string    `json:"title" gorm:"not null"`
+	Done         bool      `json:"done" gorm:"not null;default:false"`
+	Position     int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Interface untuk layanan subtask
+type SubtaskService interface {
+	ListSubtasks(ctx context.Context, taskID uint) ([]Subtask, error)
+	GetSubtask(ctx context.Context, taskID, id uint) (*Subtask, error)
+	CreateSubtask(ctx context.Context, subtask *Subtask) error
+	UpdateSubtask(ctx context.Context, subtask *Subtask) error
+	DeleteSubtask(ctx context.Context, taskID, id uint) error
+}
+
+// Struct implementasi SubtaskService dengan GORM
+type SubtaskServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *SubtaskServiceImpl) ListSubtasks(ctx context.Context, taskID uint) ([]Subtask, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return nil, err
+	}
+
+	var subtasks []Subtask
+	err := db.Where("parent_task_id = ?", taskID).Order("position, id").Find(&subtasks).Error
+	return subtasks, err
+}
+
+func (s *SubtaskServiceImpl) GetSubtask(ctx context.Context, taskID, id uint) (*Subtask, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return nil, err
+	}
+
+	var subtask Subtask
+	err := db.Where("parent_task_id = ?", taskID).First(&subtask, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubtaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// CreateSubtask menambahkan subtask di akhir checklist milik ParentTaskID.
+func (s *SubtaskServiceImpl) CreateSubtask(ctx context.Context, subtask *Subtask) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, subtask.ParentTaskID); err != nil {
+			return err
+		}
+
+		var max int
+		err := tx.Model(&Subtask{}).
+			Where("parent_task_id = ?", subtask.ParentTaskID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&max).Error
+		if err != nil {
+			return err
+		}
+		subtask.Position = max + 1
+		return tx.Create(subtask).Error
+	})
+}
+
+func (s *SubtaskServiceImpl) UpdateSubtask(ctx context.Context, subtask *Subtask) error {
+	return s.DB.WithContext(ctx).Save(subtask).Error
+}
+
+func (s *SubtaskServiceImpl) DeleteSubtask(ctx context.Context, taskID, id uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return err
+	}
+
+	result := db.Where("parent_task_id = ?", taskID).Delete(&Subtask{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubtaskNotFound
+	}
+	return nil
+}
+
+// ensureTaskExists mengecek task ada dan bisa diakses user di context tx.
+func ensureTaskExists(tx *gorm.DB, taskID uint) error {
+	var count int64
+	if err := tx.Model(&Task{}).Scopes(accessibleTasks).Where("id = ?", taskID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
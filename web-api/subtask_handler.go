@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type subtaskRequest struct {
+	Title string `json:"title" binding:"required"`
+	Done  bool   `json:"done"`
+}
+
+type patchSubtaskRequest struct {
+	Title *string `json:"title"`
+	Done  *bool   `json:"done"`
+}
+
+// SubtaskHandler berisi HTTP handler untuk /tasks/:id/subtasks.
+type SubtaskHandler struct {
+	Service SubtaskService
+}
+
+func (h *SubtaskHandler) ListSubtasks(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	subtasks, err := h.Service.ListSubtasks(c.Request.Context(), taskID)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subtasks": subtasks})
+}
+
+func (h *SubtaskHandler) CreateSubtask(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	var req subtaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		problem(c, http.StatusBadRequest, "title must not be empty")
+		return
+	}
+
+	subtask := Subtask{ParentTaskID: taskID, Title: req.Title, Done: req.Done}
+	err := h.Service.CreateSubtask(c.Request.Context(), &subtask)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subtask)
+}
+
+func (h *SubtaskHandler) GetSubtask(c *gin.Context) {
+	subtask, ok := h.loadSubtask(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, subtask)
+}
+
+func (h *SubtaskHandler) PatchSubtask(c *gin.Context) {
+	subtask, ok := h.loadSubtask(c)
+	if !ok {
+		return
+	}
+
+	var req patchSubtaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			problem(c, http.StatusBadRequest, "title must not be empty")
+			return
+		}
+		subtask.Title = title
+	}
+	if req.Done != nil {
+		subtask.Done = *req.Done
+	}
+
+	if err := h.Service.UpdateSubtask(c.Request.Context(), subtask); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subtask)
+}
+
+func (h *SubtaskHandler) DeleteSubtask(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseSubtaskID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.DeleteSubtask(c.Request.Context(), taskID, id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if errors.Is(err, ErrSubtaskNotFound) {
+		subtaskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SubtaskHandler) loadSubtask(c *gin.Context) (*Subtask, bool) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return nil, false
+	}
+	id, ok := parseSubtaskID(c)
+	if !ok {
+		return nil, false
+	}
+
+	subtask, err := h.Service.GetSubtask(c.Request.Context(), taskID, id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return nil, false
+	}
+	if errors.Is(err, ErrSubtaskNotFound) {
+		subtaskNotFound(c, id)
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return subtask, true
+}
+
+func parseSubtaskID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("subtaskID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid subtask id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func subtaskNotFound(c *gin.Context, id uint) {
+	problem(c, http.StatusNotFound, "subtask not found", gin.H{
+		"id": id,
+	})
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTagNotFound  = errors.New("tag not found")
+	ErrTagNameTaken = errors.New("tag name already exists")
+)
+
+// Tag adalah label yang bisa dipasang ke banyak task lewat tabel task_tags.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    *uint     `json:"-" gorm:"uniqueIndex:idx_tags_user_name,priority:1"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name      string    `json:"name" gorm:"type:varchar(64);not null;uniqueIndex:idx_tags_user_name,priority:2"`
+	Color     string    `json:"color" gorm:"type:varchar(16);not null;default:''"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MissingTagsError dikembalikan jika sebagian tag yang diminta tidak ada.
+type MissingTagsError struct {
+	IDs []uint
+}
+
+func (e *MissingTagsError) Error() string {
+	return fmt.Sprintf("tags not found: %v", e.IDs)
+}
+
+func (e *MissingTagsError) Unwrap() error {
+	return ErrTagNotFound
+}
+
+// TagChange berisi tag yang dipasang dan dilepas pada operasi bulk.
+type TagChange struct {
+	Add    []uint
+	Remove []uint
+}
+
+func (t TagChange) Empty() bool {
+	return len(t.Add) == 0 && len(t.Remove) == 0
+}
+
+// Interface untuk layanan tag
+type TagService interface {
+	ListTags(ctx context.Context) ([]Tag, error)
+	GetTag(ctx context.Context, id uint) (*Tag, error)
+	CreateTag(ctx context.Context, tag *Tag) error
+	UpdateTag(ctx context.Context, tag *Tag) error
+	DeleteTag(ctx context.Context, id uint) error
+	AttachTag(ctx context.Context, taskID, tagID uint) error
+	DetachTag(ctx context.Context, taskID, tagID uint) error
+}
+
+// Struct implementasi TagService dengan GORM
+type TagServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *TagServiceImpl) ListTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (s *TagServiceImpl) GetTag(ctx context.Context, id uint) (*Tag, error) {
+	var tag Tag
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).First(&tag, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (s *TagServiceImpl) CreateTag(ctx context.Context, tag *Tag) error {
+	owner := ownerID(ctx)
+	tag.UserID = &owner
+	err := s.DB.WithContext(ctx).Create(tag).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrTagNameTaken
+	}
+	return err
+}
+
+func (s *TagServiceImpl) UpdateTag(ctx context.Context, tag *Tag) error {
+	err := s.DB.WithContext(ctx).Save(tag).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrTagNameTaken
+	}
+	return err
+}
+
+// DeleteTag menghapus tag; relasi di task_tags ikut terhapus lewat ON DELETE CASCADE.
+func (s *TagServiceImpl) DeleteTag(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&Tag{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func (s *TagServiceImpl) AttachTag(ctx context.Context, taskID, tagID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, taskID); err != nil {
+			return err
+		}
+		return applyTagChange(tx, []uint{taskID}, TagChange{Add: []uint{tagID}})
+	})
+}
+
+func (s *TagServiceImpl) DetachTag(ctx context.Context, taskID, tagID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, taskID); err != nil {
+			return err
+		}
+		return applyTagChange(tx, []uint{taskID}, TagChange{Remove: []uint{tagID}})
+	})
+}
+
+// taskTag adalah baris pada tabel join task_tags.
+type taskTag struct {
+	TaskID uint
+	TagID  uint
+}
+
+// applyTagChange memasang dan melepas tag untuk semua taskIDs di dalam tx.
+func applyTagChange(tx *gorm.DB, taskIDs []uint, change TagChange) error {
+	if change.Empty() {
+		return nil
+	}
+	if err := ensureTagsExist(tx, append(append([]uint{}, change.Add...), change.Remove...)); err != nil {
+		return err
+	}
+
+	if len(change.Remove) > 0 {
+		err := tx.Table("task_tags").
+			Where("task_id IN ? AND tag_id IN ?", taskIDs, change.Remove).
+			Delete(&taskTag{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(change.Add) > 0 {
+		rows := make([]taskTag, 0, len(taskIDs)*len(change.Add))
+		for _, taskID := range taskIDs {
+			for _, tagID := range change.Add {
+				rows = append(rows, taskTag{TaskID: taskID, TagID: tagID})
+			}
+		}
+		err := tx.Table("task_tags").Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ensureTagsExist(tx *gorm.DB, ids []uint) error {
+	var found []uint
+	if err := tx.Model(&Tag{}).Scopes(ownedByUser).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return err
+	}
+
+	exists := make(map[uint]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	missing := &MissingTagsError{}
+	for _, id := range ids {
+		if !exists[id] {
+			exists[id] = true
+			missing.IDs = append(missing.IDs, id)
+		}
+	}
+	if len(missing.IDs) > 0 {
+		return missing
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+// TagHandler berisi HTTP handler untuk /tags dan pemasangan tag ke task.
+type TagHandler struct {
+	Service TagService
+}
+
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.Service.ListTags(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	req, ok := bindTagRequest(c)
+	if !ok {
+		return
+	}
+
+	tag := Tag{Name: req.Name, Color: req.Color}
+	if err := h.Service.CreateTag(c.Request.Context(), &tag); err != nil {
+		tagError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+func (h *TagHandler) GetTag(c *gin.Context) {
+	tag, ok := h.loadTag(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tag, ok := h.loadTag(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindTagRequest(c)
+	if !ok {
+		return
+	}
+
+	tag.Name = req.Name
+	tag.Color = req.Color
+	if err := h.Service.UpdateTag(c.Request.Context(), tag); err != nil {
+		tagError(c, err, tag.ID)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := parseTagID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteTag(c.Request.Context(), id); err != nil {
+		tagError(c, err, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AttachTag memasang tag :tagID ke task :id. Memasang ulang tag yang sama tidak dianggap error.
+func (h *TagHandler) AttachTag(c *gin.Context) {
+	h.changeTaskTag(c, h.Service.AttachTag)
+}
+
+func (h *TagHandler) DetachTag(c *gin.Context) {
+	h.changeTaskTag(c, h.Service.DetachTag)
+}
+
+func (h *TagHandler) changeTaskTag(c *gin.Context, change func(ctx context.Context, taskID, tagID uint) error) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	tagID, ok := parseTagID(c, "tagID")
+	if !ok {
+		return
+	}
+
+	err := change(c.Request.Context(), taskID, tagID)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		tagError(c, err, tagID)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TagHandler) loadTag(c *gin.Context) (*Tag, bool) {
+	id, ok := parseTagID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	tag, err := h.Service.GetTag(c.Request.Context(), id)
+	if err != nil {
+		tagError(c, err, id)
+		return nil, false
+	}
+	return tag, true
+}
+
+func bindTagRequest(c *gin.Context) (tagRequest, bool) {
+	var req tagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	req.Name = normalizeTagName(req.Name)
+	if req.Name == "" {
+		problem(c, http.StatusBadRequest, "name must not be empty")
+		return req, false
+	}
+	if len(req.Name) > 64 {
+		problem(c, http.StatusBadRequest, "name must be at most 64 characters")
+		return req, false
+	}
+	return req, true
+}
+
+// normalizeTagName membuat nama tag case-insensitive supaya filter ?tag= konsisten.
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func parseTagID(c *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid tag id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func tagError(c *gin.Context, err error, id uint) {
+	var missing *MissingTagsError
+	switch {
+	case errors.As(err, &missing):
+		problem(c, http.StatusNotFound, "tag not found", gin.H{"ids": missing.IDs})
+	case errors.Is(err, ErrTagNotFound):
+		problem(c, http.StatusNotFound, "tag not found", gin.H{"id": id})
+	case errors.Is(err, ErrTagNameTaken):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
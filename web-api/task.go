@@ -0,0 +1,627 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrTaskVersionConflict = errors.New("task was modified by another request")
+	ErrInvalidAssignee     = errors.New("assignee does not have access to the task")
+)
+
+// MissingTasksError dikembalikan operasi bulk jika sebagian id tidak ditemukan.
+type MissingTasksError struct {
+	IDs []uint
+}
+
+func (e *MissingTasksError) Error() string {
+	return fmt.Sprintf("tasks not found: %v", e.IDs)
+}
+
+func (e *MissingTasksError) Unwrap() error {
+	return ErrTaskNotFound
+}
+
+// Task adalah model todo yang disimpan di tabel tasks.
+type Task struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      *uint      `json:"-" gorm:"index"`
+	User        *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	WorkspaceID *uint      `json:"workspace_id" gorm:"index"`
+	Workspace   *Workspace `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Title       string     `json:"title" gorm:"not null"`
+	Description string     `json:"description"`
+	// DescriptionHTML hanya diisi jika client meminta ?render=html.
+	DescriptionHTML *string  `json:"description_html,omitempty" gorm:"-"`
+	ProjectID       *uint    `json:"project_id" gorm:"index"`
+	Project         *Project `json:"project,omitempty" gorm:"constraint:OnDelete:SET NULL"`
+	// AssigneeID adalah user yang mengerjakan task; bisa berbeda dari pembuatnya (UserID).
+	AssigneeID  *uint      `json:"assignee_id" gorm:"index"`
+	Assignee    *User      `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Status      TaskStatus `json:"status" gorm:"type:varchar(16);not null;default:'todo';index"`
+	Priority    Priority   `json:"priority" gorm:"type:varchar(16);not null;default:'medium';index"`
+	Position    int        `json:"position" gorm:"not null;default:0;index"`
+	DueAt       *time.Time `json:"due_at" gorm:"index"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// ArchivedAt diisi saat task diarsipkan; task arsip hanya muncul di daftar dengan ?archived=true.
+	ArchivedAt *time.Time `json:"archived_at" gorm:"index"`
+	// RemindedDueAt adalah due_at terakhir yang sudah dikirimi email pengingat.
+	RemindedDueAt *time.Time `json:"-"`
+	// Recurrence berisi RRULE (subset RFC 5545); kosong berarti task tidak berulang.
+	Recurrence          string `json:"recurrence" gorm:"not null;default:''"`
+	RecurrenceIndex     int    `json:"-" gorm:"not null;default:1"`
+	RecurrenceParentID  *uint  `json:"recurrence_parent_id"`
+	RecurrenceScheduled bool   `json:"-" gorm:"not null;default:false;index"`
+	// Version naik setiap kali task disimpan lewat UpdateTask/UpdateTasks dan
+	// dipakai untuk If-Match; perubahan urutan, subtask dan tag tidak menaikkannya.
+	Version   int       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt diisi saat task dihapus; task tetap ada di trash sampai dipulihkan atau dihapus permanen.
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"`
+	Subtasks  []Subtask      `json:"subtasks" gorm:"foreignKey:ParentTaskID;constraint:OnDelete:CASCADE"`
+	Tags      []Tag          `json:"tags" gorm:"many2many:task_tags;constraint:OnDelete:CASCADE"`
+	// Attachments hanya berisi metadata; isi file diunduh lewat /tasks/:id/attachments/:attachmentID.
+	Attachments []Attachment `json:"attachments" gorm:"constraint:OnDelete:CASCADE"`
+	// CustomFields berisi nilai custom field workspace; selalu kosong untuk task pribadi.
+	CustomFields []CustomFieldValue `json:"custom_fields" gorm:"constraint:OnDelete:CASCADE"`
+	BlockedBy    []TaskRef          `json:"blocked_by" gorm:"-"`
+	Blocks       []TaskRef          `json:"blocks" gorm:"-"`
+	// Links diisi saat task di-encode ke JSON.
+	Links Links `json:"_links,omitempty" gorm:"-"`
+}
+
+// SetStatus memindahkan task ke status baru jika transisinya diizinkan.
+// CompletedAt diisi saat masuk ke done dan dikosongkan saat keluar dari done.
+func (t *Task) SetStatus(status TaskStatus) error {
+	if t.Status == "" {
+		t.Status = StatusTodo
+	}
+	if !t.Status.CanTransitionTo(status) {
+		return &InvalidTransitionError{TaskID: t.ID, From: t.Status, To: status}
+	}
+	if status == t.Status {
+		return nil
+	}
+
+	t.Status = status
+	if status == StatusDone {
+		now := time.Now()
+		t.CompletedAt = &now
+	} else {
+		t.CompletedAt = nil
+	}
+	return nil
+}
+
+// BeforeSave memastikan project_id menunjuk ke project di tempat yang sama
+// (data pribadi pemilik task atau workspace-nya) dan assignee_id ke user yang
+// boleh mengerjakannya sebelum task disimpan.
+func (t *Task) BeforeSave(tx *gorm.DB) error {
+	tx = tx.Session(&gorm.Session{NewDB: true})
+	if t.ProjectID != nil {
+		if err := ensureProjectExists(tx, *t.ProjectID, t.UserID, t.WorkspaceID); err != nil {
+			return err
+		}
+	}
+	if t.AssigneeID == nil {
+		return nil
+	}
+	return ensureAssignable(tx, t)
+}
+
+// AfterCreate memberi notifikasi ke assignee dan mengirim event task.created ke
+// webhook workspace dan client WebSocket, dari jalur mana pun task dibuat
+// (termasuk bulk, duplikat, template, dan task berulang).
+func (t *Task) AfterCreate(tx *gorm.DB) error {
+	// Instance task berulang mewarisi assignee dari task sebelumnya, jadi
+	// assignee tidak perlu diberi tahu lagi.
+	if t.RecurrenceParentID == nil {
+		if err := notifyAssignee(tx, nil, t); err != nil {
+			return err
+		}
+	}
+	if err := enqueueWebhooks(tx, WebhookTaskCreated, *t); err != nil {
+		return err
+	}
+	return recordTaskStream(tx, WebhookTaskCreated, *t)
+}
+
+// ensureAssignable mengecek bahwa assignee bisa membuka task (lihat
+// canSeeTask): member workspace-nya, atau untuk task pribadi pemiliknya dan
+// penerima share task atau project-nya.
+func ensureAssignable(tx *gorm.DB, t *Task) error {
+	ok, err := canSeeTask(tx, t, *t.AssigneeID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
+
+// unassignWithoutAccess mengosongkan assignee_id task yang di-assign ke userID
+// tetapi tidak lagi bisa diakses user itu, setelah ia keluar dari workspace
+// atau share-nya dicabut, termasuk task di trash. Tanpa ini task tersebut
+// tidak bisa disimpan lagi.
+func unassignWithoutAccess(tx *gorm.DB, userID uint) error {
+	sub := tx.Session(&gorm.Session{NewDB: true})
+	memberOf := sub.Model(&WorkspaceMember{}).Select("workspace_id").Where("user_id = ?", userID)
+	sharedTasks := sub.Model(&TaskPermission{}).Select("task_id").Where("user_id = ? AND task_id IS NOT NULL", userID)
+	sharedProjects := sub.Model(&TaskPermission{}).Select("project_id").Where("user_id = ? AND project_id IS NOT NULL", userID)
+	return tx.Unscoped().Model(&Task{}).
+		Where("assignee_id = ?", userID).
+		Where(sub.Where("workspace_id IS NOT NULL AND workspace_id NOT IN (?)", memberOf).
+			Or(sub.Where("workspace_id IS NULL AND (user_id IS NULL OR user_id <> ?)", userID).
+				Where("id NOT IN (?)", sharedTasks).
+				Where("project_id IS NULL OR project_id NOT IN (?)", sharedProjects))).
+		Update("assignee_id", nil).Error
+}
+
+// Validate mengecek aturan antar-field yang tidak bisa dicek dari satu request saja.
+func (t *Task) Validate() error {
+	if !t.Priority.Valid() {
+		return fmt.Errorf("invalid priority %q", t.Priority)
+	}
+	if t.Recurrence == "" {
+		return nil
+	}
+	if _, err := ParseRRule(t.Recurrence); err != nil {
+		return fmt.Errorf("invalid recurrence: %w", err)
+	}
+	if t.DueAt == nil {
+		return errors.New("recurring tasks require due_at")
+	}
+	return nil
+}
+
+// TaskValidationError dikembalikan operasi bulk jika sebuah task tidak valid setelah diubah.
+type TaskValidationError struct {
+	TaskID uint
+	Err    error
+}
+
+func (e *TaskValidationError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.TaskID, e.Err)
+}
+
+func (e *TaskValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Interface untuk layanan task
+type TaskService interface {
+	ListTasks(ctx context.Context, filter TaskFilter, page Page, include TaskInclude) (*TaskPage, error)
+	SearchTasks(ctx context.Context, q string, filter TaskFilter, page Page) ([]TaskSearchResult, int64, error)
+	SummarizeTasks(ctx context.Context, filter TaskFilter, now time.Time, loc *time.Location) (*TaskSummary, error)
+	ListAgendaTasks(ctx context.Context, filter TaskFilter, start, end time.Time) ([]Task, error)
+	TaskStats(ctx context.Context, weeks int, now time.Time, loc *time.Location) (*TaskStats, error)
+	GetTask(ctx context.Context, id uint, include TaskInclude) (*Task, error)
+	CreateTask(ctx context.Context, task *Task) error
+	CreateTasks(ctx context.Context, tasks []Task) error
+	UpdateTask(ctx context.Context, task *Task) error
+	UpdateTasks(ctx context.Context, ids []uint, apply func(task *Task) error, tags TagChange) ([]Task, error)
+	DeleteTask(ctx context.Context, id uint) error
+	DeleteTasks(ctx context.Context, ids []uint) error
+	MoveTask(ctx context.Context, id, targetID uint, after bool) (*Task, error)
+	DuplicateTask(ctx context.Context, id uint) (*Task, error)
+	AddDependency(ctx context.Context, taskID, blockerID uint) error
+	RemoveDependency(ctx context.Context, taskID, blockerID uint) error
+	OpenBlockers(ctx context.Context, ids []uint) (map[uint][]TaskRef, error)
+}
+
+// Struct implementasi TaskService dengan GORM
+type TaskServiceImpl struct {
+	DB *gorm.DB
+	// UndoWindow adalah lama hapus dan selesai bisa dibatalkan lewat POST /undo; 0 mematikannya.
+	UndoWindow time.Duration
+}
+
+// TaskPage adalah satu halaman hasil ListTasks.
+type TaskPage struct {
+	Tasks []Task
+	Total int64
+	// Next berisi cursor halaman berikutnya pada mode cursor; nil jika sudah habis.
+	Next *Cursor
+}
+
+// ListTasks mengembalikan satu halaman task beserta jumlah total task yang cocok dengan filter.
+func (s *TaskServiceImpl) ListTasks(ctx context.Context, filter TaskFilter, page Page, include TaskInclude) (*TaskPage, error) {
+	db := s.DB.WithContext(ctx)
+
+	result := &TaskPage{}
+	if err := filter.apply(db.Model(&Task{}).Scopes(accessibleTasks)).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	query := filter.apply(include.preload(db).Scopes(accessibleTasks))
+	if page.CursorMode {
+		// Keyset pagination harus memakai urutan yang sama dengan kunci cursor.
+		if page.Cursor != nil {
+			query = query.Where("created_at > ? OR (created_at = ? AND id > ?)",
+				page.Cursor.CreatedAt, page.Cursor.CreatedAt, page.Cursor.ID)
+		}
+		query = query.Order("created_at, id").Limit(page.Limit + 1)
+	} else {
+		query = filter.order(query).Limit(page.Limit).Offset(page.Offset)
+	}
+
+	if err := query.Find(&result.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if page.CursorMode && len(result.Tasks) > page.Limit {
+		result.Tasks = result.Tasks[:page.Limit]
+		last := result.Tasks[len(result.Tasks)-1]
+		result.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	if include.Dependencies {
+		if err := loadDependencies(db, result.Tasks); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *TaskServiceImpl) GetTask(ctx context.Context, id uint, include TaskInclude) (*Task, error) {
+	db := s.DB.WithContext(ctx)
+	tasks := make([]Task, 1)
+	err := include.preload(db).Scopes(accessibleTasks).First(&tasks[0], id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if include.Dependencies {
+		if err := loadDependencies(db, tasks); err != nil {
+			return nil, err
+		}
+	}
+	return &tasks[0], nil
+}
+
+func (s *TaskServiceImpl) CreateTask(ctx context.Context, task *Task) error {
+	owner := ownerID(ctx)
+	task.UserID = &owner
+	task.WorkspaceID = workspaceID(ctx)
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := placeInSharedProject(tx, task); err != nil {
+			return err
+		}
+		position, err := nextPosition(tx, task.UserID, task.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		task.Position = position
+		return tx.Create(task).Error
+	})
+}
+
+// CreateTasks menyimpan semua task dalam satu transaksi; jika satu gagal, semuanya dibatalkan.
+func (s *TaskServiceImpl) CreateTasks(ctx context.Context, tasks []Task) error {
+	owner := ownerID(ctx)
+	workspace := workspaceID(ctx)
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		position, err := nextPosition(tx, &owner, workspace)
+		if err != nil {
+			return err
+		}
+		for i := range tasks {
+			tasks[i].UserID = &owner
+			tasks[i].WorkspaceID = workspace
+			tasks[i].Position = position + i
+		}
+		return tx.Create(&tasks).Error
+	})
+}
+
+// UpdateTask hanya menyimpan kolom task; subtasks dikelola lewat SubtaskService.
+// Mengembalikan ErrTaskVersionConflict jika task sudah diubah request lain sejak dimuat.
+func (s *TaskServiceImpl) UpdateTask(ctx context.Context, task *Task) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := saveTaskVersion(tx, task)
+		if err != nil {
+			return err
+		}
+		return s.recordCompletions(tx, []Task{before}, []Task{*task})
+	})
+}
+
+// UpdateTasks memuat semua task dalam ids, menjalankan apply pada masing-masing,
+// mengubah tag sesuai tags, lalu menyimpannya dalam satu transaksi.
+func (s *TaskServiceImpl) UpdateTasks(ctx context.Context, ids []uint, apply func(task *Task) error, tags TagChange) ([]Task, error) {
+	var tasks []Task
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		tasks, err = findTasksByIDs(tx, accessibleTasks, ids)
+		if err != nil {
+			return err
+		}
+		before := make([]Task, len(tasks))
+		for i := range tasks {
+			if err := apply(&tasks[i]); err != nil {
+				return err
+			}
+			if before[i], err = saveTaskVersion(tx, &tasks[i]); err != nil {
+				return err
+			}
+		}
+		if err := s.recordCompletions(tx, before, tasks); err != nil {
+			return err
+		}
+		if tags.Empty() {
+			return nil
+		}
+
+		if err := applyTagChange(tx, ids, tags); err != nil {
+			return err
+		}
+		tasks, err = findTasksByIDs(tx, accessibleTasks, ids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// DeleteTask memindahkan task ke trash. Hanya task milik user (atau
+// workspace-nya) yang bisa dihapus; task yang dibagikan tidak bisa dihapus
+// penerimanya.
+func (s *TaskServiceImpl) DeleteTask(ctx context.Context, id uint) error {
+	err := s.DeleteTasks(ctx, []uint{id})
+	if errors.Is(err, ErrTaskNotFound) {
+		return ErrTaskNotFound
+	}
+	return err
+}
+
+// DeleteTasks menghapus semua task dalam ids dalam satu transaksi. Seperti
+// DeleteTask, task yang dibagikan dianggap tidak ada.
+func (s *TaskServiceImpl) DeleteTasks(ctx context.Context, ids []uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tasks, err := findTasksByIDs(tx, ownedByWorkspace, ids)
+		if err != nil {
+			return err
+		}
+		snapshot := undoSnapshot{Tasks: make([]Task, len(tasks))}
+		for i, task := range tasks {
+			snapshot.Tasks[i] = Task{ID: task.ID}
+		}
+		if err := recordUndo(tx, s.UndoWindow, UndoDelete, snapshot); err != nil {
+			return err
+		}
+		if err := enqueueWebhooks(tx, WebhookTaskDeleted, tasks...); err != nil {
+			return err
+		}
+		if err := recordTaskStream(tx, WebhookTaskDeleted, tasks...); err != nil {
+			return err
+		}
+		return tx.Delete(&Task{}, ids).Error
+	})
+}
+
+// recordCompletions mencatat task yang baru saja diselesaikan (before belum
+// done, after done) sebagai satu aksi yang bisa dibatalkan.
+func (s *TaskServiceImpl) recordCompletions(tx *gorm.DB, before, after []Task) error {
+	var completed []Task
+	for i := range after {
+		if before[i].Status != StatusDone && after[i].Status == StatusDone {
+			completed = append(completed, Task{
+				ID:          before[i].ID,
+				Status:      before[i].Status,
+				CompletedAt: before[i].CompletedAt,
+			})
+		}
+	}
+	return recordUndo(tx, s.UndoWindow, UndoComplete, undoSnapshot{Tasks: completed})
+}
+
+// MoveTask memindahkan task ke sebelum (atau sesudah, jika after true) targetID
+// lalu menomori ulang position semua task milik user (atau workspace) agar urutannya tetap rapat.
+func (s *TaskServiceImpl) MoveTask(ctx context.Context, id, targetID uint, after bool) (*Task, error) {
+	var moved *Task
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tasks []Task
+		if err := preloadTaskRelations(tx).Scopes(ownedByWorkspace).Order("position, id").Find(&tasks).Error; err != nil {
+			return err
+		}
+
+		from := indexOfTask(tasks, id)
+		if from < 0 {
+			return ErrTaskNotFound
+		}
+		task := tasks[from]
+		tasks = append(tasks[:from], tasks[from+1:]...)
+
+		to := indexOfTask(tasks, targetID)
+		if to < 0 {
+			return &MissingTasksError{IDs: []uint{targetID}}
+		}
+		if after {
+			to++
+		}
+		tasks = append(tasks[:to], append([]Task{task}, tasks[to:]...)...)
+
+		for i := range tasks {
+			position := i + 1
+			if tasks[i].Position == position {
+				continue
+			}
+			tasks[i].Position = position
+			if err := tx.Model(&tasks[i]).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		moved = &tasks[indexOfTask(tasks, id)]
+		return loadDependencies(tx, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// DuplicateTask membuat salinan task beserta subtasks dan tag-nya di akhir daftar.
+// Salinan selalu belum selesai.
+func (s *TaskServiceImpl) DuplicateTask(ctx context.Context, id uint) (*Task, error) {
+	var clone Task
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var original Task
+		err := preloadTaskRelations(tx).Scopes(ownedByWorkspace).First(&original, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		position, err := nextPosition(tx, original.UserID, original.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		clone = Task{
+			UserID:      original.UserID,
+			WorkspaceID: original.WorkspaceID,
+			Title:       original.Title,
+			Description: original.Description,
+			ProjectID:   original.ProjectID,
+			AssigneeID:  original.AssigneeID,
+			Status:      StatusTodo,
+			Priority:    original.Priority,
+			Position:    position,
+			DueAt:       original.DueAt,
+			Recurrence:  original.Recurrence,
+			Tags:        original.Tags,
+		}
+		for _, subtask := range original.Subtasks {
+			clone.Subtasks = append(clone.Subtasks, Subtask{
+				Title:    subtask.Title,
+				Position: subtask.Position,
+			})
+		}
+		return tx.Create(&clone).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// saveTaskVersion menyimpan semua kolom task hanya jika version di database
+// masih sama dengan yang dimuat, lalu menaikkan version, mencatat kolom yang
+// berubah ke riwayat task, dan memberi tahu watcher jika statusnya berubah.
+func saveTaskVersion(tx *gorm.DB, task *Task) (Task, error) {
+	var before Task
+	if err := tx.First(&before, task.ID).Error; err != nil {
+		return before, err
+	}
+
+	loaded := task.Version
+	task.Version++
+	// reminded_due_at hanya diubah ReminderScheduler, jadi tidak ikut ditimpa.
+	result := tx.Model(task).
+		Select("*").
+		Omit(clause.Associations, "reminded_due_at").
+		Where("version = ?", loaded).
+		Updates(task)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrTaskVersionConflict
+	}
+	if result.Error != nil {
+		task.Version = loaded
+		return before, result.Error
+	}
+	if err := recordTaskChanges(tx, &before, task); err != nil {
+		return before, err
+	}
+	if err := notifyAssignee(tx, &before, task); err != nil {
+		return before, err
+	}
+	event := WebhookTaskUpdated
+	if before.Status != StatusDone && task.Status == StatusDone {
+		event = WebhookTaskCompleted
+	}
+	if err := enqueueWebhooks(tx, event, *task); err != nil {
+		return before, err
+	}
+	if err := recordTaskStream(tx, event, *task); err != nil {
+		return before, err
+	}
+	if before.Status == task.Status {
+		return before, nil
+	}
+	return before, notifyWatchers(tx, task.ID, NotificationStatusChange, nil)
+}
+
+func preloadTaskRelations(tx *gorm.DB) *gorm.DB {
+	return defaultTaskInclude.preload(tx)
+}
+
+func indexOfTask(tasks []Task, id uint) int {
+	for i, task := range tasks {
+		if task.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextPosition mengembalikan position untuk task baru, yaitu di akhir daftar
+// milik userID, atau di akhir daftar workspace jika workspaceID diisi.
+func nextPosition(tx *gorm.DB, userID, workspaceID *uint) (int, error) {
+	var max int
+	err := inSpace(tx.Model(&Task{}), userID, workspaceID).Select("COALESCE(MAX(position), 0)").Scan(&max).Error
+	return max + 1, err
+}
+
+// findTasksByIDs mengembalikan MissingTasksError jika ada id yang tidak ada di
+// database atau tidak termasuk scope (accessibleTasks atau ownedByWorkspace).
+func findTasksByIDs(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, ids []uint) ([]Task, error) {
+	var tasks []Task
+	if err := preloadTaskRelations(tx).Scopes(scope).Where("id IN ?", ids).Order("id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if len(tasks) == len(ids) {
+		return tasks, loadDependencies(tx, tasks)
+	}
+
+	found := make(map[uint]bool, len(tasks))
+	for _, task := range tasks {
+		found[task.ID] = true
+	}
+	missing := &MissingTasksError{}
+	for _, id := range ids {
+		if !found[id] {
+			missing.IDs = append(missing.IDs, id)
+		}
+	}
+	return nil, missing
+}
+
+// SeedTasks mengisi tabel tasks dengan todo awal jika tabel masih kosong.
+// Task ini belum punya pemilik sampai user pertama mendaftar.
+func (s *TaskServiceImpl) SeedTasks(ctx context.Context, titles ...string) error {
+	var count int64
+	if err := s.DB.WithContext(ctx).Model(&Task{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	tasks := make([]Task, 0, len(titles))
+	for i, title := range titles {
+		tasks = append(tasks, Task{Title: title, Status: StatusTodo, Priority: PriorityMedium, Position: i + 1})
+	}
+	return s.DB.WithContext(ctx).Create(&tasks).Error
+}
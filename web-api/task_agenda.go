@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// maxProjectedOccurrences membatasi kemunculan yang diproyeksikan per seri.
+const maxProjectedOccurrences = 500
+
+// AgendaDay berisi task yang jatuh tempo pada satu tanggal (di zona waktu request).
+type AgendaDay struct {
+	Date    string        `json:"date"`
+	Entries []AgendaEntry `json:"entries"`
+}
+
+// AgendaEntry adalah satu task pada agenda. Untuk kemunculan recurrence yang
+// belum dibuat, Projected bernilai true, Task adalah task terakhir di seri
+// tersebut dan DueAt adalah jadwal kemunculannya.
+type AgendaEntry struct {
+	DueAt     time.Time `json:"due_at"`
+	Projected bool      `json:"projected"`
+	Task      Task      `json:"task"`
+}
+
+// ListAgendaTasks mengembalikan task dengan due_at di [start, end), ditambah task
+// terakhir dari setiap seri recurrence yang mulai sebelum end supaya kemunculan
+// berikutnya bisa diproyeksikan.
+func (s *TaskServiceImpl) ListAgendaTasks(ctx context.Context, filter TaskFilter, start, end time.Time) ([]Task, error) {
+	db := s.DB.WithContext(ctx)
+
+	var tasks []Task
+	err := filter.apply(preloadTaskRelations(db).Scopes(accessibleTasks)).
+		Where("(due_at >= ? AND due_at < ?) OR (recurrence <> '' AND recurrence_scheduled = ? AND due_at < ?)",
+			start.UTC(), end.UTC(), false, end.UTC()).
+		Order("due_at, id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := loadDependencies(db, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// buildAgenda mengelompokkan task per hari dari start sampai sebelum end,
+// termasuk hari tanpa task. start harus tengah malam di zona waktu loc.
+func buildAgenda(tasks []Task, start, end time.Time, loc *time.Location) []AgendaDay {
+	var days []AgendaDay
+	index := make(map[string]int)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		index[date] = len(days)
+		days = append(days, AgendaDay{Date: date, Entries: []AgendaEntry{}})
+	}
+
+	add := func(due time.Time, task Task, projected bool) {
+		if due.Before(start) || !due.Before(end) {
+			return
+		}
+		i := index[due.In(loc).Format(time.DateOnly)]
+		days[i].Entries = append(days[i].Entries, AgendaEntry{DueAt: due, Projected: projected, Task: task})
+	}
+
+	for _, task := range tasks {
+		if task.DueAt == nil {
+			continue
+		}
+		add(*task.DueAt, task, false)
+		if task.Recurrence == "" || task.RecurrenceScheduled {
+			continue
+		}
+
+		rule, err := ParseRRule(task.Recurrence)
+		if err != nil {
+			continue
+		}
+		due, occurrence := *task.DueAt, task.RecurrenceIndex
+		for n := 0; n < maxProjectedOccurrences; n++ {
+			next, ok := rule.Next(due, occurrence)
+			if !ok || !next.Before(end) {
+				break
+			}
+			due, occurrence = next, occurrence+1
+			add(due, task, true)
+		}
+	}
+
+	// Task asli sudah urut berdasarkan due_at, tapi proyeksi ditambahkan belakangan.
+	for i := range days {
+		slices.SortStableFunc(days[i].Entries, func(a, b AgendaEntry) int {
+			return a.DueAt.Compare(b.DueAt)
+		})
+	}
+	return days
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAgendaDays = 7
+	maxAgendaDays     = 92
+)
+
+// ShowAgenda mengembalikan task per hari untuk ?from= sampai ?to= (tanggal
+// YYYY-MM-DD, inklusif) di zona waktu ?tz=. Kemunculan recurrence berikutnya
+// ikut ditampilkan sebagai entry projected. Filter GET /tasks tetap berlaku.
+func (h *TaskHandler) ShowAgenda(c *gin.Context) {
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, err := parseLocation(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	start, end, err := parseAgendaRange(c, time.Now(), loc)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks, err := h.Service.ListAgendaTasks(c.Request.Context(), filter, start, end)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from": start.Format(time.DateOnly),
+		"to":   end.AddDate(0, 0, -1).Format(time.DateOnly),
+		"tz":   loc.String(),
+		"days": buildAgenda(tasks, start, end, loc),
+	})
+}
+
+// parseAgendaRange mengembalikan [start, end) berupa tengah malam di loc.
+// Default-nya 7 hari mulai hari ini.
+func parseAgendaRange(c *gin.Context, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("from"); value != "" {
+		from, err := time.ParseInLocation(time.DateOnly, value, loc)
+		if err != nil {
+			return start, start, fmt.Errorf("invalid from %q: must be a YYYY-MM-DD date", value)
+		}
+		start = from
+	}
+
+	end := start.AddDate(0, 0, defaultAgendaDays)
+	if value := c.Query("to"); value != "" {
+		to, err := time.ParseInLocation(time.DateOnly, value, loc)
+		if err != nil {
+			return start, start, fmt.Errorf("invalid to %q: must be a YYYY-MM-DD date", value)
+		}
+		end = to.AddDate(0, 0, 1)
+	}
+
+	if !end.After(start) {
+		return start, end, fmt.Errorf("to must not be before from")
+	}
+	if end.After(start.AddDate(0, 0, maxAgendaDays)) {
+		return start, end, fmt.Errorf("agenda range must be at most %d days", maxAgendaDays)
+	}
+	return start, end, nil
+}
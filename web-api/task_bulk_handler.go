@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxBulkTasks = 100
+
+type bulkTaskResult struct {
+	Index int    `json:"index"`
+	Task  *Task  `json:"task,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type bulkUpdateRequest struct {
+	IDs        []uint           `json:"ids" binding:"required"`
+	Patch      patchTaskRequest `json:"patch"`
+	AddTags    []uint           `json:"add_tags"`
+	RemoveTags []uint           `json:"remove_tags"`
+}
+
+type bulkDeleteRequest struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
+
+// CreateTasksBulk membuat banyak task sekaligus. Semua item divalidasi dulu;
+// jika ada yang tidak valid, tidak ada task yang disimpan.
+func (h *TaskHandler) CreateTasksBulk(c *gin.Context) {
+	var reqs []taskRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&reqs); err != nil {
+		problem(c, http.StatusBadRequest, "request body must be a JSON array of tasks")
+		return
+	}
+	if len(reqs) == 0 {
+		problem(c, http.StatusBadRequest, "at least one task is required")
+		return
+	}
+	if len(reqs) > maxBulkTasks {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("at most %d tasks per request", maxBulkTasks))
+		return
+	}
+
+	results := make([]bulkTaskResult, len(reqs))
+	tasks := make([]Task, len(reqs))
+	invalid := false
+	for i := range reqs {
+		results[i].Index = i
+		if err := validateTaskRequest(&reqs[i]); err != nil {
+			results[i].Error = err.Error()
+			invalid = true
+			continue
+		}
+		tasks[i] = reqs[i].toTask()
+		if err := tasks[i].Validate(); err != nil {
+			results[i].Error = err.Error()
+			invalid = true
+		}
+	}
+	if invalid {
+		problem(c, http.StatusBadRequest, "one or more tasks are invalid", gin.H{
+			"results": results,
+		})
+		return
+	}
+
+	if err := h.Service.CreateTasks(c.Request.Context(), tasks); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+
+	for i := range tasks {
+		results[i].Task = &tasks[i]
+	}
+	for i := range tasks {
+		h.afterSave(&tasks[i])
+	}
+	c.JSON(http.StatusCreated, gin.H{"results": results})
+}
+
+// BulkUpdateTasks menerapkan patch yang sama ke semua task dalam ids dan
+// memasang/melepas tag dari add_tags dan remove_tags.
+// Jika ada id yang tidak ditemukan, tidak ada perubahan yang disimpan.
+func (h *TaskHandler) BulkUpdateTasks(c *gin.Context) {
+	var req bulkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	ids, ok := validateBulkIDs(c, req.IDs)
+	if !ok {
+		return
+	}
+	if err := req.Patch.validate(); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Patch.Status != nil && req.Patch.status == StatusDone && !h.allowCompletion(c, ids...) {
+		return
+	}
+
+	tasks, err := h.Service.UpdateTasks(c.Request.Context(), ids, func(task *Task) error {
+		if err := req.Patch.apply(task); err != nil {
+			return err
+		}
+		if err := task.Validate(); err != nil {
+			return &TaskValidationError{TaskID: task.ID, Err: err}
+		}
+		return nil
+	}, TagChange{Add: req.AddTags, Remove: req.RemoveTags})
+	if err != nil {
+		bulkError(c, err)
+		return
+	}
+	for i := range tasks {
+		h.afterSave(&tasks[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
+// BulkDeleteTasks menghapus semua task dalam ids secara all-or-nothing.
+func (h *TaskHandler) BulkDeleteTasks(c *gin.Context) {
+	var req bulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	ids, ok := validateBulkIDs(c, req.IDs)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteTasks(c.Request.Context(), ids); err != nil {
+		bulkError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validateBulkIDs membuang id duplikat dan membatasi jumlah id per request.
+func validateBulkIDs(c *gin.Context, ids []uint) ([]uint, bool) {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) == 0 {
+		problem(c, http.StatusBadRequest, "at least one id is required")
+		return nil, false
+	}
+	if len(unique) > maxBulkTasks {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("at most %d ids per request", maxBulkTasks))
+		return nil, false
+	}
+	return unique, true
+}
+
+func bulkError(c *gin.Context, err error) {
+	var missing *MissingTasksError
+	if errors.As(err, &missing) {
+		problem(c, http.StatusNotFound, "task not found", gin.H{
+			"ids": missing.IDs,
+		})
+		return
+	}
+	var missingTags *MissingTagsError
+	if errors.As(err, &missingTags) {
+		problem(c, http.StatusNotFound, "tag not found", gin.H{
+			"ids": missingTags.IDs,
+		})
+		return
+	}
+	var transition *InvalidTransitionError
+	if errors.As(err, &transition) {
+		problem(c, http.StatusConflict, err.Error(), gin.H{
+			"id": transition.TaskID,
+		})
+		return
+	}
+	var invalid *TaskValidationError
+	if errors.As(err, &invalid) {
+		problem(c, http.StatusBadRequest, invalid.Err.Error(), gin.H{
+			"id": invalid.TaskID,
+		})
+		return
+	}
+	saveTaskError(c, err)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+)
+
+// TaskDependency menyatakan bahwa TaskID terblokir oleh BlockedByID.
+type TaskDependency struct {
+	TaskID      uint `gorm:"primaryKey"`
+	BlockedByID uint `gorm:"primaryKey;index"`
+	CreatedAt   time.Time
+	Task        Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	BlockedBy   Task `gorm:"foreignKey:BlockedByID;constraint:OnDelete:CASCADE"`
+}
+
+// TaskRef adalah ringkasan task yang dipakai di graf dependency.
+type TaskRef struct {
+	ID     uint       `json:"id"`
+	Title  string     `json:"title"`
+	Status TaskStatus `json:"status"`
+}
+
+// BlockedTaskError dikembalikan saat task belum boleh diselesaikan karena blocker masih terbuka.
+type BlockedTaskError struct {
+	TaskID   uint
+	Blockers []TaskRef
+}
+
+func (e *BlockedTaskError) Error() string {
+	return fmt.Sprintf("task %d is blocked by %d open task(s)", e.TaskID, len(e.Blockers))
+}
+
+// AddDependency menandai taskID terblokir oleh blockerID dan menolak jika membentuk siklus.
+func (s *TaskServiceImpl) AddDependency(ctx context.Context, taskID, blockerID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureTaskExists(tx, taskID); err != nil {
+			return err
+		}
+		err := ensureTaskExists(tx, blockerID)
+		if errors.Is(err, ErrTaskNotFound) {
+			return &MissingTasksError{IDs: []uint{blockerID}}
+		}
+		if err != nil {
+			return err
+		}
+
+		cycle, err := dependsOn(tx, blockerID, taskID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		dependency := TaskDependency{TaskID: taskID, BlockedByID: blockerID}
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&dependency).Error
+	})
+}
+
+func (s *TaskServiceImpl) RemoveDependency(ctx context.Context, taskID, blockerID uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return err
+	}
+
+	result := db.
+		Where("task_id = ? AND blocked_by_id = ?", taskID, blockerID).
+		Delete(&TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+// OpenBlockers mengembalikan blocker yang belum selesai untuk setiap task dalam ids.
+// Blocker yang dibatalkan dianggap tertutup seperti yang selesai.
+// Blocker yang juga ada di ids diabaikan karena akan diselesaikan bersamaan.
+func (s *TaskServiceImpl) OpenBlockers(ctx context.Context, ids []uint) (map[uint][]TaskRef, error) {
+	var rows []struct {
+		TaskID uint
+		TaskRef
+	}
+	db := s.DB.WithContext(ctx)
+	visible := db.Model(&Task{}).Scopes(accessibleTasks).Select("id")
+	err := db.
+		Table("task_dependencies AS d").
+		Select("d.task_id, t.id, t.title, t.status").
+		Joins("JOIN tasks t ON t.id = d.blocked_by_id").
+		Where("d.task_id IN ? AND t.status NOT IN ? AND t.id NOT IN ? AND t.id IN (?)", ids, []TaskStatus{StatusDone, StatusCancelled}, ids, visible).
+		Order("t.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := make(map[uint][]TaskRef)
+	for _, row := range rows {
+		blockers[row.TaskID] = append(blockers[row.TaskID], row.TaskRef)
+	}
+	return blockers, nil
+}
+
+// dependsOn melakukan BFS di sepanjang relasi blocked_by untuk mengecek apakah
+// from (langsung atau tidak langsung) terblokir oleh target.
+func dependsOn(tx *gorm.DB, from, target uint) (bool, error) {
+	visited := map[uint]bool{from: true}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		if visited[target] {
+			return true, nil
+		}
+
+		var next []uint
+		err := tx.Model(&TaskDependency{}).
+			Where("task_id IN ?", queue).
+			Pluck("blocked_by_id", &next).Error
+		if err != nil {
+			return false, err
+		}
+
+		queue = queue[:0]
+		for _, id := range next {
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+	return visited[target], nil
+}
+
+// loadDependencies mengisi BlockedBy dan Blocks untuk setiap task. Task di
+// trash tidak ikut ditampilkan.
+func loadDependencies(tx *gorm.DB, tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tasks))
+	byID := make(map[uint]*Task, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+		byID[tasks[i].ID] = &tasks[i]
+		tasks[i].BlockedBy = []TaskRef{}
+		tasks[i].Blocks = []TaskRef{}
+	}
+
+	var blockedBy []struct {
+		OwnerID uint
+		TaskRef
+	}
+	err := tx.Table("task_dependencies AS d").
+		Select("d.task_id AS owner_id, t.id, t.title, t.status").
+		Joins("JOIN tasks t ON t.id = d.blocked_by_id AND t.deleted_at IS NULL").
+		Where("d.task_id IN ?", ids).
+		Order("t.id").
+		Scan(&blockedBy).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range blockedBy {
+		byID[row.OwnerID].BlockedBy = append(byID[row.OwnerID].BlockedBy, row.TaskRef)
+	}
+
+	var blocks []struct {
+		OwnerID uint
+		TaskRef
+	}
+	err = tx.Table("task_dependencies AS d").
+		Select("d.blocked_by_id AS owner_id, t.id, t.title, t.status").
+		Joins("JOIN tasks t ON t.id = d.task_id AND t.deleted_at IS NULL").
+		Where("d.blocked_by_id IN ?", ids).
+		Order("t.id").
+		Scan(&blocks).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range blocks {
+		byID[row.OwnerID].Blocks = append(byID[row.OwnerID].Blocks, row.TaskRef)
+	}
+	return nil
+}
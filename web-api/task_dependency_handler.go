@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type dependencyRequest struct {
+	BlockedBy uint `json:"blocked_by" binding:"required"`
+}
+
+// AddDependency menandai task :id terblokir oleh task lain.
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	var req dependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if req.BlockedBy == id {
+		problem(c, http.StatusBadRequest, "a task cannot block itself")
+		return
+	}
+
+	err := h.Service.AddDependency(c.Request.Context(), id, req.BlockedBy)
+	var missing *MissingTasksError
+	switch {
+	case errors.As(err, &missing):
+		problem(c, http.StatusBadRequest, "blocking task not found", gin.H{
+			"id": req.BlockedBy,
+		})
+		return
+	case errors.Is(err, ErrTaskNotFound):
+		taskNotFound(c, id)
+		return
+	case errors.Is(err, ErrDependencyCycle):
+		problem(c, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	h.GetTask(c)
+}
+
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blockerID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid blocking task id")
+		return
+	}
+
+	err = h.Service.RemoveDependency(c.Request.Context(), id, uint(blockerID))
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if errors.Is(err, ErrDependencyNotFound) {
+		problem(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// allowCompletion mengecek blocker sebelum task diselesaikan. Query ?force=true
+// melewati pengecekan ini.
+func (h *TaskHandler) allowCompletion(c *gin.Context, ids ...uint) bool {
+	if force, _ := strconv.ParseBool(c.Query("force")); force {
+		return true
+	}
+
+	blockers, err := h.Service.OpenBlockers(c.Request.Context(), ids)
+	if err != nil {
+		internalError(c, err)
+		return false
+	}
+	if len(blockers) == 0 {
+		return true
+	}
+
+	blocked := make([]gin.H, 0, len(blockers))
+	for _, id := range ids {
+		if refs, ok := blockers[id]; ok {
+			blocked = append(blocked, gin.H{"id": id, "blocked_by": refs})
+		}
+	}
+	problem(c, http.StatusConflict, "task is blocked by open tasks; pass ?force=true to complete anyway", gin.H{
+		"tasks": blocked,
+	})
+	return false
+}
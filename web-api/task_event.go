@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TaskEvent mencatat satu perubahan kolom task: siapa (UserID) mengubah Field
+// dari OldValue menjadi NewValue. Nilai NULL berarti kolomnya kosong. UserID
+// menjadi NULL jika akun pengubahnya sudah dihapus; ActorName dan ActorEmail
+// diisi dari tabel users saat riwayat dimuat.
+type TaskEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TaskID     uint      `json:"task_id" gorm:"not null;index"`
+	Task       *Task     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UserID     *uint     `json:"actor_id"`
+	User       *User     `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	ActorName  *string   `json:"actor_name" gorm:"->;-:migration"`
+	ActorEmail *string   `json:"actor_email" gorm:"->;-:migration"`
+	Field      string    `json:"field" gorm:"type:varchar(32);not null"`
+	OldValue   *string   `json:"old_value" gorm:"type:text"`
+	NewValue   *string   `json:"new_value" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TaskEventPage adalah satu halaman hasil ListTaskHistory.
+type TaskEventPage struct {
+	Events []TaskEvent
+	Total  int64
+}
+
+// Interface untuk layanan riwayat task
+type TaskEventService interface {
+	ListTaskHistory(ctx context.Context, taskID uint, page Page) (*TaskEventPage, error)
+}
+
+// Struct implementasi TaskEventService dengan GORM
+type TaskEventServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListTaskHistory mengembalikan perubahan task dari yang terbaru.
+func (s *TaskEventServiceImpl) ListTaskHistory(ctx context.Context, taskID uint, page Page) (*TaskEventPage, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureTaskExists(db, taskID); err != nil {
+		return nil, err
+	}
+
+	result := &TaskEventPage{}
+	if err := db.Model(&TaskEvent{}).Where("task_id = ?", taskID).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err := db.Model(&TaskEvent{}).
+		Select("task_events.*, u.name AS actor_name, u.email AS actor_email").
+		Joins("LEFT JOIN users u ON u.id = task_events.user_id").
+		Where("task_events.task_id = ?", taskID).
+		Order("task_events.created_at DESC, task_events.id DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&result.Events).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// trackedTaskFields adalah kolom yang perubahannya dicatat, beserta cara
+// mengubah nilainya menjadi teks. nil berarti kolomnya kosong.
+var trackedTaskFields = []struct {
+	name  string
+	value func(task *Task) *string
+}{
+	{"title", func(t *Task) *string { return stringValue(t.Title) }},
+	{"description", func(t *Task) *string { return stringValue(t.Description) }},
+	{"status", func(t *Task) *string { return stringValue(string(t.Status)) }},
+	{"priority", func(t *Task) *string { return stringValue(string(t.Priority)) }},
+	{"due_at", func(t *Task) *string { return timeValue(t.DueAt) }},
+	{"project_id", func(t *Task) *string { return idValue(t.ProjectID) }},
+	{"assignee_id", func(t *Task) *string { return idValue(t.AssigneeID) }},
+	{"recurrence", func(t *Task) *string { return stringValue(t.Recurrence) }},
+	{"archived_at", func(t *Task) *string { return timeValue(t.ArchivedAt) }},
+}
+
+// recordTaskChanges menyimpan TaskEvent untuk setiap kolom yang berbeda antara
+// before dan after, atas nama user di context tx.
+func recordTaskChanges(tx *gorm.DB, before, after *Task) error {
+	var actor *uint
+	if id := ownerID(tx.Statement.Context); id != 0 {
+		actor = &id
+	}
+
+	var events []TaskEvent
+	for _, field := range trackedTaskFields {
+		old, updated := field.value(before), field.value(after)
+		if equalValue(old, updated) {
+			continue
+		}
+		events = append(events, TaskEvent{
+			TaskID:   after.ID,
+			UserID:   actor,
+			Field:    field.name,
+			OldValue: old,
+			NewValue: updated,
+		})
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+func stringValue(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func timeValue(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+func idValue(id *uint) *string {
+	if id == nil {
+		return nil
+	}
+	s := strconv.FormatUint(uint64(*id), 10)
+	return &s
+}
+
+func equalValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskEventHandler berisi HTTP handler untuk /tasks/:id/history.
+type TaskEventHandler struct {
+	Service TaskEventService
+}
+
+// ListTaskHistory mengembalikan siapa mengubah kolom apa dan kapan, dari yang
+// terbaru, jadi hanya mendukung paging limit/offset.
+func (h *TaskEventHandler) ListTaskHistory(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "task history only supports limit/offset pagination")
+		return
+	}
+
+	result, err := h.Service.ListTaskHistory(c.Request.Context(), taskID, page)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": result.Events,
+		"meta":   page.Meta(result.Total, nil),
+	})
+}
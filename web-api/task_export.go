@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportBatch adalah jumlah task yang dimuat per query saat export.
+const exportBatch = 500
+
+// taskCSVColumns adalah kolom CSV export, dalam urutan ini. Kolom baru hanya
+// ditambahkan di akhir supaya spreadsheet yang sudah ada tidak rusak.
+var taskCSVColumns = []string{
+	"id", "title", "description", "status", "priority", "project_id", "project",
+	"assignee_id", "tags", "due_at", "completed_at", "created_at", "updated_at",
+	"recurrence", "subtasks_done", "subtasks_total",
+}
+
+// writeTaskCSVRow menulis satu task sesuai taskCSVColumns. Waktu ditulis dalam
+// RFC 3339 di location, dan kolom yang kosong ditulis sebagai string kosong.
+func writeTaskCSVRow(w *csv.Writer, task *Task, location *time.Location) error {
+	optionalID := func(id *uint) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*id), 10)
+	}
+	optionalTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(location).Format(time.RFC3339)
+	}
+	project := ""
+	if task.Project != nil {
+		project = task.Project.Name
+	}
+	tags := make([]string, len(task.Tags))
+	for i, tag := range task.Tags {
+		tags[i] = tag.Name
+	}
+	done := 0
+	for _, subtask := range task.Subtasks {
+		if subtask.Done {
+			done++
+		}
+	}
+
+	return w.Write([]string{
+		strconv.FormatUint(uint64(task.ID), 10),
+		csvSafe(task.Title),
+		csvSafe(task.Description),
+		string(task.Status),
+		string(task.Priority),
+		optionalID(task.ProjectID),
+		csvSafe(project),
+		optionalID(task.AssigneeID),
+		csvSafe(strings.Join(tags, ", ")),
+		optionalTime(task.DueAt),
+		optionalTime(task.CompletedAt),
+		task.CreatedAt.In(location).Format(time.RFC3339),
+		task.UpdatedAt.In(location).Format(time.RFC3339),
+		task.Recurrence,
+		strconv.Itoa(done),
+		strconv.Itoa(len(task.Subtasks)),
+	})
+}
+
+// csvSafe mencegah formula injection: nilai teks yang diawali karakter yang
+// dibaca spreadsheet sebagai formula diberi awalan apostrof. Quoting dan
+// escaping CSV sendiri diurus encoding/csv.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportTasksCSV mengunduh semua task yang cocok dengan filter GET /tasks
+// (termasuk sort) sebagai CSV dengan kolom taskCSVColumns, tanpa paging. Waktu
+// ditulis di zona ?tz= atau zona preferensi user.
+func (h *TaskHandler) ExportTasksCSV(c *gin.Context) {
+	var body bytes.Buffer
+	w := csv.NewWriter(&body)
+	if err := w.Write(taskCSVColumns); err != nil {
+		internalError(c, err)
+		return
+	}
+	ok := h.exportTasks(c, func(task *Task, loc *time.Location) error {
+		return writeTaskCSVRow(w, task, loc)
+	})
+	if !ok {
+		return
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="tasks.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
+
+// ExportTasksTodoTxt seperti ExportTasksCSV, tetapi dalam format todo.txt
+// (lihat writeTodoTxtLine) sehingga bisa diimpor kembali lewat /import/todotxt.
+func (h *TaskHandler) ExportTasksTodoTxt(c *gin.Context) {
+	var body bytes.Buffer
+	ok := h.exportTasks(c, func(task *Task, loc *time.Location) error {
+		return writeTodoTxtLine(&body, task, loc)
+	})
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="todo.txt"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", body.Bytes())
+}
+
+// exportTasks membaca filter dan ?tz= lalu memanggil write untuk setiap task
+// yang cocok. Task dimuat per exportBatch supaya preload relasi tidak menjadi
+// satu query yang sangat besar. Jika gagal, respons error sudah ditulis.
+func (h *TaskHandler) exportTasks(c *gin.Context, write func(task *Task, loc *time.Location) error) bool {
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	loc, err := parseLocation(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	page := Page{Limit: exportBatch}
+	include := TaskInclude{Subtasks: true, Tags: true, Project: true}
+	for {
+		result, err := h.Service.ListTasks(c.Request.Context(), filter, page, include)
+		if err != nil {
+			internalError(c, err)
+			return false
+		}
+		for i := range result.Tasks {
+			if err := write(&result.Tasks[i], loc); err != nil {
+				internalError(c, err)
+				return false
+			}
+		}
+		if len(result.Tasks) < page.Limit {
+			return true
+		}
+		page.Offset += page.Limit
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Beli susu", "Beli susu"},
+		{"=HYPERLINK(\"https://evil.example\")", "'=HYPERLINK(\"https://evil.example\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+		{" =1", " =1"},
+		{"'quoted", "'quoted"},
+	}
+	for _, tt := range tests {
+		if got := csvSafe(tt.value); got != tt.want {
+			t.Errorf("csvSafe(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTaskCSVRow(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := time.Date(2025, 1, 10, 16, 59, 0, 0, time.UTC)
+	projectID, assigneeID := uint(3), uint(7)
+	task := &Task{
+		ID:          12,
+		Title:       "=cmd|' /C calc'!A0",
+		Description: "line one\nline \"two\", with comma",
+		Status:      StatusInProgress,
+		Priority:    PriorityHigh,
+		ProjectID:   &projectID,
+		Project:     &Project{Name: "@Home"},
+		AssigneeID:  &assigneeID,
+		Tags:        []Tag{{Name: "-urgent"}, {Name: "work"}},
+		DueAt:       &due,
+		Recurrence:  "FREQ=WEEKLY",
+		Subtasks:    []Subtask{{Done: true}, {Done: false}, {Done: true}},
+	}
+	task.CreatedAt, task.UpdatedAt = created, created
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if err := w.Write(taskCSVColumns); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTaskCSVRow(w, task, jakarta); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v\n%s", err, out.String())
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	want := map[string]string{
+		"id":             "12",
+		"title":          "'=cmd|' /C calc'!A0",
+		"description":    "line one\nline \"two\", with comma",
+		"status":         string(StatusInProgress),
+		"priority":       string(PriorityHigh),
+		"project_id":     "3",
+		"project":        "'@Home",
+		"assignee_id":    "7",
+		"tags":           "'-urgent, work",
+		"due_at":         "2025-01-10T23:59:00+07:00",
+		"completed_at":   "",
+		"created_at":     "2025-01-02T10:04:05+07:00",
+		"updated_at":     "2025-01-02T10:04:05+07:00",
+		"recurrence":     "FREQ=WEEKLY",
+		"subtasks_done":  "2",
+		"subtasks_total": "3",
+	}
+	if len(row) != len(want) {
+		t.Errorf("got %d columns, want %d", len(row), len(want))
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("%s = %q, want %q", column, row[column], value)
+		}
+	}
+}
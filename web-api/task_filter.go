@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TaskFilter berisi filter opsional untuk daftar task. Field kosong berarti tidak difilter.
+type TaskFilter struct {
+	Overdue    bool
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Statuses   []TaskStatus
+	Priorities []Priority
+	Tags       []string
+	ProjectID  *uint
+	// Assignee adalah nilai ?assignee=: "me", "none", atau id user. "me"
+	// baru diterjemahkan saat query dijalankan supaya saved filter tetap
+	// berarti "saya" bagi siapa pun yang memakainya.
+	Assignee string
+	// Archived memilih task arsip (diarsipkan sendiri atau project-nya
+	// diarsipkan) sebagai ganti task biasa.
+	Archived bool
+	// CustomFields berisi filter ?field.<id>=nilai; semua harus cocok.
+	CustomFields []CustomFieldFilter
+	// Sort diterapkan berurutan sebelum urutan manual (position, id).
+	Sort []SortField
+}
+
+// CustomFieldFilter cocok dengan task yang nilai custom field FieldID-nya
+// salah satu dari Values (dalam bentuk kanonik, lihat CustomField.Normalize).
+type CustomFieldFilter struct {
+	FieldID uint
+	Values  []string
+}
+
+// SortField adalah satu kolom dari ?sort=; awalan "-" berarti descending.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// taskSortColumns adalah whitelist kolom yang boleh dipakai di ?sort=.
+// priority dan status diurutkan sesuai tingkatannya, bukan abjad.
+var taskSortColumns = map[string]string{
+	"title":        "title",
+	"status":       statusRankSQL(),
+	"priority":     priorityRankSQL(),
+	"position":     "position",
+	"due_at":       "due_at",
+	"completed_at": "completed_at",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+// apply menerjemahkan filter menjadi klausa WHERE yang terparameterisasi.
+func (f TaskFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.Overdue {
+		db = db.Where("due_at < ? AND status NOT IN ?", time.Now(), []TaskStatus{StatusDone, StatusCancelled})
+	}
+	if f.DueBefore != nil {
+		db = db.Where("due_at < ?", *f.DueBefore)
+	}
+	if f.DueAfter != nil {
+		db = db.Where("due_at >= ?", *f.DueAfter)
+	}
+	if len(f.Statuses) > 0 {
+		db = db.Where("status IN ?", f.Statuses)
+	}
+	if len(f.Priorities) > 0 {
+		db = db.Where("priority IN ?", f.Priorities)
+	}
+	if f.ProjectID != nil {
+		db = db.Where("project_id = ?", *f.ProjectID)
+	}
+	switch f.Assignee {
+	case "":
+	case assigneeNone:
+		db = db.Where("assignee_id IS NULL")
+	case assigneeMe:
+		db = db.Where("assignee_id = ?", ownerID(db.Statement.Context))
+	default:
+		db = db.Where("assignee_id = ?", f.Assignee)
+	}
+	archivedProjects := db.Session(&gorm.Session{NewDB: true}).
+		Model(&Project{}).
+		Select("id").
+		Where("archived_at IS NOT NULL")
+	if f.Archived {
+		db = db.Where(db.Session(&gorm.Session{NewDB: true}).
+			Where("archived_at IS NOT NULL").
+			Or("project_id IN (?)", archivedProjects))
+	} else {
+		db = db.Where("archived_at IS NULL AND (project_id IS NULL OR project_id NOT IN (?))", archivedProjects)
+	}
+	if len(f.Tags) > 0 {
+		db = db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Table("task_tags").
+			Select("task_tags.task_id").
+			Joins("JOIN tags ON tags.id = task_tags.tag_id").
+			Where("tags.name IN ?", f.Tags))
+	}
+	for _, field := range f.CustomFields {
+		db = db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&CustomFieldValue{}).
+			Select("task_id").
+			Where("field_id = ? AND value IN ?", field.FieldID, field.Values))
+	}
+	return db
+}
+
+func (f TaskFilter) order(db *gorm.DB) *gorm.DB {
+	for _, field := range f.Sort {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		// Task tanpa due_at/completed_at selalu di akhir, ke mana pun arahnya.
+		db = db.Order(taskSortColumns[field.Field] + " " + direction + " NULLS LAST")
+	}
+	return db.Order("position, id")
+}
+
+// parseTaskFilter membaca query string seperti ?overdue=true&due_after=2025-01-01T00:00:00Z.
+// Semua filter bisa digabung; nilai dipisah koma berarti salah satu dari nilai tersebut.
+func parseTaskFilter(c *gin.Context) (TaskFilter, error) {
+	return parseTaskFilterValues(c.Request.URL.Query())
+}
+
+// taskFilterKeys adalah parameter query yang dibaca parseTaskFilterValues,
+// selain parameter berawalan customFieldFilterPrefix.
+var taskFilterKeys = []string{"overdue", "due_before", "due_after", "status", "priority", "project_id", "assignee", "archived", "tag", "sort"}
+
+const (
+	assigneeMe   = "me"
+	assigneeNone = "none"
+)
+
+const customFieldFilterPrefix = "field."
+
+func isTaskFilterKey(key string) bool {
+	return slices.Contains(taskFilterKeys, key) || strings.HasPrefix(key, customFieldFilterPrefix)
+}
+
+// parseTaskFilterValues dipakai untuk query string request maupun filter yang disimpan.
+func parseTaskFilterValues(values url.Values) (TaskFilter, error) {
+	var filter TaskFilter
+
+	if value := values.Get("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid overdue %q", value)
+		}
+		filter.Overdue = overdue
+	}
+
+	if value := values.Get("archived"); value != "" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid archived %q", value)
+		}
+		filter.Archived = archived
+	}
+
+	var err error
+	if filter.DueBefore, err = parseTimeValue(values, "due_before"); err != nil {
+		return filter, err
+	}
+	if filter.DueAfter, err = parseTimeValue(values, "due_after"); err != nil {
+		return filter, err
+	}
+
+	if value := values.Get("status"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			status, err := ParseTaskStatus(part)
+			if err != nil {
+				return filter, err
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if value := values.Get("priority"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			priority, err := ParsePriority(part)
+			if err != nil {
+				return filter, err
+			}
+			filter.Priorities = append(filter.Priorities, priority)
+		}
+	}
+
+	if value := values.Get("tag"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			if name = normalizeTagName(name); name != "" {
+				filter.Tags = append(filter.Tags, name)
+			}
+		}
+	}
+
+	if value := values.Get("project_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || id == 0 {
+			return filter, fmt.Errorf("invalid project_id %q", value)
+		}
+		projectID := uint(id)
+		filter.ProjectID = &projectID
+	}
+
+	if value := strings.ToLower(strings.TrimSpace(values.Get("assignee"))); value != "" {
+		if value != assigneeMe && value != assigneeNone {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || id == 0 {
+				return filter, fmt.Errorf("invalid assignee %q: must be me, none, or a user id", value)
+			}
+			value = strconv.FormatUint(id, 10)
+		}
+		filter.Assignee = value
+	}
+
+	if filter.CustomFields, err = parseCustomFieldFilters(values); err != nil {
+		return filter, err
+	}
+
+	if value := values.Get("sort"); value != "" {
+		if filter.Sort, err = parseSort(value); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// parseCustomFieldFilters membaca ?field.<id>=nilai. Beberapa nilai dipisah
+// koma berarti salah satunya; field yang diulang digabung.
+func parseCustomFieldFilters(values url.Values) ([]CustomFieldFilter, error) {
+	var keys []string
+	for key := range values {
+		if strings.HasPrefix(key, customFieldFilterPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	// Diurutkan supaya query yang dihasilkan stabil.
+	slices.Sort(keys)
+
+	var filters []CustomFieldFilter
+	for _, key := range keys {
+		id, err := strconv.ParseUint(strings.TrimPrefix(key, customFieldFilterPrefix), 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid custom field filter %q: must be field.<id>", key)
+		}
+		filter := CustomFieldFilter{FieldID: uint(id)}
+		for _, value := range values[key] {
+			for _, part := range strings.Split(value, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					filter.Values = append(filter.Values, part)
+				}
+			}
+		}
+		if len(filter.Values) == 0 {
+			return nil, fmt.Errorf("invalid custom field filter %q: value must not be empty", key)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// parseSort membaca daftar seperti "due_at,-priority". Kolom di luar
+// taskSortColumns dan kolom yang diulang ditolak.
+func parseSort(value string) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := taskSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("invalid sort %q: unknown field %q", value, field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("invalid sort %q: field %q is repeated", value, field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func parseTimeValue(values url.Values, key string) (*time.Time, error) {
+	value := values.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be an RFC3339 timestamp", key, value)
+	}
+	return &t, nil
+}
@@ -0,0 +1,620 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type taskRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	ProjectID   *uint      `json:"project_id"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	Recurrence  string     `json:"recurrence"`
+
+	status   TaskStatus
+	priority Priority
+}
+
+// patchTaskRequest memakai pointer supaya field yang tidak dikirim bisa dibedakan dari zero value.
+type patchTaskRequest struct {
+	Title       *string             `json:"title"`
+	Description *string             `json:"description"`
+	ProjectID   optional[uint]      `json:"project_id"`
+	AssigneeID  optional[uint]      `json:"assignee_id"`
+	Status      *string             `json:"status"`
+	Priority    *string             `json:"priority"`
+	DueAt       optional[time.Time] `json:"due_at"`
+	Recurrence  *string             `json:"recurrence"`
+
+	status   TaskStatus
+	priority Priority
+}
+
+// optional membedakan field yang tidak dikirim, dikirim null, dan dikirim berisi nilai.
+type optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
+
+// validate merapikan field yang dikirim dan mengembalikan error jika tidak valid.
+func (req *patchTaskRequest) validate() error {
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return errors.New("title must not be empty")
+		}
+		req.Title = &title
+	}
+	if req.Status != nil {
+		status, err := ParseTaskStatus(*req.Status)
+		if err != nil {
+			return err
+		}
+		req.status = status
+	}
+	if req.Priority != nil {
+		priority, err := ParsePriority(*req.Priority)
+		if err != nil {
+			return err
+		}
+		req.priority = priority
+	}
+	if req.Recurrence != nil {
+		recurrence := normalizeRecurrence(*req.Recurrence)
+		req.Recurrence = &recurrence
+	}
+	return nil
+}
+
+// apply menggabungkan field yang dikirim ke task. Panggil validate terlebih dahulu.
+func (req *patchTaskRequest) apply(task *Task) error {
+	if req.Title != nil {
+		task.Title = *req.Title
+	}
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+	if req.ProjectID.Set {
+		task.ProjectID = req.ProjectID.Value
+	}
+	if req.AssigneeID.Set {
+		task.AssigneeID = req.AssigneeID.Value
+	}
+	if req.Status != nil {
+		if err := task.SetStatus(req.status); err != nil {
+			return err
+		}
+	}
+	if req.Priority != nil {
+		task.Priority = req.priority
+	}
+	if req.DueAt.Set {
+		task.DueAt = req.DueAt.Value
+	}
+	if req.Recurrence != nil {
+		task.Recurrence = *req.Recurrence
+	}
+	return nil
+}
+
+// completes bernilai true jika patch ini memindahkan task ke status done.
+func (req *patchTaskRequest) completes(task *Task) bool {
+	return req.Status != nil && req.status == StatusDone && task.Status != StatusDone
+}
+
+// moveTaskRequest berisi tepat satu dari before atau after.
+type moveTaskRequest struct {
+	Before *uint `json:"before"`
+	After  *uint `json:"after"`
+}
+
+// TaskHandler berisi HTTP handler untuk resource task.
+type TaskHandler struct {
+	Service   TaskService
+	Scheduler *RecurrenceScheduler
+}
+
+func (h *TaskHandler) ShowTasks(c *gin.Context) {
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	view, err := parseTaskView(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if page.CursorMode && len(filter.Sort) > 0 {
+		problem(c, http.StatusBadRequest, "sort is not supported with cursor pagination")
+		return
+	}
+
+	result, err := h.Service.ListTasks(c.Request.Context(), filter, page, view.include)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	tasks, err := view.tasks(result.Tasks)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task": tasks,
+		"meta": page.Meta(result.Total, result.Next),
+	})
+}
+
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	req, ok := bindTaskRequest(c)
+	if !ok {
+		return
+	}
+
+	task := req.toTask()
+	if err := task.Validate(); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Service.CreateTask(c.Request.Context(), &task); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+	h.afterSave(&task)
+
+	c.Header("ETag", taskETag(&task))
+	c.JSON(http.StatusCreated, task)
+}
+
+func (h *TaskHandler) GetTask(c *gin.Context) {
+	view, err := parseTaskView(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := h.loadTaskIncluding(c, view.include)
+	if !ok {
+		return
+	}
+	selected, err := view.task(task)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, selected)
+}
+
+func (h *TaskHandler) ReplaceTask(c *gin.Context) {
+	task, ok := h.loadTask(c)
+	if !ok || !checkIfMatch(c, task) {
+		return
+	}
+
+	req, ok := bindTaskRequest(c)
+	if !ok {
+		return
+	}
+
+	if req.status == StatusDone && task.Status != StatusDone && !h.allowCompletion(c, task.ID) {
+		return
+	}
+	if err := task.SetStatus(req.status); err != nil {
+		invalidTransition(c, err)
+		return
+	}
+
+	task.Title = req.Title
+	task.Description = req.Description
+	task.ProjectID = req.ProjectID
+	task.AssigneeID = req.AssigneeID
+	task.Priority = req.priority
+	task.DueAt = req.DueAt
+	task.Recurrence = req.Recurrence
+	if err := task.Validate(); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Service.UpdateTask(c.Request.Context(), task); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+	h.afterSave(task)
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	task, ok := h.loadTask(c)
+	if !ok || !checkIfMatch(c, task) {
+		return
+	}
+
+	var req patchTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if err := req.validate(); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.completes(task) && !h.allowCompletion(c, task.ID) {
+		return
+	}
+
+	if err := req.apply(task); err != nil {
+		invalidTransition(c, err)
+		return
+	}
+	if err := task.Validate(); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Service.UpdateTask(c.Request.Context(), task); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+	h.afterSave(task)
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) CompleteTask(c *gin.Context) {
+	h.setTaskStatus(c, StatusDone)
+}
+
+func (h *TaskHandler) ReopenTask(c *gin.Context) {
+	h.setTaskStatus(c, StatusTodo)
+}
+
+func (h *TaskHandler) setTaskStatus(c *gin.Context, status TaskStatus) {
+	task, ok := h.loadTask(c)
+	if !ok || !checkIfMatch(c, task) {
+		return
+	}
+
+	if status == StatusDone && task.Status != StatusDone && !h.allowCompletion(c, task.ID) {
+		return
+	}
+
+	if err := task.SetStatus(status); err != nil {
+		invalidTransition(c, err)
+		return
+	}
+	if err := h.Service.UpdateTask(c.Request.Context(), task); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+	h.afterSave(task)
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) ArchiveTask(c *gin.Context) {
+	h.setTaskArchived(c, true)
+}
+
+func (h *TaskHandler) UnarchiveTask(c *gin.Context) {
+	h.setTaskArchived(c, false)
+}
+
+// setTaskArchived mengarsipkan atau mengembalikan task. Task yang sudah dalam
+// keadaan yang diminta dikembalikan apa adanya.
+func (h *TaskHandler) setTaskArchived(c *gin.Context, archived bool) {
+	task, ok := h.loadTask(c)
+	if !ok || !checkIfMatch(c, task) {
+		return
+	}
+
+	if archived == (task.ArchivedAt != nil) {
+		c.JSON(http.StatusOK, task)
+		return
+	}
+	task.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		task.ArchivedAt = &now
+	}
+	if err := h.Service.UpdateTask(c.Request.Context(), task); err != nil {
+		saveTaskError(c, err)
+		return
+	}
+	h.afterSave(task)
+
+	c.JSON(http.StatusOK, task)
+}
+
+// MoveTask memindahkan task ke sebelum atau sesudah task lain.
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	var req moveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if (req.Before == nil) == (req.After == nil) {
+		problem(c, http.StatusBadRequest, "exactly one of before or after is required")
+		return
+	}
+
+	targetID, after := req.Before, false
+	if req.After != nil {
+		targetID, after = req.After, true
+	}
+	if *targetID == id {
+		problem(c, http.StatusBadRequest, "cannot move a task relative to itself")
+		return
+	}
+
+	task, err := h.Service.MoveTask(c.Request.Context(), id, *targetID, after)
+	var missing *MissingTasksError
+	if errors.As(err, &missing) {
+		problem(c, http.StatusBadRequest, "target task not found", gin.H{
+			"id": *targetID,
+		})
+		return
+	}
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) DuplicateTask(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.Service.DuplicateTask(c.Request.Context(), id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// DeleteTask menghapus task. Jika If-Match dikirim, task dimuat dulu untuk dicek versinya.
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	if c.GetHeader("If-Match") != "" {
+		task, ok := h.loadTask(c)
+		if !ok || !checkIfMatch(c, task) {
+			return
+		}
+	}
+
+	err := h.Service.DeleteTask(c.Request.Context(), id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// afterSave membangunkan scheduler jika task berulang baru saja diselesaikan.
+func (h *TaskHandler) afterSave(task *Task) {
+	if task.Status == StatusDone && task.Recurrence != "" {
+		h.Scheduler.Notify()
+	}
+}
+
+// loadTask mengambil task berdasarkan :id dan menulis response error jika gagal.
+func (h *TaskHandler) loadTask(c *gin.Context) (*Task, bool) {
+	return h.loadTaskIncluding(c, defaultTaskInclude)
+}
+
+func (h *TaskHandler) loadTaskIncluding(c *gin.Context, include TaskInclude) (*Task, bool) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return nil, false
+	}
+
+	task, err := h.Service.GetTask(c.Request.Context(), id, include)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// bindTaskRequest membaca body JSON dan memvalidasi title.
+func bindTaskRequest(c *gin.Context) (taskRequest, bool) {
+	var req taskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	if err := validateTaskRequest(&req); err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+// validateTaskRequest merapikan input dan mengembalikan error jika tidak valid.
+func validateTaskRequest(req *taskRequest) error {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return errors.New("title must not be empty")
+	}
+	status, err := ParseTaskStatus(req.Status)
+	if err != nil {
+		return err
+	}
+	req.status = status
+	priority, err := ParsePriority(req.Priority)
+	if err != nil {
+		return err
+	}
+	req.priority = priority
+	req.Recurrence = normalizeRecurrence(req.Recurrence)
+	return nil
+}
+
+func (req *taskRequest) toTask() Task {
+	task := Task{
+		Title:       req.Title,
+		Description: req.Description,
+		ProjectID:   req.ProjectID,
+		AssigneeID:  req.AssigneeID,
+		Priority:    req.priority,
+		DueAt:       req.DueAt,
+		Recurrence:  req.Recurrence,
+	}
+	// Task baru boleh langsung dibuat dengan status apa pun.
+	task.SetStatus(req.status)
+	return task
+}
+
+func normalizeRecurrence(value string) string {
+	return strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(value), "RRULE:"))
+}
+
+func parseTaskID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid task id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func taskNotFound(c *gin.Context, id uint) {
+	problem(c, http.StatusNotFound, "task not found", gin.H{
+		"id": id,
+	})
+}
+
+// invalidTransition menulis 409 untuk InvalidTransitionError dan 400 untuk error lain.
+func invalidTransition(c *gin.Context, err error) {
+	var transition *InvalidTransitionError
+	if errors.As(err, &transition) {
+		problem(c, http.StatusConflict, err.Error(), gin.H{
+			"from": transition.From,
+			"to":   transition.To,
+		})
+		return
+	}
+	problem(c, http.StatusBadRequest, err.Error())
+}
+
+// saveTaskError memetakan error validasi dari database ke 400, sisanya 500.
+func saveTaskError(c *gin.Context, err error) {
+	if errors.Is(err, ErrProjectNotFound) {
+		problem(c, http.StatusBadRequest, "project not found")
+		return
+	}
+	if errors.Is(err, ErrInvalidAssignee) {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, ErrTaskVersionConflict) {
+		problem(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	internalError(c, err)
+}
+
+// taskETag adalah ETag task, yaitu version-nya, misalnya "3". Respons task
+// mengirimnya supaya client bisa langsung memakainya di If-Match.
+func taskETag(task *Task) string {
+	return `"` + strconv.Itoa(task.Version) + `"`
+}
+
+// checkIfMatch membandingkan header If-Match dengan version task (taskETag),
+// misalnya If-Match: "3". Tanpa header, perubahan tetap diizinkan (last write wins).
+func checkIfMatch(c *gin.Context, task *Task) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+
+	version := strconv.Itoa(task.Version)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.Trim(strings.TrimPrefix(strings.TrimSpace(candidate), "W/"), `"`)
+		if candidate == "*" || candidate == version {
+			return true
+		}
+	}
+
+	problem(c, http.StatusPreconditionFailed, ErrTaskVersionConflict.Error(), gin.H{
+		"id":      task.ID,
+		"version": task.Version,
+	})
+	return false
+}
+
+func internalError(c *gin.Context, err error) {
+	log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	problem(c, http.StatusInternalServerError, "internal server error")
+}
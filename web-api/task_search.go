@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// searchConfig dipakai untuk to_tsvector/to_tsquery. Postgres tidak punya
+// konfigurasi bahasa Indonesia, jadi dipakai 'simple' tanpa stemming.
+const searchConfig = "simple"
+
+// TaskSearchResult adalah satu hasil pencarian beserta skor dan potongan teks
+// yang kata kuncinya ditandai dengan <mark>.
+type TaskSearchResult struct {
+	Task       Task            `json:"task"`
+	Rank       float64         `json:"rank"`
+	Highlights SearchHighlight `json:"highlights"`
+}
+
+type SearchHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type searchRow struct {
+	ID                   uint
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// migrateTaskSearch menambah kolom tsvector (generated) dan index GIN untuk
+// pencarian. Kolom ini tidak ada di struct Task karena hanya dibaca lewat SQL.
+func migrateTaskSearch(db *gorm.DB) error {
+	err := db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('` + searchConfig + `', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('` + searchConfig + `', coalesce(description, '')), 'B')
+		) STORED`).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector)").Error
+}
+
+// SearchTasks mencari task dengan query gaya web ("kata", -kata, OR) lalu
+// mengurutkannya berdasarkan relevansi. Filter tetap berlaku.
+func (s *TaskServiceImpl) SearchTasks(ctx context.Context, q string, filter TaskFilter, page Page) ([]TaskSearchResult, int64, error) {
+	db := s.DB.WithContext(ctx)
+	match := func(tx *gorm.DB) *gorm.DB {
+		return filter.apply(tx.Model(&Task{}).Scopes(accessibleTasks).Where("search_vector @@ websearch_to_tsquery('"+searchConfig+"', ?)", q))
+	}
+
+	var total int64
+	if err := match(db).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []searchRow
+	err := match(db).
+		Select(`id,
+			ts_rank(search_vector, websearch_to_tsquery('`+searchConfig+`', ?)) AS rank,
+			ts_headline('`+searchConfig+`', title, websearch_to_tsquery('`+searchConfig+`', ?),
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+			ts_headline('`+searchConfig+`', description, websearch_to_tsquery('`+searchConfig+`', ?),
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS description_highlight`, q, q, q).
+		Order("rank DESC, id").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []TaskSearchResult{}, total, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var tasks []Task
+	if err := preloadTaskRelations(db).Scopes(accessibleTasks).Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := loadDependencies(db, tasks); err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[uint]Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	// Urutan hasil mengikuti rank; task yang terhapus di antara dua query dilewati.
+	results := make([]TaskSearchResult, 0, len(rows))
+	for _, row := range rows {
+		task, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, TaskSearchResult{
+			Task: task,
+			Rank: row.Rank,
+			Highlights: SearchHighlight{
+				Title:       row.TitleHighlight,
+				Description: row.DescriptionHighlight,
+			},
+		})
+	}
+	return results, total, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxSearchQueryLength = 256
+
+// SearchTasks mencari task berdasarkan judul dan deskripsi lewat ?q=.
+// Filter dan limit/offset sama dengan GET /tasks; hasil diurutkan berdasarkan relevansi.
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		problem(c, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len(q) > maxSearchQueryLength {
+		problem(c, http.StatusBadRequest, "q is too long")
+		return
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode || len(filter.Sort) > 0 {
+		problem(c, http.StatusBadRequest, "search results are ordered by relevance and only support limit/offset")
+		return
+	}
+
+	results, total, err := h.Service.SearchTasks(c.Request.Context(), q, filter, page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"meta":    page.Meta(total, nil),
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TaskStats berisi statistik produktivitas per minggu (minggu dimulai Senin).
+type TaskStats struct {
+	Since time.Time   `json:"since"`
+	Weeks []WeekStats `json:"weeks"`
+	// CompletionRate adalah porsi task yang dibuat sejak Since dan sekarang sudah done.
+	CompletionRate float64 `json:"completion_rate"`
+	// AverageCompletionSeconds adalah rata-rata selang created_at ke completed_at
+	// untuk task yang selesai sejak Since; null jika belum ada.
+	AverageCompletionSeconds *float64 `json:"average_completion_seconds"`
+}
+
+// WeekStats membandingkan task yang dibuat dan diselesaikan dalam satu minggu.
+// CompletionRate adalah porsi task yang dibuat minggu itu dan sekarang sudah done.
+type WeekStats struct {
+	WeekStart      string  `json:"week_start"`
+	Created        int64   `json:"created"`
+	Completed      int64   `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+type weekCountRow struct {
+	Week  string
+	Count int64
+	Done  int64
+}
+
+// TaskStats menghitung statistik untuk weeks minggu terakhir, termasuk minggu
+// ini, dengan batas minggu di zona waktu loc. Semua angka dihitung lewat
+// query agregat di Postgres.
+func (s *TaskServiceImpl) TaskStats(ctx context.Context, weeks int, now time.Time, loc *time.Location) (*TaskStats, error) {
+	db := s.DB.WithContext(ctx)
+	since := weekStart(now.In(loc)).AddDate(0, 0, -7*(weeks-1))
+	week := "to_char(date_trunc('week', %s AT TIME ZONE ?), 'YYYY-MM-DD')"
+
+	var created []weekCountRow
+	err := db.Model(&Task{}).Scopes(accessibleTasks).
+		Select(fmt.Sprintf(week, "created_at")+` AS week, COUNT(*) AS count,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS done`, loc.String(), StatusDone).
+		Where("created_at >= ?", since).
+		Group("week").
+		Scan(&created).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []weekCountRow
+	err = db.Model(&Task{}).Scopes(accessibleTasks).
+		Select(fmt.Sprintf(week, "completed_at")+" AS week, COUNT(*) AS count", loc.String()).
+		Where("status = ? AND completed_at >= ?", StatusDone, since).
+		Group("week").
+		Scan(&completed).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var average *float64
+	err = db.Model(&Task{}).Scopes(accessibleTasks).
+		Select("AVG(EXTRACT(EPOCH FROM completed_at - created_at))::float8").
+		Where("status = ? AND completed_at >= ?", StatusDone, since).
+		Row().Scan(&average)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &TaskStats{Since: since, AverageCompletionSeconds: average}
+	index := make(map[string]int, weeks)
+	for i := 0; i < weeks; i++ {
+		date := since.AddDate(0, 0, 7*i).Format(time.DateOnly)
+		index[date] = i
+		stats.Weeks = append(stats.Weeks, WeekStats{WeekStart: date})
+	}
+
+	var totalCreated, totalDone int64
+	for _, row := range created {
+		if i, ok := index[row.Week]; ok {
+			stats.Weeks[i].Created = row.Count
+			stats.Weeks[i].CompletionRate = ratio(row.Done, row.Count)
+		}
+		totalCreated += row.Count
+		totalDone += row.Done
+	}
+	for _, row := range completed {
+		if i, ok := index[row.Week]; ok {
+			stats.Weeks[i].Completed = row.Count
+		}
+	}
+	stats.CompletionRate = ratio(totalDone, totalCreated)
+	return stats, nil
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultStatsWeeks = 12
+	maxStatsWeeks     = 104
+)
+
+// ShowStats mengembalikan statistik produktivitas untuk ?weeks= minggu terakhir
+// (default 12) dengan batas minggu di zona waktu ?tz=.
+func (h *TaskHandler) ShowStats(c *gin.Context) {
+	weeks := defaultStatsWeeks
+	if value := c.Query("weeks"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxStatsWeeks {
+			problem(c, http.StatusBadRequest, fmt.Sprintf("invalid weeks %q: must be between 1 and %d", value, maxStatsWeeks))
+			return
+		}
+		weeks = n
+	}
+	loc, err := parseLocation(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.Service.TaskStats(c.Request.Context(), weeks, time.Now(), loc)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
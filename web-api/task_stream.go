@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// taskStreamLookback adalah jendela event yang dibaca ulang setiap polling.
+	// Transaksi yang commit belakangan bisa punya ID lebih kecil dari event yang
+	// sudah terkirim, jadi event dicari berdasarkan waktu, bukan ID terakhir.
+	taskStreamLookback = 10 * time.Second
+	// taskStreamRetention: event yang lebih lama dihapus dari tabel, jadi
+	// stream SSE hanya bisa dilanjutkan dalam jendela ini.
+	taskStreamRetention = 5 * time.Minute
+	// taskStreamBuffer adalah jumlah pesan yang boleh tertahan per koneksi;
+	// client yang lebih lambat diputus.
+	taskStreamBuffer = 64
+)
+
+// TaskStreamEvent adalah antrean event untuk GET /ws dan GET /events; ID-nya
+// dipakai sebagai id event SSE. Baris ditulis di transaksi yang sama dengan
+// perubahan task sehingga perubahan yang batal tidak pernah terkirim, dan
+// event dari instance server lain ikut sampai.
+type TaskStreamEvent struct {
+	ID          uint           `gorm:"primaryKey"`
+	WorkspaceID *uint          `gorm:"index"`
+	OwnerID     *uint          `gorm:"index"`
+	Payload     WebhookPayload `gorm:"serializer:json;type:text"`
+	CreatedAt   time.Time      `gorm:"index"`
+}
+
+// recordTaskStream mencatat event untuk client WebSocket dan SSE.
+// task.completed dikirim sebagai task.updated karena client hanya perlu tahu
+// isi task terbaru.
+func recordTaskStream(tx *gorm.DB, event WebhookEvent, tasks ...Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	if event == WebhookTaskCompleted {
+		event = WebhookTaskUpdated
+	}
+	now := time.Now()
+	events := make([]TaskStreamEvent, len(tasks))
+	for i, task := range tasks {
+		events[i] = TaskStreamEvent{
+			WorkspaceID: task.WorkspaceID,
+			OwnerID:     task.UserID,
+			Payload:     WebhookPayload{Event: event, OccurredAt: now, Task: task},
+		}
+	}
+	return tx.Session(&gorm.Session{NewDB: true}).Create(&events).Error
+}
+
+// TaskStreamHub berjalan di background, membaca TaskStreamEvent baru, lalu
+// meneruskannya ke koneksi WebSocket dan SSE yang berhak: semua member untuk task
+// workspace, dan pemiliknya untuk task pribadi.
+type TaskStreamHub struct {
+	DB       *gorm.DB
+	Interval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[uint]map[*taskStreamSubscriber]struct{}
+	// seen hanya dipakai goroutine Run.
+	seen map[uint]time.Time
+}
+
+type taskStreamSubscriber struct {
+	userID uint
+	// events ditutup hub jika buffer penuh.
+	events chan taskStreamMessage
+}
+
+// taskStreamMessage adalah satu event yang siap dikirim; Data berisi
+// WebhookPayload dalam JSON.
+type taskStreamMessage struct {
+	ID    uint
+	Event WebhookEvent
+	Data  []byte
+}
+
+func newTaskStreamMessage(event TaskStreamEvent) (taskStreamMessage, error) {
+	data, err := json.Marshal(event.Payload)
+	return taskStreamMessage{ID: event.ID, Event: event.Payload.Event, Data: data}, err
+}
+
+// Subscribe mendaftarkan koneksi baru milik userID.
+func (h *TaskStreamHub) Subscribe(userID uint) *taskStreamSubscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers == nil {
+		h.subscribers = make(map[uint]map[*taskStreamSubscriber]struct{})
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*taskStreamSubscriber]struct{})
+	}
+	sub := &taskStreamSubscriber{userID: userID, events: make(chan taskStreamMessage, taskStreamBuffer)}
+	h.subscribers[userID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe melepas koneksi; aman dipanggil setelah hub memutusnya.
+func (h *TaskStreamHub) Unsubscribe(sub *taskStreamSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+func (h *TaskStreamHub) remove(sub *taskStreamSubscriber) {
+	subs := h.subscribers[sub.userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+	close(sub.events)
+}
+
+// Run meneruskan event baru setiap Interval sampai ctx dibatalkan. Event yang
+// sudah ada saat start hanya ditandai terbaca.
+func (h *TaskStreamHub) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	h.seen = make(map[uint]time.Time)
+	deliver := false
+	for {
+		if err := h.poll(ctx, deliver); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("task stream: %v", err)
+		}
+		deliver = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *TaskStreamHub) poll(ctx context.Context, deliver bool) error {
+	db := h.DB.WithContext(ctx)
+	now := time.Now()
+	if err := db.Where("created_at < ?", now.Add(-taskStreamRetention)).Delete(&TaskStreamEvent{}).Error; err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-taskStreamLookback)
+	var events []TaskStreamEvent
+	if err := db.Where("created_at >= ?", cutoff).Order("id").Find(&events).Error; err != nil {
+		return err
+	}
+	for id, createdAt := range h.seen {
+		if createdAt.Before(cutoff) {
+			delete(h.seen, id)
+		}
+	}
+	var fresh []TaskStreamEvent
+	for _, event := range events {
+		if _, ok := h.seen[event.ID]; ok {
+			continue
+		}
+		h.seen[event.ID] = event.CreatedAt
+		fresh = append(fresh, event)
+	}
+	if !deliver || len(fresh) == 0 {
+		return nil
+	}
+
+	members, err := h.members(db, fresh)
+	if err != nil {
+		return err
+	}
+	for _, event := range fresh {
+		message, err := newTaskStreamMessage(event)
+		if err != nil {
+			return err
+		}
+		if event.WorkspaceID != nil {
+			for _, userID := range members[*event.WorkspaceID] {
+				h.send(userID, message)
+			}
+		} else if event.OwnerID != nil {
+			h.send(*event.OwnerID, message)
+		}
+	}
+	return nil
+}
+
+// Since mengembalikan event yang boleh dilihat userID setelah event lastID,
+// untuk melanjutkan stream SSE. ok false berarti lastID sudah dihapus dari
+// tabel sehingga sebagian event mungkin terlewat.
+func (h *TaskStreamHub) Since(ctx context.Context, userID, lastID uint) (messages []taskStreamMessage, ok bool, err error) {
+	db := h.DB.WithContext(ctx)
+	var count int64
+	if err := db.Model(&TaskStreamEvent{}).Where("id = ?", lastID).Count(&count).Error; err != nil {
+		return nil, false, err
+	}
+	if count == 0 {
+		return nil, false, nil
+	}
+
+	var events []TaskStreamEvent
+	workspaces := db.Model(&WorkspaceMember{}).Select("workspace_id").Where("user_id = ?", userID)
+	err = db.Where("id > ?", lastID).
+		Where("workspace_id IN (?) OR (workspace_id IS NULL AND owner_id = ?)", workspaces, userID).
+		Order("id").
+		Find(&events).Error
+	if err != nil {
+		return nil, false, err
+	}
+	messages = make([]taskStreamMessage, len(events))
+	for i, event := range events {
+		if messages[i], err = newTaskStreamMessage(event); err != nil {
+			return nil, false, err
+		}
+	}
+	return messages, true, nil
+}
+
+// members mengembalikan user yang sedang terhubung per workspace dari events.
+func (h *TaskStreamHub) members(db *gorm.DB, events []TaskStreamEvent) (map[uint][]uint, error) {
+	h.mu.Lock()
+	connected := make([]uint, 0, len(h.subscribers))
+	for userID := range h.subscribers {
+		connected = append(connected, userID)
+	}
+	h.mu.Unlock()
+
+	var workspaces []uint
+	for _, event := range events {
+		if event.WorkspaceID != nil {
+			workspaces = append(workspaces, *event.WorkspaceID)
+		}
+	}
+	members := make(map[uint][]uint)
+	if len(connected) == 0 || len(workspaces) == 0 {
+		return members, nil
+	}
+
+	var rows []WorkspaceMember
+	err := db.Select("workspace_id", "user_id").
+		Where("workspace_id IN ? AND user_id IN ?", workspaces, connected).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		members[row.WorkspaceID] = append(members[row.WorkspaceID], row.UserID)
+	}
+	return members, nil
+}
+
+func (h *TaskStreamHub) send(userID uint, message taskStreamMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.events <- message:
+		default:
+			h.remove(sub)
+		}
+	}
+}
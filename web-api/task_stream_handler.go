@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	taskStreamWriteWait  = 10 * time.Second
+	taskStreamPongWait   = 60 * time.Second
+	taskStreamPingPeriod = 30 * time.Second
+)
+
+// taskStreamUpgrader memakai cek Origin bawaan gorilla (Origin harus sama
+// dengan Host jika dikirim), sehingga halaman lain tidak bisa membuka koneksi
+// dengan cookie session user.
+var taskStreamUpgrader = websocket.Upgrader{}
+
+// TaskStreamHandler melayani GET /ws dan GET /events: setiap perubahan task di
+// workspace user dan task pribadinya dikirim sebagai pesan JSON dengan bentuk
+// yang sama seperti payload webhook.
+type TaskStreamHandler struct {
+	Hub *TaskStreamHub
+}
+
+func (h *TaskStreamHandler) WebSocket(c *gin.Context) {
+	streamResponse(c)
+	conn, err := taskStreamUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade sudah menulis respons error ke client.
+		return
+	}
+	defer conn.Close()
+
+	sub := h.Hub.Subscribe(currentUser(c).ID)
+	defer h.Hub.Unsubscribe(sub)
+
+	// Pesan dari client tidak dipakai; pembacaan hanya untuk memproses pong
+	// dan mendeteksi koneksi yang ditutup.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(taskStreamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(taskStreamPongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(taskStreamPingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case message, ok := <-sub.events:
+			conn.SetWriteDeadline(time.Now().Add(taskStreamWriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"))
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, message.Data); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(taskStreamWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// Events melayani GET /events untuk client yang tidak bisa memakai WebSocket.
+// Client yang menyambung ulang dengan Last-Event-ID (atau ?last_event_id=)
+// menerima event yang terlewat selama masih disimpan; jika sudah terhapus,
+// server mengirim event reset dan client perlu memuat ulang daftar task.
+func (h *TaskStreamHandler) Events(c *gin.Context) {
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+	var last uint64
+	if lastID != "" {
+		var err error
+		if last, err = strconv.ParseUint(lastID, 10, 64); err != nil {
+			problem(c, http.StatusBadRequest, "invalid Last-Event-ID")
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	user := currentUser(c)
+	// Subscribe sebelum membaca event lama supaya tidak ada event yang jatuh di
+	// antara keduanya; duplikatnya dilewati di bawah.
+	sub := h.Hub.Subscribe(user.ID)
+	defer h.Hub.Unsubscribe(sub)
+
+	var missed []taskStreamMessage
+	resumed := true
+	if lastID != "" {
+		var err error
+		if missed, resumed, err = h.Hub.Since(ctx, user.ID, uint(last)); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	streamResponse(c)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if !resumed {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	sent := make(map[uint]bool, len(missed))
+	for _, message := range missed {
+		writeTaskEvent(c.Writer, message)
+		sent[message.ID] = true
+	}
+	c.Writer.Flush()
+
+	ping := time.NewTicker(taskStreamPingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case message, ok := <-sub.events:
+			// Client yang terlalu lambat diputus; EventSource menyambung ulang
+			// sendiri dengan Last-Event-ID.
+			if !ok {
+				return
+			}
+			if sent[message.ID] {
+				continue
+			}
+			writeTaskEvent(c.Writer, message)
+		case <-ping.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case <-ctx.Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeTaskEvent(w io.Writer, message taskStreamMessage) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Event, message.Data)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// TaskSummary berisi jumlah task untuk dashboard.
+type TaskSummary struct {
+	Total     int64                `json:"total"`
+	ByStatus  map[TaskStatus]int64 `json:"by_status"`
+	ByProject []ProjectCount       `json:"by_project"`
+	Overdue   int64                `json:"overdue"`
+	DueToday  int64                `json:"due_today"`
+}
+
+// ProjectCount adalah jumlah task per project; ProjectID nil untuk task tanpa project.
+type ProjectCount struct {
+	ProjectID *uint `json:"project_id"`
+	Count     int64 `json:"count"`
+}
+
+type summaryRow struct {
+	Status    TaskStatus
+	ProjectID *uint
+	Count     int64
+	Overdue   int64
+	DueToday  int64
+}
+
+// SummarizeTasks menghitung semua angka dalam satu query yang dikelompokkan per
+// (status, project_id), lalu menjumlahkannya di Go. "Hari ini" dihitung pada
+// zona waktu loc. Overdue dan due_today hanya menghitung task yang masih terbuka.
+func (s *TaskServiceImpl) SummarizeTasks(ctx context.Context, filter TaskFilter, now time.Time, loc *time.Location) (*TaskSummary, error) {
+	local := now.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	closed := []TaskStatus{StatusDone, StatusCancelled}
+
+	var rows []summaryRow
+	err := filter.apply(s.DB.WithContext(ctx).Model(&Task{}).Scopes(accessibleTasks)).
+		Select(`status, project_id, COUNT(*) AS count,
+			SUM(CASE WHEN due_at < ? AND status NOT IN ? THEN 1 ELSE 0 END) AS overdue,
+			SUM(CASE WHEN due_at >= ? AND due_at < ? AND status NOT IN ? THEN 1 ELSE 0 END) AS due_today`,
+			now, closed, dayStart.UTC(), dayEnd.UTC(), closed).
+		Group("status, project_id").
+		Order("project_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TaskSummary{
+		ByStatus:  make(map[TaskStatus]int64, len(statusTransitions)),
+		ByProject: []ProjectCount{},
+	}
+	for status := range statusTransitions {
+		summary.ByStatus[status] = 0
+	}
+
+	for _, row := range rows {
+		summary.Total += row.Count
+		summary.ByStatus[row.Status] += row.Count
+		summary.Overdue += row.Overdue
+		summary.DueToday += row.DueToday
+
+		// Baris diurutkan per project_id, jadi baris dengan project yang sama berurutan.
+		last := len(summary.ByProject) - 1
+		if last < 0 || !sameProjectID(summary.ByProject[last].ProjectID, row.ProjectID) {
+			summary.ByProject = append(summary.ByProject, ProjectCount{ProjectID: row.ProjectID})
+			last++
+		}
+		summary.ByProject[last].Count += row.Count
+	}
+	return summary, nil
+}
+
+func sameProjectID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
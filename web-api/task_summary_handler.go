@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SummarizeTasks mengembalikan jumlah task per status dan project, serta jumlah
+// task overdue dan jatuh tempo hari ini. Filter GET /tasks tetap berlaku;
+// ?tz= menentukan batas "hari ini" (default UTC).
+func (h *TaskHandler) SummarizeTasks(c *gin.Context) {
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, err := parseLocation(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := h.Service.SummarizeTasks(c.Request.Context(), filter, time.Now(), loc)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// parseLocation membaca ?tz= berupa nama zona IANA seperti Asia/Jakarta.
+// Tanpa ?tz=, zona dari preferensi user yang dipakai.
+func parseLocation(c *gin.Context) (*time.Location, error) {
+	value := c.Query("tz")
+	if value == "" {
+		user := currentUser(c)
+		if user == nil || user.Preferences.Timezone == "" {
+			return time.UTC, nil
+		}
+		value = user.Preferences.Timezone
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz %q", value)
+	}
+	return loc, nil
+}
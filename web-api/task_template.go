@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTemplateNotFound  = errors.New("template not found")
+	ErrTemplateNameTaken = errors.New("template name already exists")
+)
+
+// TaskTemplate adalah task yang disimpan dengan nama untuk dipakai berulang,
+// misalnya checklist mingguan. Template milik user, sama seperti tag dan
+// saved filter, sehingga bisa dipakai di data pribadi maupun di workspace.
+type TaskTemplate struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	UserID      *uint             `json:"-" gorm:"uniqueIndex:idx_task_templates_user_name,priority:1"`
+	User        *User             `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name        string            `json:"name" gorm:"type:varchar(128);not null;uniqueIndex:idx_task_templates_user_name,priority:2"`
+	Title       string            `json:"title" gorm:"not null"`
+	Description string            `json:"description"`
+	Priority    Priority          `json:"priority" gorm:"type:varchar(16);not null;default:'medium'"`
+	Subtasks    []TemplateSubtask `json:"subtasks" gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE"`
+	Tags        []Tag             `json:"tags" gorm:"many2many:task_template_tags;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// TemplateSubtask adalah subtask yang ikut dibuat saat template dipakai.
+type TemplateSubtask struct {
+	ID         uint   `json:"-" gorm:"primaryKey"`
+	TemplateID uint   `json:"-" gorm:"not null;index"`
+	Title      string `json:"title" gorm:"not null"`
+	Position   int    `json:"position" gorm:"not null;default:0"`
+}
+
+// TemplateOverrides mengganti nilai template untuk satu task yang dibuat.
+type TemplateOverrides struct {
+	Title     string
+	ProjectID *uint
+	DueAt     *time.Time
+}
+
+// Interface untuk layanan template task
+type TaskTemplateService interface {
+	ListTemplates(ctx context.Context) ([]TaskTemplate, error)
+	GetTemplate(ctx context.Context, id uint) (*TaskTemplate, error)
+	CreateTemplate(ctx context.Context, template *TaskTemplate, tagIDs []uint) error
+	CreateTemplateFromTask(ctx context.Context, taskID uint, name string) (*TaskTemplate, error)
+	UpdateTemplate(ctx context.Context, template *TaskTemplate, tagIDs []uint) error
+	DeleteTemplate(ctx context.Context, id uint) error
+	InstantiateTemplate(ctx context.Context, id uint, overrides TemplateOverrides) (*Task, error)
+}
+
+// Struct implementasi TaskTemplateService dengan GORM
+type TaskTemplateServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *TaskTemplateServiceImpl) ListTemplates(ctx context.Context) ([]TaskTemplate, error) {
+	var templates []TaskTemplate
+	err := preloadTemplateRelations(s.DB.WithContext(ctx)).Scopes(ownedByUser).Order("name").Find(&templates).Error
+	return templates, err
+}
+
+func (s *TaskTemplateServiceImpl) GetTemplate(ctx context.Context, id uint) (*TaskTemplate, error) {
+	var template TaskTemplate
+	if err := findTemplate(s.DB.WithContext(ctx), id, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// CreateTemplate menyimpan template baru dengan subtasks dari template.Subtasks
+// dan tag tagIDs, yang harus milik user.
+func (s *TaskTemplateServiceImpl) CreateTemplate(ctx context.Context, template *TaskTemplate, tagIDs []uint) error {
+	owner := ownerID(ctx)
+	template.UserID = &owner
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setTemplateTags(tx, template, tagIDs); err != nil {
+			return err
+		}
+		return createTemplate(tx, template)
+	})
+}
+
+// CreateTemplateFromTask menyalin judul, deskripsi, prioritas, subtasks, dan
+// tag task menjadi template bernama name. Hanya tag milik user yang ikut,
+// supaya template dari task yang dibagikan tidak memakai tag orang lain.
+func (s *TaskTemplateServiceImpl) CreateTemplateFromTask(ctx context.Context, taskID uint, name string) (*TaskTemplate, error) {
+	owner := ownerID(ctx)
+	var template TaskTemplate
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task Task
+		err := preloadTaskRelations(tx).Scopes(accessibleTasks).First(&task, taskID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		template = TaskTemplate{
+			UserID:      &owner,
+			Name:        name,
+			Title:       task.Title,
+			Description: task.Description,
+			Priority:    task.Priority,
+			Subtasks:    []TemplateSubtask{},
+			Tags:        []Tag{},
+		}
+		for _, subtask := range task.Subtasks {
+			template.Subtasks = append(template.Subtasks, TemplateSubtask{
+				Title:    subtask.Title,
+				Position: subtask.Position,
+			})
+		}
+		for _, tag := range task.Tags {
+			if tag.UserID != nil && *tag.UserID == owner {
+				template.Tags = append(template.Tags, tag)
+			}
+		}
+		return createTemplate(tx, &template)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// UpdateTemplate mengganti semua isi template, termasuk subtasks dan tag.
+func (s *TaskTemplateServiceImpl) UpdateTemplate(ctx context.Context, template *TaskTemplate, tagIDs []uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setTemplateTags(tx, template, tagIDs); err != nil {
+			return err
+		}
+		err := tx.Omit(clause.Associations).Save(template).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrTemplateNameTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("template_id = ?", template.ID).Delete(&TemplateSubtask{}).Error; err != nil {
+			return err
+		}
+		for i := range template.Subtasks {
+			template.Subtasks[i].ID = 0
+			template.Subtasks[i].TemplateID = template.ID
+		}
+		if len(template.Subtasks) > 0 {
+			if err := tx.Create(&template.Subtasks).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(template).Association("Tags").Replace(template.Tags)
+	})
+}
+
+func (s *TaskTemplateServiceImpl) DeleteTemplate(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&TaskTemplate{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// InstantiateTemplate membuat task baru dari template di akhir daftar, seperti
+// CreateTask, beserta subtasks dan tag-nya.
+func (s *TaskTemplateServiceImpl) InstantiateTemplate(ctx context.Context, id uint, overrides TemplateOverrides) (*Task, error) {
+	owner := ownerID(ctx)
+	var task Task
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var template TaskTemplate
+		if err := findTemplate(tx, id, &template); err != nil {
+			return err
+		}
+
+		task = Task{
+			UserID:      &owner,
+			WorkspaceID: workspaceID(ctx),
+			Title:       template.Title,
+			Description: template.Description,
+			ProjectID:   overrides.ProjectID,
+			Status:      StatusTodo,
+			Priority:    template.Priority,
+			DueAt:       overrides.DueAt,
+			Tags:        template.Tags,
+		}
+		if overrides.Title != "" {
+			task.Title = overrides.Title
+		}
+		for _, subtask := range template.Subtasks {
+			task.Subtasks = append(task.Subtasks, Subtask{
+				Title:    subtask.Title,
+				Position: subtask.Position,
+			})
+		}
+
+		if err := placeInSharedProject(tx, &task); err != nil {
+			return err
+		}
+		position, err := nextPosition(tx, task.UserID, task.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		task.Position = position
+		return tx.Create(&task).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func createTemplate(tx *gorm.DB, template *TaskTemplate) error {
+	err := tx.Create(template).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrTemplateNameTaken
+	}
+	return err
+}
+
+// setTemplateTags memuat tag tagIDs milik user ke template.Tags.
+func setTemplateTags(tx *gorm.DB, template *TaskTemplate, tagIDs []uint) error {
+	template.Tags = []Tag{}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	if err := ensureTagsExist(tx, tagIDs); err != nil {
+		return err
+	}
+	return tx.Scopes(ownedByUser).Where("id IN ?", tagIDs).Order("name").Find(&template.Tags).Error
+}
+
+func findTemplate(tx *gorm.DB, id uint, template *TaskTemplate) error {
+	err := preloadTemplateRelations(tx).Scopes(ownedByUser).First(template, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTemplateNotFound
+	}
+	return err
+}
+
+func preloadTemplateRelations(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Subtasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		})
+}
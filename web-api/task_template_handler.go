@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type templateRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority"`
+	Subtasks    []string `json:"subtasks"`
+	TagIDs      []uint   `json:"tag_ids"`
+}
+
+type templateFromTaskRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// instantiateRequest boleh kosong; field yang dikirim menggantikan isi template.
+type instantiateRequest struct {
+	Title     string     `json:"title"`
+	ProjectID *uint      `json:"project_id"`
+	DueAt     *time.Time `json:"due_at"`
+}
+
+// TemplateHandler berisi HTTP handler untuk /templates.
+type TemplateHandler struct {
+	Service TaskTemplateService
+}
+
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.Service.ListTemplates(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	req, ok := bindTemplateRequest(c)
+	if !ok {
+		return
+	}
+
+	template := req.toTemplate()
+	if err := h.Service.CreateTemplate(c.Request.Context(), &template, req.TagIDs); err != nil {
+		templateError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// CreateTemplateFromTask menyimpan task :id sebagai template dengan nama dari body.
+func (h *TemplateHandler) CreateTemplateFromTask(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req templateFromTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	name, ok := validateTemplateName(c, req.Name)
+	if !ok {
+		return
+	}
+
+	template, err := h.Service.CreateTemplateFromTask(c.Request.Context(), taskID, name)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		templateError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+	req, ok := bindTemplateRequest(c)
+	if !ok {
+		return
+	}
+
+	updated := req.toTemplate()
+	updated.ID = template.ID
+	updated.UserID = template.UserID
+	updated.CreatedAt = template.CreatedAt
+	if err := h.Service.UpdateTemplate(c.Request.Context(), &updated, req.TagIDs); err != nil {
+		templateError(c, err, template.ID)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteTemplate(c.Request.Context(), id); err != nil {
+		templateError(c, err, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InstantiateTemplate membuat task baru dari template. Task dibuat di data
+// pribadi atau di workspace dari header X-Workspace-ID, seperti POST /tasks.
+func (h *TemplateHandler) InstantiateTemplate(c *gin.Context) {
+	id, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	var req instantiateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+	}
+
+	task, err := h.Service.InstantiateTemplate(c.Request.Context(), id, TemplateOverrides{
+		Title:     strings.TrimSpace(req.Title),
+		ProjectID: req.ProjectID,
+		DueAt:     req.DueAt,
+	})
+	if errors.Is(err, ErrTemplateNotFound) {
+		templateError(c, err, id)
+		return
+	}
+	if err != nil {
+		saveTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+func (h *TemplateHandler) loadTemplate(c *gin.Context) (*TaskTemplate, bool) {
+	id, ok := parseTemplateID(c)
+	if !ok {
+		return nil, false
+	}
+
+	template, err := h.Service.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		templateError(c, err, id)
+		return nil, false
+	}
+	return template, true
+}
+
+func (req templateRequest) toTemplate() TaskTemplate {
+	template := TaskTemplate{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    Priority(req.Priority),
+		Subtasks:    []TemplateSubtask{},
+	}
+	for i, title := range req.Subtasks {
+		template.Subtasks = append(template.Subtasks, TemplateSubtask{Title: title, Position: i + 1})
+	}
+	return template
+}
+
+func bindTemplateRequest(c *gin.Context) (templateRequest, bool) {
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return req, false
+	}
+
+	name, ok := validateTemplateName(c, req.Name)
+	if !ok {
+		return req, false
+	}
+	req.Name = name
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		problem(c, http.StatusBadRequest, "title must not be empty")
+		return req, false
+	}
+	priority, err := ParsePriority(req.Priority)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+	req.Priority = string(priority)
+	for i, title := range req.Subtasks {
+		req.Subtasks[i] = strings.TrimSpace(title)
+		if req.Subtasks[i] == "" {
+			problem(c, http.StatusBadRequest, "subtask titles must not be empty")
+			return req, false
+		}
+	}
+	return req, true
+}
+
+func validateTemplateName(c *gin.Context, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		problem(c, http.StatusBadRequest, "name must not be empty")
+		return "", false
+	}
+	if len(name) > 128 {
+		problem(c, http.StatusBadRequest, "name must be at most 128 characters")
+		return "", false
+	}
+	return name, true
+}
+
+func parseTemplateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid template id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func templateError(c *gin.Context, err error, id uint) {
+	var missingTags *MissingTagsError
+	switch {
+	case errors.Is(err, ErrTemplateNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrTemplateNameTaken):
+		problem(c, http.StatusConflict, err.Error())
+	case errors.As(err, &missingTags):
+		problem(c, http.StatusNotFound, "tag not found", gin.H{"ids": missingTags.IDs})
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const telegramLinkCodeTTL = 10 * time.Minute
+
+var (
+	ErrTelegramNotLinked       = errors.New("telegram is not linked to this account")
+	ErrTelegramLinkCodeInvalid = errors.New("invalid or expired telegram link code")
+)
+
+// TelegramLink menghubungkan satu user dengan chat pribadinya dengan bot.
+type TelegramLink struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	ChatID    int64     `json:"chat_id" gorm:"not null;uniqueIndex"`
+	Username  string    `json:"username" gorm:"not null;default:''"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TelegramLinkCode disimpan sebagai hash dan dikirim user ke bot lewat
+// "/start <code>" untuk menghubungkan chat-nya. Hanya bisa dipakai sekali.
+type TelegramLinkCode struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	CodeHash  string    `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// Interface untuk layanan integrasi Telegram
+type TelegramService interface {
+	GetLink(ctx context.Context) (*TelegramLink, error)
+	CreateLinkCode(ctx context.Context) (string, time.Time, error)
+	Unlink(ctx context.Context) error
+	LinkChat(ctx context.Context, code string, chatID int64, username string) (*User, error)
+	FindByChat(ctx context.Context, chatID int64) (*TelegramLink, error)
+	UnlinkChat(ctx context.Context, chatID int64) error
+}
+
+// Struct implementasi TelegramService dengan GORM
+type TelegramServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *TelegramServiceImpl) GetLink(ctx context.Context) (*TelegramLink, error) {
+	var link TelegramLink
+	err := s.DB.WithContext(ctx).Where("user_id = ?", ownerID(ctx)).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTelegramNotLinked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CreateLinkCode membuat kode baru untuk user di context dan membatalkan kode
+// sebelumnya.
+func (s *TelegramServiceImpl) CreateLinkCode(ctx context.Context) (string, time.Time, error) {
+	value, err := randomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	code := TelegramLinkCode{
+		UserID:    ownerID(ctx),
+		CodeHash:  hashToken(value),
+		ExpiresAt: time.Now().Add(telegramLinkCodeTTL),
+	}
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", code.UserID).Delete(&TelegramLinkCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&code).Error
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return value, code.ExpiresAt, nil
+}
+
+func (s *TelegramServiceImpl) Unlink(ctx context.Context) error {
+	result := s.DB.WithContext(ctx).Where("user_id = ?", ownerID(ctx)).Delete(&TelegramLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTelegramNotLinked
+	}
+	return nil
+}
+
+// LinkChat memakai kode dan menghubungkan chatID ke pemilik kode. Link lama
+// milik user itu atau milik chat itu diganti.
+func (s *TelegramServiceImpl) LinkChat(ctx context.Context, code string, chatID int64, username string) (*User, error) {
+	var user User
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var linkCode TelegramLinkCode
+		err := tx.Preload("User").
+			Where("code_hash = ? AND expires_at > ?", hashToken(code), time.Now()).
+			First(&linkCode).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTelegramLinkCodeInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if linkCode.User == nil || linkCode.User.Disabled() {
+			return ErrTelegramLinkCodeInvalid
+		}
+		user = *linkCode.User
+
+		if err := tx.Delete(&linkCode).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR chat_id = ?", user.ID, chatID).Delete(&TelegramLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&TelegramLink{UserID: user.ID, ChatID: chatID, Username: username}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByChat memuat link sebuah chat beserta user-nya.
+func (s *TelegramServiceImpl) FindByChat(ctx context.Context, chatID int64) (*TelegramLink, error) {
+	var link TelegramLink
+	err := s.DB.WithContext(ctx).Preload("User").Where("chat_id = ?", chatID).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTelegramNotLinked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (s *TelegramServiceImpl) UnlinkChat(ctx context.Context, chatID int64) error {
+	result := s.DB.WithContext(ctx).Where("chat_id = ?", chatID).Delete(&TelegramLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTelegramNotLinked
+	}
+	return nil
+}
+
+// TelegramBot memanggil Bot API untuk mengirim pesan yang bukan balasan
+// langsung atas update, misalnya pengingat jatuh tempo.
+type TelegramBot struct {
+	Token    string
+	Username string
+	APIURL   string
+	Client   *http.Client
+}
+
+// newTelegramBot membaca TELEGRAM_BOT_TOKEN; tanpa token bot tidak aktif (nil).
+func newTelegramBot() *TelegramBot {
+	token := getEnv("TELEGRAM_BOT_TOKEN", "")
+	if token == "" {
+		return nil
+	}
+	return &TelegramBot{
+		Token:    token,
+		Username: strings.TrimPrefix(getEnv("TELEGRAM_BOT_USERNAME", ""), "@"),
+		APIURL:   strings.TrimSuffix(getEnv("TELEGRAM_API_URL", "https://api.telegram.org"), "/"),
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LinkURL adalah deep link yang membuka chat bot dan mengirim "/start <code>".
+// Kosong jika TELEGRAM_BOT_USERNAME tidak di-set.
+func (b *TelegramBot) LinkURL(code string) string {
+	if b.Username == "" {
+		return ""
+	}
+	return "https://t.me/" + b.Username + "?start=" + code
+}
+
+func (b *TelegramBot) SendMessage(ctx context.Context, chatID int64, text string) error {
+	body, err := json.Marshal(map[string]any{"chat_id": chatID, "text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.APIURL+"/bot"+b.Token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		// Error dari http.Client memuat URL yang berisi token bot.
+		return errors.New("telegram: sendMessage request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("telegram: sendMessage returned %d: %s", resp.StatusCode, message)
+	}
+	return nil
+}
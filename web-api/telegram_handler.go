@@ -0,0 +1,182 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const telegramHelp = "Send any message to add it as a task, or use:\n" +
+	"/add <title> - add a task\n" +
+	"/unlink - disconnect this chat from your account"
+
+// telegramUpdate adalah bagian update Bot API yang dipakai webhook.
+type telegramUpdate struct {
+	Message *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID   int64  `json:"id"`
+			Type string `json:"type"`
+		} `json:"chat"`
+		From *struct {
+			Username string `json:"username"`
+		} `json:"from"`
+	} `json:"message"`
+}
+
+// TelegramHandler berisi HTTP handler untuk /me/telegram dan webhook bot.
+// Secret adalah secret_token yang didaftarkan lewat setWebhook; Telegram
+// mengirimnya di header X-Telegram-Bot-Api-Secret-Token.
+type TelegramHandler struct {
+	Service TelegramService
+	Tasks   TaskService
+	Bot     *TelegramBot
+	Secret  string
+}
+
+func (h *TelegramHandler) GetLink(c *gin.Context) {
+	link, err := h.Service.GetLink(c.Request.Context())
+	if err != nil {
+		telegramError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// CreateLinkCode membuat kode sekali pakai yang dikirim user ke bot dengan
+// "/start <code>"; url berisi deep link jika username bot diketahui.
+func (h *TelegramHandler) CreateLinkCode(c *gin.Context) {
+	code, expiresAt, err := h.Service.CreateLinkCode(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"code": code, "expires_at": expiresAt, "url": h.Bot.LinkURL(code)})
+}
+
+func (h *TelegramHandler) Unlink(c *gin.Context) {
+	if err := h.Service.Unlink(c.Request.Context()); err != nil {
+		telegramError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Webhook menerima update dari Telegram. Balasan dikirim di body respons
+// sebagai pemanggilan sendMessage, jadi tidak perlu request terpisah ke Bot API.
+// Hanya chat pribadi yang dilayani.
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	secret := c.GetHeader("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.Secret)) != 1 {
+		problem(c, http.StatusUnauthorized, "invalid telegram secret token")
+		return
+	}
+	var update telegramUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	message := update.Message
+	if message == nil || message.Chat.Type != "private" || strings.TrimSpace(message.Text) == "" {
+		c.Status(http.StatusOK)
+		return
+	}
+	chatID := message.Chat.ID
+
+	command, argument := parseTelegramCommand(message.Text)
+	ctx := c.Request.Context()
+	switch command {
+	case "/start":
+		if argument == "" {
+			telegramReply(c, chatID, "Create a link code in the app, then send /start <code> here.")
+			return
+		}
+		username := ""
+		if message.From != nil {
+			username = message.From.Username
+		}
+		user, err := h.Service.LinkChat(ctx, argument, chatID, username)
+		if errors.Is(err, ErrTelegramLinkCodeInvalid) {
+			telegramReply(c, chatID, "That link code is invalid or has expired.")
+			return
+		}
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		telegramReply(c, chatID, "Linked to "+user.Email+". "+telegramHelp)
+		return
+	case "/help":
+		telegramReply(c, chatID, telegramHelp)
+		return
+	case "/unlink":
+		err := h.Service.UnlinkChat(ctx, chatID)
+		if err != nil && !errors.Is(err, ErrTelegramNotLinked) {
+			internalError(c, err)
+			return
+		}
+		telegramReply(c, chatID, "This chat is no longer linked.")
+		return
+	case "/add", "":
+	default:
+		telegramReply(c, chatID, telegramHelp)
+		return
+	}
+
+	link, err := h.Service.FindByChat(ctx, chatID)
+	if errors.Is(err, ErrTelegramNotLinked) {
+		telegramReply(c, chatID, "This chat is not linked yet. Create a link code in the app, then send /start <code> here.")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if link.User == nil || link.User.Disabled() {
+		telegramReply(c, chatID, "Your account is disabled.")
+		return
+	}
+	if argument == "" {
+		telegramReply(c, chatID, "Usage: /add <title>")
+		return
+	}
+
+	task := Task{Title: argument, Status: StatusTodo, Priority: PriorityMedium}
+	if err := h.Tasks.CreateTask(withUser(ctx, link.User), &task); err != nil {
+		internalError(c, err)
+		return
+	}
+	telegramReply(c, chatID, fmt.Sprintf("Created task #%d: %s", task.ID, task.Title))
+}
+
+// parseTelegramCommand memecah pesan menjadi command dan argumennya. Pesan
+// biasa dikembalikan dengan command kosong, dan akhiran "@namabot" dibuang.
+func parseTelegramCommand(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+	command, argument, _ := strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+	return strings.ToLower(command), strings.TrimSpace(argument)
+}
+
+func telegramReply(c *gin.Context, chatID int64, text string) {
+	c.JSON(http.StatusOK, gin.H{"method": "sendMessage", "chat_id": chatID, "text": text})
+}
+
+func telegramError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrTelegramNotLinked):
+		problem(c, http.StatusNotFound, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// todoistLabel mencocokkan label "@nama" di CONTENT.
+	todoistLabel = regexp.MustCompile(`(^|\s)@([^\s@]+)`)
+	// todoistFileID adalah akhiran " [123456]" pada nama file backup.
+	todoistFileID = regexp.MustCompile(`\s*\[\d+\]$`)
+)
+
+// parseTodoistBackup membaca backup Todoist: file zip berisi satu CSV per
+// project (Settings > Backups), atau satu CSV hasil export project. Nama
+// project diambil dari nama file. location dipakai untuk DATE jika kolom
+// TIMEZONE kosong atau tidak dikenal.
+func parseTodoistBackup(filename string, data []byte, location *time.Location, report *ImportReport) ([]ImportProject, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		project, err := parseTodoistCSV(filename, bytes.NewReader(data), location, report)
+		if err != nil {
+			return nil, err
+		}
+		return []ImportProject{*project}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip file: %w", err)
+	}
+	files := make([]*zip.File, 0, len(archive.File))
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if !strings.EqualFold(path.Ext(file.Name), ".csv") {
+			report.skip(file.Name, "file", "not a CSV file")
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	// Sisa kuota dibagi ke semua file supaya arsip kecil tidak bisa
+	// diekstrak menjadi data yang sangat besar.
+	remaining := int64(maxImportBytes)
+	var projects []ImportProject
+	for _, file := range files {
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		limited := &io.LimitedReader{R: reader, N: remaining + 1}
+		project, err := parseTodoistCSV(file.Name, limited, location, report)
+		reader.Close()
+		if limited.N == 0 {
+			return nil, fmt.Errorf("backup must be at most %d bytes when extracted", maxImportBytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		remaining = limited.N - 1
+		projects = append(projects, *project)
+	}
+	if len(projects) == 0 {
+		return nil, errors.New("backup does not contain any CSV files")
+	}
+	return projects, nil
+}
+
+// parseTodoistCSV membaca satu CSV Todoist. Baris task dengan INDENT 1 menjadi
+// task; baris dengan INDENT lebih besar menjadi subtask dari task di atasnya,
+// karena subtask di sini hanya satu tingkat. Section dan komentar (note) tidak
+// diimpor dan dicatat di report.
+func parseTodoistCSV(filename string, r io.Reader, location *time.Location, report *ImportReport) (*ImportProject, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: cannot read CSV header: %w", filename, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	// Kolom lain yang dipakai (DESCRIPTION, PRIORITY, INDENT, DATE, TIMEZONE)
+	// boleh tidak ada; AUTHOR, RESPONSIBLE, DURATION dan sebagainya diabaikan.
+	for _, name := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s: not a Todoist CSV (missing column %s)", filename, name)
+		}
+	}
+
+	base := path.Base(filename)
+	name := strings.TrimSpace(todoistFileID.ReplaceAllString(strings.TrimSuffix(base, path.Ext(base)), ""))
+	if name == "" {
+		name = "Todoist"
+	}
+	project := &ImportProject{Name: name}
+	var parent *ImportTask
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		source := fmt.Sprintf("%s:%d", base, line)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				report.skip(source, "row", "invalid CSV: %v", parseErr.Err)
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		switch kind := strings.ToLower(field("TYPE")); kind {
+		case "":
+			continue
+		case "task":
+		case "section", "note":
+			report.skip(source, kind, "%ss are not imported", kind)
+			continue
+		default:
+			report.skip(source, "row", "unknown type %q", kind)
+			continue
+		}
+
+		title, labels := splitTodoistLabels(field("CONTENT"))
+		if title == "" {
+			report.skip(source, "task", "task has no content")
+			continue
+		}
+		indent, _ := strconv.Atoi(field("INDENT"))
+		if indent > 1 && parent != nil {
+			parent.Subtasks = append(parent.Subtasks, Subtask{Title: title, Position: len(parent.Subtasks) + 1})
+			continue
+		}
+
+		task := ImportTask{
+			Title:       title,
+			Description: field("DESCRIPTION"),
+			Status:      StatusTodo,
+			Priority:    todoistPriority(field("PRIORITY")),
+		}
+		for _, label := range labels {
+			tag, err := importTagName(label)
+			if err != nil {
+				report.skip(source, "label", "label %q: %v", label, err)
+				continue
+			}
+			task.Tags = append(task.Tags, tag)
+		}
+		if date := field("DATE"); date != "" {
+			taskLocation := location
+			if zone := field("TIMEZONE"); zone != "" {
+				if zoneLocation, err := time.LoadLocation(zone); err == nil {
+					taskLocation = zoneLocation
+				}
+			}
+			dueAt, ok := parseImportDate(date, taskLocation)
+			if ok {
+				task.DueAt = &dueAt
+			} else {
+				report.skip(source, "due_date", "due date %q is not a fixed date; task imported without it", date)
+			}
+		}
+		project.Tasks = append(project.Tasks, task)
+		parent = &project.Tasks[len(project.Tasks)-1]
+	}
+	return project, nil
+}
+
+// splitTodoistLabels memisahkan label "@nama" dari judul task.
+func splitTodoistLabels(content string) (string, []string) {
+	var labels []string
+	for _, match := range todoistLabel.FindAllStringSubmatch(content, -1) {
+		labels = append(labels, match[2])
+	}
+	title := todoistLabel.ReplaceAllString(content, "$1")
+	return strings.Join(strings.Fields(title), " "), labels
+}
+
+// todoistPriority memetakan PRIORITY di CSV, yaitu 1 (p1, tertinggi) sampai
+// 4 (p4, default Todoist), ke prioritas di sini.
+func todoistPriority(value string) Priority {
+	switch value {
+	case "1":
+		return PriorityUrgent
+	case "2":
+		return PriorityHigh
+	case "3":
+		return PriorityMedium
+	}
+	return PriorityLow
+}
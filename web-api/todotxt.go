@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const todoTxtDate = "2006-01-02"
+
+// todoTxtPriorities memetakan prioritas ke huruf todo.txt. medium ditulis
+// tanpa prioritas, jadi task todo.txt tanpa prioritas menjadi medium.
+var todoTxtPriorities = map[Priority]string{PriorityUrgent: "A", PriorityHigh: "B", PriorityLow: "C"}
+
+// parseTodoTxt membaca file todo.txt (https://github.com/todotxt/todo.txt).
+// Satu baris adalah satu task:
+//
+//	x 2026-10-14 2026-10-01 (A) Judul +Project @context due:2026-10-20
+//
+// Awalan "x" menandai task selesai, diikuti tanggal selesai dan tanggal
+// dibuat. Prioritas A menjadi urgent, B high, C sampai Z low, dan tanpa
+// prioritas medium; task selesai menyimpan prioritasnya di pri:. +project
+// pertama menjadi project (yang sudah ada dipakai), @context menjadi tag, dan
+// due: menjadi due_at pukul 23:59 di location. status:in_progress dan
+// status:cancelled dari export dikenali; token lain, termasuk +project
+// berikutnya, tetap menjadi bagian judul.
+func parseTodoTxt(data []byte, location *time.Location, report *ImportReport) ([]ImportProject, error) {
+	var projects []ImportProject
+	byName := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxImportBytes)
+	total := 0
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if text == "" {
+			continue
+		}
+		source := fmt.Sprintf("line %d", line)
+		task, project := parseTodoTxtLine(text, location, source, report)
+		if task == nil {
+			continue
+		}
+		if total++; total > maxImportTasks {
+			return nil, ErrImportTooLarge
+		}
+		index, ok := byName[strings.ToLower(project)]
+		if !ok {
+			index = len(projects)
+			byName[strings.ToLower(project)] = index
+			projects = append(projects, ImportProject{Name: project, Reuse: true})
+		}
+		projects[index].Tasks = append(projects[index].Tasks, *task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, errors.New("file does not contain any tasks")
+	}
+	return projects, nil
+}
+
+// parseTodoTxtLine mengembalikan task dan nama project-nya, atau nil jika
+// baris tidak bisa diimpor (dicatat di report).
+func parseTodoTxtLine(text string, location *time.Location, source string, report *ImportReport) (*ImportTask, string) {
+	tokens := strings.Fields(text)
+	task := &ImportTask{Status: StatusTodo, Priority: PriorityMedium}
+	date := func() (time.Time, bool) {
+		if len(tokens) == 0 {
+			return time.Time{}, false
+		}
+		parsed, err := time.ParseInLocation(todoTxtDate, tokens[0], location)
+		if err == nil {
+			tokens = tokens[1:]
+		}
+		return parsed, err == nil
+	}
+
+	if tokens[0] == "x" {
+		tokens = tokens[1:]
+		task.Status = StatusDone
+		if completedAt, ok := date(); ok {
+			task.CompletedAt = &completedAt
+		}
+	} else if priority, ok := todoTxtPriority(tokens[0]); ok {
+		tokens = tokens[1:]
+		task.Priority = priority
+	}
+	if createdAt, ok := date(); ok {
+		task.CreatedAt = createdAt
+	}
+
+	project := ""
+	var title []string
+	for _, token := range tokens {
+		key, value, _ := strings.Cut(token, ":")
+		switch {
+		case len(token) > 1 && token[0] == '+' && project == "":
+			project = token[1:]
+		case len(token) > 1 && token[0] == '@':
+			tag, err := importTagName(token[1:])
+			if err != nil {
+				report.skip(source, "context", "context %q: %v", token, err)
+				continue
+			}
+			task.Tags = append(task.Tags, tag)
+		case key == "due" && value != "":
+			dueAt, err := time.ParseInLocation(todoTxtDate, value, location)
+			if err != nil {
+				report.skip(source, "due_date", "due date %q is not YYYY-MM-DD; kept in the title", value)
+				title = append(title, token)
+				continue
+			}
+			dueAt = dueAt.Add(23*time.Hour + 59*time.Minute)
+			task.DueAt = &dueAt
+		case key == "pri" && task.Status == StatusDone:
+			if priority, ok := todoTxtPriority("(" + value + ")"); ok {
+				task.Priority = priority
+			} else {
+				title = append(title, token)
+			}
+		case key == "status" && (value == string(StatusInProgress) || value == string(StatusCancelled)) && task.Status != StatusDone:
+			task.Status = TaskStatus(value)
+		default:
+			title = append(title, token)
+		}
+	}
+	task.Title = strings.Join(title, " ")
+	if task.Title == "" {
+		report.skip(source, "task", "task has no title")
+		return nil, ""
+	}
+	return task, project
+}
+
+// todoTxtPriority membaca prioritas "(A)" sampai "(Z)".
+func todoTxtPriority(token string) (Priority, bool) {
+	if len(token) != 3 || token[0] != '(' || token[2] != ')' || token[1] < 'A' || token[1] > 'Z' {
+		return "", false
+	}
+	switch token[1] {
+	case 'A':
+		return PriorityUrgent, true
+	case 'B':
+		return PriorityHigh, true
+	}
+	return PriorityLow, true
+}
+
+// writeTodoTxtLine menulis task sebagai satu baris todo.txt yang bisa dibaca
+// lagi oleh parseTodoTxt. Spasi di nama project dan tag diganti "_", dan
+// deskripsi, subtask, serta jam pada due_at tidak ikut karena tidak ada
+// padanannya di todo.txt.
+func writeTodoTxtLine(w io.Writer, task *Task, location *time.Location) error {
+	var parts []string
+	priority := todoTxtPriorities[task.Priority]
+	if task.Status == StatusDone {
+		// Tanggal dibuat hanya boleh ditulis setelah tanggal selesai.
+		completedAt := task.UpdatedAt
+		if task.CompletedAt != nil {
+			completedAt = *task.CompletedAt
+		}
+		parts = append(parts, "x", completedAt.In(location).Format(todoTxtDate))
+	} else if priority != "" {
+		parts = append(parts, "("+priority+")")
+	}
+	parts = append(parts, task.CreatedAt.In(location).Format(todoTxtDate))
+	parts = append(parts, strings.Fields(task.Title)...)
+	if task.Project != nil {
+		parts = append(parts, "+"+todoTxtWord(task.Project.Name))
+	}
+	for _, tag := range task.Tags {
+		parts = append(parts, "@"+todoTxtWord(tag.Name))
+	}
+	if task.DueAt != nil {
+		parts = append(parts, "due:"+task.DueAt.In(location).Format(todoTxtDate))
+	}
+	if task.Status == StatusDone && priority != "" {
+		parts = append(parts, "pri:"+priority)
+	}
+	if task.Status == StatusInProgress || task.Status == StatusCancelled {
+		parts = append(parts, "status:"+string(task.Status))
+	}
+	_, err := io.WriteString(w, strings.Join(parts, " ")+"\n")
+	return err
+}
+
+func todoTxtWord(value string) string {
+	return strings.Join(strings.Fields(value), "_")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Interface untuk layanan trash
+type TrashService interface {
+	ListTrash(ctx context.Context, page Page) (*TaskPage, error)
+	RestoreTask(ctx context.Context, id uint) (*Task, error)
+	PurgeTask(ctx context.Context, id uint) ([]Attachment, error)
+}
+
+// Struct implementasi TrashService dengan GORM
+type TrashServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListTrash mengembalikan task yang dihapus milik user (atau workspace-nya),
+// dari yang terakhir dihapus.
+func (s *TrashServiceImpl) ListTrash(ctx context.Context, page Page) (*TaskPage, error) {
+	db := s.DB.WithContext(ctx)
+	result := &TaskPage{}
+	if err := trashedTasks(db.Model(&Task{})).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err := trashedTasks(preloadTaskRelations(db)).
+		Order("deleted_at DESC, id DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&result.Tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RestoreTask mengeluarkan task dari trash.
+func (s *TrashServiceImpl) RestoreTask(ctx context.Context, id uint) (*Task, error) {
+	var task *Task
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		restored, err := restoreTasks(tx, []uint{id})
+		if err != nil {
+			return err
+		}
+		if len(restored) == 0 {
+			return ErrTaskNotFound
+		}
+		tasks, err := findTasksByIDs(tx, ownedByWorkspace, restored)
+		if err != nil {
+			return err
+		}
+		task = &tasks[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// PurgeTask menghapus task di trash secara permanen beserta subtask, komentar,
+// dan riwayatnya. Lampirannya dikembalikan supaya file-nya bisa dihapus dari
+// storage oleh pemanggil.
+func (s *TrashServiceImpl) PurgeTask(ctx context.Context, id uint) ([]Attachment, error) {
+	var attachments []Attachment
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task Task
+		err := trashedTasks(tx.Preload("Attachments")).First(&task, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+		attachments = task.Attachments
+		return tx.Unscoped().Delete(&task).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// restoreTasks mengeluarkan task dalam ids yang masih ada di trash milik user
+// (atau workspace-nya) dan mengembalikan id yang benar-benar dipulihkan.
+func restoreTasks(tx *gorm.DB, ids []uint) ([]uint, error) {
+	var found []uint
+	if err := trashedTasks(tx.Model(&Task{})).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	err := tx.Unscoped().Model(&Task{}).Where("id IN ?", found).Update("deleted_at", nil).Error
+	return found, err
+}
+
+// trashedTasks membatasi query ke task di trash milik user (atau workspace-nya).
+func trashedTasks(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Scopes(ownedByWorkspace).Where("tasks.deleted_at IS NOT NULL")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler berisi HTTP handler untuk /trash.
+type TrashHandler struct {
+	Service TrashService
+	Files   FileStore
+}
+
+// ListTrash mengembalikan task yang dihapus, dari yang terakhir dihapus, jadi
+// hanya mendukung paging limit/offset.
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "trash only supports limit/offset pagination")
+		return
+	}
+
+	result, err := h.Service.ListTrash(c.Request.Context(), page)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": result.Tasks,
+		"meta":  page.Meta(result.Total, nil),
+	})
+}
+
+func (h *TrashHandler) RestoreTask(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.Service.RestoreTask(c.Request.Context(), id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TrashHandler) PurgeTask(c *gin.Context) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	attachments, err := h.Service.PurgeTask(ctx, id)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, id)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	// Seperti DeleteAttachment, file yang gagal dihapus hanya menjadi sampah di storage.
+	for _, attachment := range attachments {
+		h.Files.Delete(ctx, attachment.StorageKey)
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeBatch       = 100
+)
+
+// trashPurgeMetrics dipublikasikan lewat expvar di GET /admin/metrics: runs
+// (jumlah putaran), purged (task yang dihapus permanen), errors, dan last_run.
+var trashPurgeMetrics = expvar.NewMap("trash_purge")
+
+// TrashPurger berjalan di background dan menghapus permanen task yang sudah
+// berada di trash lebih lama dari Retention, beserta file lampirannya.
+type TrashPurger struct {
+	DB        *gorm.DB
+	Files     FileStore
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// parseTrashRetention membaca TRASH_RETENTION (default 30 hari); "0"
+// mematikan penghapusan otomatis.
+func parseTrashRetention() (time.Duration, error) {
+	if getEnv("TRASH_RETENTION", "") == "0" {
+		return 0, nil
+	}
+	return parseDurationEnv("TRASH_RETENTION", defaultTrashRetention)
+}
+
+// Run membersihkan trash setiap Interval sampai ctx dibatalkan.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.purgeExpired(ctx)
+		trashPurgeMetrics.Add("runs", 1)
+		trashPurgeMetrics.Add("purged", purged)
+		last := new(expvar.String)
+		last.Set(time.Now().UTC().Format(time.RFC3339))
+		trashPurgeMetrics.Set("last_run", last)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			trashPurgeMetrics.Add("errors", 1)
+			log.Printf("trash purge: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("trash purge: deleted %d task(s)", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpired menghapus task kedaluwarsa per batch dan mengembalikan
+// jumlahnya. Setiap task dihapus sendiri-sendiri supaya task yang baru saja
+// dipulihkan dari trash dilewati, dan file-nya tidak ikut terhapus.
+func (p *TrashPurger) purgeExpired(ctx context.Context) (int64, error) {
+	db := p.DB.WithContext(ctx)
+	cutoff := time.Now().Add(-p.Retention)
+	var purged int64
+	for {
+		var tasks []Task
+		err := db.Unscoped().Preload("Attachments").
+			Where("deleted_at < ?", cutoff).
+			Order("id").
+			Limit(trashPurgeBatch).
+			Find(&tasks).Error
+		if err != nil {
+			return purged, err
+		}
+
+		for i := range tasks {
+			deleted := db.Unscoped().Where("deleted_at < ?", cutoff).Delete(&tasks[i])
+			if deleted.Error != nil {
+				return purged, deleted.Error
+			}
+			if deleted.RowsAffected == 0 {
+				continue
+			}
+			purged++
+			for _, attachment := range tasks[i].Attachments {
+				p.Files.Delete(ctx, attachment.StorageKey)
+			}
+		}
+		if len(tasks) < trashPurgeBatch {
+			return purged, nil
+		}
+	}
+}
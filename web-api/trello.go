@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trelloBoard adalah bagian file JSON hasil "Print and export > Export as
+// JSON" board Trello yang dipakai import; actions, members dan sebagainya
+// diabaikan.
+type trelloBoard struct {
+	Lists []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		Desc        string     `json:"desc"`
+		IDList      string     `json:"idList"`
+		Closed      bool       `json:"closed"`
+		Pos         float64    `json:"pos"`
+		Due         *time.Time `json:"due"`
+		DueComplete bool       `json:"dueComplete"`
+		IDLabels    []string   `json:"idLabels"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// parseTrelloBoard menerjemahkan board Trello: setiap list menjadi project,
+// card menjadi task, item checklist menjadi subtask, dan label menjadi tag.
+// Label tanpa nama memakai nama warnanya. Card dengan due date yang ditandai
+// selesai menjadi task done. List dan card yang diarsipkan tidak diimpor.
+func parseTrelloBoard(data []byte, report *ImportReport) ([]ImportProject, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("invalid Trello board JSON: %w", err)
+	}
+	if len(board.Lists) == 0 {
+		return nil, errors.New("board does not contain any lists")
+	}
+
+	labels := map[string]string{}
+	for _, label := range board.Labels {
+		name := label.Name
+		if strings.TrimSpace(name) == "" {
+			name = label.Color
+		}
+		tag, err := importTagName(name)
+		if err != nil {
+			report.skip("label "+label.ID, "label", "%v", err)
+			continue
+		}
+		labels[label.ID] = tag
+	}
+
+	sort.SliceStable(board.Checklists, func(i, j int) bool { return board.Checklists[i].Pos < board.Checklists[j].Pos })
+	subtasks := map[string][]Subtask{}
+	for _, checklist := range board.Checklists {
+		items := checklist.CheckItems
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+		for _, item := range items {
+			title := strings.TrimSpace(item.Name)
+			if title == "" {
+				continue
+			}
+			subtasks[checklist.IDCard] = append(subtasks[checklist.IDCard], Subtask{
+				Title:    title,
+				Done:     item.State == "complete",
+				Position: len(subtasks[checklist.IDCard]) + 1,
+			})
+		}
+	}
+
+	sort.SliceStable(board.Lists, func(i, j int) bool { return board.Lists[i].Pos < board.Lists[j].Pos })
+	sort.SliceStable(board.Cards, func(i, j int) bool { return board.Cards[i].Pos < board.Cards[j].Pos })
+	var projects []ImportProject
+	lists := map[string]int{}
+	for _, list := range board.Lists {
+		source := "list " + list.ID
+		name := strings.TrimSpace(list.Name)
+		switch {
+		case list.Closed:
+			report.skip(source, "list", "list %q is archived", list.Name)
+			continue
+		case name == "":
+			report.skip(source, "list", "list has no name")
+			continue
+		}
+		lists[list.ID] = len(projects)
+		projects = append(projects, ImportProject{Name: name})
+	}
+
+	for _, card := range board.Cards {
+		source := "card " + card.ID
+		index, ok := lists[card.IDList]
+		title := strings.TrimSpace(card.Name)
+		switch {
+		case card.Closed:
+			report.skip(source, "card", "card %q is archived", card.Name)
+			continue
+		case !ok:
+			report.skip(source, "card", "card %q is in an archived or missing list", card.Name)
+			continue
+		case title == "":
+			report.skip(source, "card", "card has no name")
+			continue
+		}
+		task := ImportTask{
+			Title:       title,
+			Description: card.Desc,
+			Status:      StatusTodo,
+			Priority:    PriorityMedium,
+			DueAt:       card.Due,
+			Subtasks:    subtasks[card.ID],
+		}
+		if card.Due != nil && card.DueComplete {
+			task.Status = StatusDone
+		}
+		for _, id := range card.IDLabels {
+			if tag, ok := labels[id]; ok {
+				task.Tags = append(task.Tags, tag)
+			}
+		}
+		projects[index].Tasks = append(projects[index].Tasks, task)
+	}
+	if len(projects) == 0 {
+		return nil, errors.New("board does not contain any open lists")
+	}
+	return projects, nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const backupCodeCount = 10
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor enrollment has not been started")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+// BackupCode adalah kode sekali pakai untuk login saat authenticator app tidak tersedia.
+type BackupCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	User      *User  `gorm:"constraint:OnDelete:CASCADE"`
+	CodeHash  string `gorm:"type:char(64);not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Interface untuk layanan two-factor authentication
+type TwoFactorService interface {
+	BeginEnrollment(ctx context.Context, userID uint) (string, error)
+	ConfirmEnrollment(ctx context.Context, userID uint, code string, now time.Time) ([]string, error)
+	VerifyCode(ctx context.Context, userID uint, code string, now time.Time) error
+	RegenerateBackupCodes(ctx context.Context, userID uint) ([]string, error)
+	CountBackupCodes(ctx context.Context, userID uint) (int64, error)
+	Disable(ctx context.Context, userID uint) error
+}
+
+// Struct implementasi TwoFactorService dengan GORM
+type TwoFactorServiceImpl struct {
+	DB *gorm.DB
+}
+
+// BeginEnrollment membuat secret baru yang belum aktif sampai dikonfirmasi
+// dengan kode dari authenticator app. Enrollment ulang mengganti secret lama.
+func (s *TwoFactorServiceImpl) BeginEnrollment(ctx context.Context, userID uint) (string, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+
+	result := s.DB.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Updates(map[string]any{"totp_secret": secret, "totp_last_step": 0})
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", ErrTwoFactorEnabled
+	}
+	return secret, nil
+}
+
+// ConfirmEnrollment mengaktifkan 2FA jika code cocok dengan secret yang sedang
+// di-enroll, lalu mengembalikan backup code baru.
+func (s *TwoFactorServiceImpl) ConfirmEnrollment(ctx context.Context, userID uint, code string, now time.Time) ([]string, error) {
+	var codes []string
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled() {
+			return ErrTwoFactorEnabled
+		}
+		if user.TOTPSecret == "" {
+			return ErrTwoFactorNotEnrolled
+		}
+		step, ok := matchTOTP(user.TOTPSecret, code, now, user.TOTPLastStep)
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		err := tx.Model(&user).Updates(map[string]any{"totp_enabled_at": now, "totp_last_step": step}).Error
+		if err != nil {
+			return err
+		}
+		codes, err = replaceBackupCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyCode menerima kode TOTP atau backup code. Keduanya hanya bisa dipakai sekali.
+func (s *TwoFactorServiceImpl) VerifyCode(ctx context.Context, userID uint, code string, now time.Time) error {
+	db := s.DB.WithContext(ctx)
+	var user User
+	if err := db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := matchTOTP(user.TOTPSecret, code, now, user.TOTPLastStep); ok {
+		// Klaim step secara atomik supaya dua request dengan kode yang sama tidak sama-sama lolos.
+		claim := db.Model(&User{}).
+			Where("id = ? AND totp_last_step < ?", userID, step).
+			Update("totp_last_step", step)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	claim := db.Model(&BackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalizeBackupCode(code))).
+		Update("used_at", now)
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *TwoFactorServiceImpl) RegenerateBackupCodes(ctx context.Context, userID uint) ([]string, error) {
+	var codes []string
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceBackupCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CountBackupCodes mengembalikan jumlah backup code yang belum dipakai.
+func (s *TwoFactorServiceImpl) CountBackupCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&BackupCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Disable mematikan 2FA dan menghapus secret serta semua backup code.
+func (s *TwoFactorServiceImpl) Disable(ctx context.Context, userID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&User{}).Where("id = ?", userID).
+			Updates(map[string]any{"totp_secret": "", "totp_enabled_at": nil, "totp_last_step": 0}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&BackupCode{}).Error
+	})
+}
+
+// replaceBackupCodes menghapus backup code lama dan membuat backupCodeCount kode baru.
+func replaceBackupCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&BackupCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, backupCodeCount)
+	rows := make([]BackupCode, backupCodeCount)
+	for i := range codes {
+		code, err := generateBackupCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		rows[i] = BackupCode{UserID: userID, CodeHash: hashToken(normalizeBackupCode(code))}
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// backupCodeAlphabet tidak memuat karakter yang mudah tertukar (0/o, 1/l/i).
+const backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// generateBackupCode menghasilkan kode berbentuk "xxxxx-xxxxx".
+func generateBackupCode() (string, error) {
+	max := big.NewInt(int64(len(backupCodeAlphabet)))
+	code := make([]byte, 0, 11)
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, backupCodeAlphabet[n.Int64()])
+	}
+	return string(code), nil
+}
+
+// normalizeBackupCode mengabaikan huruf besar, spasi, dan tanda hubung.
+func normalizeBackupCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type disableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// ShowTwoFactor menampilkan status 2FA user yang sedang login.
+func (h *AuthHandler) ShowTwoFactor(c *gin.Context) {
+	user := currentUser(c)
+	remaining, err := h.TwoFactor.CountBackupCodes(c.Request.Context(), user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":                user.TwoFactorEnabled(),
+		"enabled_at":             user.TOTPEnabledAt,
+		"backup_codes_remaining": remaining,
+	})
+}
+
+// EnrollTwoFactor membuat secret TOTP baru beserta URI otpauth:// untuk QR code.
+// 2FA belum aktif sampai dikonfirmasi lewat ConfirmTwoFactor.
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	user := currentUser(c)
+	secret, err := h.TwoFactor.BeginEnrollment(c.Request.Context(), user.ID)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_uri": totpURI(h.Issuer, user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactor mengaktifkan 2FA dengan kode pertama dari authenticator app
+// dan mengembalikan backup code. Backup code hanya ditampilkan sekali ini.
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	codes, err := h.TwoFactor.ConfirmEnrollment(c.Request.Context(), currentUser(c).ID, req.Code, time.Now())
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"backup_codes": codes})
+}
+
+// RegenerateBackupCodes mengganti semua backup code; kode lama tidak berlaku lagi.
+func (h *AuthHandler) RegenerateBackupCodes(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	user := currentUser(c)
+	if err := h.TwoFactor.VerifyCode(c.Request.Context(), user.ID, req.Code, time.Now()); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	codes, err := h.TwoFactor.RegenerateBackupCodes(c.Request.Context(), user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"backup_codes": codes})
+}
+
+// DisableTwoFactor mematikan 2FA setelah password dan kode 2FA dicek ulang.
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req disableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	user := currentUser(c)
+	if !checkPassword(user, req.Password) {
+		problem(c, http.StatusBadRequest, "invalid password")
+		return
+	}
+	if err := h.TwoFactor.VerifyCode(c.Request.Context(), user.ID, req.Code, time.Now()); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	if err := h.TwoFactor.Disable(c.Request.Context(), user.ID); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkSecondFactor dipanggil saat login setelah password benar. User dengan
+// 2FA aktif harus mengirim code berisi kode TOTP atau backup code.
+func (h *AuthHandler) checkSecondFactor(c *gin.Context, user *User, code string) bool {
+	if !user.TwoFactorEnabled() {
+		return true
+	}
+	if code == "" {
+		problem(c, http.StatusUnauthorized, "two-factor code required", gin.H{
+			"two_factor_required": true,
+		})
+		return false
+	}
+
+	err := h.TwoFactor.VerifyCode(c.Request.Context(), user.ID, code, time.Now())
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		h.Logins.Fail(user.Email, c.ClientIP(), time.Now())
+		if h.recordEvent(c, user.ID, AuthEventLoginFailed) {
+			problem(c, http.StatusUnauthorized, err.Error())
+		}
+		return false
+	}
+	if err != nil {
+		internalError(c, err)
+		return false
+	}
+	return true
+}
+
+func twoFactorError(c *gin.Context, err error) {
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, ErrTwoFactorEnabled) || errors.Is(err, ErrTwoFactorNotEnabled) || errors.Is(err, ErrTwoFactorNotEnrolled) {
+		problem(c, http.StatusConflict, err.Error())
+		return
+	}
+	internalError(c, err)
+}
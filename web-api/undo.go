@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultUndoWindow = 10 * time.Minute
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// UndoKind adalah jenis aksi yang bisa dibatalkan lewat POST /undo.
+type UndoKind string
+
+const (
+	UndoDelete   UndoKind = "delete"
+	UndoComplete UndoKind = "complete"
+)
+
+// UndoAction adalah catatan sementara satu aksi user (hapus atau selesaikan
+// task, termasuk lewat bulk) yang masih bisa dibatalkan sampai ExpiresAt.
+// Snapshot disimpan dengan gob karena Task menyembunyikan sebagian kolomnya
+// dari JSON.
+type UndoAction struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	UserID      uint         `json:"-" gorm:"not null;index"`
+	User        *User        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	WorkspaceID *uint        `json:"-"`
+	Workspace   *Workspace   `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Kind        UndoKind     `json:"action" gorm:"type:varchar(16);not null"`
+	Snapshot    undoSnapshot `json:"-" gorm:"serializer:gob;type:bytes"`
+	ExpiresAt   time.Time    `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// undoSnapshot berisi task sebelum aksinya. Untuk UndoDelete cukup id-nya
+// karena task masih ada di trash; untuk UndoComplete ditambah status sebelumnya.
+type undoSnapshot struct {
+	Tasks []Task
+}
+
+// UndoResult adalah hasil Undo: aksi yang dibatalkan dan task sesudahnya.
+type UndoResult struct {
+	Kind  UndoKind
+	Tasks []Task
+}
+
+// Interface untuk layanan undo
+type UndoService interface {
+	Undo(ctx context.Context) (*UndoResult, error)
+}
+
+// Struct implementasi UndoService dengan GORM
+type UndoServiceImpl struct {
+	DB *gorm.DB
+}
+
+// Undo membatalkan aksi terakhir user di context yang belum kedaluwarsa, di
+// tempat yang sama (data pribadi atau workspace yang dipilih), lalu menghapus
+// catatannya supaya tidak bisa dibatalkan dua kali.
+func (s *UndoServiceImpl) Undo(ctx context.Context) (*UndoResult, error) {
+	var result UndoResult
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var action UndoAction
+		err := inUndoSpace(tx).
+			Where("expires_at > ?", time.Now()).
+			Order("created_at DESC, id DESC").
+			First(&action).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNothingToUndo
+		}
+		if err != nil {
+			return err
+		}
+		// Jika request lain sudah membatalkan aksi ini lebih dulu, tidak ada baris yang terhapus.
+		deleted := tx.Delete(&action)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return ErrNothingToUndo
+		}
+
+		result.Kind = action.Kind
+		var ids []uint
+		switch action.Kind {
+		case UndoDelete:
+			ids, err = restoreTasks(tx, snapshotIDs(&action.Snapshot))
+		case UndoComplete:
+			ids, err = reopenTasks(tx, &action)
+		}
+		if err != nil {
+			return err
+		}
+		result.Tasks = []Task{}
+		if len(ids) == 0 {
+			return nil
+		}
+		result.Tasks, err = findTasksByIDs(tx, accessibleTasks, ids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// parseUndoWindow membaca UNDO_WINDOW (default 10 menit); "0" mematikan undo.
+func parseUndoWindow() (time.Duration, error) {
+	if getEnv("UNDO_WINDOW", "") == "0" {
+		return 0, nil
+	}
+	return parseDurationEnv("UNDO_WINDOW", defaultUndoWindow)
+}
+
+// recordUndo menyimpan aksi kind atas nama user di context selama window,
+// sekalian membersihkan catatan yang sudah kedaluwarsa. window 0 berarti
+// undo dimatikan.
+func recordUndo(tx *gorm.DB, window time.Duration, kind UndoKind, snapshot undoSnapshot) error {
+	ctx := tx.Statement.Context
+	if window <= 0 || ownerID(ctx) == 0 || len(snapshot.Tasks) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if err := tx.Where("expires_at <= ?", now).Delete(&UndoAction{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(&UndoAction{
+		UserID:      ownerID(ctx),
+		WorkspaceID: workspaceID(ctx),
+		Kind:        kind,
+		Snapshot:    snapshot,
+		ExpiresAt:   now.Add(window),
+	}).Error
+}
+
+// reopenTasks mengembalikan status task yang diselesaikan oleh action. Task
+// yang sudah tidak done lagi dilewati. Kemunculan berikutnya dari task
+// berulang yang belum disentuh ikut dihapus permanen supaya tidak dobel saat
+// task diselesaikan lagi.
+func reopenTasks(tx *gorm.DB, action *UndoAction) ([]uint, error) {
+	var ids []uint
+	for _, before := range action.Snapshot.Tasks {
+		var task Task
+		err := tx.Scopes(accessibleTasks).First(&task, before.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if task.Status != StatusDone {
+			continue
+		}
+
+		if task.Recurrence != "" && task.RecurrenceScheduled {
+			err := tx.Unscoped().
+				Where("recurrence_parent_id = ? AND status = ? AND version = 1 AND created_at >= ?",
+					task.ID, StatusTodo, action.CreatedAt).
+				Delete(&Task{}).Error
+			if err != nil {
+				return nil, err
+			}
+			task.RecurrenceScheduled = false
+		}
+		task.Status = before.Status
+		task.CompletedAt = before.CompletedAt
+		if _, err := saveTaskVersion(tx, &task); err != nil {
+			return nil, err
+		}
+		ids = append(ids, task.ID)
+	}
+	return ids, nil
+}
+
+func snapshotIDs(snapshot *undoSnapshot) []uint {
+	ids := make([]uint, len(snapshot.Tasks))
+	for i, task := range snapshot.Tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// inUndoSpace membatasi catatan undo ke milik user di context, di workspace
+// yang dipilih atau di data pribadi.
+func inUndoSpace(tx *gorm.DB) *gorm.DB {
+	ctx := tx.Statement.Context
+	query := tx.Where("user_id = ?", ownerID(ctx))
+	if id := workspaceID(ctx); id != nil {
+		return query.Where("workspace_id = ?", *id)
+	}
+	return query.Where("workspace_id IS NULL")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UndoHandler berisi HTTP handler untuk POST /undo.
+type UndoHandler struct {
+	Service UndoService
+}
+
+// Undo membatalkan hapus atau selesai terakhir milik user yang login dan
+// mengembalikan task yang dipulihkan.
+func (h *UndoHandler) Undo(c *gin.Context) {
+	result, err := h.Service.Undo(c.Request.Context())
+	if errors.Is(err, ErrNothingToUndo) {
+		problem(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"action": result.Kind, "tasks": result.Tasks})
+}
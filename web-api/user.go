@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrEmailTaken    = errors.New("email already registered")
+	ErrLastAdmin     = errors.New("cannot remove the last admin")
+	ErrUserDisabled  = errors.New("account is disabled")
+	ErrUsernameTaken = errors.New("username already taken")
+)
+
+// usernamePattern adalah format username: huruf kecil, angka, _, ., dan -,
+// 3 sampai 32 karakter, diawali huruf, angka, atau _.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_][a-z0-9_.-]{2,31}$`)
+
+// User adalah akun yang bisa login ke web API.
+//
+// EmailVerifiedAt diisi saat user membuka link verifikasi. TOTPSecret disimpan
+// apa adanya karena dibutuhkan untuk menghitung kode; 2FA baru aktif setelah
+// TOTPEnabledAt diisi, dan TOTPLastStep menolak kode yang dipakai ulang.
+// TokenVersion dinaikkan untuk mencabut semua access token yang sudah diterbitkan.
+// Role menentukan akses ke endpoint /admin; user pertama otomatis menjadi admin.
+// DisabledAt diisi admin untuk memblokir login dan semua request user.
+// Username opsional dan dipakai untuk @mention di komentar.
+// DigestSentAt adalah waktu digest harian terakhir dikirim.
+type User struct {
+	ID              uint            `json:"id" gorm:"primaryKey"`
+	Name            string          `json:"name" gorm:"not null;default:''"`
+	Email           string          `json:"email" gorm:"type:varchar(254);not null;uniqueIndex"`
+	Username        *string         `json:"username" gorm:"type:varchar(32);uniqueIndex"`
+	PasswordHash    string          `json:"-" gorm:"not null"`
+	EmailVerifiedAt *time.Time      `json:"email_verified_at"`
+	TOTPSecret      string          `json:"-" gorm:"not null;default:''"`
+	TOTPEnabledAt   *time.Time      `json:"two_factor_enabled_at"`
+	TOTPLastStep    int64           `json:"-" gorm:"not null;default:0"`
+	TokenVersion    int             `json:"-" gorm:"not null;default:0"`
+	Role            Role            `json:"role" gorm:"type:varchar(16);not null;default:'member'"`
+	DisabledAt      *time.Time      `json:"disabled_at"`
+	Preferences     UserPreferences `json:"preferences" gorm:"embedded;embeddedPrefix:pref_"`
+	AvatarURL       string          `json:"avatar_url" gorm:"type:varchar(255);not null;default:''"`
+	DigestSentAt    *time.Time      `json:"-"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// UserPreferences berisi pengaturan milik user. Timezone adalah zona IANA
+// default untuk ?tz= di summary, agenda, dan stats; Locale adalah tag bahasa
+// BCP 47 seperti id-ID untuk dipakai client; DailyDigest mengaktifkan email
+// ringkasan task setiap pagi.
+type UserPreferences struct {
+	Timezone    string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	Locale      string `json:"locale" gorm:"type:varchar(35);not null;default:'en'"`
+	DailyDigest bool   `json:"daily_digest" gorm:"not null;default:false"`
+}
+
+var defaultUserPreferences = UserPreferences{Timezone: "UTC", Locale: "en"}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// normalizeEmail membuat email case-insensitive supaya satu alamat hanya bisa didaftarkan sekali.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeUsername membuat username case-insensitive. Username kosong berarti
+// user tidak punya username dan tidak bisa di-mention.
+func normalizeUsername(username string) (*string, error) {
+	username = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(username), "@")))
+	if username == "" {
+		return nil, nil
+	}
+	if !usernamePattern.MatchString(username) {
+		return nil, errors.New("username must be 3-32 characters of lowercase letters, digits, _, . or -")
+	}
+	return &username, nil
+}
+
+// Interface untuk layanan user
+type UserService interface {
+	CreateUser(ctx context.Context, user *User) error
+	GetUser(ctx context.Context, id uint) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UpdateUser(ctx context.Context, user *User) error
+	SetAvatar(ctx context.Context, userID uint, url string) error
+	DeleteUser(ctx context.Context, userID uint) error
+	RevokeCredentials(ctx context.Context, userID uint) error
+	SetRole(ctx context.Context, userID uint, role Role) error
+	ListUsers(ctx context.Context, page Page) ([]User, int64, error)
+	SetDisabled(ctx context.Context, userID uint, disabled bool) error
+}
+
+// Struct implementasi UserService dengan GORM
+type UserServiceImpl struct {
+	DB *gorm.DB
+}
+
+// CreateUser menyimpan user baru. User pertama menjadi admin dan juga
+// menerima data yang dibuat sebelum ada akun, termasuk task awal dari SeedTasks.
+func (s *UserServiceImpl) CreateUser(ctx context.Context, user *User) error {
+	user.Email = normalizeEmail(user.Email)
+	user.Role = RoleMember
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 1 {
+			return nil
+		}
+		if err := tx.Model(user).Update("role", RoleAdmin).Error; err != nil {
+			return err
+		}
+		return claimOrphanedData(tx, user.ID)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+func (s *UserServiceImpl) GetUser(ctx context.Context, id uint) (*User, error) {
+	var user User
+	err := s.DB.WithContext(ctx).First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserServiceImpl) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	var user User
+	err := s.DB.WithContext(ctx).Where("email = ?", normalizeEmail(email)).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser hanya menyimpan kolom profil supaya tidak menimpa kolom
+// kredensial (password, 2FA, token_version) yang bisa berubah bersamaan.
+func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *User) error {
+	user.Email = normalizeEmail(user.Email)
+	err := s.DB.WithContext(ctx).Model(user).
+		Select("name", "email", "username", "email_verified_at", "pref_timezone", "pref_locale", "pref_daily_digest").
+		Updates(user).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return s.duplicateProfileError(ctx, user)
+	}
+	return err
+}
+
+// duplicateProfileError menentukan kolom unik mana yang bentrok saat UpdateUser.
+func (s *UserServiceImpl) duplicateProfileError(ctx context.Context, user *User) error {
+	if user.Username == nil {
+		return ErrEmailTaken
+	}
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&User{}).
+		Where("username = ? AND id <> ?", *user.Username, user.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrUsernameTaken
+	}
+	return ErrEmailTaken
+}
+
+func (s *UserServiceImpl) SetAvatar(ctx context.Context, userID uint, url string) error {
+	return s.DB.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("avatar_url", url).Error
+}
+
+// DeleteUser menghapus user beserta semua datanya dalam satu transaksi. Data
+// dihapus eksplisit (tidak hanya mengandalkan ON DELETE CASCADE) supaya tetap
+// bersih di database yang foreign key-nya dibuat sebelum kolom user_id ada.
+// Subtask, relasi tag, dan dependency ikut terhapus lewat cascade dari tasks.
+func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := leaveAllWorkspaces(tx, userID); err != nil {
+			return err
+		}
+		owned := []any{
+			&Task{}, &Project{}, &Tag{}, &SavedFilter{}, &TaskTemplate{},
+			&RefreshToken{}, &Session{}, &APIKey{}, &OAuthIdentity{}, &BackupCode{},
+			&PasswordResetToken{}, &EmailVerificationToken{}, &TaskPermission{}, &AuthEvent{}, &Comment{}, &CommentReaction{}, &TaskWatcher{}, &UndoAction{}, &Notification{}, &SlackConnection{}, &SlackAuthState{}, &TelegramLink{}, &TelegramLinkCode{}, &PushSubscription{}, &CalendarFeed{}, &GoogleTasksConnection{}, &GoogleTasksAuthState{}, &GoogleTaskLink{}, &CalDAVResource{},
+		}
+		// Unscoped supaya task di trash ikut terhapus permanen.
+		for _, model := range owned {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		// Riwayat, lampiran, dan task milik user lain tetap ada, hanya
+		// pengubah, pengunggah, dan assignee-nya yang dikosongkan.
+		if err := tx.Model(&TaskEvent{}).Where("user_id = ?", userID).Update("user_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Attachment{}).Where("uploader_id = ?", userID).Update("uploader_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&Task{}).Where("assignee_id = ?", userID).Update("assignee_id", nil).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&User{}, userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// RevokeCredentials mencabut semua access token, refresh token, dan session
+// milik user. API key tidak ikut dicabut karena dikelola terpisah.
+func (s *UserServiceImpl) RevokeCredentials(ctx context.Context, userID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return revokeCredentials(tx, userID, time.Now())
+	})
+}
+
+// SetRole mengganti role user. Admin terakhir tidak bisa diturunkan supaya
+// endpoint /admin selalu bisa diakses seseorang; pengecekan jumlah admin
+// dilakukan di dalam UPDATE yang sama.
+func (s *UserServiceImpl) SetRole(ctx context.Context, userID uint, role Role) error {
+	db := s.DB.WithContext(ctx)
+	query := db.Model(&User{}).Where("id = ?", userID)
+	if role != RoleAdmin {
+		otherAdmins := db.Model(&User{}).Select("COUNT(*)").Where("role = ? AND id <> ?", RoleAdmin, userID)
+		query = query.Where("role <> ? OR (?) > 0", RoleAdmin, otherAdmins)
+	}
+	result := query.Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	return ErrLastAdmin
+}
+
+// ListUsers mengembalikan satu halaman user (urut dari yang paling lama) beserta jumlah totalnya.
+func (s *UserServiceImpl) ListUsers(ctx context.Context, page Page) ([]User, int64, error) {
+	db := s.DB.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []User
+	err := db.Order("id").Limit(page.Limit).Offset(page.Offset).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// SetDisabled menonaktifkan atau mengaktifkan kembali akun. Saat dinonaktifkan,
+// semua access token, refresh token, dan session user ikut dicabut.
+func (s *UserServiceImpl) SetDisabled(ctx context.Context, userID uint, disabled bool) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var disabledAt *time.Time
+		if disabled {
+			disabledAt = &now
+		}
+		result := tx.Model(&User{}).Where("id = ?", userID).Update("disabled_at", disabledAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		if !disabled {
+			return nil
+		}
+		return revokeCredentials(tx, userID, now)
+	})
+}
+
+func revokeCredentials(tx *gorm.DB, userID uint, now time.Time) error {
+	err := tx.Model(&User{}).Where("id = ?", userID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&Session{}).Error
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskWatcher menandai UserID mengikuti task TaskID: ia mendapat notifikasi
+// saat status task berubah atau ada komentar baru. Name dan Email diisi dari
+// tabel users saat daftar watcher dimuat.
+type TaskWatcher struct {
+	TaskID    uint      `json:"-" gorm:"primaryKey"`
+	Task      *Task     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint      `json:"user_id" gorm:"primaryKey;index"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name      string    `json:"name" gorm:"->;-:migration"`
+	Email     string    `json:"email" gorm:"->;-:migration"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Interface untuk layanan watcher task
+type WatcherService interface {
+	ListWatchers(ctx context.Context, taskID uint) ([]TaskWatcher, error)
+	Watch(ctx context.Context, taskID uint) error
+	Unwatch(ctx context.Context, taskID uint) error
+}
+
+// Struct implementasi WatcherService dengan GORM. Berbeda dengan route task
+// lain, akses dicek dengan canSeeTask untuk user di context, sehingga viewer
+// (workspace maupun share) juga bisa mengikuti task.
+type WatcherServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *WatcherServiceImpl) ListWatchers(ctx context.Context, taskID uint) ([]TaskWatcher, error) {
+	db := s.DB.WithContext(ctx)
+	if err := ensureVisibleTask(db, taskID); err != nil {
+		return nil, err
+	}
+
+	var watchers []TaskWatcher
+	err := db.Model(&TaskWatcher{}).
+		Select("task_watchers.*, u.name AS name, u.email AS email").
+		Joins("JOIN users u ON u.id = task_watchers.user_id").
+		Where("task_watchers.task_id = ?", taskID).
+		Order("task_watchers.created_at, task_watchers.user_id").
+		Find(&watchers).Error
+	return watchers, err
+}
+
+// Watch menambahkan user di context sebagai watcher; mengikuti task yang sudah
+// diikuti tidak mengubah apa-apa.
+func (s *WatcherServiceImpl) Watch(ctx context.Context, taskID uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureVisibleTask(db, taskID); err != nil {
+		return err
+	}
+	watcher := TaskWatcher{TaskID: taskID, UserID: ownerID(ctx)}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&watcher).Error
+}
+
+// Unwatch berhenti mengikuti task. Task yang tidak diikuti tidak dianggap error.
+func (s *WatcherServiceImpl) Unwatch(ctx context.Context, taskID uint) error {
+	db := s.DB.WithContext(ctx)
+	if err := ensureVisibleTask(db, taskID); err != nil {
+		return err
+	}
+	return db.Where("task_id = ? AND user_id = ?", taskID, ownerID(ctx)).Delete(&TaskWatcher{}).Error
+}
+
+// ensureVisibleTask adalah ensureTaskExists dengan aturan canSeeTask, tanpa
+// melihat workspace yang dipilih atau role minimum request.
+func ensureVisibleTask(tx *gorm.DB, taskID uint) error {
+	var task Task
+	err := tx.Select("id", "user_id", "workspace_id", "project_id").First(&task, taskID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+	ok, err := canSeeTask(tx, &task, ownerID(tx.Statement.Context))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// notifyWatchers memberi notifikasi kind ke watcher task yang masih bisa
+// membuka task-nya, kecuali user di context yang memicunya. Untuk komentar,
+// watcher yang sudah mendapat notifikasi mention dari komentar yang sama
+// dilewati supaya tidak menerima dua notifikasi.
+func notifyWatchers(tx *gorm.DB, taskID uint, kind NotificationType, commentID *uint) error {
+	actor := ownerID(tx.Statement.Context)
+	query := tx.Model(&TaskWatcher{}).Where("task_id = ? AND user_id <> ?", taskID, actor)
+	if commentID != nil {
+		query = query.Where("user_id NOT IN (?)", tx.Session(&gorm.Session{NewDB: true}).
+			Model(&Notification{}).Select("user_id").Where("comment_id = ?", *commentID))
+	}
+	var watchers []TaskWatcher
+	if err := query.Find(&watchers).Error; err != nil {
+		return err
+	}
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	var task Task
+	if err := tx.Select("id", "user_id", "workspace_id", "project_id").First(&task, taskID).Error; err != nil {
+		return err
+	}
+	var actorID *uint
+	if actor != 0 {
+		actorID = &actor
+	}
+	var notifications []Notification
+	for _, watcher := range watchers {
+		ok, err := canSeeTask(tx, &task, watcher.UserID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			notifications = append(notifications, Notification{
+				UserID:    watcher.UserID,
+				Type:      kind,
+				ActorID:   actorID,
+				TaskID:    &task.ID,
+				CommentID: commentID,
+			})
+		}
+	}
+	return notify(tx, notifications)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatcherHandler berisi HTTP handler untuk /tasks/:id/watchers dan /tasks/:id/watch.
+type WatcherHandler struct {
+	Service WatcherService
+}
+
+func (h *WatcherHandler) ListWatchers(c *gin.Context) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	watchers, err := h.Service.ListWatchers(c.Request.Context(), taskID)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watchers": watchers})
+}
+
+func (h *WatcherHandler) Watch(c *gin.Context) {
+	h.changeWatch(c, h.Service.Watch)
+}
+
+func (h *WatcherHandler) Unwatch(c *gin.Context) {
+	h.changeWatch(c, h.Service.Unwatch)
+}
+
+func (h *WatcherHandler) changeWatch(c *gin.Context, change func(ctx context.Context, taskID uint) error) {
+	taskID, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	err := change(c.Request.Context(), taskID)
+	if errors.Is(err, ErrTaskNotFound) {
+		taskNotFound(c, taskID)
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
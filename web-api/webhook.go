@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxWebhooksPerWorkspace = 20
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrTooManyWebhooks = fmt.Errorf("a workspace can have at most %d webhooks", maxWebhooksPerWorkspace)
+)
+
+// WebhookEvent adalah jenis kejadian task yang dikirim ke webhook.
+type WebhookEvent string
+
+const (
+	WebhookTaskCreated   WebhookEvent = "task.created"
+	WebhookTaskUpdated   WebhookEvent = "task.updated"
+	WebhookTaskCompleted WebhookEvent = "task.completed"
+	WebhookTaskDeleted   WebhookEvent = "task.deleted"
+)
+
+var webhookEvents = []WebhookEvent{WebhookTaskCreated, WebhookTaskUpdated, WebhookTaskCompleted, WebhookTaskDeleted}
+
+// WebhookFormat menentukan bentuk body yang dikirim: payload JSON lengkap,
+// atau pesan teks untuk Slack incoming webhook.
+type WebhookFormat string
+
+const (
+	WebhookFormatJSON  WebhookFormat = "json"
+	WebhookFormatSlack WebhookFormat = "slack"
+)
+
+func ParseWebhookFormat(value string) (WebhookFormat, error) {
+	switch format := WebhookFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return WebhookFormatJSON, nil
+	case WebhookFormatJSON, WebhookFormatSlack:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q: must be json or slack", value)
+}
+
+func ParseWebhookEvent(value string) (WebhookEvent, error) {
+	event := WebhookEvent(strings.ToLower(strings.TrimSpace(value)))
+	if slices.Contains(webhookEvents, event) {
+		return event, nil
+	}
+	return "", fmt.Errorf("invalid event %q: must be one of task.created, task.updated, task.completed, task.deleted", value)
+}
+
+// Webhook adalah URL milik satu workspace yang menerima POST untuk setiap
+// kejadian di Events. Secret dipakai untuk menandatangani body dan hanya
+// ditampilkan saat webhook dibuat.
+type Webhook struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"not null;index"`
+	Workspace   *Workspace     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	URL         string         `json:"url" gorm:"not null"`
+	Format      WebhookFormat  `json:"format" gorm:"type:varchar(16);not null;default:'json'"`
+	Secret      string         `json:"-" gorm:"not null"`
+	Events      []WebhookEvent `json:"events" gorm:"serializer:json"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// DeliveryStatus adalah keadaan satu pengiriman webhook.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookPayload adalah body JSON yang dikirim ke webhook.
+type WebhookPayload struct {
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Task       Task         `json:"task"`
+}
+
+// WebhookDelivery mencatat satu kejadian untuk satu webhook beserta hasil
+// percobaan terakhirnya. Selama Status pending, WebhookDispatcher mengirimnya
+// lagi pada NextAttemptAt.
+type WebhookDelivery struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	WebhookID      uint           `json:"webhook_id" gorm:"not null;index"`
+	Webhook        *Webhook       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Event          WebhookEvent   `json:"event" gorm:"type:varchar(32);not null"`
+	Payload        WebhookPayload `json:"payload" gorm:"serializer:json;type:text"`
+	Status         DeliveryStatus `json:"status" gorm:"type:varchar(16);not null;index"`
+	Attempts       int            `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus *int           `json:"response_status"`
+	Error          string         `json:"error" gorm:"not null;default:''"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at" gorm:"index"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// WebhookDeliveryPage adalah satu halaman log pengiriman beserta totalnya.
+type WebhookDeliveryPage struct {
+	Deliveries []WebhookDelivery
+	Total      int64
+}
+
+// Interface untuk layanan webhook
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	CreateWebhook(ctx context.Context, webhook *Webhook) error
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, webhookID uint, page Page) (*WebhookDeliveryPage, error)
+}
+
+// Struct implementasi WebhookService dengan GORM. Semua method bekerja pada
+// workspace di context (dari /workspaces/:id).
+type WebhookServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := s.DB.WithContext(ctx).Scopes(inCurrentWorkspace).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// CreateWebhook menyimpan webhook dengan secret acak yang diisikan ke webhook.Secret.
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, webhook *Webhook) error {
+	workspace := workspaceID(ctx)
+	if workspace == nil {
+		return ErrWebhookNotFound
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	webhook.WorkspaceID = *workspace
+	webhook.Secret = secret
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Webhook{}).Scopes(inCurrentWorkspace).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxWebhooksPerWorkspace {
+			return ErrTooManyWebhooks
+		}
+		return tx.Create(webhook).Error
+	})
+}
+
+// DeleteWebhook menghapus webhook beserta log pengirimannya.
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(inCurrentWorkspace).Delete(&Webhook{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries mengembalikan log pengiriman webhook, dari yang terbaru.
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, webhookID uint, page Page) (*WebhookDeliveryPage, error) {
+	db := s.DB.WithContext(ctx)
+	var webhook Webhook
+	err := db.Scopes(inCurrentWorkspace).First(&webhook, webhookID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WebhookDeliveryPage{}
+	if err := db.Model(&WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err = db.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, id DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&result.Deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// enqueueWebhooks mencatat pengiriman event untuk setiap task workspace ke
+// semua webhook workspace-nya yang berlangganan event itu. Pengiriman
+// sebenarnya dilakukan WebhookDispatcher setelah transaksi selesai.
+func enqueueWebhooks(tx *gorm.DB, event WebhookEvent, tasks ...Task) error {
+	tx = tx.Session(&gorm.Session{NewDB: true})
+	now := time.Now()
+	hooks := make(map[uint][]Webhook)
+	var deliveries []WebhookDelivery
+	for _, task := range tasks {
+		if task.WorkspaceID == nil {
+			continue
+		}
+		workspace := *task.WorkspaceID
+		if _, ok := hooks[workspace]; !ok {
+			var found []Webhook
+			if err := tx.Where("workspace_id = ?", workspace).Find(&found).Error; err != nil {
+				return err
+			}
+			hooks[workspace] = found
+		}
+		for _, webhook := range hooks[workspace] {
+			if !slices.Contains(webhook.Events, event) {
+				continue
+			}
+			deliveries = append(deliveries, WebhookDelivery{
+				WebhookID:     webhook.ID,
+				Event:         event,
+				Payload:       WebhookPayload{Event: event, OccurredAt: now, Task: task},
+				Status:        DeliveryPending,
+				NextAttemptAt: &now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Create(&deliveries).Error
+}
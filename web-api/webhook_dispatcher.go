@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookMaxAttempts = 6
+	webhookBatch       = 50
+	webhookTimeout     = 10 * time.Second
+	// webhookLease menahan pengiriman yang sedang diproses supaya instance
+	// lain tidak mengirimnya bersamaan.
+	webhookLease    = time.Minute
+	webhookMaxError = 500
+)
+
+var errPrivateAddress = errors.New("webhook address is not public")
+
+// WebhookDispatcher berjalan di background dan mengirim WebhookDelivery yang
+// masih pending. Pengiriman yang gagal (error jaringan atau status selain 2xx)
+// diulang dengan jeda yang makin panjang sampai webhookMaxAttempts kali.
+type WebhookDispatcher struct {
+	DB       *gorm.DB
+	Client   *http.Client
+	Interval time.Duration
+}
+
+// newWebhookClient membuat http.Client untuk webhook. Kecuali allowPrivate,
+// koneksi ke alamat loopback, private, dan link-local ditolak supaya webhook
+// tidak bisa dipakai untuk menjangkau layanan internal. Redirect tidak diikuti.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			return checkWebhookAddress(address)
+		}
+	}
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run mengirim pengiriman yang jatuh tempo setiap Interval sampai ctx dibatalkan.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.deliverDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) error {
+	var deliveries []WebhookDelivery
+	err := d.DB.WithContext(ctx).Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", DeliveryPending, time.Now()).
+		Order("next_attempt_at, id").
+		Limit(webhookBatch).
+		Find(&deliveries).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if err := d.deliver(ctx, &deliveries[i]); err != nil {
+			log.Printf("webhook dispatcher: delivery %d: %v", deliveries[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// deliver mengklaim satu pengiriman (menaikkan Attempts hanya jika belum
+// diklaim instance lain), mengirimnya, lalu menyimpan hasilnya.
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *WebhookDelivery) error {
+	db := d.DB.WithContext(ctx)
+	lease := time.Now().Add(webhookLease)
+	claim := db.Model(&WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", delivery.ID, DeliveryPending, delivery.Attempts).
+		Updates(map[string]any{"attempts": delivery.Attempts + 1, "next_attempt_at": lease})
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 || delivery.Webhook == nil {
+		return nil
+	}
+	delivery.Attempts++
+
+	status, err := d.send(ctx, delivery)
+	updates := map[string]any{"response_status": nil, "error": ""}
+	if status != 0 {
+		updates["response_status"] = status
+	}
+	now := time.Now()
+	switch {
+	case err == nil:
+		updates["status"] = DeliverySucceeded
+		updates["delivered_at"] = now
+		updates["next_attempt_at"] = nil
+	case delivery.Attempts >= webhookMaxAttempts:
+		updates["status"] = DeliveryFailed
+		updates["next_attempt_at"] = nil
+	default:
+		updates["next_attempt_at"] = now.Add(webhookRetryDelay(delivery.Attempts))
+	}
+	if err != nil {
+		message := err.Error()
+		if len(message) > webhookMaxError {
+			message = message[:webhookMaxError]
+		}
+		updates["error"] = message
+	}
+	return db.Model(delivery).Updates(updates).Error
+}
+
+// checkWebhookAddress dipanggil untuk setiap alamat host:port yang akan
+// di-dial, setelah DNS di-resolve, jadi nama host yang mengarah ke jaringan
+// internal juga tertolak.
+func checkWebhookAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// webhookSignature adalah nilai X-Webhook-Signature untuk body:
+// "sha256=" diikuti HMAC-SHA256 body dengan secret, dalam hex.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send mengirim payload dan mengembalikan status HTTP (0 jika tidak ada respons).
+// Body ditandatangani dengan HMAC-SHA256 memakai secret webhook di header
+// X-Webhook-Signature supaya penerima bisa memastikan asalnya.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *WebhookDelivery) (int, error) {
+	var body []byte
+	var err error
+	if delivery.Webhook.Format == WebhookFormatSlack {
+		body, err = json.Marshal(gin.H{"text": slackMessage(&delivery.Payload)})
+	} else {
+		body, err = json.Marshal(delivery.Payload)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todolist-webhooks")
+	req.Header.Set("X-Webhook-Event", string(delivery.Event))
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Signature", webhookSignature(delivery.Webhook.Secret, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookRetryDelay adalah jeda sebelum percobaan berikutnya setelah attempts
+// kali gagal: 30 detik, 2 menit, 8 menit, 32 menit, lalu sekitar 2 jam.
+func webhookRetryDelay(attempts int) time.Duration {
+	return 30 * time.Second << (2 * (attempts - 1))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	// RFC 4231, test case 2.
+	got := webhookSignature("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("webhookSignature() = %q, want %q", got, want)
+	}
+	if webhookSignature("other", []byte("what do ya want for nothing?")) == want {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+		{"127.0.0.1:80", true},
+		{"127.8.8.8:80", true},
+		{"10.0.0.5:80", true},
+		{"172.16.0.1:80", true},
+		{"192.168.1.1:80", true},
+		{"169.254.169.254:80", true},
+		{"0.0.0.0:80", true},
+		{"224.0.0.1:80", true},
+		{"[::1]:80", true},
+		{"[::]:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd00::1]:80", true},
+		{"[ff02::1]:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"[::ffff:10.0.0.1]:80", true},
+		{"example.com:80", true},
+		{"93.184.216.34", true},
+	}
+	for _, tt := range tests {
+		err := checkWebhookAddress(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkWebhookAddress(%q) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	var got struct {
+		header http.Header
+		body   []byte
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.header = r.Header.Clone()
+		got.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	delivery := &WebhookDelivery{
+		ID:      42,
+		Event:   WebhookTaskCreated,
+		Webhook: &Webhook{URL: server.URL, Format: WebhookFormatJSON, Secret: "s3cret"},
+		Payload: WebhookPayload{Event: WebhookTaskCreated, OccurredAt: time.Unix(1700000000, 0).UTC(), Task: Task{ID: 7, Title: "Beli susu"}},
+	}
+	d := &WebhookDispatcher{Client: newWebhookClient(true)}
+	status, err := d.send(context.Background(), delivery)
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("send() = (%d, %v), want (202, nil)", status, err)
+	}
+
+	if sig := got.header.Get("X-Webhook-Signature"); sig != webhookSignature("s3cret", got.body) {
+		t.Errorf("X-Webhook-Signature = %q does not match the body", sig)
+	}
+	if event := got.header.Get("X-Webhook-Event"); event != string(WebhookTaskCreated) {
+		t.Errorf("X-Webhook-Event = %q", event)
+	}
+	if id := got.header.Get("X-Webhook-Delivery"); id != "42" {
+		t.Errorf("X-Webhook-Delivery = %q, want 42", id)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil || payload.Task.ID != 7 {
+		t.Errorf("body = %s (%v)", got.body, err)
+	}
+}
+
+func TestWebhookSendStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	delivery := &WebhookDelivery{Event: WebhookTaskDeleted, Webhook: &Webhook{URL: server.URL, Secret: "s"}}
+	d := &WebhookDispatcher{Client: newWebhookClient(true)}
+	// Redirect tidak diikuti dan dianggap gagal.
+	if status, err := d.send(context.Background(), delivery); err == nil || status != http.StatusFound {
+		t.Errorf("send() = (%d, %v), want (302, error)", status, err)
+	}
+}
+
+func TestWebhookClientBlocksPrivate(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	delivery := &WebhookDelivery{Event: WebhookTaskCreated, Webhook: &Webhook{URL: server.URL, Secret: "s"}}
+	d := &WebhookDispatcher{Client: newWebhookClient(false)}
+	status, err := d.send(context.Background(), delivery)
+	if !errors.Is(err, errPrivateAddress) || status != 0 {
+		t.Errorf("send() to loopback = (%d, %v), want errPrivateAddress", status, err)
+	}
+	if called {
+		t.Error("request reached the loopback server")
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	want := []time.Duration{30 * time.Second, 2 * time.Minute, 8 * time.Minute, 32 * time.Minute, 128 * time.Minute}
+	for i, w := range want {
+		if got := webhookRetryDelay(i + 1); got != w {
+			t.Errorf("webhookRetryDelay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type webhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Format string   `json:"format"`
+	Events []string `json:"events"`
+}
+
+// WebhookHandler berisi HTTP handler untuk /workspaces/:id/webhooks.
+type WebhookHandler struct {
+	Service WebhookService
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.Service.ListWebhooks(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// CreateWebhook mendaftarkan URL baru. Tanpa events, webhook menerima semua
+// event; format "slack" mengirim pesan teks ke Slack incoming webhook. Secret
+// untuk memverifikasi tanda tangan hanya ada di respons ini.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	target, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || len(req.URL) > 2048 {
+		problem(c, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+	format, err := ParseWebhookFormat(req.Format)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	events := webhookEvents
+	if len(req.Events) > 0 {
+		events = nil
+		for _, value := range req.Events {
+			event, err := ParseWebhookEvent(value)
+			if err != nil {
+				problem(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			if !slices.Contains(events, event) {
+				events = append(events, event)
+			}
+		}
+	}
+
+	webhook := Webhook{URL: target.String(), Format: format, Events: events}
+	if err := h.Service.CreateWebhook(c.Request.Context(), &webhook); err != nil {
+		webhookError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": webhook, "secret": webhook.Secret})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteWebhook(c.Request.Context(), id); err != nil {
+		webhookError(c, err, id)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries mengembalikan log pengiriman dari yang terbaru, jadi hanya
+// mendukung paging limit/offset.
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.CursorMode {
+		problem(c, http.StatusBadRequest, "webhook deliveries only support limit/offset pagination")
+		return
+	}
+
+	result, err := h.Service.ListDeliveries(c.Request.Context(), id, page)
+	if err != nil {
+		webhookError(c, err, id)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": result.Deliveries,
+		"meta":       page.Meta(result.Total, nil),
+	})
+}
+
+func parseWebhookID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("webhookID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid webhook id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func webhookError(c *gin.Context, err error, id uint) {
+	switch {
+	case errors.Is(err, ErrWebhookNotFound):
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+	case errors.Is(err, ErrTooManyWebhooks):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	pushTTL          = 24 * time.Hour
+	pushRecordSize   = 4096
+	maxPushEndpoints = 20
+)
+
+var (
+	ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+	ErrTooManyPushSubscriptions = fmt.Errorf("an account can have at most %d push subscriptions", maxPushEndpoints)
+	// errPushGone dikembalikan saat push service menjawab 404 atau 410, yaitu
+	// subscription sudah tidak berlaku dan harus dihapus.
+	errPushGone = errors.New("push subscription is gone")
+)
+
+// PushSubscription adalah subscription Push API dari satu browser. P256DH dan
+// Auth adalah kunci browser (base64url) untuk mengenkripsi payload.
+type PushSubscription struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;index"`
+	User      *User     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Endpoint  string    `json:"endpoint" gorm:"type:varchar(2048);not null;uniqueIndex"`
+	P256DH    string    `json:"-" gorm:"column:p256dh;not null"`
+	Auth      string    `json:"-" gorm:"not null"`
+	UserAgent string    `json:"user_agent" gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PushMessage adalah payload JSON yang diterima service worker.
+type PushMessage struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	TaskID *uint  `json:"task_id,omitempty"`
+}
+
+// Interface untuk layanan push subscription
+type PushSubscriptionService interface {
+	ListSubscriptions(ctx context.Context) ([]PushSubscription, error)
+	Subscribe(ctx context.Context, subscription *PushSubscription) error
+	Unsubscribe(ctx context.Context, id uint) error
+}
+
+// Struct implementasi PushSubscriptionService dengan GORM. Semua method
+// bekerja pada subscription milik user di context.
+type PushSubscriptionServiceImpl struct {
+	DB *gorm.DB
+}
+
+func (s *PushSubscriptionServiceImpl) ListSubscriptions(ctx context.Context) ([]PushSubscription, error) {
+	var subscriptions []PushSubscription
+	err := s.DB.WithContext(ctx).Scopes(ownedByUser).Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Subscribe menyimpan subscription. Endpoint yang sudah terdaftar (misalnya
+// browser yang berlangganan ulang, atau dipakai akun lain) dipindahkan ke user
+// di context dengan kunci yang baru.
+func (s *PushSubscriptionServiceImpl) Subscribe(ctx context.Context, subscription *PushSubscription) error {
+	subscription.UserID = ownerID(ctx)
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint = ?", subscription.Endpoint).Delete(&PushSubscription{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&PushSubscription{}).Scopes(ownedByUser).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxPushEndpoints {
+			return ErrTooManyPushSubscriptions
+		}
+		return tx.Create(subscription).Error
+	})
+}
+
+func (s *PushSubscriptionServiceImpl) Unsubscribe(ctx context.Context, id uint) error {
+	result := s.DB.WithContext(ctx).Scopes(ownedByUser).Delete(&PushSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// WebPush mengirim pesan Web Push yang dienkripsi (RFC 8291, aes128gcm) dan
+// diautentikasi dengan VAPID (RFC 8292).
+type WebPush struct {
+	PublicKey  []byte
+	PrivateKey *ecdsa.PrivateKey
+	Subject    string
+	Client     *http.Client
+}
+
+// newWebPush membaca VAPID_PUBLIC_KEY dan VAPID_PRIVATE_KEY (base64url, format
+// keluaran `web-push generate-vapid-keys`) dan VAPID_SUBJECT. Tanpa kunci, Web
+// Push tidak aktif (nil). client dipakai untuk request ke push service.
+func newWebPush(client *http.Client) (*WebPush, error) {
+	publicValue, privateValue := getEnv("VAPID_PUBLIC_KEY", ""), getEnv("VAPID_PRIVATE_KEY", "")
+	if publicValue == "" && privateValue == "" {
+		return nil, nil
+	}
+	scalar, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(privateValue, "="))
+	if err != nil {
+		return nil, errors.New("invalid VAPID_PRIVATE_KEY: not base64url")
+	}
+	key, err := ecdh.P256().NewPrivateKey(scalar)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID_PRIVATE_KEY: %w", err)
+	}
+	public := key.PublicKey().Bytes()
+	if publicValue != base64.RawURLEncoding.EncodeToString(public) {
+		return nil, errors.New("VAPID_PUBLIC_KEY does not match VAPID_PRIVATE_KEY")
+	}
+	subject := getEnv("VAPID_SUBJECT", "")
+	if !strings.HasPrefix(subject, "mailto:") && !strings.HasPrefix(subject, "https://") {
+		return nil, errors.New("VAPID_SUBJECT must be a mailto: or https:// URL")
+	}
+	return &WebPush{
+		PublicKey: public,
+		PrivateKey: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(public[1:33]),
+				Y:     new(big.Int).SetBytes(public[33:]),
+			},
+			D: new(big.Int).SetBytes(scalar),
+		},
+		Subject: subject,
+		Client:  client,
+	}, nil
+}
+
+// PublicKeyString adalah applicationServerKey untuk pushManager.subscribe.
+func (p *WebPush) PublicKeyString() string {
+	return base64.RawURLEncoding.EncodeToString(p.PublicKey)
+}
+
+// NotifyUser mengirim message ke semua subscription userID. Subscription yang
+// sudah tidak berlaku dihapus; error lain hanya dicatat ke log.
+func (p *WebPush) NotifyUser(ctx context.Context, db *gorm.DB, userID uint, message PushMessage) error {
+	var subscriptions []PushSubscription
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	for i := range subscriptions {
+		err := p.Send(ctx, &subscriptions[i], payload)
+		if errors.Is(err, errPushGone) {
+			if err := db.WithContext(ctx).Delete(&subscriptions[i]).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("web push: subscription %d: %v", subscriptions[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// Send mengenkripsi payload untuk satu subscription dan mengirimnya ke push service.
+func (p *WebPush) Send(ctx context.Context, subscription *PushSubscription, payload []byte) error {
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+	body, err := encryptPushPayload(subscription, payload)
+	if err != nil {
+		return err
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": p.Subject,
+	}).SignedString(p.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+p.PublicKeyString())
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(pushTTL.Seconds())))
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errPushGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("push service returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// encryptPushPayload mengenkripsi payload sebagai satu record aes128gcm
+// (RFC 8188) dengan kunci yang diturunkan dari ECDH antara kunci sementara
+// server dan kunci p256dh browser, dicampur auth secret (RFC 8291).
+func encryptPushPayload(subscription *PushSubscription, payload []byte) ([]byte, error) {
+	userPublic, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(subscription.P256DH, "="))
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(subscription.Auth, "="))
+	if err != nil {
+		return nil, err
+	}
+	browserKey, err := ecdh.P256().NewPublicKey(userPublic)
+	if err != nil {
+		return nil, err
+	}
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := serverKey.ECDH(browserKey)
+	if err != nil {
+		return nil, err
+	}
+	serverPublic := serverKey.PublicKey().Bytes()
+
+	prkKey, err := hkdf.Extract(sha256.New, shared, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(userPublic) + string(serverPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 menandai record terakhir, tanpa padding.
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	record := gcm.Seal(nil, nonce, plaintext, nil)
+	if len(record) > pushRecordSize {
+		return nil, errors.New("push payload is too large")
+	}
+
+	header := make([]byte, 0, 21+len(serverPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, pushRecordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+	return append(header, record...), nil
+}
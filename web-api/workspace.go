@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrMemberNotFound    = errors.New("workspace member not found")
+	ErrAlreadyMember     = errors.New("user is already a member of this workspace")
+	ErrLastOwner         = errors.New("workspace must keep at least one owner")
+)
+
+// WorkspaceRole menentukan apa yang boleh dilakukan member di workspace.
+type WorkspaceRole string
+
+const (
+	// WorkspaceViewer hanya bisa membaca task dan project.
+	WorkspaceViewer WorkspaceRole = "viewer"
+	// WorkspaceEditor bisa membuat, mengubah, dan menghapus task dan project.
+	WorkspaceEditor WorkspaceRole = "editor"
+	// WorkspaceOwner juga bisa mengganti nama, menghapus workspace, dan mengelola member.
+	WorkspaceOwner WorkspaceRole = "owner"
+)
+
+var workspaceRoleRank = map[WorkspaceRole]int{
+	WorkspaceViewer: 1,
+	WorkspaceEditor: 2,
+	WorkspaceOwner:  3,
+}
+
+func (r WorkspaceRole) Valid() bool {
+	return workspaceRoleRank[r] > 0
+}
+
+// AtLeast bernilai true jika r sama dengan atau lebih tinggi dari minimum.
+func (r WorkspaceRole) AtLeast(minimum WorkspaceRole) bool {
+	return workspaceRoleRank[r] >= workspaceRoleRank[minimum]
+}
+
+func ParseWorkspaceRole(value string) (WorkspaceRole, error) {
+	if r := WorkspaceRole(strings.ToLower(strings.TrimSpace(value))); r.Valid() {
+		return r, nil
+	}
+	return "", fmt.Errorf("invalid workspace role %q: must be one of viewer, editor, owner", value)
+}
+
+// Workspace adalah ruang bersama tempat beberapa user berbagi task dan project,
+// misalnya satu keluarga atau tim kecil. Role hanya diisi saat workspace
+// dimuat untuk user tertentu dan berisi role user tersebut.
+type Workspace struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	Name      string        `json:"name" gorm:"type:varchar(128);not null"`
+	Role      WorkspaceRole `json:"role,omitempty" gorm:"->;-:migration"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// WorkspaceMember menghubungkan user ke workspace. Name dan Email diisi dari
+// tabel users saat daftar member dimuat.
+type WorkspaceMember struct {
+	WorkspaceID uint          `json:"workspace_id" gorm:"primaryKey"`
+	Workspace   *Workspace    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	UserID      uint          `json:"user_id" gorm:"primaryKey;index"`
+	User        *User         `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Role        WorkspaceRole `json:"role" gorm:"type:varchar(16);not null;default:'editor'"`
+	Name        string        `json:"name" gorm:"->;-:migration"`
+	Email       string        `json:"email" gorm:"->;-:migration"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Interface untuk layanan workspace
+type WorkspaceService interface {
+	ListWorkspaces(ctx context.Context) ([]Workspace, error)
+	CreateWorkspace(ctx context.Context, workspace *Workspace) error
+	UpdateWorkspace(ctx context.Context, workspace *Workspace) error
+	DeleteWorkspace(ctx context.Context, id uint) error
+	GetMembership(ctx context.Context, workspaceID, userID uint) (*WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID uint) ([]WorkspaceMember, error)
+	AddMember(ctx context.Context, workspaceID uint, email string, role WorkspaceRole) (*WorkspaceMember, error)
+	SetMemberRole(ctx context.Context, workspaceID, userID uint, role WorkspaceRole) error
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error
+}
+
+// Struct implementasi WorkspaceService dengan GORM
+type WorkspaceServiceImpl struct {
+	DB *gorm.DB
+}
+
+// ListWorkspaces mengembalikan semua workspace yang diikuti user di context beserta role-nya.
+func (s *WorkspaceServiceImpl) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+	var workspaces []Workspace
+	err := s.DB.WithContext(ctx).
+		Select("workspaces.*, m.role").
+		Joins("JOIN workspace_members m ON m.workspace_id = workspaces.id").
+		Where("m.user_id = ?", ownerID(ctx)).
+		Order("workspaces.name, workspaces.id").
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+// CreateWorkspace membuat workspace dengan user di context sebagai owner.
+func (s *WorkspaceServiceImpl) CreateWorkspace(ctx context.Context, workspace *Workspace) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		workspace.Role = WorkspaceOwner
+		return tx.Create(&WorkspaceMember{WorkspaceID: workspace.ID, UserID: ownerID(ctx), Role: WorkspaceOwner}).Error
+	})
+}
+
+func (s *WorkspaceServiceImpl) UpdateWorkspace(ctx context.Context, workspace *Workspace) error {
+	return s.DB.WithContext(ctx).Model(workspace).Select("name").Updates(workspace).Error
+}
+
+// DeleteWorkspace menghapus workspace beserta semua task, project, dan member-nya.
+func (s *WorkspaceServiceImpl) DeleteWorkspace(ctx context.Context, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteWorkspaces(tx, []uint{id})
+	})
+}
+
+// GetMembership memuat keanggotaan userID di workspace beserta workspace-nya.
+func (s *WorkspaceServiceImpl) GetMembership(ctx context.Context, workspaceID, userID uint) (*WorkspaceMember, error) {
+	var member WorkspaceMember
+	err := s.DB.WithContext(ctx).Preload("Workspace").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	member.Workspace.Role = member.Role
+	return &member, nil
+}
+
+func (s *WorkspaceServiceImpl) ListMembers(ctx context.Context, workspaceID uint) ([]WorkspaceMember, error) {
+	var members []WorkspaceMember
+	err := s.DB.WithContext(ctx).
+		Select("workspace_members.*, u.name, u.email").
+		Joins("JOIN users u ON u.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", workspaceID).
+		Order("workspace_members.created_at, workspace_members.user_id").
+		Find(&members).Error
+	return members, err
+}
+
+// AddMember menambahkan user yang sudah terdaftar dengan email tersebut ke workspace.
+func (s *WorkspaceServiceImpl) AddMember(ctx context.Context, workspaceID uint, email string, role WorkspaceRole) (*WorkspaceMember, error) {
+	var user User
+	err := s.DB.WithContext(ctx).Where("email = ?", normalizeEmail(email)).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	member := WorkspaceMember{WorkspaceID: workspaceID, UserID: user.ID, Role: role}
+	err = s.DB.WithContext(ctx).Create(&member).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrAlreadyMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	member.Name = user.Name
+	member.Email = user.Email
+	return &member, nil
+}
+
+// SetMemberRole mengganti role member. Owner terakhir tidak bisa diturunkan;
+// pengecekannya dilakukan di dalam UPDATE yang sama seperti UserServiceImpl.SetRole.
+func (s *WorkspaceServiceImpl) SetMemberRole(ctx context.Context, workspaceID, userID uint, role WorkspaceRole) error {
+	db := s.DB.WithContext(ctx)
+	query := db.Model(&WorkspaceMember{}).Where("workspace_id = ? AND user_id = ?", workspaceID, userID)
+	if role != WorkspaceOwner {
+		query = query.Where("role <> ? OR (?) > 0", WorkspaceOwner, otherOwners(db, workspaceID, userID))
+	}
+	result := query.Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	return s.lastOwnerOrMissing(ctx, workspaceID, userID)
+}
+
+// RemoveMember mengeluarkan member dari workspace. Task dan project yang dibuatnya
+// tetap ada di workspace.
+func (s *WorkspaceServiceImpl) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	var removed bool
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Where("role <> ? OR (?) > 0", WorkspaceOwner, otherOwners(tx, workspaceID, userID)).
+			Delete(&WorkspaceMember{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+		return unassignWithoutAccess(tx, userID)
+	})
+	if err != nil || removed {
+		return err
+	}
+	return s.lastOwnerOrMissing(ctx, workspaceID, userID)
+}
+
+func (s *WorkspaceServiceImpl) lastOwnerOrMissing(ctx context.Context, workspaceID, userID uint) error {
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrMemberNotFound
+	}
+	return ErrLastOwner
+}
+
+func otherOwners(db *gorm.DB, workspaceID, userID uint) *gorm.DB {
+	return db.Model(&WorkspaceMember{}).Select("COUNT(*)").
+		Where("workspace_id = ? AND role = ? AND user_id <> ?", workspaceID, WorkspaceOwner, userID)
+}
+
+// deleteWorkspaces menghapus workspace beserta isinya secara eksplisit, seperti DeleteUser.
+func deleteWorkspaces(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	for _, model := range []any{&Task{}, &Project{}, &WorkspaceMember{}} {
+		if err := tx.Unscoped().Where("workspace_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&Workspace{}, ids).Error
+}
+
+// leaveAllWorkspaces dipanggil sebelum user dihapus. Workspace yang hanya
+// berisi user itu ikut dihapus; di workspace lain, member paling lama
+// dijadikan owner jika user adalah owner terakhir, dan task serta project
+// buatan user tetap ada tanpa pembuat.
+func leaveAllWorkspaces(tx *gorm.DB, userID uint) error {
+	var memberships []WorkspaceMember
+	if err := tx.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
+		return err
+	}
+
+	var empty []uint
+	for _, membership := range memberships {
+		var others []WorkspaceMember
+		err := tx.Where("workspace_id = ? AND user_id <> ?", membership.WorkspaceID, userID).
+			Order("created_at, user_id").Find(&others).Error
+		if err != nil {
+			return err
+		}
+		if len(others) == 0 {
+			empty = append(empty, membership.WorkspaceID)
+			continue
+		}
+		if membership.Role != WorkspaceOwner || hasOwner(others) {
+			continue
+		}
+		err = tx.Model(&others[0]).Update("role", WorkspaceOwner).Error
+		if err != nil {
+			return err
+		}
+	}
+	if err := deleteWorkspaces(tx, empty); err != nil {
+		return err
+	}
+
+	for _, model := range []any{&Task{}, &Project{}} {
+		err := tx.Unscoped().Model(model).Where("user_id = ? AND workspace_id IS NOT NULL", userID).Update("user_id", nil).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Where("user_id = ?", userID).Delete(&WorkspaceMember{}).Error
+}
+
+func hasOwner(members []WorkspaceMember) bool {
+	for _, member := range members {
+		if member.Role == WorkspaceOwner {
+			return true
+		}
+	}
+	return false
+}
+
+type workspaceContextKey struct{}
+
+// withWorkspace menyimpan keanggotaan workspace yang dipilih ke context request.
+func withWorkspace(ctx context.Context, member *WorkspaceMember) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, member)
+}
+
+// workspaceFromContext mengembalikan keanggotaan workspace yang dipilih, atau
+// nil jika request memakai data pribadi user.
+func workspaceFromContext(ctx context.Context) *WorkspaceMember {
+	member, _ := ctx.Value(workspaceContextKey{}).(*WorkspaceMember)
+	return member
+}
+
+// workspaceHeader memilih workspace untuk route task dan project. Tanpa header
+// ini request memakai data pribadi user.
+const workspaceHeader = "X-Workspace-ID"
+
+// useWorkspace memilih workspace dari header X-Workspace-ID. Viewer hanya boleh
+// memakai GET/HEAD. Harus dipasang setelah requireAuth.
+func useWorkspace(workspaces WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(workspaceHeader)
+		if value == "" {
+			c.Next()
+			return
+		}
+		minimum := WorkspaceEditor
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			minimum = WorkspaceViewer
+		}
+		if loadWorkspace(c, workspaces, value, minimum) {
+			c.Next()
+		}
+	}
+}
+
+// requireWorkspaceRole memuat workspace dari parameter :id dan menolak member
+// yang role-nya di bawah minimum. Bukan member dijawab 404.
+func requireWorkspaceRole(workspaces WorkspaceService, minimum WorkspaceRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if loadWorkspace(c, workspaces, c.Param("id"), minimum) {
+			c.Next()
+		}
+	}
+}
+
+func loadWorkspace(c *gin.Context, workspaces WorkspaceService, value string, minimum WorkspaceRole) bool {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid workspace id")
+		return false
+	}
+	ctx := c.Request.Context()
+	member, err := workspaces.GetMembership(ctx, uint(id), ownerID(ctx))
+	if errors.Is(err, ErrWorkspaceNotFound) {
+		problem(c, http.StatusNotFound, err.Error(), gin.H{"id": id})
+		return false
+	}
+	if err != nil {
+		internalError(c, err)
+		c.Abort()
+		return false
+	}
+	if !member.Role.AtLeast(minimum) {
+		problem(c, http.StatusForbidden, "requires workspace "+string(minimum)+" role")
+		return false
+	}
+	c.Request = c.Request.WithContext(withWorkspace(ctx, member))
+	return true
+}
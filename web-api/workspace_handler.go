@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type workspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type addMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role"`
+}
+
+type memberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// WorkspaceHandler berisi HTTP handler untuk /workspaces. Keanggotaan dan role
+// di route /workspaces/:id dicek oleh requireWorkspaceRole di main.go.
+type WorkspaceHandler struct {
+	Service WorkspaceService
+}
+
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	workspaces, err := h.Service.ListWorkspaces(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+}
+
+// CreateWorkspace membuat workspace baru dengan user yang login sebagai owner.
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	name, ok := bindWorkspaceName(c)
+	if !ok {
+		return
+	}
+
+	workspace := Workspace{Name: name}
+	if err := h.Service.CreateWorkspace(c.Request.Context(), &workspace); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace)
+}
+
+// GetWorkspace mengembalikan workspace beserta daftar member-nya.
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	member := workspaceFromContext(c.Request.Context())
+	members, err := h.Service.ListMembers(c.Request.Context(), member.WorkspaceID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspace": member.Workspace,
+		"members":   members,
+	})
+}
+
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	name, ok := bindWorkspaceName(c)
+	if !ok {
+		return
+	}
+
+	workspace := workspaceFromContext(c.Request.Context()).Workspace
+	workspace.Name = name
+	if err := h.Service.UpdateWorkspace(c.Request.Context(), workspace); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// DeleteWorkspace menghapus workspace beserta semua task dan project di dalamnya.
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	member := workspaceFromContext(c.Request.Context())
+	if err := h.Service.DeleteWorkspace(c.Request.Context(), member.WorkspaceID); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddMember menambahkan user yang sudah terdaftar ke workspace. Role default-nya editor.
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	role := WorkspaceEditor
+	if req.Role != "" {
+		var err error
+		if role, err = ParseWorkspaceRole(req.Role); err != nil {
+			problem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	workspace := workspaceFromContext(c.Request.Context())
+	member, err := h.Service.AddMember(c.Request.Context(), workspace.WorkspaceID, req.Email, role)
+	if err != nil {
+		memberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	userID, ok := parseMemberID(c)
+	if !ok {
+		return
+	}
+	var req memberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	role, err := ParseWorkspaceRole(req.Role)
+	if err != nil {
+		problem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	workspace := workspaceFromContext(ctx)
+	if err := h.Service.SetMemberRole(ctx, workspace.WorkspaceID, userID, role); err != nil {
+		memberError(c, err)
+		return
+	}
+	member, err := h.Service.GetMembership(ctx, workspace.WorkspaceID, userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember mengeluarkan member dari workspace. Owner bisa mengeluarkan
+// siapa saja; member lain hanya bisa keluar sendiri (user_id miliknya).
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, ok := parseMemberID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	workspace := workspaceFromContext(ctx)
+	if userID != workspace.UserID && !workspace.Role.AtLeast(WorkspaceOwner) {
+		problem(c, http.StatusForbidden, "requires workspace owner role")
+		return
+	}
+	if err := h.Service.RemoveMember(ctx, workspace.WorkspaceID, userID); err != nil {
+		memberError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func bindWorkspaceName(c *gin.Context) (string, bool) {
+	var req workspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return "", false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 128 {
+		problem(c, http.StatusBadRequest, "name must be between 1 and 128 characters")
+		return "", false
+	}
+	return name, true
+}
+
+func parseMemberID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid user id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func memberError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrMemberNotFound):
+		problem(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrAlreadyMember), errors.Is(err, ErrLastOwner):
+		problem(c, http.StatusConflict, err.Error())
+	default:
+		internalError(c, err)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationInvalid       = errors.New("invalid or expired invitation")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+)
+
+// WorkspaceInvitation mengundang email yang belum tentu terdaftar ke workspace.
+// Seperti PasswordResetToken, token hanya dikirim lewat email dan yang disimpan
+// hanya hash-nya; undangan hanya bisa diterima sekali.
+type WorkspaceInvitation struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint          `json:"workspace_id" gorm:"not null;index"`
+	Workspace   *Workspace    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Email       string        `json:"email" gorm:"type:varchar(254);not null"`
+	Role        WorkspaceRole `json:"role" gorm:"type:varchar(16);not null"`
+	InvitedByID *uint         `json:"invited_by_id"`
+	InvitedBy   *User         `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	TokenHash   string        `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt   time.Time     `json:"expires_at" gorm:"not null"`
+	AcceptedAt  *time.Time    `json:"-"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Interface untuk layanan undangan workspace
+type WorkspaceInvitationService interface {
+	CreateInvitation(ctx context.Context, workspaceID uint, email string, role WorkspaceRole) (*WorkspaceInvitation, string, error)
+	ListInvitations(ctx context.Context, workspaceID uint) ([]WorkspaceInvitation, error)
+	RevokeInvitation(ctx context.Context, workspaceID, id uint) error
+	AcceptInvitation(ctx context.Context, token string) (*Workspace, error)
+}
+
+// Struct implementasi WorkspaceInvitationService dengan GORM
+type WorkspaceInvitationServiceImpl struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+// CreateInvitation membuat undangan atas nama user di context. Undangan lama
+// yang belum diterima untuk email yang sama diganti, sehingga hanya link
+// terbaru yang berlaku.
+func (s *WorkspaceInvitationServiceImpl) CreateInvitation(ctx context.Context, workspaceID uint, email string, role WorkspaceRole) (*WorkspaceInvitation, string, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	inviter := ownerID(ctx)
+	invitation := WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		Email:       normalizeEmail(email),
+		Role:        role,
+		InvitedByID: &inviter,
+		TokenHash:   hashToken(value),
+		ExpiresAt:   time.Now().Add(s.TTL),
+	}
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&WorkspaceMember{}).
+			Joins("JOIN users u ON u.id = workspace_members.user_id").
+			Where("workspace_members.workspace_id = ? AND u.email = ?", workspaceID, invitation.Email).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyMember
+		}
+
+		err = tx.Where("workspace_id = ? AND email = ? AND accepted_at IS NULL", workspaceID, invitation.Email).
+			Delete(&WorkspaceInvitation{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&invitation).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &invitation, value, nil
+}
+
+// ListInvitations menampilkan undangan yang belum diterima dan belum kedaluwarsa.
+func (s *WorkspaceInvitationServiceImpl) ListInvitations(ctx context.Context, workspaceID uint) ([]WorkspaceInvitation, error) {
+	var invitations []WorkspaceInvitation
+	err := s.DB.WithContext(ctx).
+		Where("workspace_id = ? AND accepted_at IS NULL AND expires_at > ?", workspaceID, time.Now()).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// RevokeInvitation membatalkan undangan yang belum diterima; link-nya tidak bisa dipakai lagi.
+func (s *WorkspaceInvitationServiceImpl) RevokeInvitation(ctx context.Context, workspaceID, id uint) error {
+	result := s.DB.WithContext(ctx).
+		Where("workspace_id = ? AND accepted_at IS NULL", workspaceID).
+		Delete(&WorkspaceInvitation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation memakai token lalu menjadikan user di context member
+// workspace dengan role dari undangan. Undangan hanya bisa diterima oleh akun
+// dengan email yang diundang.
+func (s *WorkspaceInvitationServiceImpl) AcceptInvitation(ctx context.Context, token string) (*Workspace, error) {
+	user := userFromContext(ctx)
+	var workspace Workspace
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invitation WorkspaceInvitation
+		err := tx.Preload("Workspace").Where("token_hash = ?", hashToken(token)).First(&invitation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if normalizeEmail(user.Email) != invitation.Email {
+			return ErrInvitationEmailMismatch
+		}
+
+		now := time.Now()
+		claim := tx.Model(&WorkspaceInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 || now.After(invitation.ExpiresAt) {
+			return ErrInvitationInvalid
+		}
+
+		member := WorkspaceMember{WorkspaceID: invitation.WorkspaceID, UserID: user.ID, Role: invitation.Role}
+		err = tx.Create(&member).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyMember
+		}
+		if err != nil {
+			return err
+		}
+		workspace = *invitation.Workspace
+		workspace.Role = member.Role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
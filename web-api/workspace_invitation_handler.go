@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type acceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// InvitationHandler berisi HTTP handler untuk /workspaces/:id/invitations dan
+// /workspaces/invitations/accept.
+type InvitationHandler struct {
+	Service WorkspaceInvitationService
+	Mailer  Mailer
+	// URL adalah halaman frontend untuk menerima undangan; token ditambahkan
+	// sebagai query ?token=. Jika kosong, email hanya berisi token.
+	URL string
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	workspace := workspaceFromContext(c.Request.Context())
+	invitations, err := h.Service.ListInvitations(c.Request.Context(), workspace.WorkspaceID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// CreateInvitation mengirim undangan ke email, terdaftar atau belum. Role
+// default-nya editor seperti AddMember. Token hanya dikirim lewat email.
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	role := WorkspaceEditor
+	if req.Role != "" {
+		var err error
+		if role, err = ParseWorkspaceRole(req.Role); err != nil {
+			problem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	workspace := workspaceFromContext(ctx)
+	invitation, token, err := h.Service.CreateInvitation(ctx, workspace.WorkspaceID, req.Email, role)
+	if err != nil {
+		memberError(c, err)
+		return
+	}
+	h.sendInvitationEmail(currentUser(c), workspace.Workspace, invitation, token)
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("invitationID"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	workspace := workspaceFromContext(c.Request.Context())
+	err = h.Service.RevokeInvitation(c.Request.Context(), workspace.WorkspaceID, uint(id))
+	if errors.Is(err, ErrInvitationNotFound) {
+		problem(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation menjadikan user yang login member workspace dari undangan.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req acceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	workspace, err := h.Service.AcceptInvitation(c.Request.Context(), req.Token)
+	switch {
+	case errors.Is(err, ErrInvitationInvalid):
+		problem(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrInvitationEmailMismatch):
+		problem(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrAlreadyMember):
+		problem(c, http.StatusConflict, err.Error())
+	case err != nil:
+		internalError(c, err)
+	default:
+		c.JSON(http.StatusOK, workspace)
+	}
+}
+
+func (h *InvitationHandler) sendInvitationEmail(inviter *User, workspace *Workspace, invitation *WorkspaceInvitation, token string) {
+	link := token
+	if h.URL != "" {
+		link = h.URL + "?token=" + url.QueryEscape(token)
+	}
+	name := inviter.Name
+	if name == "" {
+		name = inviter.Email
+	}
+	sendInBackground(h.Mailer, Email{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You have been invited to %s", workspace.Name),
+		Body: fmt.Sprintf("%s invited you to join the workspace %q as %s. Sign in or create an account with this email address, then open the link below. It expires at %s and can only be used once.\n\n%s",
+			name, workspace.Name, invitation.Role, invitation.ExpiresAt.UTC().Format(time.RFC1123), link),
+	})
+}